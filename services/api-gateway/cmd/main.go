@@ -1,15 +1,39 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 
+	"booking-system/shared/pkg/jwks"
+	"github.com/redis/go-redis/v9"
+
 	"services/api-gateway/internal/config"
+	"services/api-gateway/internal/middleware"
 	"services/api-gateway/internal/routes"
 )
 
 func main() {
 	cfg := config.NewConfig()
+
+	// Access tokens revoked by user-service are listed in Redis
+	redisOpt, err := redis.ParseURL(cfg.RedisURL)
+	if err != nil {
+		log.Fatalf("Invalid REDIS_URL: %v", err)
+	}
+	redisClient := redis.NewClient(redisOpt)
+	if err := redisClient.Ping(context.Background()).Err(); err != nil {
+		log.Printf("Redis unavailable, token denylist checks will fail open: %v", err)
+	}
+	defer redisClient.Close()
+	middleware.UseTokenDenylist(redisClient)
+
+	// Access tokens are verified with user-service's published public keys
+	middleware.UseJWKSVerifier(jwks.NewVerifier(jwks.VerifierConfig{
+		URL:    cfg.JWKSURL,
+		Issuer: cfg.JWTIssuer,
+	}))
+
 	router := routes.SetupRoutes(cfg)
 
 	log.Println("API Gateway listening on port 8081")
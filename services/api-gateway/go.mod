@@ -1,9 +1,18 @@
 module services/api-gateway
 
-go 1.24.1
+go 1.24.2
 
 require (
-	github.com/golang-jwt/jwt/v5 v5.2.2
+	booking-system/shared v0.0.0-00010101000000-000000000000
 	github.com/gorilla/mux v1.8.1
+	github.com/redis/go-redis/v9 v9.10.0
 	golang.org/x/time v0.11.0
 )
+
+require (
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	github.com/golang-jwt/jwt/v5 v5.2.2 // indirect
+)
+
+replace booking-system/shared => ../../shared
@@ -9,7 +9,9 @@ type Config struct {
 	BookingURL   string
 	ExpertURL    string
 	NotifyURL    string
-	JWTSecret    string
+	JWKSURL      string
+	JWTIssuer    string
+	RedisURL     string
 	RateLimit    int
 	RateDuration int
 }
@@ -20,7 +22,9 @@ func NewConfig() *Config {
 		BookingURL:   getEnv("BOOKING_SERVICE_URL", "http://localhost:8082"),
 		ExpertURL:    getEnv("EXPERT_SERVICE_URL", "http://localhost:8083"),
 		NotifyURL:    getEnv("NOTIFY_SERVICE_URL", "http://localhost:8084"),
-		JWTSecret:    getEnv("JWT_SECRET", "your-secret-key"),
+		JWKSURL:      getEnv("JWKS_URL", "http://localhost:8080/.well-known/jwks.json"),
+		JWTIssuer:    getEnv("JWT_ISSUER", "user-service"),
+		RedisURL:     getEnv("REDIS_URL", "redis://localhost:6379/0"),
 		RateLimit:    100,
 		RateDuration: 60,
 	}
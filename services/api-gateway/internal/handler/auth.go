@@ -3,9 +3,13 @@ package handler
 import (
 	"bytes"
 	"io"
+	"net"
 	"net/http"
 	"strings"
 
+	"booking-system/shared/pkg/apperr"
+	"booking-system/shared/pkg/i18n"
+
 	"services/api-gateway/internal/config"
 )
 
@@ -23,7 +27,7 @@ func HandleAuth(w http.ResponseWriter, r *http.Request) {
 	// Read the request body into a buffer
 	bodyBytes, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, "Error reading request body", http.StatusInternalServerError)
+		apperr.Respond(w, r, apperr.New(apperr.KindInternal, "read_request_failed"))
 		return
 	}
 	defer r.Body.Close()
@@ -35,13 +39,57 @@ func HandleAuth(w http.ResponseWriter, r *http.Request) {
 		targetPath = "/user/register"
 	case "/auth/login":
 		targetPath = "/user/login"
+	case "/auth/login/mfa":
+		targetPath = "/user/login/mfa"
+	case "/auth/login/mfa/enroll":
+		targetPath = "/user/login/mfa/enroll"
+	case "/auth/login/mfa/enroll/confirm":
+		targetPath = "/user/login/mfa/enroll/confirm"
 	case "/auth/refresh":
 		targetPath = "/user/refresh"
+	case "/auth/verify-email":
+		targetPath = "/user/verify-email"
+	case "/auth/verify-email/resend":
+		targetPath = "/user/verify-email/resend"
+	case "/auth/unlock-account":
+		targetPath = "/user/unlock-account"
+	case "/auth/forgot-password":
+		targetPath = "/user/forgot-password"
+	case "/auth/reset-password":
+		targetPath = "/user/reset-password"
+	case "/auth/change-password":
+		targetPath = "/user/change-password"
+	case "/auth/logout":
+		targetPath = "/user/logout"
+	case "/auth/logout-all":
+		targetPath = "/user/logout-all"
+	case "/auth/profile":
+		targetPath = "/user/profile"
+	case "/auth/data-export":
+		targetPath = "/user/data-export"
+	case "/auth/profile/avatar":
+		targetPath = "/user/profile/avatar"
+	case "/auth/notification-settings":
+		targetPath = "/user/notification-settings"
+	case "/auth/notification-settings/preferences":
+		targetPath = "/user/notification-settings/preferences"
 	default:
-		http.Error(w, "Unknown authentication path", http.StatusNotFound)
+		// Session, MFA and admin routes carry IDs in the path
+		if r.URL.Path == "/auth/sessions" || strings.HasPrefix(r.URL.Path, "/auth/sessions/") ||
+			r.URL.Path == "/auth/mfa" || strings.HasPrefix(r.URL.Path, "/auth/mfa/") ||
+			strings.HasPrefix(r.URL.Path, "/auth/admin/") {
+			targetPath = "/user" + strings.TrimPrefix(r.URL.Path, "/auth")
+			break
+		}
+		apperr.Respond(w, r, apperr.NotFound("unknown_auth_path"))
 		return
 	}
 
+	// Verification links carry their token in the query string
+	if r.URL.RawQuery != "" {
+		targetPath += "?" + r.URL.RawQuery
+	}
+
 	// Forward request to user service
 	cfg := config.NewConfig()
 	client := &http.Client{}
@@ -49,7 +97,7 @@ func HandleAuth(w http.ResponseWriter, r *http.Request) {
 	// Create new request with body from buffer
 	userServiceReq, err := http.NewRequest(r.Method, cfg.UserURL+targetPath, bytes.NewReader(bodyBytes))
 	if err != nil {
-		http.Error(w, "Error creating request", http.StatusInternalServerError)
+		apperr.Respond(w, r, apperr.New(apperr.KindInternal, i18n.InternalError))
 		return
 	}
 
@@ -64,19 +112,35 @@ func HandleAuth(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// user-service throttles logins per client IP, so it must see the
+	// caller's address rather than one the caller claims
+	if ip, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		userServiceReq.Header.Set("X-Forwarded-For", ip)
+	}
+
 	// Set Content-Length for the new request
 	userServiceReq.ContentLength = int64(len(bodyBytes))
 
 	// Send request to user service
 	resp, err := client.Do(userServiceReq)
 	if err != nil {
-		http.Error(w, "Error connecting to user service", http.StatusInternalServerError)
+		apperr.Respond(w, r, apperr.Unavailable("user_service_unavailable"))
 		return
 	}
 	defer resp.Body.Close()
 
-	// Copy response
-	w.Header().Set("Content-Type", "application/json")
+	// Copy response; data exports are ZIP downloads
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	w.Header().Set("Content-Type", contentType)
+	if disposition := resp.Header.Get("Content-Disposition"); disposition != "" {
+		w.Header().Set("Content-Disposition", disposition)
+	}
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		w.Header().Set("Retry-After", retryAfter)
+	}
 	w.WriteHeader(resp.StatusCode)
 
 	// Copy response body
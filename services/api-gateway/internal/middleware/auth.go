@@ -4,13 +4,18 @@ import (
 	"context"
 	"net/http"
 	"strings"
-	"time"
 
-	"services/api-gateway/internal/config"
-
-	"github.com/golang-jwt/jwt/v5"
+	"booking-system/shared/pkg/apperr"
+	"booking-system/shared/pkg/jwks"
 )
 
+var verifier *jwks.Verifier
+
+// UseJWKSVerifier sets the verifier AuthMiddleware checks access tokens with
+func UseJWKSVerifier(v *jwks.Verifier) {
+	verifier = v
+}
+
 func AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Skip auth for public routes
@@ -22,37 +27,31 @@ func AuthMiddleware(next http.Handler) http.Handler {
 		// Get token from header
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
-			http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+			apperr.Respond(w, r, apperr.Unauthorized("authorization_required"))
 			return
 		}
 
-		// Extract token
+		// Extract token and verify it against user-service's public keys
 		tokenString := strings.Replace(authHeader, "Bearer ", "", 1)
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			return []byte(config.NewConfig().JWTSecret), nil
-		})
-
-		if err != nil || !token.Valid {
-			http.Error(w, "Invalid token", http.StatusUnauthorized)
+		if verifier == nil {
+			apperr.Respond(w, r, apperr.Unavailable("token_verification_unavailable"))
 			return
 		}
-
-		// Check token expiration
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		claims, err := verifier.Verify(tokenString)
+		if err != nil {
+			apperr.Respond(w, r, apperr.Unauthorized("invalid_token"))
 			return
 		}
 
-		exp, ok := claims["exp"].(float64)
-		if !ok || float64(time.Now().Unix()) > exp {
-			http.Error(w, "Token expired", http.StatusUnauthorized)
+		// Check logout / revocation
+		if isTokenRevoked(r.Context(), claims.ID) {
+			apperr.Respond(w, r, apperr.Unauthorized("token_revoked"))
 			return
 		}
 
 		// Add user info to context
 		ctx := r.Context()
-		ctx = context.WithValue(ctx, "user_id", claims["user_id"])
+		ctx = context.WithValue(ctx, "user_id", claims.Subject)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// accessTokenDenylistPrefix must match the key user-service writes on logout
+const accessTokenDenylistPrefix = "auth:denylist:"
+
+var denylistClient *redis.Client
+
+// UseTokenDenylist makes AuthMiddleware reject access tokens that were
+// revoked by logout, logout-all or a password change
+func UseTokenDenylist(client *redis.Client) {
+	denylistClient = client
+}
+
+// isTokenRevoked fails open when Redis cannot be reached: access tokens are
+// short-lived and their refresh tokens stay revoked in user-service
+func isTokenRevoked(ctx context.Context, tokenID string) bool {
+	if denylistClient == nil || tokenID == "" {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer cancel()
+	n, err := denylistClient.Exists(ctx, accessTokenDenylistPrefix+tokenID).Result()
+	if err != nil {
+		log.Printf("token denylist check failed: %v", err)
+		return false
+	}
+	return n > 0
+}
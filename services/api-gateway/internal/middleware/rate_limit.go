@@ -5,6 +5,9 @@ import (
 	"net/http"
 	"sync"
 	"golang.org/x/time/rate"
+
+	"booking-system/shared/pkg/apperr"
+	"booking-system/shared/pkg/i18n"
 )
 
 var visitors = make(map[string]*rate.Limiter)
@@ -26,12 +29,12 @@ func RateLimitMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ip, _, err := net.SplitHostPort(r.RemoteAddr)
 		if err != nil {
-			http.Error(w, "Cannot parse IP", http.StatusInternalServerError)
+			apperr.Respond(w, r, apperr.New(apperr.KindInternal, i18n.InternalError))
 			return
 		}
 		limiter := getVisitor(ip)
 		if !limiter.Allow() {
-			http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+			apperr.Respond(w, r, apperr.RateLimited("too_many_requests"))
 			return
 		}
 		next.ServeHTTP(w, r)
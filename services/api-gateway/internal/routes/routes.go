@@ -15,8 +15,18 @@ func SetupRoutes(cfg *config.Config) http.Handler {
 
 	// Public routes
 	router.HandleFunc("/auth/register", handler.HandleAuth).Methods("POST")
-	router.HandleFunc("/auth/login", handler.HandleAuth).Methods("POST")
+	router.Handle("/auth/login", middleware.RateLimitMiddleware(http.HandlerFunc(handler.HandleAuth))).Methods("POST")
 	router.HandleFunc("/auth/refresh", handler.HandleAuth).Methods("POST")
+	router.HandleFunc("/auth/verify-email", handler.HandleAuth).Methods("GET", "POST")
+	router.Handle("/auth/verify-email/resend", middleware.RateLimitMiddleware(http.HandlerFunc(handler.HandleAuth))).Methods("POST")
+	router.Handle("/auth/unlock-account", middleware.RateLimitMiddleware(http.HandlerFunc(handler.HandleAuth))).Methods("GET", "POST")
+	router.Handle("/auth/forgot-password", middleware.RateLimitMiddleware(http.HandlerFunc(handler.HandleAuth))).Methods("POST")
+	router.Handle("/auth/login/mfa", middleware.RateLimitMiddleware(http.HandlerFunc(handler.HandleAuth))).Methods("POST")
+	router.Handle("/auth/login/mfa/enroll", middleware.RateLimitMiddleware(http.HandlerFunc(handler.HandleAuth))).Methods("POST")
+	router.Handle("/auth/login/mfa/enroll/confirm", middleware.RateLimitMiddleware(http.HandlerFunc(handler.HandleAuth))).Methods("POST")
+	router.Handle("/auth/reset-password", middleware.RateLimitMiddleware(http.HandlerFunc(handler.HandleAuth))).Methods("POST")
+	// Profile pictures, served by user-service under /user/avatars
+	router.PathPrefix("/avatars/").Handler(middleware.NewReverseProxy(cfg.UserURL + "/user")).Methods("GET")
 
 	// Secured routes group
 	secured := router.PathPrefix("/").Subrouter()
@@ -24,6 +34,17 @@ func SetupRoutes(cfg *config.Config) http.Handler {
 	secured.Use(middleware.AuthMiddleware)
 
 	// User service routes
+	secured.HandleFunc("/auth/change-password", handler.HandleAuth).Methods("POST")
+	secured.HandleFunc("/auth/logout", handler.HandleAuth).Methods("POST")
+	secured.HandleFunc("/auth/logout-all", handler.HandleAuth).Methods("POST")
+	secured.HandleFunc("/auth/profile", handler.HandleAuth).Methods("GET", "PUT", "DELETE")
+	secured.HandleFunc("/auth/data-export", handler.HandleAuth).Methods("GET")
+	secured.HandleFunc("/auth/profile/avatar", handler.HandleAuth).Methods("POST", "DELETE")
+	secured.HandleFunc("/auth/notification-settings", handler.HandleAuth).Methods("GET", "PUT")
+	secured.HandleFunc("/auth/notification-settings/preferences", handler.HandleAuth).Methods("PUT")
+	secured.PathPrefix("/auth/sessions").HandlerFunc(handler.HandleAuth).Methods("GET", "DELETE")
+	secured.PathPrefix("/auth/mfa").HandlerFunc(handler.HandleAuth).Methods("GET", "POST")
+	secured.PathPrefix("/auth/admin/").HandlerFunc(handler.HandleAuth).Methods("GET", "POST", "PUT", "DELETE")
 	secured.PathPrefix("/users").Handler(middleware.NewReverseProxy(cfg.UserURL))
 
 	// Booking service routes
@@ -31,6 +52,8 @@ func SetupRoutes(cfg *config.Config) http.Handler {
 
 	// Other service routes
 	secured.PathPrefix("/experts").Handler(middleware.NewReverseProxy(cfg.ExpertURL))
+	secured.PathPrefix("/expert-applications").Handler(middleware.NewReverseProxy(cfg.ExpertURL))
+	secured.PathPrefix("/certifications").Handler(middleware.NewReverseProxy(cfg.ExpertURL))
 	secured.PathPrefix("/notifications").Handler(middleware.NewReverseProxy(cfg.NotifyURL))
 
 	return router
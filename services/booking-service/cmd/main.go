@@ -10,7 +10,9 @@ import (
 	"syscall"
 	"time"
 
+	"services/booking-service/internal/client"
 	"services/booking-service/internal/config"
+	"services/booking-service/internal/events"
 	"services/booking-service/internal/handler"
 	"services/booking-service/internal/repository"
 	"services/booking-service/internal/routes"
@@ -19,9 +21,15 @@ import (
 	"services/booking-service/pkg/logger"
 	"services/booking-service/pkg/utils"
 
+	"booking-system/shared/pkg/jwks"
+	"booking-system/shared/pkg/mailer"
+	"booking-system/shared/pkg/notify"
 	"github.com/gin-gonic/gin"
 )
 
+// reminderInterval is how often due booking reminders are sent
+const reminderInterval = time.Minute
+
 func main() {
 	// Initialize logger
 	appLogger := logger.NewLogger()
@@ -55,16 +63,41 @@ func main() {
 	// Initialize repositories
 	bookingRepo := repository.NewBookingRepository(gormDB)
 	statusHistoryRepo := repository.NewStatusHistoryRepository(gormDB)
+	sessionRepo := repository.NewSessionRepository(gormDB)
+	privacyRepo := repository.NewPrivacyRepository(gormDB)
+	notificationRepo := repository.NewNotificationRepository(gormDB)
+
+	// Booking events let expert-service invalidate cached slots
+	eventPublisher := events.NewPublisher(redisClient, appLogger)
+
+	// Expert-service client used to verify experts and working hours
+	expertClient := client.NewExpertClient(client.ExpertClientConfig{
+		BaseURL:          cfg.ExpertService.URL,
+		Timeout:          cfg.ExpertService.Timeout,
+		MaxRetries:       cfg.ExpertService.MaxRetries,
+		BreakerThreshold: cfg.ExpertService.BreakerThreshold,
+		BreakerCooldown:  cfg.ExpertService.BreakerCooldown,
+	})
+
+	// Notifications follow each user's settings from user-service, whose
+	// outbox delivers those held back by quiet hours or digests
+	notifier := notify.NewNotifier(db, notify.SendersFromEnv(mailer.FromEnv()))
 
 	// Initialize services
-	bookingService := service.NewBookingService(bookingRepo, statusHistoryRepo, redisClient, appLogger)
-	conflictChecker := service.NewConflictChecker(bookingRepo, redisClient)
-	statusService := service.NewStatusService(statusHistoryRepo, bookingRepo, appLogger)
+	notificationService := service.NewNotificationService(notificationRepo, notifier, appLogger)
+	conflictChecker := service.NewConflictChecker(bookingRepo, sessionRepo, redisClient)
+	verificationPolicy := service.NewVerificationPolicy(bookingRepo, cfg.Verification.MaxPendingUnverified)
+	bookingService := service.NewBookingService(bookingRepo, statusHistoryRepo, redisClient, eventPublisher, conflictChecker, expertClient, cfg.ExpertService.FallbackPolicy, verificationPolicy, notificationService, appLogger)
+	statusService := service.NewStatusService(statusHistoryRepo, bookingRepo, eventPublisher, notificationService, appLogger)
+	sessionService := service.NewSessionService(sessionRepo, conflictChecker, expertClient, cfg.ExpertService.FallbackPolicy, eventPublisher, appLogger)
+	privacyService := service.NewPrivacyService(privacyRepo, appLogger)
 
 	// Initialize handlers
 	bookingHandler := handler.NewBookingHandler(bookingService, conflictChecker, appLogger)
 	statusHandler := handler.NewStatusHandler(statusService, appLogger)
 	historyHandler := handler.NewHistoryHandler(bookingService, appLogger)
+	sessionHandler := handler.NewSessionHandler(sessionService, appLogger)
+	privacyHandler := handler.NewPrivacyHandler(privacyService, appLogger)
 
 	// Initialize Gin router
 	if cfg.App.Environment == "production" {
@@ -72,10 +105,13 @@ func main() {
 	}
 
 	router := gin.Default()
-	router.Use(utils.JWTAuthMiddleware())
+	router.Use(utils.JWTAuthMiddleware(jwks.NewVerifier(jwks.VerifierConfig{
+		URL:    cfg.Auth.JWKSURL,
+		Issuer: cfg.Auth.Issuer,
+	})))
 
 	// Setup routes
-	routes.SetupRoutes(router, bookingHandler, statusHandler, historyHandler)
+	routes.SetupRoutes(router, bookingHandler, statusHandler, historyHandler, sessionHandler, privacyHandler)
 
 	// Create HTTP server
 	srv := &http.Server{
@@ -86,6 +122,8 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	go sendReminders(context.Background(), notificationService, reminderInterval, appLogger)
+
 	// Start server in goroutine
 	go func() {
 		appLogger.Info(fmt.Sprintf("Booking service starting on port %s", cfg.App.Port))
@@ -111,3 +149,19 @@ func main() {
 
 	appLogger.Info("Booking service stopped")
 }
+
+// sendReminders sends the due booking reminders every interval
+func sendReminders(ctx context.Context, notificationService service.NotificationServiceInterface, interval time.Duration, appLogger logger.LoggerInterface) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := notificationService.SendReminders(); err != nil {
+			appLogger.Error("Failed to send booking reminders", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"booking-system/shared/pkg/apperr"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrExpertNotFound           = apperr.NotFound("expert_not_found")
+	ErrExpertUnavailable        = apperr.Conflict("expert_not_accepting_bookings")
+	ErrExpertUnverified         = apperr.Conflict("expert_unverified")
+	ErrExpertNotApproved        = apperr.Conflict("expert_not_approved")
+	ErrOutsideWorkingHours      = apperr.Conflict("outside_working_hours")
+	ErrExpertOffTime            = apperr.Conflict("expert_off_time")
+	ErrExpertServiceUnavailable = apperr.Unavailable("expert_service_unavailable")
+	ErrServiceNotFound          = apperr.NotFound("consultation_service_not_found")
+	ErrServiceInactive          = apperr.Conflict("consultation_service_inactive")
+)
+
+// Reasons returned by expert-service's check-window endpoint
+const (
+	reasonExpertNotFound      = "expert_not_found"
+	reasonExpertUnavailable   = "expert_unavailable"
+	reasonExpertUnverified    = "expert_unverified"
+	reasonExpertNotApproved   = "expert_not_approved"
+	reasonOutsideWorkingHours = "outside_working_hours"
+	reasonOffTime             = "off_time"
+)
+
+type ExpertClientInterface interface {
+	CheckBookingWindow(ctx context.Context, expertID uuid.UUID, startTime time.Time, durationMinutes int) error
+	GetService(ctx context.Context, expertID, serviceID uuid.UUID) (*ConsultationService, error)
+}
+
+// ConsultationService is a consultation type from an expert's catalog
+type ConsultationService struct {
+	ID              uuid.UUID `json:"id"`
+	ExpertID        uuid.UUID `json:"expert_id"`
+	Name            string    `json:"name"`
+	DurationMinutes int       `json:"duration_minutes"`
+	Price           float64   `json:"price"`
+	MeetingType     string    `json:"meeting_type"`
+	IsActive        bool      `json:"is_active"`
+}
+
+type ExpertClientConfig struct {
+	BaseURL          string
+	Timeout          time.Duration
+	MaxRetries       int
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+type ExpertClient struct {
+	baseURL    string
+	httpClient *http.Client
+	maxRetries int
+	breaker    *CircuitBreaker
+}
+
+func NewExpertClient(cfg ExpertClientConfig) ExpertClientInterface {
+	return &ExpertClient{
+		baseURL:    strings.TrimRight(cfg.BaseURL, "/"),
+		httpClient: &http.Client{Timeout: cfg.Timeout},
+		maxRetries: cfg.MaxRetries,
+		breaker:    NewCircuitBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown),
+	}
+}
+
+type checkWindowRequest struct {
+	ExpertID        string    `json:"expert_id"`
+	StartTime       time.Time `json:"start_time"`
+	DurationMinutes int       `json:"duration_minutes"`
+}
+
+type checkWindowResponse struct {
+	Available bool   `json:"available"`
+	Reason    string `json:"reason"`
+}
+
+// CheckBookingWindow asks expert-service whether the expert exists, accepts
+// bookings and works during the whole window. It returns nil when the window
+// is bookable, one of the Err* values otherwise.
+func (c *ExpertClient) CheckBookingWindow(ctx context.Context, expertID uuid.UUID, startTime time.Time, durationMinutes int) error {
+	body, err := json.Marshal(checkWindowRequest{
+		ExpertID:        expertID.String(),
+		StartTime:       startTime,
+		DurationMinutes: durationMinutes,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode check request: %v", err)
+	}
+
+	var result checkWindowResponse
+	if err := c.call(ctx, http.MethodPost, "/api/v1/availability/check-window", body, &result); err != nil {
+		return err
+	}
+
+	if result.Available {
+		return nil
+	}
+	switch result.Reason {
+	case reasonExpertNotFound:
+		return ErrExpertNotFound
+	case reasonExpertUnavailable:
+		return ErrExpertUnavailable
+	case reasonExpertUnverified:
+		return ErrExpertUnverified
+	case reasonExpertNotApproved:
+		return ErrExpertNotApproved
+	case reasonOutsideWorkingHours:
+		return ErrOutsideWorkingHours
+	case reasonOffTime:
+		return ErrExpertOffTime
+	default:
+		return ErrExpertUnavailable
+	}
+}
+
+// GetService fetches an active consultation type of the expert
+func (c *ExpertClient) GetService(ctx context.Context, expertID, serviceID uuid.UUID) (*ConsultationService, error) {
+	path := fmt.Sprintf("/api/experts/%s/services/%s", expertID, serviceID)
+
+	var svc ConsultationService
+	if err := c.call(ctx, http.MethodGet, path, nil, &svc); err != nil {
+		var statusErr *statusError
+		if errors.As(err, &statusErr) && statusErr.code == http.StatusNotFound {
+			return nil, ErrServiceNotFound
+		}
+		return nil, err
+	}
+
+	if !svc.IsActive {
+		return nil, ErrServiceInactive
+	}
+	return &svc, nil
+}
+
+// statusError is returned for non-2xx answers that are not worth retrying
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("expert service returned status %d", e.code)
+}
+
+// call sends the request through the circuit breaker, retrying network
+// errors and 5xx responses with exponential backoff
+func (c *ExpertClient) call(ctx context.Context, method, path string, body []byte, out interface{}) error {
+	if !c.breaker.Allow() {
+		return fmt.Errorf("%w: circuit open", ErrExpertServiceUnavailable)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			backoff := time.Duration(100<<uint(attempt-1)) * time.Millisecond
+			select {
+			case <-ctx.Done():
+				c.breaker.RecordFailure()
+				return fmt.Errorf("%w: %v", ErrExpertServiceUnavailable, ctx.Err())
+			case <-time.After(backoff):
+			}
+		}
+
+		retry, err := c.do(ctx, method, path, body, out)
+		if err == nil {
+			c.breaker.RecordSuccess()
+			return nil
+		}
+		lastErr = err
+		if !retry {
+			// The service answered; a bad request is not a health problem
+			c.breaker.RecordSuccess()
+			return err
+		}
+	}
+
+	c.breaker.RecordFailure()
+	return fmt.Errorf("%w: %v", ErrExpertServiceUnavailable, lastErr)
+}
+
+func (c *ExpertClient) do(ctx context.Context, method, path string, body []byte, out interface{}) (bool, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return false, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return true, fmt.Errorf("expert service returned status %d", resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, &statusError{code: resp.StatusCode}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("failed to decode expert service response: %v", err)
+	}
+	return false, nil
+}
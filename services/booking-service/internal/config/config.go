@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"time"
 )
 
 type Config struct {
@@ -16,6 +17,26 @@ type Config struct {
 	Redis struct {
 		URL string
 	}
+	ExpertService struct {
+		URL              string
+		Timeout          time.Duration
+		MaxRetries       int
+		BreakerThreshold int
+		BreakerCooldown  time.Duration
+		// FallbackPolicy decides what CreateBooking does when expert-service
+		// cannot be reached: "reject" (default) or "allow"
+		FallbackPolicy string
+	}
+	Auth struct {
+		// JWKSURL is where user-service publishes its token signing keys
+		JWKSURL string
+		Issuer  string
+	}
+	Verification struct {
+		// MaxPendingUnverified is how many pending bookings a user who has
+		// not verified their email may hold; 0 blocks them from booking
+		MaxPendingUnverified int
+	}
 }
 
 func Load() (*Config, error) {
@@ -31,6 +52,21 @@ func Load() (*Config, error) {
 	// Redis config
 	cfg.Redis.URL = getEnv("REDIS_URL", "localhost:6379")
 
+	// Expert service client config
+	cfg.ExpertService.URL = getEnv("EXPERT_SERVICE_URL", "http://localhost:8083")
+	cfg.ExpertService.Timeout = time.Duration(getEnvAsInt("EXPERT_SERVICE_TIMEOUT_MS", 2000)) * time.Millisecond
+	cfg.ExpertService.MaxRetries = getEnvAsInt("EXPERT_SERVICE_MAX_RETRIES", 2)
+	cfg.ExpertService.BreakerThreshold = getEnvAsInt("EXPERT_SERVICE_BREAKER_THRESHOLD", 5)
+	cfg.ExpertService.BreakerCooldown = time.Duration(getEnvAsInt("EXPERT_SERVICE_BREAKER_COOLDOWN_SEC", 30)) * time.Second
+	cfg.ExpertService.FallbackPolicy = getEnv("EXPERT_CHECK_FALLBACK", "reject")
+
+	// Auth config
+	cfg.Auth.JWKSURL = getEnv("JWKS_URL", "http://localhost:8080/.well-known/jwks.json")
+	cfg.Auth.Issuer = getEnv("JWT_ISSUER", "user-service")
+
+	// Verification policy config
+	cfg.Verification.MaxPendingUnverified = getEnvAsInt("UNVERIFIED_MAX_PENDING_BOOKINGS", 1)
+
 	return cfg, nil
 }
 
@@ -44,8 +80,6 @@ func getEnv(key, defaultValue string) string {
 
 // getEnvAsInt reads an environment variable as an integer
 // Returns the default value if the environment variable is not set or cannot be parsed as an integer
-//
-//nolint:unused
 func getEnvAsInt(key string, defaultValue int) int {
 	valueStr := getEnv(key, "")
 	if value, err := strconv.Atoi(valueStr); err == nil {
@@ -0,0 +1,68 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"services/booking-service/pkg/logger"
+)
+
+// BookingChannel is the Redis pub/sub channel other services subscribe to
+// in order to react to booking changes (e.g. expert-service slot caches).
+const BookingChannel = "booking.events"
+
+const (
+	TypeBookingCreated       = "booking.created"
+	TypeBookingUpdated       = "booking.updated"
+	TypeBookingCancelled     = "booking.cancelled"
+	TypeBookingStatusChanged = "booking.status_changed"
+	TypeSessionCreated       = "session.created"
+	TypeSessionCancelled     = "session.cancelled"
+)
+
+type Event struct {
+	Type       string    `json:"type"`
+	ExpertID   string    `json:"expert_id"`
+	BookingID  string    `json:"booking_id,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+type PublisherInterface interface {
+	PublishBookingEvent(eventType string, expertID, bookingID uuid.UUID)
+}
+
+type Publisher struct {
+	redisClient *redis.Client
+	logger      logger.LoggerInterface
+}
+
+func NewPublisher(redisClient *redis.Client, logger logger.LoggerInterface) PublisherInterface {
+	return &Publisher{
+		redisClient: redisClient,
+		logger:      logger,
+	}
+}
+
+// PublishBookingEvent is best effort: subscribers' caches expire on their own,
+// so a lost event only delays invalidation.
+func (p *Publisher) PublishBookingEvent(eventType string, expertID, bookingID uuid.UUID) {
+	data, err := json.Marshal(Event{
+		Type:       eventType,
+		ExpertID:   expertID.String(),
+		BookingID:  bookingID.String(),
+		OccurredAt: time.Now(),
+	})
+	if err != nil {
+		p.logger.Error("Failed to marshal booking event", err)
+		return
+	}
+
+	if err := p.redisClient.Publish(context.Background(), BookingChannel, data).Err(); err != nil {
+		p.logger.Error(fmt.Sprintf("Failed to publish %s event for booking %s", eventType, bookingID), err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"time"
 
+	"booking-system/shared/pkg/apperr"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
@@ -35,52 +36,24 @@ func NewBookingHandler(
 func (h *BookingHandler) CreateBooking(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized"))
+		utils.RespondError(c, http.StatusUnauthorized, "unauthorized")
 		return
 	}
 
 	var req model.CreateBookingRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request format"))
+		utils.RespondInvalid(c, err)
 		return
 	}
 
-	// Validate request
-	if err := req.Validate(); err != nil {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
-		return
-	}
-
-	// Calculate end time from scheduled time and duration
-	endTime := req.ScheduledTime.Add(time.Duration(req.DurationMinutes) * time.Minute)
-
-	// Check for conflicts
-	hasConflict, err := h.conflictChecker.CheckBookingConflict(
-		req.ExpertID,
-		userID.(uuid.UUID),
-		req.ScheduledTime,
-		endTime,
-	)
-	if err != nil {
-		h.logger.Error("Failed to check booking conflict", err)
-		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Internal server error"))
-		return
-	}
-
-	if hasConflict {
-		c.JSON(http.StatusConflict, utils.ErrorResponse("Time slot is already booked"))
-		return
-	}
-
-	// Create booking
+	// Create booking; the service validates it against the expert's catalog and calendar
 	booking, err := h.bookingService.CreateBooking(userID.(uuid.UUID), &req)
 	if err != nil {
-		h.logger.Error("Failed to create booking", err)
-		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to create booking"))
+		utils.RespondFailed(c, h.logger, err, "create_booking_failed")
 		return
 	}
 
-	c.JSON(http.StatusCreated, utils.SuccessResponse("Booking created successfully", booking))
+	c.JSON(http.StatusCreated, utils.SuccessResponse(c, "booking_created", booking))
 }
 
 // GetBooking retrieves a booking by ID
@@ -88,7 +61,7 @@ func (h *BookingHandler) GetBooking(c *gin.Context) {
 	bookingIDStr := c.Param("id")
 	bookingID, err := uuid.Parse(bookingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid booking ID"))
+		utils.RespondError(c, http.StatusBadRequest, "invalid_booking_id")
 		return
 	}
 
@@ -97,8 +70,7 @@ func (h *BookingHandler) GetBooking(c *gin.Context) {
 
 	booking, err := h.bookingService.GetBookingByID(bookingID)
 	if err != nil {
-		h.logger.Error("Failed to get booking", err)
-		c.JSON(http.StatusNotFound, utils.ErrorResponse("Booking not found"))
+		utils.RespondFailed(c, h.logger, err, "get_booking_failed")
 		return
 	}
 
@@ -106,11 +78,11 @@ func (h *BookingHandler) GetBooking(c *gin.Context) {
 	if userRole != "admin" &&
 		booking.UserID != userID.(uuid.UUID) &&
 		booking.ExpertID != userID.(uuid.UUID) {
-		c.JSON(http.StatusForbidden, utils.ErrorResponse("Access denied"))
+		utils.RespondError(c, http.StatusForbidden, "access_denied")
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.SuccessResponse("Booking retrieved successfully", booking))
+	c.JSON(http.StatusOK, utils.SuccessResponse(c, "booking_retrieved", booking))
 }
 
 // UpdateBooking updates a booking
@@ -118,7 +90,7 @@ func (h *BookingHandler) UpdateBooking(c *gin.Context) {
 	bookingIDStr := c.Param("id")
 	bookingID, err := uuid.Parse(bookingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid booking ID"))
+		utils.RespondError(c, http.StatusBadRequest, "invalid_booking_id")
 		return
 	}
 
@@ -127,38 +99,37 @@ func (h *BookingHandler) UpdateBooking(c *gin.Context) {
 
 	var req model.UpdateBookingRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request format"))
+		utils.RespondInvalid(c, err)
 		return
 	}
 
 	// Get existing booking to check authorization
 	existingBooking, err := h.bookingService.GetBookingByID(bookingID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, utils.ErrorResponse("Booking not found"))
+		utils.RespondFailed(c, h.logger, err, "get_booking_failed")
 		return
 	}
 
 	// Check authorization
 	if userRole != "admin" && existingBooking.UserID != userID.(uuid.UUID) {
-		c.JSON(http.StatusForbidden, utils.ErrorResponse("Access denied"))
+		utils.RespondError(c, http.StatusForbidden, "access_denied")
 		return
 	}
 
 	// Check if booking can be updated (not within 1 hour)
 	if time.Now().Add(time.Hour).After(existingBooking.ScheduledTime) {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Cannot update booking within 1 hour of start time"))
+		utils.RespondErr(c, apperr.InvalidTransition("booking_update_too_late"))
 		return
 	}
 
 	// Update booking
 	booking, err := h.bookingService.UpdateBooking(bookingID, &req)
 	if err != nil {
-		h.logger.Error("Failed to update booking", err)
-		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update booking"))
+		utils.RespondFailed(c, h.logger, err, "update_booking_failed")
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.SuccessResponse("Booking updated successfully", booking))
+	c.JSON(http.StatusOK, utils.SuccessResponse(c, "booking_updated", booking))
 }
 
 // CancelBooking cancels a booking
@@ -166,7 +137,7 @@ func (h *BookingHandler) CancelBooking(c *gin.Context) {
 	bookingIDStr := c.Param("id")
 	bookingID, err := uuid.Parse(bookingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid booking ID"))
+		utils.RespondError(c, http.StatusBadRequest, "invalid_booking_id")
 		return
 	}
 
@@ -176,7 +147,7 @@ func (h *BookingHandler) CancelBooking(c *gin.Context) {
 	// Get existing booking to check authorization
 	existingBooking, err := h.bookingService.GetBookingByID(bookingID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, utils.ErrorResponse("Booking not found"))
+		utils.RespondFailed(c, h.logger, err, "get_booking_failed")
 		return
 	}
 
@@ -184,31 +155,30 @@ func (h *BookingHandler) CancelBooking(c *gin.Context) {
 	if userRole != "admin" &&
 		existingBooking.UserID != userID.(uuid.UUID) &&
 		existingBooking.ExpertID != userID.(uuid.UUID) {
-		c.JSON(http.StatusForbidden, utils.ErrorResponse("Access denied"))
+		utils.RespondError(c, http.StatusForbidden, "access_denied")
 		return
 	}
 
 	// Check if booking can be cancelled (not within 1 hour)
 	if time.Now().Add(time.Hour).After(existingBooking.ScheduledTime) {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Cannot cancel booking within 1 hour of start time"))
+		utils.RespondErr(c, apperr.InvalidTransition("booking_cancel_too_late"))
 		return
 	}
 
 	var req model.CancelBookingRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request format"))
+		utils.RespondInvalid(c, err)
 		return
 	}
 
 	// Cancel booking
 	err = h.bookingService.CancelBooking(bookingID, userID.(uuid.UUID), &req)
 	if err != nil {
-		h.logger.Error("Failed to cancel booking", err)
-		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to cancel booking"))
+		utils.RespondFailed(c, h.logger, err, "cancel_booking_failed")
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.SuccessResponse("Booking cancelled successfully", nil))
+	c.JSON(http.StatusOK, utils.SuccessResponse(c, "booking_cancelled", nil))
 }
 
 // GetUserBookings retrieves bookings for a user
@@ -217,7 +187,7 @@ func (h *BookingHandler) GetUserBookings(c *gin.Context) {
 
 	var req model.GetBookingsRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid query parameters"))
+		utils.RespondInvalid(c, err)
 		return
 	}
 
@@ -231,8 +201,7 @@ func (h *BookingHandler) GetUserBookings(c *gin.Context) {
 
 	bookings, total, err := h.bookingService.GetUserBookings(userID.(uuid.UUID), &req)
 	if err != nil {
-		h.logger.Error("Failed to get user bookings", err)
-		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve bookings"))
+		utils.RespondFailed(c, h.logger, err, "get_bookings_failed")
 		return
 	}
 
@@ -244,7 +213,7 @@ func (h *BookingHandler) GetUserBookings(c *gin.Context) {
 		TotalPages: int((total + int64(req.Limit) - 1) / int64(req.Limit)),
 	}
 
-	c.JSON(http.StatusOK, utils.SuccessResponse("Bookings retrieved successfully", response))
+	c.JSON(http.StatusOK, utils.SuccessResponse(c, "bookings_retrieved", response))
 }
 
 // GetExpertBookings retrieves bookings for an expert
@@ -253,7 +222,7 @@ func (h *BookingHandler) GetExpertBookings(c *gin.Context) {
 
 	var req model.GetBookingsRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid query parameters"))
+		utils.RespondInvalid(c, err)
 		return
 	}
 
@@ -267,8 +236,7 @@ func (h *BookingHandler) GetExpertBookings(c *gin.Context) {
 
 	bookings, total, err := h.bookingService.GetExpertBookings(expertID.(uuid.UUID), &req)
 	if err != nil {
-		h.logger.Error("Failed to get expert bookings", err)
-		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve bookings"))
+		utils.RespondFailed(c, h.logger, err, "get_bookings_failed")
 		return
 	}
 
@@ -280,5 +248,5 @@ func (h *BookingHandler) GetExpertBookings(c *gin.Context) {
 		TotalPages: int((total + int64(req.Limit) - 1) / int64(req.Limit)),
 	}
 
-	c.JSON(http.StatusOK, utils.SuccessResponse("Bookings retrieved successfully", response))
+	c.JSON(http.StatusOK, utils.SuccessResponse(c, "bookings_retrieved", response))
 }
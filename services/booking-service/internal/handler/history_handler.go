@@ -31,13 +31,13 @@ func (h *HistoryHandler) GetBookingHistory(c *gin.Context) {
 	userIDVal, exists := c.Get("user_id")
 	if !exists {
 		h.logger.Error("user_id not found in context")
-		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized"))
+		utils.RespondError(c, http.StatusUnauthorized, "unauthorized")
 		return
 	}
 	userID, ok := userIDVal.(uuid.UUID)
 	if !ok {
 		h.logger.Error("user_id is not of type uuid.UUID")
-		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Internal server error"))
+		utils.RespondError(c, http.StatusInternalServerError, "internal_error")
 		return
 	}
 
@@ -88,8 +88,7 @@ func (h *HistoryHandler) GetBookingHistory(c *gin.Context) {
 
 	bookings, total, err := h.bookingService.GetBookingHistory(userID, req)
 	if err != nil {
-		h.logger.Error("Failed to get booking history", err)
-		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve booking history"))
+		utils.RespondFailed(c, h.logger, err, "get_booking_history_failed")
 		return
 	}
 
@@ -108,7 +107,7 @@ func (h *HistoryHandler) GetBookingHistory(c *gin.Context) {
 		},
 	}
 
-	c.JSON(http.StatusOK, utils.SuccessResponse("Booking history retrieved successfully", response))
+	c.JSON(http.StatusOK, utils.SuccessResponse(c, "booking_history_retrieved", response))
 }
 
 // GetExpertHistory retrieves booking history for an expert
@@ -116,13 +115,13 @@ func (h *HistoryHandler) GetExpertHistory(c *gin.Context) {
 	expertIDVal, exists := c.Get("user_id")
 	if !exists {
 		h.logger.Error("user_id not found in context")
-		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized"))
+		utils.RespondError(c, http.StatusUnauthorized, "unauthorized")
 		return
 	}
 	expertID, ok := expertIDVal.(uuid.UUID)
 	if !ok {
 		h.logger.Error("user_id is not of type uuid.UUID")
-		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Internal server error"))
+		utils.RespondError(c, http.StatusInternalServerError, "internal_error")
 		return
 	}
 
@@ -172,8 +171,7 @@ func (h *HistoryHandler) GetExpertHistory(c *gin.Context) {
 
 	bookings, total, err := h.bookingService.GetExpertHistory(expertID, req)
 	if err != nil {
-		h.logger.Error("Failed to get expert history", err)
-		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve expert history"))
+		utils.RespondFailed(c, h.logger, err, "get_expert_history_failed")
 		return
 	}
 
@@ -192,7 +190,7 @@ func (h *HistoryHandler) GetExpertHistory(c *gin.Context) {
 		},
 	}
 
-	c.JSON(http.StatusOK, utils.SuccessResponse("Expert history retrieved successfully", response))
+	c.JSON(http.StatusOK, utils.SuccessResponse(c, "expert_history_retrieved", response))
 }
 
 // GetUpcomingBookings retrieves upcoming bookings for a user or expert
@@ -200,26 +198,26 @@ func (h *HistoryHandler) GetUpcomingBookings(c *gin.Context) {
 	userIDVal, exists := c.Get("user_id")
 	if !exists {
 		h.logger.Error("user_id not found in context")
-		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized"))
+		utils.RespondError(c, http.StatusUnauthorized, "unauthorized")
 		return
 	}
 	userID, ok := userIDVal.(uuid.UUID)
 	if !ok {
 		h.logger.Error("user_id is not of type uuid.UUID")
-		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Internal server error"))
+		utils.RespondError(c, http.StatusInternalServerError, "internal_error")
 		return
 	}
 
 	userRoleVal, exists := c.Get("user_role")
 	if !exists {
 		h.logger.Error("user_role not found in context")
-		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized"))
+		utils.RespondError(c, http.StatusUnauthorized, "unauthorized")
 		return
 	}
 	userRole, ok := userRoleVal.(string)
 	if !ok {
 		h.logger.Error("user_role is not of type string")
-		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Internal server error"))
+		utils.RespondError(c, http.StatusInternalServerError, "internal_error")
 		return
 	}
 
@@ -244,12 +242,11 @@ func (h *HistoryHandler) GetUpcomingBookings(c *gin.Context) {
 	}
 
 	if err != nil {
-		h.logger.Error("Failed to get upcoming bookings", err)
-		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve upcoming bookings"))
+		utils.RespondFailed(c, h.logger, err, "get_upcoming_bookings_failed")
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.SuccessResponse("Upcoming bookings retrieved successfully", bookings))
+	c.JSON(http.StatusOK, utils.SuccessResponse(c, "upcoming_bookings_retrieved", bookings))
 }
 
 // GetPastBookings retrieves past bookings for a user or expert
@@ -257,26 +254,26 @@ func (h *HistoryHandler) GetPastBookings(c *gin.Context) {
 	userIDVal, exists := c.Get("user_id")
 	if !exists {
 		h.logger.Error("user_id not found in context")
-		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized"))
+		utils.RespondError(c, http.StatusUnauthorized, "unauthorized")
 		return
 	}
 	userID, ok := userIDVal.(uuid.UUID)
 	if !ok {
 		h.logger.Error("user_id is not of type uuid.UUID")
-		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Internal server error"))
+		utils.RespondError(c, http.StatusInternalServerError, "internal_error")
 		return
 	}
 
 	userRoleVal, exists := c.Get("user_role")
 	if !exists {
 		h.logger.Error("user_role not found in context")
-		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized"))
+		utils.RespondError(c, http.StatusUnauthorized, "unauthorized")
 		return
 	}
 	userRole, ok := userRoleVal.(string)
 	if !ok {
 		h.logger.Error("user_role is not of type string")
-		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Internal server error"))
+		utils.RespondError(c, http.StatusInternalServerError, "internal_error")
 		return
 	}
 
@@ -302,8 +299,7 @@ func (h *HistoryHandler) GetPastBookings(c *gin.Context) {
 	}
 
 	if err != nil {
-		h.logger.Error("Failed to get past bookings", err)
-		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve past bookings"))
+		utils.RespondFailed(c, h.logger, err, "get_past_bookings_failed")
 		return
 	}
 
@@ -317,5 +313,5 @@ func (h *HistoryHandler) GetPastBookings(c *gin.Context) {
 		},
 	}
 
-	c.JSON(http.StatusOK, utils.SuccessResponse("Past bookings retrieved successfully", response))
+	c.JSON(http.StatusOK, utils.SuccessResponse(c, "past_bookings_retrieved", response))
 }
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"services/booking-service/internal/service"
+	"services/booking-service/pkg/logger"
+	"services/booking-service/pkg/utils"
+)
+
+type PrivacyHandler struct {
+	privacyService service.PrivacyServiceInterface
+	logger         logger.LoggerInterface
+}
+
+func NewPrivacyHandler(privacyService service.PrivacyServiceInterface, logger logger.LoggerInterface) *PrivacyHandler {
+	return &PrivacyHandler{
+		privacyService: privacyService,
+		logger:         logger,
+	}
+}
+
+// ExportUserData returns the caller's bookings, status history and session
+// reservations. Admins may name another user with ?user_id=.
+func (h *PrivacyHandler) ExportUserData(c *gin.Context) {
+	userID, ok := h.targetUser(c)
+	if !ok {
+		return
+	}
+
+	export, err := h.privacyService.ExportUserData(userID)
+	if err != nil {
+		utils.RespondFailed(c, h.logger, err, "export_data_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(c, "data_exported", export))
+}
+
+// AnonymizeUserData strips the caller's notes from their booking records.
+// Admins may name another user with ?user_id=.
+func (h *PrivacyHandler) AnonymizeUserData(c *gin.Context) {
+	userID, ok := h.targetUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.privacyService.AnonymizeUserData(userID); err != nil {
+		utils.RespondFailed(c, h.logger, err, "anonymize_data_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(c, "data_anonymized", nil))
+}
+
+// targetUser resolves whose data a privacy request is about: the caller, or
+// the user named in ?user_id= when the caller is an admin
+func (h *PrivacyHandler) targetUser(c *gin.Context) (uuid.UUID, bool) {
+	userIDVal, exists := c.Get("user_id")
+	if !exists {
+		h.logger.Error("user_id not found in context")
+		utils.RespondError(c, http.StatusUnauthorized, "unauthorized")
+		return uuid.Nil, false
+	}
+	callerID, ok := userIDVal.(uuid.UUID)
+	if !ok {
+		h.logger.Error("user_id is not of type uuid.UUID")
+		utils.RespondError(c, http.StatusInternalServerError, "internal_error")
+		return uuid.Nil, false
+	}
+
+	requested := c.Query("user_id")
+	if requested == "" {
+		return callerID, true
+	}
+	userID, err := uuid.Parse(requested)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid_user_id")
+		return uuid.Nil, false
+	}
+	if userID != callerID && c.GetString("user_role") != "admin" {
+		utils.RespondError(c, http.StatusForbidden, "access_denied")
+		return uuid.Nil, false
+	}
+	return userID, true
+}
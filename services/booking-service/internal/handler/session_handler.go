@@ -0,0 +1,245 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"services/booking-service/internal/model"
+	"services/booking-service/internal/service"
+	"services/booking-service/pkg/logger"
+	"services/booking-service/pkg/utils"
+)
+
+type SessionHandler struct {
+	sessionService service.SessionServiceInterface
+	logger         logger.LoggerInterface
+}
+
+func NewSessionHandler(sessionService service.SessionServiceInterface, logger logger.LoggerInterface) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionService,
+		logger:         logger,
+	}
+}
+
+// CreateSession creates a new group session; only the expert themselves or
+// an admin may create one
+func (h *SessionHandler) CreateSession(c *gin.Context) {
+	var req model.CreateSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondInvalid(c, err)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	session, err := h.sessionService.CreateSession(&req, userID.(uuid.UUID), c.GetString("user_role"))
+	if err != nil {
+		utils.RespondFailed(c, h.logger, err, "create_session_failed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse(c, "session_created", session))
+}
+
+// GetSession retrieves a group session with its seat counts
+func (h *SessionHandler) GetSession(c *gin.Context) {
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid_session_id")
+		return
+	}
+
+	session, err := h.sessionService.GetSession(sessionID)
+	if err != nil {
+		utils.RespondFailed(c, h.logger, err, "get_session_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(c, "session_retrieved", session))
+}
+
+// GetExpertSessions retrieves the sessions of an expert
+func (h *SessionHandler) GetExpertSessions(c *gin.Context) {
+	expertID, err := uuid.Parse(c.Param("expert_id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid_expert_id")
+		return
+	}
+	includePast := c.Query("include_past") == "true"
+
+	sessions, err := h.sessionService.GetExpertSessions(expertID, includePast)
+	if err != nil {
+		utils.RespondFailed(c, h.logger, err, "get_sessions_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(c, "sessions_retrieved", sessions))
+}
+
+// GetUpcomingSessions retrieves sessions open for registration
+func (h *SessionHandler) GetUpcomingSessions(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	sessions, err := h.sessionService.GetUpcomingSessions(limit)
+	if err != nil {
+		utils.RespondFailed(c, h.logger, err, "get_sessions_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(c, "sessions_retrieved", sessions))
+}
+
+// GetUserSessions retrieves the sessions the current user registered for
+func (h *SessionHandler) GetUserSessions(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	sessions, err := h.sessionService.GetUserSessions(userID.(uuid.UUID))
+	if err != nil {
+		utils.RespondFailed(c, h.logger, err, "get_sessions_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(c, "sessions_retrieved", sessions))
+}
+
+// GetSessionAttendees retrieves the reservations of a session
+func (h *SessionHandler) GetSessionAttendees(c *gin.Context) {
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid_session_id")
+		return
+	}
+
+	if !h.authorizeExpert(c, sessionID) {
+		return
+	}
+
+	attendees, err := h.sessionService.GetAttendees(sessionID)
+	if err != nil {
+		utils.RespondFailed(c, h.logger, err, "get_attendees_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(c, "attendees_retrieved", attendees))
+}
+
+// RegisterSession reserves seats in a session for the current user
+func (h *SessionHandler) RegisterSession(c *gin.Context) {
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid_session_id")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	// The body is optional, one seat is reserved by default
+	var req model.RegisterSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		utils.RespondError(c, http.StatusBadRequest, "invalid_request_format")
+		return
+	}
+
+	attendee, err := h.sessionService.RegisterForSession(sessionID, userID.(uuid.UUID), req.Seats)
+	if err != nil {
+		utils.RespondFailed(c, h.logger, err, "register_session_failed")
+		return
+	}
+
+	if attendee.Status == model.AttendeeStatusWaitlisted {
+		c.JSON(http.StatusAccepted, utils.SuccessResponse(c, "session_waitlisted", attendee))
+		return
+	}
+	c.JSON(http.StatusCreated, utils.SuccessResponse(c, "session_registered", attendee))
+}
+
+// CancelSessionRegistration cancels the current user's reservation
+func (h *SessionHandler) CancelSessionRegistration(c *gin.Context) {
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid_session_id")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	if err := h.sessionService.CancelRegistration(sessionID, userID.(uuid.UUID)); err != nil {
+		utils.RespondFailed(c, h.logger, err, "cancel_registration_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(c, "registration_cancelled", nil))
+}
+
+// CancelSession cancels a session and every reservation in it
+func (h *SessionHandler) CancelSession(c *gin.Context) {
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid_session_id")
+		return
+	}
+
+	if !h.authorizeExpert(c, sessionID) {
+		return
+	}
+
+	if err := h.sessionService.CancelSession(sessionID); err != nil {
+		utils.RespondFailed(c, h.logger, err, "cancel_session_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(c, "session_cancelled", nil))
+}
+
+// MarkSessionAttendance records that an attendee attended the session
+func (h *SessionHandler) MarkSessionAttendance(c *gin.Context) {
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid_session_id")
+		return
+	}
+
+	var req model.MarkAttendanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondInvalid(c, err)
+		return
+	}
+
+	if !h.authorizeExpert(c, sessionID) {
+		return
+	}
+
+	if err := h.sessionService.MarkAttendance(sessionID, req.UserID); err != nil {
+		utils.RespondFailed(c, h.logger, err, "mark_attendance_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(c, "attendance_recorded", nil))
+}
+
+// authorizeExpert lets only the account owning the session's expert
+// profile, or an admin, continue
+func (h *SessionHandler) authorizeExpert(c *gin.Context, sessionID uuid.UUID) bool {
+	userID, _ := c.Get("user_id")
+
+	session, err := h.sessionService.GetSession(sessionID)
+	if err != nil {
+		utils.RespondFailed(c, h.logger, err, "get_session_failed")
+		return false
+	}
+
+	if err := h.sessionService.AuthorizeExpert(session.ExpertID, userID.(uuid.UUID), c.GetString("user_role")); err != nil {
+		utils.RespondFailed(c, h.logger, err, "get_session_failed")
+		return false
+	}
+	return true
+}
@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
+	"booking-system/shared/pkg/apperr"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
@@ -50,7 +52,7 @@ func (h *StatusHandler) UpdateBookingStatus(c *gin.Context) {
 	bookingIDStr := c.Param("id")
 	bookingID, err := uuid.Parse(bookingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid booking ID"))
+		utils.RespondError(c, http.StatusBadRequest, "invalid_booking_id")
 		return
 	}
 
@@ -59,13 +61,13 @@ func (h *StatusHandler) UpdateBookingStatus(c *gin.Context) {
 
 	var req UpdateStatusRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request format"))
+		utils.RespondInvalid(c, err)
 		return
 	}
 
 	// Validate status
 	if !isValidBookingStatus(req.Status) {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid booking status"))
+		utils.RespondError(c, http.StatusBadRequest, "invalid_booking_status")
 		return
 	}
 
@@ -78,21 +80,11 @@ func (h *StatusHandler) UpdateBookingStatus(c *gin.Context) {
 		req.Note,
 	)
 	if err != nil {
-		switch err.Error() {
-		case "booking not found":
-			c.JSON(http.StatusNotFound, utils.ErrorResponse("Booking not found"))
-		case "access denied":
-			c.JSON(http.StatusForbidden, utils.ErrorResponse("Access denied"))
-		case "invalid status transition":
-			c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid status transition"))
-		default:
-			h.logger.Error("Failed to update booking status", err)
-			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update status"))
-		}
+		utils.RespondFailed(c, h.logger, err, "update_status_failed")
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.SuccessResponse("Booking status updated successfully", nil))
+	c.JSON(http.StatusOK, utils.SuccessResponse(c, "booking_status_updated", nil))
 }
 
 // GetBookingStatus retrieves the current status of a booking
@@ -100,22 +92,17 @@ func (h *StatusHandler) GetBookingStatus(c *gin.Context) {
 	bookingIDStr := c.Param("id")
 	bookingID, err := uuid.Parse(bookingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid booking ID"))
+		utils.RespondError(c, http.StatusBadRequest, "invalid_booking_id")
 		return
 	}
 
 	status, err := h.statusService.GetBookingStatus(bookingID)
 	if err != nil {
-		if err.Error() == "booking not found" {
-			c.JSON(http.StatusNotFound, utils.ErrorResponse("Booking not found"))
-		} else {
-			h.logger.Error("Failed to get booking status", err)
-			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve status"))
-		}
+		utils.RespondFailed(c, h.logger, err, "get_status_failed")
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.SuccessResponse("Booking status retrieved successfully", map[string]interface{}{
+	c.JSON(http.StatusOK, utils.SuccessResponse(c, "booking_status_retrieved", map[string]interface{}{
 		"status": status,
 	}))
 }
@@ -125,7 +112,7 @@ func (h *StatusHandler) GetStatusHistory(c *gin.Context) {
 	bookingIDStr := c.Param("id")
 	bookingID, err := uuid.Parse(bookingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid booking ID"))
+		utils.RespondError(c, http.StatusBadRequest, "invalid_booking_id")
 		return
 	}
 
@@ -134,19 +121,11 @@ func (h *StatusHandler) GetStatusHistory(c *gin.Context) {
 
 	history, err := h.statusService.GetStatusHistory(bookingID, userID.(uuid.UUID), userRole.(string))
 	if err != nil {
-		switch err.Error() {
-		case "booking not found":
-			c.JSON(http.StatusNotFound, utils.ErrorResponse("Booking not found"))
-		case "access denied":
-			c.JSON(http.StatusForbidden, utils.ErrorResponse("Access denied"))
-		default:
-			h.logger.Error("Failed to get status history", err)
-			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve status history"))
-		}
+		utils.RespondFailed(c, h.logger, err, "get_status_history_failed")
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.SuccessResponse("Status history retrieved successfully", history))
+	c.JSON(http.StatusOK, utils.SuccessResponse(c, "status_history_retrieved", history))
 }
 
 // ConfirmBooking confirms a booking (expert only)
@@ -154,7 +133,7 @@ func (h *StatusHandler) ConfirmBooking(c *gin.Context) {
 	bookingIDStr := c.Param("id")
 	bookingID, err := uuid.Parse(bookingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid booking ID"))
+		utils.RespondError(c, http.StatusBadRequest, "invalid_booking_id")
 		return
 	}
 
@@ -175,21 +154,14 @@ func (h *StatusHandler) ConfirmBooking(c *gin.Context) {
 		req.Note,
 	)
 	if err != nil {
-		switch err.Error() {
-		case "booking not found":
-			c.JSON(http.StatusNotFound, utils.ErrorResponse("Booking not found"))
-		case "access denied":
-			c.JSON(http.StatusForbidden, utils.ErrorResponse("Access denied"))
-		case "invalid status transition":
-			c.JSON(http.StatusBadRequest, utils.ErrorResponse("Cannot confirm this booking"))
-		default:
-			h.logger.Error("Failed to confirm booking", err)
-			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to confirm booking"))
+		if errors.Is(err, model.ErrInvalidStatusTransition) {
+			err = apperr.InvalidTransition("booking_cannot_confirm")
 		}
+		utils.RespondFailed(c, h.logger, err, "confirm_booking_failed")
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.SuccessResponse("Booking confirmed successfully", nil))
+	c.JSON(http.StatusOK, utils.SuccessResponse(c, "booking_confirmed", nil))
 }
 
 // RejectBooking rejects a booking (expert only)
@@ -197,7 +169,7 @@ func (h *StatusHandler) RejectBooking(c *gin.Context) {
 	bookingIDStr := c.Param("id")
 	bookingID, err := uuid.Parse(bookingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid booking ID"))
+		utils.RespondError(c, http.StatusBadRequest, "invalid_booking_id")
 		return
 	}
 
@@ -206,12 +178,12 @@ func (h *StatusHandler) RejectBooking(c *gin.Context) {
 
 	var req RejectBookingRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Rejection reason is required"))
+		utils.RespondError(c, http.StatusBadRequest, "rejection_reason_required")
 		return
 	}
 
 	if req.Reason == "" {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Rejection reason is required"))
+		utils.RespondError(c, http.StatusBadRequest, "rejection_reason_required")
 		return
 	}
 
@@ -223,21 +195,14 @@ func (h *StatusHandler) RejectBooking(c *gin.Context) {
 		req.Reason,
 	)
 	if err != nil {
-		switch err.Error() {
-		case "booking not found":
-			c.JSON(http.StatusNotFound, utils.ErrorResponse("Booking not found"))
-		case "access denied":
-			c.JSON(http.StatusForbidden, utils.ErrorResponse("Access denied"))
-		case "invalid status transition":
-			c.JSON(http.StatusBadRequest, utils.ErrorResponse("Cannot reject this booking"))
-		default:
-			h.logger.Error("Failed to reject booking", err)
-			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to reject booking"))
+		if errors.Is(err, model.ErrInvalidStatusTransition) {
+			err = apperr.InvalidTransition("booking_cannot_reject")
 		}
+		utils.RespondFailed(c, h.logger, err, "reject_booking_failed")
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.SuccessResponse("Booking rejected successfully", nil))
+	c.JSON(http.StatusOK, utils.SuccessResponse(c, "booking_rejected", nil))
 }
 
 // CompleteBooking marks a booking as completed (expert only)
@@ -245,7 +210,7 @@ func (h *StatusHandler) CompleteBooking(c *gin.Context) {
 	bookingIDStr := c.Param("id")
 	bookingID, err := uuid.Parse(bookingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid booking ID"))
+		utils.RespondError(c, http.StatusBadRequest, "invalid_booking_id")
 		return
 	}
 
@@ -266,21 +231,14 @@ func (h *StatusHandler) CompleteBooking(c *gin.Context) {
 		req.Summary,
 	)
 	if err != nil {
-		switch err.Error() {
-		case "booking not found":
-			c.JSON(http.StatusNotFound, utils.ErrorResponse("Booking not found"))
-		case "access denied":
-			c.JSON(http.StatusForbidden, utils.ErrorResponse("Access denied"))
-		case "invalid status transition":
-			c.JSON(http.StatusBadRequest, utils.ErrorResponse("Cannot complete this booking"))
-		default:
-			h.logger.Error("Failed to complete booking", err)
-			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to complete booking"))
+		if errors.Is(err, model.ErrInvalidStatusTransition) {
+			err = apperr.InvalidTransition("booking_cannot_complete")
 		}
+		utils.RespondFailed(c, h.logger, err, "complete_booking_failed")
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.SuccessResponse("Booking completed successfully", nil))
+	c.JSON(http.StatusOK, utils.SuccessResponse(c, "booking_completed", nil))
 }
 
 // isValidBookingStatus checks if the given status is valid
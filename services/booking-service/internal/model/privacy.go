@@ -0,0 +1,19 @@
+package model
+
+import (
+	"booking-system/shared/pkg/apperr"
+	"github.com/google/uuid"
+)
+
+// UserDataExport holds everything booking-service stores about a user, for
+// the user's data export
+type UserDataExport struct {
+	UserID               uuid.UUID         `json:"user_id"`
+	Bookings             []Booking         `json:"bookings"`
+	StatusHistory        []StatusHistory   `json:"status_history"`
+	SessionRegistrations []SessionAttendee `json:"session_registrations"`
+}
+
+// ErrUpcomingBookings is returned when a user asks to be erased while they
+// still have bookings or session seats ahead of them
+var ErrUpcomingBookings = apperr.Conflict("upcoming_bookings")
@@ -2,51 +2,64 @@
 package model
 
 import (
-	"fmt"
 	"time"
 
+	"booking-system/shared/pkg/i18n"
 	"github.com/google/uuid"
 )
 
-// CreateBookingRequest struct for creating a new booking
+// CreateBookingRequest struct for creating a new booking. Duration, price and
+// meeting type come from the referenced expert service, not from the client.
 type CreateBookingRequest struct {
-	ExpertID        uuid.UUID `json:"expert_id" binding:"required"`
-	ScheduledTime   time.Time `json:"start_time" binding:"required"`
-	EndTime         time.Time `json:"end_time" binding:"required"`
-	DurationMinutes int       `json:"duration_minutes"`
-	MeetingType     string    `json:"meeting_type"`
-	MeetingURL      *string   `json:"meeting_url,omitempty"`
-	MeetingAddress  *string   `json:"meeting_address,omitempty"`
-	Notes           string    `json:"notes"`
-	Status          string    `json:"status"`
-}
-
-// Validate validates the create booking request
-func (req *CreateBookingRequest) Validate() error {
-	if req.DurationMinutes < 15 || req.DurationMinutes > 480 {
-		return fmt.Errorf("duration must be between 15 and 480 minutes")
+	ExpertID       uuid.UUID `json:"expert_id" binding:"required"`
+	ServiceID      uuid.UUID `json:"service_id" binding:"required"`
+	ScheduledTime  time.Time `json:"start_time" binding:"required"`
+	MeetingURL     *string   `json:"meeting_url,omitempty"`
+	MeetingAddress *string   `json:"meeting_address,omitempty"`
+	Notes          string    `json:"notes"`
+}
+
+// Validate validates the meeting details against the meeting type of the booked service
+func (req *CreateBookingRequest) Validate(meetingType BookingType) error {
+	if meetingType != TypeOnline && meetingType != TypeOffline {
+		return i18n.NewError("invalid_booking_type")
 	}
-	if req.MeetingType != string(TypeOnline) && req.MeetingType != string(TypeOffline) {
-		return fmt.Errorf("invalid booking type")
+	if meetingType == TypeOffline && req.MeetingAddress == nil {
+		return i18n.NewError("meeting_address_required")
 	}
-	if req.MeetingType == string(TypeOffline) && req.MeetingAddress == nil {
-		return fmt.Errorf("meeting address is required for offline booking")
-	}
-	if req.MeetingType == string(TypeOnline) && req.MeetingURL == nil {
-		return fmt.Errorf("meeting URL is required for online booking")
+	if meetingType == TypeOnline && req.MeetingURL == nil {
+		return i18n.NewError("meeting_url_required")
 	}
 	return nil
 }
 
-// UpdateBookingRequest struct for updating a booking
+// UpdateBookingRequest struct for updating a booking. Changing the service
+// takes duration, price and meeting type from the new service.
 type UpdateBookingRequest struct {
-	ScheduledTime   *time.Time   `json:"scheduled_datetime,omitempty" validate:"omitempty"`
-	DurationMinutes *int         `json:"duration_minutes,omitempty" validate:"omitempty,min=15,max=480"`
-	MeetingType     *BookingType `json:"meeting_type,omitempty" validate:"omitempty,oneof=online offline"`
-	Notes           *string      `json:"notes,omitempty" validate:"omitempty,max=1000"`
-	MeetingAddress  *string      `json:"meeting_address,omitempty" validate:"omitempty,max=255"`
-	MeetingURL      *string      `json:"meeting_url,omitempty" validate:"omitempty,max=255,url"`
-	Price           *float64     `json:"price,omitempty" validate:"omitempty,min=0"`
+	ServiceID      *uuid.UUID `json:"service_id,omitempty" validate:"omitempty"`
+	ScheduledTime  *time.Time `json:"scheduled_datetime,omitempty" validate:"omitempty"`
+	Notes          *string    `json:"notes,omitempty" validate:"omitempty,max=1000"`
+	MeetingAddress *string    `json:"meeting_address,omitempty" validate:"omitempty,max=255"`
+	MeetingURL     *string    `json:"meeting_url,omitempty" validate:"omitempty,max=255,url"`
+}
+
+// Validate checks that the booking, with the update applied, has the meeting
+// details its meeting type needs
+func (req *UpdateBookingRequest) Validate(meetingType BookingType, booking *Booking) error {
+	address, url := booking.MeetingAddress, booking.MeetingURL
+	if req.MeetingAddress != nil {
+		address = *req.MeetingAddress
+	}
+	if req.MeetingURL != nil {
+		url = *req.MeetingURL
+	}
+	if meetingType == TypeOffline && address == "" {
+		return i18n.NewError("meeting_address_required")
+	}
+	if meetingType == TypeOnline && url == "" {
+		return i18n.NewError("meeting_url_required")
+	}
+	return nil
 }
 
 // UpdateBookingStatusRequest struct for changing booking status
@@ -0,0 +1,144 @@
+// Session model định nghĩa buổi tư vấn nhóm (workshop) có nhiều người tham gia
+package model
+
+import (
+	"time"
+
+	"booking-system/shared/pkg/apperr"
+	"github.com/google/uuid"
+)
+
+// SessionStatus represents the status of a group session
+type SessionStatus string
+
+const (
+	SessionStatusScheduled SessionStatus = "scheduled"
+	SessionStatusCancelled SessionStatus = "cancelled"
+	SessionStatusCompleted SessionStatus = "completed"
+)
+
+// AttendeeStatus represents the status of a seat reservation
+type AttendeeStatus string
+
+const (
+	AttendeeStatusRegistered AttendeeStatus = "registered"
+	AttendeeStatusWaitlisted AttendeeStatus = "waitlisted"
+	AttendeeStatusCancelled  AttendeeStatus = "cancelled"
+	AttendeeStatusAttended   AttendeeStatus = "attended"
+)
+
+// Session represents a group session run by one expert for several attendees
+type Session struct {
+	ID              uuid.UUID     `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ExpertID        uuid.UUID     `json:"expert_id" gorm:"type:uuid"`
+	Title           string        `json:"title"`
+	Description     string        `json:"description,omitempty"`
+	ScheduledTime   time.Time     `json:"scheduled_datetime" gorm:"column:scheduled_datetime"`
+	DurationMinutes int           `json:"duration_minutes" gorm:"default:60"`
+	MeetingType     BookingType   `json:"meeting_type" gorm:"default:'online'"`
+	MeetingURL      string        `json:"meeting_url,omitempty"`
+	MeetingAddress  string        `json:"meeting_address,omitempty"`
+	Capacity        int           `json:"capacity"`
+	PricePerSeat    float64       `json:"price_per_seat"`
+	Status          SessionStatus `json:"status" gorm:"default:'scheduled'"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+	CancelledAt     *time.Time    `json:"cancelled_at,omitempty"`
+}
+
+// TableName returns the table name in the database
+func (Session) TableName() string {
+	return "group_sessions"
+}
+
+// GetEndTime returns the end time of the session
+func (s *Session) GetEndTime() time.Time {
+	return s.ScheduledTime.Add(time.Duration(s.DurationMinutes) * time.Minute)
+}
+
+// IsOpen checks if the session still accepts registrations
+func (s *Session) IsOpen() bool {
+	return s.Status == SessionStatusScheduled && time.Now().Before(s.ScheduledTime)
+}
+
+// SessionAttendee is a seat reservation of one user in a group session
+type SessionAttendee struct {
+	ID           uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	SessionID    uuid.UUID      `json:"session_id" gorm:"type:uuid"`
+	UserID       uuid.UUID      `json:"user_id" gorm:"type:uuid"`
+	Seats        int            `json:"seats" gorm:"default:1"`
+	Price        float64        `json:"price"`
+	Status       AttendeeStatus `json:"status" gorm:"default:'registered'"`
+	RegisteredAt time.Time      `json:"registered_at"`
+	CancelledAt  *time.Time     `json:"cancelled_at,omitempty"`
+	AttendedAt   *time.Time     `json:"attended_at,omitempty"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
+
+// TableName returns the table name in the database
+func (SessionAttendee) TableName() string {
+	return "group_session_attendees"
+}
+
+// IsActive checks if the reservation still holds or waits for seats
+func (a *SessionAttendee) IsActive() bool {
+	return a.Status == AttendeeStatusRegistered || a.Status == AttendeeStatusWaitlisted
+}
+
+// SessionResponse struct for session API responses
+type SessionResponse struct {
+	*Session
+	SeatsTaken     int  `json:"seats_taken"`
+	SeatsAvailable int  `json:"seats_available"`
+	WaitlistCount  int  `json:"waitlist_count"`
+	IsFull         bool `json:"is_full"`
+}
+
+// CreateSessionRequest struct for creating a group session
+type CreateSessionRequest struct {
+	ExpertID        uuid.UUID `json:"expert_id" binding:"required"`
+	Title           string    `json:"title" binding:"required,max=255"`
+	Description     string    `json:"description"`
+	ScheduledTime   time.Time `json:"start_time" binding:"required"`
+	DurationMinutes int       `json:"duration_minutes" binding:"required,min=15,max=480"`
+	MeetingType     string    `json:"meeting_type" binding:"required,oneof=online offline"`
+	MeetingURL      *string   `json:"meeting_url,omitempty"`
+	MeetingAddress  *string   `json:"meeting_address,omitempty"`
+	Capacity        int       `json:"capacity" binding:"required,min=2,max=500"`
+	PricePerSeat    float64   `json:"price_per_seat" binding:"min=0"`
+}
+
+// RegisterSessionRequest struct for reserving seats in a group session
+type RegisterSessionRequest struct {
+	Seats int `json:"seats" binding:"omitempty,min=1,max=10"`
+}
+
+// MarkAttendanceRequest struct for recording who attended a session
+type MarkAttendanceRequest struct {
+	UserID uuid.UUID `json:"user_id" binding:"required"`
+}
+
+// CancelSessionRequest struct for cancelling a group session
+type CancelSessionRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// Errors returned by booking and group session operations
+var (
+	ErrBookingNotFound         = apperr.NotFound("booking_not_found")
+	ErrBookingAccessDenied     = apperr.Forbidden("access_denied")
+	ErrInvalidStatusTransition = apperr.InvalidTransition("invalid_status_transition")
+	ErrBookingNotCancellable   = apperr.InvalidTransition("booking_cannot_cancel")
+	ErrSessionNotFound         = apperr.NotFound("session_not_found")
+	ErrSessionAccessDenied     = apperr.Forbidden("access_denied")
+	ErrExpertNotFound          = apperr.NotFound("expert_not_found")
+	ErrSessionNotOpen          = apperr.InvalidTransition("session_not_open")
+	ErrAlreadyRegistered       = apperr.Conflict("session_already_registered")
+	ErrReservationNotFound     = apperr.NotFound("reservation_not_found")
+	ErrSeatsExceedCapacity     = apperr.Validation("seats_exceed_capacity")
+	ErrSessionAlreadyClosed    = apperr.InvalidTransition("session_already_closed")
+	ErrTimeSlotTaken           = apperr.Conflict("time_slot_taken")
+	ErrInvalidBooking          = apperr.Validation("invalid_booking")
+	ErrEmailNotVerified        = apperr.Forbidden("email_not_verified")
+)
@@ -2,6 +2,7 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -32,10 +33,14 @@ type BookingRepositoryInterface interface {
 
 	// Conflict checking
 	CheckConflict(req *model.CheckConflictRequest) ([]model.Booking, error)
-	HasExpertConflict(expertID uuid.UUID, startTime, endTime time.Time) (bool, error)
-	HasUserConflict(userID uuid.UUID, startTime, endTime time.Time) (bool, error)
+	HasExpertConflict(expertID uuid.UUID, startTime, endTime time.Time, excludeID *uuid.UUID) (bool, error)
+	HasUserConflict(userID uuid.UUID, startTime, endTime time.Time, excludeID *uuid.UUID) (bool, error)
 	GetExpertBookingsByDate(expertID uuid.UUID, date time.Time) ([]model.Booking, error)
 
+	// Verification policy
+	IsUserEmailVerified(userID uuid.UUID) (bool, error)
+	CountPendingByUser(userID uuid.UUID) (int64, error)
+
 	// History and statistics
 	GetHistoryByUserID(userID uuid.UUID, offset, limit int, status string, startDate, endDate *time.Time) ([]*model.Booking, int, error)
 	GetHistoryByExpertID(expertID uuid.UUID, offset, limit int, status string, startDate, endDate *time.Time) ([]*model.Booking, int, error)
@@ -74,6 +79,9 @@ func (r *bookingRepository) Create(booking *model.Booking) (*model.Booking, erro
 func (r *bookingRepository) GetByID(id uuid.UUID) (*model.Booking, error) {
 	var booking model.Booking
 	err := r.db.First(&booking, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, model.ErrBookingNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -153,10 +161,11 @@ func (r *bookingRepository) CheckConflict(req *model.CheckConflictRequest) ([]mo
 	return conflictBookings, err
 }
 
-// HasExpertConflict checks for expert time conflicts
-func (r *bookingRepository) HasExpertConflict(expertID uuid.UUID, startTime, endTime time.Time) (bool, error) {
+// HasExpertConflict checks for expert time conflicts, ignoring the booking
+// excludeID when it is set
+func (r *bookingRepository) HasExpertConflict(expertID uuid.UUID, startTime, endTime time.Time, excludeID *uuid.UUID) (bool, error) {
 	var count int64
-	err := r.db.Model(&model.Booking{}).
+	err := excluding(r.db.Model(&model.Booking{}), excludeID).
 		Where("expert_id = ? AND status IN (?, ?) AND "+
 			"((scheduled_datetime < ? AND scheduled_datetime + (duration_minutes || ' minutes')::interval > ?) OR "+
 			"(scheduled_datetime < ? AND scheduled_datetime + (duration_minutes || ' minutes')::interval > ?) OR "+
@@ -167,10 +176,11 @@ func (r *bookingRepository) HasExpertConflict(expertID uuid.UUID, startTime, end
 	return count > 0, err
 }
 
-// HasUserConflict checks for user time conflicts
-func (r *bookingRepository) HasUserConflict(userID uuid.UUID, startTime, endTime time.Time) (bool, error) {
+// HasUserConflict checks for user time conflicts, ignoring the booking
+// excludeID when it is set
+func (r *bookingRepository) HasUserConflict(userID uuid.UUID, startTime, endTime time.Time, excludeID *uuid.UUID) (bool, error) {
 	var count int64
-	err := r.db.Model(&model.Booking{}).
+	err := excluding(r.db.Model(&model.Booking{}), excludeID).
 		Where("user_id = ? AND status IN (?, ?) AND "+
 			"((scheduled_datetime < ? AND scheduled_datetime + (duration_minutes || ' minutes')::interval > ?) OR "+
 			"(scheduled_datetime < ? AND scheduled_datetime + (duration_minutes || ' minutes')::interval > ?) OR "+
@@ -181,6 +191,33 @@ func (r *bookingRepository) HasUserConflict(userID uuid.UUID, startTime, endTime
 	return count > 0, err
 }
 
+// excluding leaves the booking excludeID out of query, e.g. the booking being
+// moved when checking its new time
+func excluding(query *gorm.DB, excludeID *uuid.UUID) *gorm.DB {
+	if excludeID == nil {
+		return query
+	}
+	return query.Where("id <> ?", *excludeID)
+}
+
+// IsUserEmailVerified reports whether the user has verified their email.
+// Users live in the shared database, owned by user-service.
+func (r *bookingRepository) IsUserEmailVerified(userID uuid.UUID) (bool, error) {
+	var verified bool
+	err := r.db.Raw("SELECT COALESCE(email_verified, false) FROM users WHERE id = ?", userID).
+		Scan(&verified).Error
+	return verified, err
+}
+
+// CountPendingByUser counts the user's bookings still awaiting confirmation
+func (r *bookingRepository) CountPendingByUser(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Booking{}).
+		Where("user_id = ? AND status = ?", userID, model.BookingStatusPending).
+		Count(&count).Error
+	return count, err
+}
+
 // GetExpertBookingsByDate gets expert bookings for a specific date
 func (r *bookingRepository) GetExpertBookingsByDate(expertID uuid.UUID, date time.Time) ([]model.Booking, error) {
 	var bookings []model.Booking
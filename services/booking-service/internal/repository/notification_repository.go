@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"services/booking-service/internal/model"
+)
+
+// NotificationRepositoryInterface finds who to notify about bookings and
+// records the reminders already sent
+type NotificationRepositoryInterface interface {
+	GetExpertUserID(expertID uuid.UUID) (uuid.UUID, error)
+	GetConfirmedBetween(from, to time.Time) ([]model.Booking, error)
+	ClaimReminders(booking *model.Booking, userID uuid.UUID, offsets []int) (int64, error)
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository creates a new instance of NotificationRepositoryInterface
+func NewNotificationRepository(db *gorm.DB) NotificationRepositoryInterface {
+	return &notificationRepository{db: db}
+}
+
+// GetExpertUserID returns the account of the expert. Bookings made before
+// expert profiles existed hold the user ID itself.
+func (r *notificationRepository) GetExpertUserID(expertID uuid.UUID) (uuid.UUID, error) {
+	var userID uuid.UUID
+	err := r.db.Raw("SELECT COALESCE((SELECT user_id FROM experts WHERE id = ?), ?)", expertID, expertID).
+		Scan(&userID).Error
+	return userID, err
+}
+
+// GetConfirmedBetween returns the confirmed bookings starting in [from, to)
+func (r *notificationRepository) GetConfirmedBetween(from, to time.Time) ([]model.Booking, error) {
+	var bookings []model.Booking
+	err := r.db.Where("status = ? AND scheduled_datetime >= ? AND scheduled_datetime < ?",
+		model.BookingStatusConfirmed, from, to).
+		Order("scheduled_datetime ASC").
+		Find(&bookings).Error
+	return bookings, err
+}
+
+// ClaimReminders records the reminders for the booking's current start time
+// and returns how many had not been sent yet
+func (r *notificationRepository) ClaimReminders(booking *model.Booking, userID uuid.UUID, offsets []int) (int64, error) {
+	var claimed int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, offset := range offsets {
+			res := tx.Exec(`INSERT INTO booking_reminders (booking_id, user_id, offset_minutes, scheduled_datetime)
+				VALUES (?, ?, ?, ?) ON CONFLICT DO NOTHING`,
+				booking.ID, userID, offset, booking.ScheduledTime)
+			if res.Error != nil {
+				return res.Error
+			}
+			claimed += res.RowsAffected
+		}
+		return nil
+	})
+	return claimed, err
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"services/booking-service/internal/model"
+)
+
+// PrivacyRepositoryInterface reads and anonymises a user's booking data
+type PrivacyRepositoryInterface interface {
+	GetUserData(userID uuid.UUID) (*model.UserDataExport, error)
+	CountUpcoming(userID uuid.UUID, now time.Time) (int64, error)
+	AnonymizeUser(userID uuid.UUID) error
+}
+
+type privacyRepository struct {
+	db *gorm.DB
+}
+
+// NewPrivacyRepository creates a new instance of PrivacyRepositoryInterface
+func NewPrivacyRepository(db *gorm.DB) PrivacyRepositoryInterface {
+	return &privacyRepository{db: db}
+}
+
+// GetUserData gathers the user's bookings with their full status history and
+// the user's group session reservations
+func (r *privacyRepository) GetUserData(userID uuid.UUID) (*model.UserDataExport, error) {
+	export := &model.UserDataExport{
+		UserID:               userID,
+		Bookings:             []model.Booking{},
+		StatusHistory:        []model.StatusHistory{},
+		SessionRegistrations: []model.SessionAttendee{},
+	}
+
+	if err := r.db.Where("user_id = ?", userID).Order("scheduled_datetime ASC").Find(&export.Bookings).Error; err != nil {
+		return nil, err
+	}
+	err := r.db.Where("booking_id IN (?)", r.db.Model(&model.Booking{}).Select("id").Where("user_id = ?", userID)).
+		Order("changed_at ASC").
+		Find(&export.StatusHistory).Error
+	if err != nil {
+		return nil, err
+	}
+	if err := r.db.Where("user_id = ?", userID).Order("registered_at ASC").Find(&export.SessionRegistrations).Error; err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+// CountUpcoming counts the user's pending or confirmed bookings and active
+// session reservations that have not happened yet
+func (r *privacyRepository) CountUpcoming(userID uuid.UUID, now time.Time) (int64, error) {
+	var bookings, seats int64
+	err := r.db.Model(&model.Booking{}).
+		Where("user_id = ? AND status IN (?, ?) AND scheduled_datetime > ?",
+			userID, model.BookingStatusPending, model.BookingStatusConfirmed, now).
+		Count(&bookings).Error
+	if err != nil {
+		return 0, err
+	}
+	err = r.db.Model(&model.SessionAttendee{}).
+		Joins("JOIN group_sessions s ON s.id = group_session_attendees.session_id").
+		Where("group_session_attendees.user_id = ? AND group_session_attendees.status IN (?, ?) AND s.status = ? AND s.scheduled_datetime > ?",
+			userID, model.AttendeeStatusRegistered, model.AttendeeStatusWaitlisted, model.SessionStatusScheduled, now).
+		Count(&seats).Error
+	if err != nil {
+		return 0, err
+	}
+	return bookings + seats, nil
+}
+
+// AnonymizeUser clears the free text the user wrote on their bookings and
+// status changes. The booking rows stay so experts keep their history.
+func (r *privacyRepository) AnonymizeUser(userID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.Booking{}).
+			Where("user_id = ?", userID).
+			Update("notes", "").Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&model.StatusHistory{}).
+			Where("changed_by = ?", userID).
+			Update("note", "").Error
+	})
+}
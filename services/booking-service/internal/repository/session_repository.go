@@ -0,0 +1,352 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"services/booking-service/internal/model"
+)
+
+// SessionRepositoryInterface defines the interface for group session operations
+type SessionRepositoryInterface interface {
+	// Session operations
+	Create(session *model.Session) (*model.Session, error)
+	GetByID(id uuid.UUID) (*model.Session, error)
+	Update(session *model.Session) (*model.Session, error)
+	GetByExpertID(expertID uuid.UUID, includePast bool) ([]model.Session, error)
+	GetUpcoming(limit int) ([]model.Session, error)
+	GetByUserID(userID uuid.UUID) ([]model.Session, error)
+	CancelSession(id uuid.UUID) error
+	GetExpertUserID(expertID uuid.UUID) (uuid.UUID, error)
+
+	// Seat reservations
+	GetSeatCounts(sessionID uuid.UUID) (taken int, waitlisted int, err error)
+	GetAttendees(sessionID uuid.UUID) ([]model.SessionAttendee, error)
+	ReserveSeats(sessionID, userID uuid.UUID, seats int) (*model.SessionAttendee, error)
+	CancelReservation(sessionID, userID uuid.UUID) (*model.SessionAttendee, []model.SessionAttendee, error)
+	MarkAttended(sessionID, userID uuid.UUID) error
+
+	// Conflict checking
+	HasExpertConflict(expertID uuid.UUID, startTime, endTime time.Time) (bool, error)
+	HasAttendeeConflict(userID uuid.UUID, startTime, endTime time.Time) (bool, error)
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository creates a new instance of SessionRepository
+func NewSessionRepository(db *gorm.DB) SessionRepositoryInterface {
+	return &sessionRepository{
+		db: db,
+	}
+}
+
+// Create creates a new group session
+func (r *sessionRepository) Create(session *model.Session) (*model.Session, error) {
+	err := r.db.Create(session).Error
+	return session, err
+}
+
+// GetByID gets a group session by ID
+func (r *sessionRepository) GetByID(id uuid.UUID) (*model.Session, error) {
+	var session model.Session
+	err := r.db.First(&session, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, model.ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Update updates a group session
+func (r *sessionRepository) Update(session *model.Session) (*model.Session, error) {
+	err := r.db.Save(session).Error
+	return session, err
+}
+
+// GetByExpertID gets the sessions of an expert, upcoming only unless includePast is set
+func (r *sessionRepository) GetByExpertID(expertID uuid.UUID, includePast bool) ([]model.Session, error) {
+	var sessions []model.Session
+	query := r.db.Where("expert_id = ?", expertID)
+	if !includePast {
+		query = query.Where("scheduled_datetime >= ?", time.Now())
+	}
+	err := query.Order("scheduled_datetime ASC").Find(&sessions).Error
+	return sessions, err
+}
+
+// GetUpcoming gets scheduled sessions that have not started yet
+func (r *sessionRepository) GetUpcoming(limit int) ([]model.Session, error) {
+	var sessions []model.Session
+	err := r.db.Where("status = ? AND scheduled_datetime > ?", model.SessionStatusScheduled, time.Now()).
+		Order("scheduled_datetime ASC").
+		Limit(limit).
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// GetByUserID gets the sessions a user holds or waits for a seat in
+func (r *sessionRepository) GetByUserID(userID uuid.UUID) ([]model.Session, error) {
+	var sessions []model.Session
+	err := r.db.Joins("JOIN group_session_attendees a ON a.session_id = group_sessions.id").
+		Where("a.user_id = ? AND a.status IN (?, ?, ?)", userID,
+			model.AttendeeStatusRegistered, model.AttendeeStatusWaitlisted, model.AttendeeStatusAttended).
+		Order("group_sessions.scheduled_datetime ASC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// GetExpertUserID returns the account that owns the expert profile
+func (r *sessionRepository) GetExpertUserID(expertID uuid.UUID) (uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	if err := r.db.Raw("SELECT user_id FROM experts WHERE id = ?", expertID).Scan(&userIDs).Error; err != nil {
+		return uuid.Nil, err
+	}
+	if len(userIDs) == 0 {
+		return uuid.Nil, model.ErrExpertNotFound
+	}
+	return userIDs[0], nil
+}
+
+// CancelSession cancels a session together with every active reservation
+func (r *sessionRepository) CancelSession(id uuid.UUID) error {
+	now := time.Now()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.Session{}).
+			Where("id = ? AND status = ?", id, model.SessionStatusScheduled).
+			Updates(map[string]interface{}{
+				"status":       model.SessionStatusCancelled,
+				"cancelled_at": now,
+				"updated_at":   now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return model.ErrSessionAlreadyClosed
+		}
+
+		return tx.Model(&model.SessionAttendee{}).
+			Where("session_id = ? AND status IN (?, ?)", id,
+				model.AttendeeStatusRegistered, model.AttendeeStatusWaitlisted).
+			Updates(map[string]interface{}{
+				"status":       model.AttendeeStatusCancelled,
+				"cancelled_at": now,
+				"updated_at":   now,
+			}).Error
+	})
+}
+
+// GetSeatCounts returns the seats held and the seats waiting on the waitlist
+func (r *sessionRepository) GetSeatCounts(sessionID uuid.UUID) (int, int, error) {
+	return seatCounts(r.db, sessionID)
+}
+
+func seatCounts(db *gorm.DB, sessionID uuid.UUID) (int, int, error) {
+	var counts struct {
+		Taken      int
+		Waitlisted int
+	}
+	err := db.Model(&model.SessionAttendee{}).
+		Select("COALESCE(SUM(seats) FILTER (WHERE status IN (?, ?)), 0) AS taken, "+
+			"COALESCE(SUM(seats) FILTER (WHERE status = ?), 0) AS waitlisted",
+			model.AttendeeStatusRegistered, model.AttendeeStatusAttended, model.AttendeeStatusWaitlisted).
+		Where("session_id = ?", sessionID).
+		Scan(&counts).Error
+	return counts.Taken, counts.Waitlisted, err
+}
+
+// GetAttendees gets every reservation of a session in registration order
+func (r *sessionRepository) GetAttendees(sessionID uuid.UUID) ([]model.SessionAttendee, error) {
+	var attendees []model.SessionAttendee
+	err := r.db.Where("session_id = ?", sessionID).
+		Order("registered_at ASC").
+		Find(&attendees).Error
+	return attendees, err
+}
+
+// ReserveSeats reserves seats for a user. The session row is locked so that
+// concurrent reservations cannot oversell it; when the seats do not fit the
+// user is put on the waitlist instead.
+func (r *sessionRepository) ReserveSeats(sessionID, userID uuid.UUID, seats int) (*model.SessionAttendee, error) {
+	var attendee *model.SessionAttendee
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		session, err := lockSession(tx, sessionID)
+		if err != nil {
+			return err
+		}
+		if !session.IsOpen() {
+			return model.ErrSessionNotOpen
+		}
+		if seats > session.Capacity {
+			return model.ErrSeatsExceedCapacity
+		}
+
+		var existing int64
+		if err := tx.Model(&model.SessionAttendee{}).
+			Where("session_id = ? AND user_id = ? AND status IN (?, ?)", sessionID, userID,
+				model.AttendeeStatusRegistered, model.AttendeeStatusWaitlisted).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return model.ErrAlreadyRegistered
+		}
+
+		taken, waitlisted, err := seatCounts(tx, sessionID)
+		if err != nil {
+			return err
+		}
+
+		status := model.AttendeeStatusRegistered
+		// Nobody jumps the waitlist, even if their seats would fit
+		if waitlisted > 0 || taken+seats > session.Capacity {
+			status = model.AttendeeStatusWaitlisted
+		}
+
+		now := time.Now()
+		attendee = &model.SessionAttendee{
+			SessionID:    sessionID,
+			UserID:       userID,
+			Seats:        seats,
+			Price:        float64(seats) * session.PricePerSeat,
+			Status:       status,
+			RegisteredAt: now,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+		return tx.Create(attendee).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return attendee, nil
+}
+
+// CancelReservation cancels a user's reservation and promotes waitlisted
+// reservations, in order, while their seats fit. It returns the cancelled
+// reservation and the promoted ones.
+func (r *sessionRepository) CancelReservation(sessionID, userID uuid.UUID) (*model.SessionAttendee, []model.SessionAttendee, error) {
+	var cancelled model.SessionAttendee
+	var promoted []model.SessionAttendee
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		session, err := lockSession(tx, sessionID)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("session_id = ? AND user_id = ? AND status IN (?, ?)", sessionID, userID,
+			model.AttendeeStatusRegistered, model.AttendeeStatusWaitlisted).
+			First(&cancelled).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.ErrReservationNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		cancelled.Status = model.AttendeeStatusCancelled
+		cancelled.CancelledAt = &now
+		cancelled.UpdatedAt = now
+		if err := tx.Save(&cancelled).Error; err != nil {
+			return err
+		}
+
+		if session.Status != model.SessionStatusScheduled {
+			return nil
+		}
+
+		taken, _, err := seatCounts(tx, sessionID)
+		if err != nil {
+			return err
+		}
+
+		var waiting []model.SessionAttendee
+		if err := tx.Where("session_id = ? AND status = ?", sessionID, model.AttendeeStatusWaitlisted).
+			Order("registered_at ASC").
+			Find(&waiting).Error; err != nil {
+			return err
+		}
+		for _, next := range waiting {
+			if taken+next.Seats > session.Capacity {
+				break
+			}
+			next.Status = model.AttendeeStatusRegistered
+			next.UpdatedAt = now
+			if err := tx.Save(&next).Error; err != nil {
+				return err
+			}
+			taken += next.Seats
+			promoted = append(promoted, next)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return &cancelled, promoted, nil
+}
+
+// MarkAttended records that a registered user attended the session
+func (r *sessionRepository) MarkAttended(sessionID, userID uuid.UUID) error {
+	now := time.Now()
+	res := r.db.Model(&model.SessionAttendee{}).
+		Where("session_id = ? AND user_id = ? AND status = ?", sessionID, userID, model.AttendeeStatusRegistered).
+		Updates(map[string]interface{}{
+			"status":      model.AttendeeStatusAttended,
+			"attended_at": now,
+			"updated_at":  now,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return model.ErrReservationNotFound
+	}
+	return nil
+}
+
+// HasExpertConflict checks whether a scheduled session of the expert overlaps the range.
+// The expert is busy once per session regardless of how many attendees it has.
+func (r *sessionRepository) HasExpertConflict(expertID uuid.UUID, startTime, endTime time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.Session{}).
+		Where("expert_id = ? AND status = ? AND "+
+			"scheduled_datetime < ? AND scheduled_datetime + (duration_minutes || ' minutes')::interval > ?",
+			expertID, model.SessionStatusScheduled, endTime, startTime).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// HasAttendeeConflict checks whether the user holds a seat in a session overlapping the range
+func (r *sessionRepository) HasAttendeeConflict(userID uuid.UUID, startTime, endTime time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.Session{}).
+		Joins("JOIN group_session_attendees a ON a.session_id = group_sessions.id").
+		Where("a.user_id = ? AND a.status = ? AND group_sessions.status = ? AND "+
+			"group_sessions.scheduled_datetime < ? AND "+
+			"group_sessions.scheduled_datetime + (group_sessions.duration_minutes || ' minutes')::interval > ?",
+			userID, model.AttendeeStatusRegistered, model.SessionStatusScheduled, endTime, startTime).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func lockSession(tx *gorm.DB, sessionID uuid.UUID) (*model.Session, error) {
+	var session model.Session
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&session, "id = ?", sessionID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, model.ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
@@ -7,7 +7,7 @@ import (
 )
 
 // SetupRoutes thiết lập các route cho booking service
-func SetupRoutes(router *gin.Engine, bookingHandler *handler.BookingHandler, statusHandler *handler.StatusHandler, historyHandler *handler.HistoryHandler) {
+func SetupRoutes(router *gin.Engine, bookingHandler *handler.BookingHandler, statusHandler *handler.StatusHandler, historyHandler *handler.HistoryHandler, sessionHandler *handler.SessionHandler, privacyHandler *handler.PrivacyHandler) {
 	// Booking routes
 	router.POST("/CreateBooking", bookingHandler.CreateBooking)
 	router.GET("/GetBooking/:id", bookingHandler.GetBooking)
@@ -21,7 +21,23 @@ func SetupRoutes(router *gin.Engine, bookingHandler *handler.BookingHandler, sta
 	router.GET("/GetBookingStatus/:id", statusHandler.GetBookingStatus)
 	router.GET("/GetStatusHistory/:id", statusHandler.GetStatusHistory)
 
+	// Group session routes
+	router.POST("/CreateSession", sessionHandler.CreateSession)
+	router.GET("/GetSession/:id", sessionHandler.GetSession)
+	router.GET("/GetUpcomingSessions", sessionHandler.GetUpcomingSessions)
+	router.GET("/GetExpertSessions/:expert_id", sessionHandler.GetExpertSessions)
+	router.GET("/GetUserSessions", sessionHandler.GetUserSessions)
+	router.GET("/GetSessionAttendees/:id", sessionHandler.GetSessionAttendees)
+	router.POST("/RegisterSession/:id", sessionHandler.RegisterSession)
+	router.DELETE("/CancelSessionRegistration/:id", sessionHandler.CancelSessionRegistration)
+	router.DELETE("/CancelSession/:id", sessionHandler.CancelSession)
+	router.PUT("/MarkSessionAttendance/:id", sessionHandler.MarkSessionAttendance)
+
 	// History routes
 	router.GET("/GetBookingHistoryByUser", historyHandler.GetBookingHistory)
 	router.GET("/GetBookingHistoryByExpert", historyHandler.GetExpertHistory)
+
+	// Privacy routes, used by user-service for data export and account erasure
+	router.GET("/ExportUserData", privacyHandler.ExportUserData)
+	router.POST("/AnonymizeUserData", privacyHandler.AnonymizeUserData)
 }
@@ -3,12 +3,15 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 
+	"services/booking-service/internal/client"
+	"services/booking-service/internal/events"
 	"services/booking-service/internal/model"
 	"services/booking-service/internal/repository"
 	"services/booking-service/pkg/logger"
@@ -33,10 +36,22 @@ type BookingServiceInterface interface {
 	GetExpertBookingsByDate(expertID uuid.UUID, date time.Time) ([]model.BookingResponse, error)
 }
 
+// Fallback policies applied when expert-service cannot be reached
+const (
+	ExpertCheckFallbackReject = "reject"
+	ExpertCheckFallbackAllow  = "allow"
+)
+
 type BookingService struct {
 	bookingRepo       repository.BookingRepositoryInterface
 	statusHistoryRepo repository.StatusHistoryRepositoryInterface
 	redisClient       *redis.Client
+	publisher         events.PublisherInterface
+	conflictChecker   ConflictCheckerInterface
+	expertClient      client.ExpertClientInterface
+	expertFallback    string
+	verification      VerificationPolicyInterface
+	notifications     NotificationServiceInterface
 	logger            logger.LoggerInterface
 }
 
@@ -44,33 +59,75 @@ func NewBookingService(
 	bookingRepo repository.BookingRepositoryInterface,
 	statusHistoryRepo repository.StatusHistoryRepositoryInterface,
 	redisClient *redis.Client,
+	publisher events.PublisherInterface,
+	conflictChecker ConflictCheckerInterface,
+	expertClient client.ExpertClientInterface,
+	expertFallback string,
+	verification VerificationPolicyInterface,
+	notifications NotificationServiceInterface,
 	logger logger.LoggerInterface,
 ) BookingServiceInterface {
 	return &BookingService{
 		bookingRepo:       bookingRepo,
 		statusHistoryRepo: statusHistoryRepo,
 		redisClient:       redisClient,
+		publisher:         publisher,
+		conflictChecker:   conflictChecker,
+		expertClient:      expertClient,
+		expertFallback:    expertFallback,
+		verification:      verification,
+		notifications:     notifications,
 		logger:            logger,
 	}
 }
 
 func (s *BookingService) CreateBooking(userID uuid.UUID, req *model.CreateBookingRequest) (*model.BookingResponse, error) {
+	// Unverified accounts may only hold a limited number of pending bookings
+	if err := s.verification.CheckCanBook(userID); err != nil {
+		return nil, err
+	}
+
+	// Duration, price and meeting type are taken from the expert's service.
+	// There is no fallback here: without the catalog they cannot be derived.
+	svc, err := s.expertClient.GetService(context.Background(), req.ExpertID, req.ServiceID)
+	if err != nil {
+		return nil, err
+	}
+	meetingType := model.BookingType(svc.MeetingType)
+
 	// Validate request
-	if err := req.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid request: %v", err)
+	if err := req.Validate(meetingType); err != nil {
+		return nil, model.ErrInvalidBooking.Wrap(err)
+	}
+
+	// Check for conflicts
+	endTime := req.ScheduledTime.Add(time.Duration(svc.DurationMinutes) * time.Minute)
+	hasConflict, err := s.conflictChecker.CheckBookingConflict(req.ExpertID, userID, req.ScheduledTime, endTime)
+	if err != nil {
+		return nil, model.ErrInvalidBooking.Wrap(err)
+	}
+	if hasConflict {
+		return nil, model.ErrTimeSlotTaken
+	}
+
+	// Validate expert, working hours and off-times with expert-service
+	if err := s.verifyExpertWindow(req.ExpertID, req.ScheduledTime, svc.DurationMinutes); err != nil {
+		return nil, err
 	}
 
 	// Create booking model
 	booking := &model.Booking{
 		UserID:          userID,
 		ExpertID:        req.ExpertID,
+		ServiceID:       &svc.ID,
 		ScheduledTime:   req.ScheduledTime,
-		DurationMinutes: req.DurationMinutes,
-		MeetingType:     model.BookingType(req.MeetingType),
+		DurationMinutes: svc.DurationMinutes,
+		MeetingType:     meetingType,
 		Status:          model.BookingStatusPending,
 		MeetingAddress:  derefString(req.MeetingAddress),
 		MeetingURL:      derefString(req.MeetingURL),
 		Notes:           req.Notes,
+		Price:           svc.Price,
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),
 	}
@@ -97,8 +154,8 @@ func (s *BookingService) CreateBooking(userID uuid.UUID, req *model.CreateBookin
 	// Cache booking data
 	s.cacheBooking(createdBooking)
 
-	// TODO: Send notification to expert
 	s.notifyBookingCreated(createdBooking)
+	s.publisher.PublishBookingEvent(events.TypeBookingCreated, createdBooking.ExpertID, createdBooking.ID)
 
 	return s.convertToBookingResponse(createdBooking), nil
 }
@@ -128,19 +185,54 @@ func (s *BookingService) UpdateBooking(bookingID uuid.UUID, req *model.UpdateBoo
 		return nil, err
 	}
 
-	// Update fields
+	// Duration, price and meeting type follow the expert's service, as in
+	// CreateBooking
+	var svc *client.ConsultationService
+	meetingType := booking.MeetingType
+	if req.ServiceID != nil {
+		svc, err = s.expertClient.GetService(context.Background(), booking.ExpertID, *req.ServiceID)
+		if err != nil {
+			return nil, err
+		}
+		meetingType = model.BookingType(svc.MeetingType)
+	}
+	if err := req.Validate(meetingType, booking); err != nil {
+		return nil, model.ErrInvalidBooking.Wrap(err)
+	}
+
+	// Re-check conflicts and the expert's working hours when the session moves
+	scheduledTime, durationMinutes := booking.ScheduledTime, booking.DurationMinutes
 	if req.ScheduledTime != nil {
-		booking.ScheduledTime = *req.ScheduledTime
+		scheduledTime = *req.ScheduledTime
+	}
+	if svc != nil {
+		durationMinutes = svc.DurationMinutes
+	}
+	if !scheduledTime.Equal(booking.ScheduledTime) || durationMinutes != booking.DurationMinutes {
+		endTime := scheduledTime.Add(time.Duration(durationMinutes) * time.Minute)
+		hasConflict, err := s.conflictChecker.CheckRescheduleConflict(booking.ID, booking.ExpertID, booking.UserID, scheduledTime, endTime)
+		if err != nil {
+			return nil, model.ErrInvalidBooking.Wrap(err)
+		}
+		if hasConflict {
+			return nil, model.ErrTimeSlotTaken
+		}
+		if err := s.verifyExpertWindow(booking.ExpertID, scheduledTime, durationMinutes); err != nil {
+			return nil, err
+		}
 	}
-	if req.DurationMinutes != nil {
-		booking.DurationMinutes = *req.DurationMinutes
+
+	// Update fields
+	booking.ScheduledTime = scheduledTime
+	if svc != nil {
+		booking.ServiceID = &svc.ID
+		booking.DurationMinutes = svc.DurationMinutes
+		booking.MeetingType = meetingType
+		booking.Price = svc.Price
 	}
 	if req.Notes != nil {
 		booking.Notes = *req.Notes
 	}
-	if req.MeetingType != nil {
-		booking.MeetingType = model.BookingType(*req.MeetingType)
-	}
 	if req.MeetingAddress != nil {
 		booking.MeetingAddress = *req.MeetingAddress
 	}
@@ -161,6 +253,7 @@ func (s *BookingService) UpdateBooking(bookingID uuid.UUID, req *model.UpdateBoo
 
 	// TODO: Send notification about update
 	s.notifyBookingUpdated(updatedBooking)
+	s.publisher.PublishBookingEvent(events.TypeBookingUpdated, updatedBooking.ExpertID, updatedBooking.ID)
 
 	return s.convertToBookingResponse(updatedBooking), nil
 }
@@ -174,7 +267,7 @@ func (s *BookingService) CancelBooking(bookingID uuid.UUID, userID uuid.UUID, re
 
 	// Check if booking can be cancelled
 	if !booking.CanBeCancelled() {
-		return fmt.Errorf("booking cannot be cancelled")
+		return model.ErrBookingNotCancellable
 	}
 
 	// Update status to cancelled
@@ -197,10 +290,11 @@ func (s *BookingService) CancelBooking(bookingID uuid.UUID, userID uuid.UUID, re
 	}
 
 	// Update cache
+	booking.Status = model.BookingStatusCancelled
 	s.cacheBooking(booking)
 
-	// TODO: Send notification about cancellation
-	s.notifyBookingCancelled(booking)
+	s.notifyBookingCancelled(booking, userID)
+	s.publisher.PublishBookingEvent(events.TypeBookingCancelled, booking.ExpertID, booking.ID)
 
 	return nil
 }
@@ -459,9 +553,24 @@ func (s *BookingService) getBookingFromCache(bookingID uuid.UUID) *model.Booking
 	return &booking
 }
 
+// verifyExpertWindow checks the expert with expert-service. When the service
+// is down the configured fallback policy decides whether the booking proceeds.
+func (s *BookingService) verifyExpertWindow(expertID uuid.UUID, scheduledTime time.Time, durationMinutes int) error {
+	err := s.expertClient.CheckBookingWindow(context.Background(), expertID, scheduledTime, durationMinutes)
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, client.ErrExpertServiceUnavailable) && s.expertFallback == ExpertCheckFallbackAllow {
+		s.logger.Warn(fmt.Sprintf("Expert service unavailable, accepting booking for expert %s without verification: %v", expertID, err))
+		return nil
+	}
+	return err
+}
+
 // Helper function to notify about booking creation
 func (s *BookingService) notifyBookingCreated(booking *model.Booking) {
-	// TODO: Implement notification logic
+	s.notifications.BookingCreated(booking)
 }
 
 // Helper function to notify about booking update
@@ -470,8 +579,8 @@ func (s *BookingService) notifyBookingUpdated(booking *model.Booking) {
 }
 
 // Helper function to notify about booking cancellation
-func (s *BookingService) notifyBookingCancelled(booking *model.Booking) {
-	// TODO: Implement notification logic
+func (s *BookingService) notifyBookingCancelled(booking *model.Booking, cancelledBy uuid.UUID) {
+	s.notifications.BookingStatusChanged(booking, cancelledBy)
 }
 
 func derefString(s *string) string {
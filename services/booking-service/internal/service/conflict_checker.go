@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"booking-system/shared/pkg/i18n"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 
@@ -15,6 +16,7 @@ import (
 
 type ConflictCheckerInterface interface {
 	CheckBookingConflict(expertID, userID uuid.UUID, startTime, endTime time.Time) (bool, error)
+	CheckRescheduleConflict(bookingID, expertID, userID uuid.UUID, startTime, endTime time.Time) (bool, error)
 	CheckExpertAvailability(expertID uuid.UUID, startTime, endTime time.Time) (bool, error)
 	CheckUserConflict(userID uuid.UUID, startTime, endTime time.Time) (bool, error)
 	LockTimeSlot(expertID uuid.UUID, startTime, endTime time.Time) (string, error)
@@ -36,15 +38,18 @@ type TimeSlot struct {
 
 type ConflictChecker struct {
 	bookingRepo repository.BookingRepositoryInterface
+	sessionRepo repository.SessionRepositoryInterface
 	redisClient *redis.Client
 }
 
 func NewConflictChecker(
 	bookingRepo repository.BookingRepositoryInterface,
+	sessionRepo repository.SessionRepositoryInterface,
 	redisClient *redis.Client,
 ) ConflictCheckerInterface {
 	return &ConflictChecker{
 		bookingRepo: bookingRepo,
+		sessionRepo: sessionRepo,
 		redisClient: redisClient,
 	}
 }
@@ -76,6 +81,29 @@ func (c *ConflictChecker) CheckBookingConflict(expertID, userID uuid.UUID, start
 	return false, nil // No conflicts
 }
 
+// CheckRescheduleConflict runs the checks of CheckBookingConflict for a
+// booking moving to a new time, ignoring the booking itself. The cache is
+// bypassed since cached results count the booking at its old time.
+func (c *ConflictChecker) CheckRescheduleConflict(bookingID, expertID, userID uuid.UUID, startTime, endTime time.Time) (bool, error) {
+	if err := c.ValidateTimeSlot(startTime, endTime); err != nil {
+		return false, err
+	}
+
+	expertConflict, err := c.hasExpertConflict(expertID, startTime, endTime, &bookingID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check expert availability: %v", err)
+	}
+	if expertConflict {
+		return true, nil
+	}
+
+	userConflict, err := c.hasUserConflict(userID, startTime, endTime, &bookingID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check user conflict: %v", err)
+	}
+	return userConflict, nil
+}
+
 func (c *ConflictChecker) CheckExpertAvailability(expertID uuid.UUID, startTime, endTime time.Time) (bool, error) {
 	// First validate the time slot to check for expired times
 	if err := c.ValidateTimeSlot(startTime, endTime); err != nil {
@@ -97,8 +125,8 @@ func (c *ConflictChecker) CheckExpertAvailability(expertID uuid.UUID, startTime,
 		}
 	}
 
-	// Check database for existing bookings
-	hasConflict, err := c.bookingRepo.HasExpertConflict(expertID, startTime, endTime)
+	// Check database for existing bookings and group sessions
+	hasConflict, err := c.hasExpertConflict(expertID, startTime, endTime, nil)
 	if err != nil {
 		return false, err
 	}
@@ -127,8 +155,8 @@ func (c *ConflictChecker) CheckUserConflict(userID uuid.UUID, startTime, endTime
 		}
 	}
 
-	// Check database for user conflicts
-	hasConflict, err := c.bookingRepo.HasUserConflict(userID, startTime, endTime)
+	// Check database for user conflicts, including seats held in group sessions
+	hasConflict, err := c.hasUserConflict(userID, startTime, endTime, nil)
 	if err != nil {
 		return false, err
 	}
@@ -155,7 +183,7 @@ func (c *ConflictChecker) LockTimeSlot(expertID uuid.UUID, startTime, endTime ti
 	}
 
 	if !success {
-		return "", fmt.Errorf("time slot is currently being booked by another user")
+		return "", i18n.NewError("time_slot_locked")
 	}
 
 	return lockKey, nil
@@ -236,25 +264,25 @@ func (c *ConflictChecker) ValidateTimeSlot(startTime, endTime time.Time) error {
 
 	// Check if booking is in the past
 	if startTime.Before(now) {
-		return fmt.Errorf("time slot has expired")
+		return i18n.NewError("time_slot_expired")
 	}
 
 	if endTime.Before(startTime) || endTime.Equal(startTime) {
-		return fmt.Errorf("end time must be after start time")
+		return i18n.NewError("end_before_start")
 	}
 
 	duration := endTime.Sub(startTime)
 	if duration < 30*time.Minute {
-		return fmt.Errorf("booking duration must be at least 30 minutes")
+		return i18n.NewError("booking_too_short")
 	}
 
 	if duration > 4*time.Hour {
-		return fmt.Errorf("booking duration cannot exceed 4 hours")
+		return i18n.NewError("booking_too_long")
 	}
 
 	// Check if booking is too far in the future (max 6 months)
 	if startTime.After(now.AddDate(0, 6, 0)) {
-		return fmt.Errorf("cannot book more than 6 months in advance")
+		return i18n.NewError("booking_too_far_ahead")
 	}
 
 	return nil
@@ -278,7 +306,7 @@ func (c *ConflictChecker) ClearExpertCache(expertID uuid.UUID) error {
 
 func (c *ConflictChecker) CheckConflict(req *model.CheckConflictRequest) (bool, error) {
 	// Check expert conflicts
-	hasExpertConflict, err := c.bookingRepo.HasExpertConflict(req.ExpertID, req.StartTime, req.EndTime)
+	hasExpertConflict, err := c.hasExpertConflict(req.ExpertID, req.StartTime, req.EndTime, nil)
 	if err != nil {
 		return false, err
 	}
@@ -288,7 +316,7 @@ func (c *ConflictChecker) CheckConflict(req *model.CheckConflictRequest) (bool,
 
 	// Check user conflicts if UserID is provided
 	if req.UserID != nil {
-		hasUserConflict, err := c.bookingRepo.HasUserConflict(*req.UserID, req.StartTime, req.EndTime)
+		hasUserConflict, err := c.hasUserConflict(*req.UserID, req.StartTime, req.EndTime, nil)
 		if err != nil {
 			return false, err
 		}
@@ -302,7 +330,7 @@ func (c *ConflictChecker) CheckConflict(req *model.CheckConflictRequest) (bool,
 
 func (c *ConflictChecker) CheckConflictWithExclusion(req *model.CheckConflictRequest, excludeID uuid.UUID) (bool, error) {
 	// Check expert conflicts
-	hasExpertConflict, err := c.bookingRepo.HasExpertConflict(req.ExpertID, req.StartTime, req.EndTime)
+	hasExpertConflict, err := c.hasExpertConflict(req.ExpertID, req.StartTime, req.EndTime, &excludeID)
 	if err != nil {
 		return false, err
 	}
@@ -312,7 +340,7 @@ func (c *ConflictChecker) CheckConflictWithExclusion(req *model.CheckConflictReq
 
 	// Check user conflicts if UserID is provided
 	if req.UserID != nil {
-		hasUserConflict, err := c.bookingRepo.HasUserConflict(*req.UserID, req.StartTime, req.EndTime)
+		hasUserConflict, err := c.hasUserConflict(*req.UserID, req.StartTime, req.EndTime, &excludeID)
 		if err != nil {
 			return false, err
 		}
@@ -337,3 +365,23 @@ func (c *ConflictChecker) GetExpertBookingsByDate(expertID uuid.UUID, date time.
 	}
 	return bookingPtrs, nil
 }
+
+// hasExpertConflict checks one-to-one bookings, except excludeID, and group
+// sessions of the expert
+func (c *ConflictChecker) hasExpertConflict(expertID uuid.UUID, startTime, endTime time.Time, excludeID *uuid.UUID) (bool, error) {
+	hasConflict, err := c.bookingRepo.HasExpertConflict(expertID, startTime, endTime, excludeID)
+	if err != nil || hasConflict {
+		return hasConflict, err
+	}
+	return c.sessionRepo.HasExpertConflict(expertID, startTime, endTime)
+}
+
+// hasUserConflict checks one-to-one bookings, except excludeID, and
+// registered group session seats of the user
+func (c *ConflictChecker) hasUserConflict(userID uuid.UUID, startTime, endTime time.Time, excludeID *uuid.UUID) (bool, error) {
+	hasConflict, err := c.bookingRepo.HasUserConflict(userID, startTime, endTime, excludeID)
+	if err != nil || hasConflict {
+		return hasConflict, err
+	}
+	return c.sessionRepo.HasAttendeeConflict(userID, startTime, endTime)
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"booking-system/shared/pkg/notify"
+	"github.com/google/uuid"
+
+	"services/booking-service/internal/model"
+	"services/booking-service/internal/repository"
+	"services/booking-service/pkg/logger"
+)
+
+// NotificationServiceInterface tells users and experts about their bookings
+// through the notification settings they chose in user-service
+type NotificationServiceInterface interface {
+	BookingCreated(booking *model.Booking)
+	BookingStatusChanged(booking *model.Booking, changedBy uuid.UUID)
+	// SendReminders sends the booking reminders that are due, at the
+	// offsets each participant chose
+	SendReminders() error
+}
+
+type NotificationService struct {
+	notificationRepo repository.NotificationRepositoryInterface
+	notifier         *notify.Notifier
+	logger           logger.LoggerInterface
+}
+
+func NewNotificationService(
+	notificationRepo repository.NotificationRepositoryInterface,
+	notifier *notify.Notifier,
+	logger logger.LoggerInterface,
+) NotificationServiceInterface {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+		notifier:         notifier,
+		logger:           logger,
+	}
+}
+
+// BookingCreated asks the expert to confirm the new booking
+func (s *NotificationService) BookingCreated(booking *model.Booking) {
+	expertUserID, err := s.notificationRepo.GetExpertUserID(booking.ExpertID)
+	if err != nil {
+		s.logger.Error("Failed to find expert account", err)
+		return
+	}
+	s.notify(expertUserID, booking, notify.EventBookingCreated)
+}
+
+// BookingStatusChanged tells the user their booking was confirmed or
+// rejected, and the other side of a cancellation that it happened. Admins
+// cancelling notify both sides.
+func (s *NotificationService) BookingStatusChanged(booking *model.Booking, changedBy uuid.UUID) {
+	switch booking.Status {
+	case model.BookingStatusConfirmed:
+		s.notify(booking.UserID, booking, notify.EventBookingConfirmed)
+	case model.BookingStatusRejected:
+		s.notify(booking.UserID, booking, notify.EventBookingRejected)
+	case model.BookingStatusCancelled:
+		expertUserID, err := s.notificationRepo.GetExpertUserID(booking.ExpertID)
+		if err != nil {
+			s.logger.Error("Failed to find expert account", err)
+			return
+		}
+		for _, userID := range []uuid.UUID{booking.UserID, expertUserID} {
+			if userID != changedBy {
+				s.notify(userID, booking, notify.EventBookingCancelled)
+			}
+		}
+	}
+}
+
+func (s *NotificationService) notify(userID uuid.UUID, booking *model.Booking, event notify.Event) {
+	msg := notify.Message{
+		Event:     event,
+		Title:     fmt.Sprintf("notification_%s_title", event),
+		Body:      fmt.Sprintf("notification_%s_body", event),
+		Args:      []interface{}{booking.ScheduledTime},
+		BookingID: booking.ID.String(),
+	}
+	if err := s.notifier.Notify(userID.String(), msg); err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to notify user %s about booking %s", userID, booking.ID), err)
+	}
+}
+
+func (s *NotificationService) SendReminders() error {
+	now := time.Now()
+	bookings, err := s.notificationRepo.GetConfirmedBetween(now, now.Add(notify.MaxReminderOffset*time.Minute))
+	if err != nil {
+		return fmt.Errorf("failed to get upcoming bookings: %v", err)
+	}
+
+	// Settings are read once per participant per run. A booking or user that
+	// fails is logged and skipped so the others are still reminded.
+	settings := map[uuid.UUID]*notify.Settings{}
+	for i := range bookings {
+		booking := &bookings[i]
+		expertUserID, err := s.notificationRepo.GetExpertUserID(booking.ExpertID)
+		if err != nil {
+			s.logger.Error(fmt.Sprintf("Failed to find expert account for booking %s", booking.ID), err)
+			continue
+		}
+		for _, userID := range []uuid.UUID{booking.UserID, expertUserID} {
+			if _, ok := settings[userID]; !ok {
+				userSettings, err := s.notifier.Settings(userID.String())
+				if err != nil {
+					s.logger.Error(fmt.Sprintf("Failed to get notification settings of user %s", userID), err)
+					continue
+				}
+				settings[userID] = userSettings
+			}
+			s.remind(userID, booking, settings[userID].ReminderOffsets, now)
+		}
+	}
+	return nil
+}
+
+// remind sends one reminder when any of the offsets has been reached since
+// the last one. Offsets that passed together, e.g. for a booking made an
+// hour before it starts, are claimed with it so the user is reminded once.
+func (s *NotificationService) remind(userID uuid.UUID, booking *model.Booking, offsets []int, now time.Time) {
+	var due []int
+	for _, offset := range offsets {
+		if !now.Before(booking.ScheduledTime.Add(-time.Duration(offset) * time.Minute)) {
+			due = append(due, offset)
+		}
+	}
+	if len(due) == 0 {
+		return
+	}
+	claimed, err := s.notificationRepo.ClaimReminders(booking, userID, due)
+	if err != nil {
+		s.logger.Error("Failed to record booking reminder", err)
+		return
+	}
+	if claimed == 0 {
+		return
+	}
+	msg := notify.Message{
+		Event:     notify.EventBookingReminder,
+		Title:     "notification_booking_reminder_title",
+		Body:      "notification_booking_reminder_body",
+		Args:      []interface{}{booking.ScheduledTime},
+		BookingID: booking.ID.String(),
+		Expires:   booking.ScheduledTime,
+	}
+	if err := s.notifier.Notify(userID.String(), msg); err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to remind user %s about booking %s", userID, booking.ID), err)
+	}
+}
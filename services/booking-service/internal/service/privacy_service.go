@@ -0,0 +1,58 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"services/booking-service/internal/model"
+	"services/booking-service/internal/repository"
+	"services/booking-service/pkg/logger"
+)
+
+// PrivacyServiceInterface exports and anonymises a user's booking data for
+// user-service's data export and account erasure
+type PrivacyServiceInterface interface {
+	ExportUserData(userID uuid.UUID) (*model.UserDataExport, error)
+	AnonymizeUserData(userID uuid.UUID) error
+}
+
+type PrivacyService struct {
+	privacyRepo repository.PrivacyRepositoryInterface
+	logger      logger.LoggerInterface
+}
+
+func NewPrivacyService(privacyRepo repository.PrivacyRepositoryInterface, logger logger.LoggerInterface) PrivacyServiceInterface {
+	return &PrivacyService{
+		privacyRepo: privacyRepo,
+		logger:      logger,
+	}
+}
+
+func (s *PrivacyService) ExportUserData(userID uuid.UUID) (*model.UserDataExport, error) {
+	export, err := s.privacyRepo.GetUserData(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user data: %w", err)
+	}
+	return export, nil
+}
+
+// AnonymizeUserData removes the user's notes from their bookings. Users with
+// upcoming bookings or seats must cancel them first, so experts are not left
+// waiting for a client who no longer exists.
+func (s *PrivacyService) AnonymizeUserData(userID uuid.UUID) error {
+	upcoming, err := s.privacyRepo.CountUpcoming(userID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to check upcoming bookings: %w", err)
+	}
+	if upcoming > 0 {
+		return model.ErrUpcomingBookings
+	}
+
+	if err := s.privacyRepo.AnonymizeUser(userID); err != nil {
+		return fmt.Errorf("failed to anonymize user data: %w", err)
+	}
+	s.logger.Info(fmt.Sprintf("Anonymized booking data of user %s", userID))
+	return nil
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"booking-system/shared/pkg/i18n"
+	"github.com/google/uuid"
+
+	"services/booking-service/internal/client"
+	"services/booking-service/internal/events"
+	"services/booking-service/internal/model"
+	"services/booking-service/internal/repository"
+	"services/booking-service/pkg/logger"
+)
+
+type SessionServiceInterface interface {
+	CreateSession(req *model.CreateSessionRequest, userID uuid.UUID, userRole string) (*model.SessionResponse, error)
+	GetSession(sessionID uuid.UUID) (*model.SessionResponse, error)
+	GetExpertSessions(expertID uuid.UUID, includePast bool) ([]model.SessionResponse, error)
+	GetUpcomingSessions(limit int) ([]model.SessionResponse, error)
+	GetUserSessions(userID uuid.UUID) ([]model.SessionResponse, error)
+	GetAttendees(sessionID uuid.UUID) ([]model.SessionAttendee, error)
+	RegisterForSession(sessionID, userID uuid.UUID, seats int) (*model.SessionAttendee, error)
+	CancelRegistration(sessionID, userID uuid.UUID) error
+	CancelSession(sessionID uuid.UUID) error
+	MarkAttendance(sessionID, userID uuid.UUID) error
+	// AuthorizeExpert lets only the account owning the expert profile, or
+	// an admin, manage the expert's sessions
+	AuthorizeExpert(expertID, userID uuid.UUID, userRole string) error
+}
+
+type SessionService struct {
+	sessionRepo     repository.SessionRepositoryInterface
+	conflictChecker ConflictCheckerInterface
+	expertClient    client.ExpertClientInterface
+	expertFallback  string
+	publisher       events.PublisherInterface
+	logger          logger.LoggerInterface
+}
+
+func NewSessionService(
+	sessionRepo repository.SessionRepositoryInterface,
+	conflictChecker ConflictCheckerInterface,
+	expertClient client.ExpertClientInterface,
+	expertFallback string,
+	publisher events.PublisherInterface,
+	logger logger.LoggerInterface,
+) SessionServiceInterface {
+	return &SessionService{
+		sessionRepo:     sessionRepo,
+		conflictChecker: conflictChecker,
+		expertClient:    expertClient,
+		expertFallback:  expertFallback,
+		publisher:       publisher,
+		logger:          logger,
+	}
+}
+
+func (s *SessionService) CreateSession(req *model.CreateSessionRequest, userID uuid.UUID, userRole string) (*model.SessionResponse, error) {
+	if err := s.AuthorizeExpert(req.ExpertID, userID, userRole); err != nil {
+		return nil, err
+	}
+	if req.MeetingType == string(model.TypeOffline) && req.MeetingAddress == nil {
+		return nil, model.ErrInvalidBooking.Wrap(i18n.NewError("meeting_address_required"))
+	}
+	if req.MeetingType == string(model.TypeOnline) && req.MeetingURL == nil {
+		return nil, model.ErrInvalidBooking.Wrap(i18n.NewError("meeting_url_required"))
+	}
+
+	endTime := req.ScheduledTime.Add(time.Duration(req.DurationMinutes) * time.Minute)
+	if err := s.conflictChecker.ValidateTimeSlot(req.ScheduledTime, endTime); err != nil {
+		return nil, model.ErrInvalidBooking.Wrap(err)
+	}
+
+	// Validate expert, working hours and off-times with expert-service
+	err := s.expertClient.CheckBookingWindow(context.Background(), req.ExpertID, req.ScheduledTime, req.DurationMinutes)
+	if err != nil {
+		if !errors.Is(err, client.ErrExpertServiceUnavailable) || s.expertFallback != ExpertCheckFallbackAllow {
+			return nil, err
+		}
+		s.logger.Warn(fmt.Sprintf("Expert service unavailable, creating session for expert %s without verification: %v", req.ExpertID, err))
+	}
+
+	available, err := s.conflictChecker.CheckExpertAvailability(req.ExpertID, req.ScheduledTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check expert availability: %v", err)
+	}
+	if !available {
+		return nil, model.ErrTimeSlotTaken
+	}
+
+	session := &model.Session{
+		ExpertID:        req.ExpertID,
+		Title:           req.Title,
+		Description:     req.Description,
+		ScheduledTime:   req.ScheduledTime,
+		DurationMinutes: req.DurationMinutes,
+		MeetingType:     model.BookingType(req.MeetingType),
+		MeetingURL:      derefString(req.MeetingURL),
+		MeetingAddress:  derefString(req.MeetingAddress),
+		Capacity:        req.Capacity,
+		PricePerSeat:    req.PricePerSeat,
+		Status:          model.SessionStatusScheduled,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+
+	createdSession, err := s.sessionRepo.Create(session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %v", err)
+	}
+
+	s.expertCalendarChanged(createdSession, events.TypeSessionCreated)
+
+	return s.convertToSessionResponse(createdSession)
+}
+
+func (s *SessionService) GetSession(sessionID uuid.UUID) (*model.SessionResponse, error) {
+	session, err := s.sessionRepo.GetByID(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.convertToSessionResponse(session)
+}
+
+func (s *SessionService) GetExpertSessions(expertID uuid.UUID, includePast bool) ([]model.SessionResponse, error) {
+	sessions, err := s.sessionRepo.GetByExpertID(expertID, includePast)
+	if err != nil {
+		return nil, err
+	}
+	return s.convertToSessionResponses(sessions)
+}
+
+func (s *SessionService) GetUpcomingSessions(limit int) ([]model.SessionResponse, error) {
+	sessions, err := s.sessionRepo.GetUpcoming(limit)
+	if err != nil {
+		return nil, err
+	}
+	return s.convertToSessionResponses(sessions)
+}
+
+func (s *SessionService) GetUserSessions(userID uuid.UUID) ([]model.SessionResponse, error) {
+	sessions, err := s.sessionRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.convertToSessionResponses(sessions)
+}
+
+func (s *SessionService) GetAttendees(sessionID uuid.UUID) ([]model.SessionAttendee, error) {
+	if _, err := s.sessionRepo.GetByID(sessionID); err != nil {
+		return nil, err
+	}
+	return s.sessionRepo.GetAttendees(sessionID)
+}
+
+// RegisterForSession reserves seats for the user, or puts the user on the
+// waitlist when the session is full. Each attendee must be free for the
+// whole session; the expert's availability was checked when the session was created.
+func (s *SessionService) RegisterForSession(sessionID, userID uuid.UUID, seats int) (*model.SessionAttendee, error) {
+	if seats <= 0 {
+		seats = 1
+	}
+
+	session, err := s.sessionRepo.GetByID(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if !session.IsOpen() {
+		return nil, model.ErrSessionNotOpen
+	}
+
+	hasConflict, err := s.conflictChecker.CheckUserConflict(userID, session.ScheduledTime, session.GetEndTime())
+	if err != nil {
+		return nil, fmt.Errorf("failed to check user conflict: %v", err)
+	}
+	if hasConflict {
+		return nil, model.ErrTimeSlotTaken
+	}
+
+	attendee, err := s.sessionRepo.ReserveSeats(sessionID, userID, seats)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info(fmt.Sprintf("User %s %s for session %s (%d seats)", userID, attendee.Status, sessionID, seats))
+	return attendee, nil
+}
+
+func (s *SessionService) CancelRegistration(sessionID, userID uuid.UUID) error {
+	_, promoted, err := s.sessionRepo.CancelReservation(sessionID, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, attendee := range promoted {
+		// TODO: Notify promoted attendees
+		s.logger.Info(fmt.Sprintf("User %s promoted from waitlist for session %s", attendee.UserID, sessionID))
+	}
+	return nil
+}
+
+func (s *SessionService) CancelSession(sessionID uuid.UUID) error {
+	session, err := s.sessionRepo.GetByID(sessionID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.sessionRepo.CancelSession(sessionID); err != nil {
+		return err
+	}
+
+	s.expertCalendarChanged(session, events.TypeSessionCancelled)
+	return nil
+}
+
+func (s *SessionService) MarkAttendance(sessionID, userID uuid.UUID) error {
+	session, err := s.sessionRepo.GetByID(sessionID)
+	if err != nil {
+		return err
+	}
+	if session.Status == model.SessionStatusCancelled {
+		return model.ErrSessionAlreadyClosed
+	}
+	return s.sessionRepo.MarkAttended(sessionID, userID)
+}
+
+// AuthorizeExpert lets through only the owning expert or an admin
+func (s *SessionService) AuthorizeExpert(expertID, userID uuid.UUID, userRole string) error {
+	if userRole == "admin" {
+		return nil
+	}
+	ownerID, err := s.sessionRepo.GetExpertUserID(expertID)
+	if err != nil {
+		return err
+	}
+	if ownerID != userID {
+		return model.ErrSessionAccessDenied
+	}
+	return nil
+}
+
+// expertCalendarChanged drops cached busy slots and tells expert-service
+func (s *SessionService) expertCalendarChanged(session *model.Session, eventType string) {
+	if err := s.conflictChecker.ClearExpertCache(session.ExpertID); err != nil {
+		s.logger.Error("Failed to clear expert cache", err)
+	}
+	s.publisher.PublishBookingEvent(eventType, session.ExpertID, session.ID)
+}
+
+func (s *SessionService) convertToSessionResponse(session *model.Session) (*model.SessionResponse, error) {
+	taken, waitlisted, err := s.sessionRepo.GetSeatCounts(session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	available := session.Capacity - taken
+	if available < 0 {
+		available = 0
+	}
+	return &model.SessionResponse{
+		Session:        session,
+		SeatsTaken:     taken,
+		SeatsAvailable: available,
+		WaitlistCount:  waitlisted,
+		IsFull:         available == 0,
+	}, nil
+}
+
+func (s *SessionService) convertToSessionResponses(sessions []model.Session) ([]model.SessionResponse, error) {
+	responses := make([]model.SessionResponse, len(sessions))
+	for i := range sessions {
+		response, err := s.convertToSessionResponse(&sessions[i])
+		if err != nil {
+			return nil, err
+		}
+		responses[i] = *response
+	}
+	return responses, nil
+}
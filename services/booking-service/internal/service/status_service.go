@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"time"
 
+	"booking-system/shared/pkg/i18n"
 	"github.com/google/uuid"
 
+	"services/booking-service/internal/events"
 	"services/booking-service/internal/model"
 	"services/booking-service/internal/repository"
 	"services/booking-service/pkg/logger"
@@ -21,17 +23,23 @@ type StatusServiceInterface interface {
 type StatusService struct {
 	statusHistoryRepo repository.StatusHistoryRepositoryInterface
 	bookingRepo       repository.BookingRepositoryInterface
+	publisher         events.PublisherInterface
+	notifications     NotificationServiceInterface
 	logger            logger.LoggerInterface
 }
 
 func NewStatusService(
 	statusHistoryRepo repository.StatusHistoryRepositoryInterface,
 	bookingRepo repository.BookingRepositoryInterface,
+	publisher events.PublisherInterface,
+	notifications NotificationServiceInterface,
 	logger logger.LoggerInterface,
 ) StatusServiceInterface {
 	return &StatusService{
 		statusHistoryRepo: statusHistoryRepo,
 		bookingRepo:       bookingRepo,
+		publisher:         publisher,
+		notifications:     notifications,
 		logger:            logger,
 	}
 }
@@ -40,7 +48,7 @@ func (s *StatusService) UpdateBookingStatus(bookingID uuid.UUID, status model.Bo
 	// Get current booking
 	booking, err := s.bookingRepo.GetByID(bookingID)
 	if err != nil {
-		return fmt.Errorf("booking not found")
+		return err
 	}
 
 	// Check authorization
@@ -76,6 +84,9 @@ func (s *StatusService) UpdateBookingStatus(bookingID uuid.UUID, status model.Bo
 		// Don't return error as the main update succeeded
 	}
 
+	s.publisher.PublishBookingEvent(events.TypeBookingStatusChanged, booking.ExpertID, bookingID)
+	s.notifications.BookingStatusChanged(booking, changedBy)
+
 	s.logger.Info(fmt.Sprintf("Booking %s status updated to %s by user %s", bookingID, status, changedBy))
 
 	return nil
@@ -84,7 +95,7 @@ func (s *StatusService) UpdateBookingStatus(bookingID uuid.UUID, status model.Bo
 func (s *StatusService) GetBookingStatus(bookingID uuid.UUID) (model.BookingStatus, error) {
 	booking, err := s.bookingRepo.GetByID(bookingID)
 	if err != nil {
-		return "", fmt.Errorf("booking not found")
+		return "", err
 	}
 
 	return booking.Status, nil
@@ -94,14 +105,14 @@ func (s *StatusService) GetStatusHistory(bookingID uuid.UUID, userID uuid.UUID,
 	// Get booking to check authorization
 	booking, err := s.bookingRepo.GetByID(bookingID)
 	if err != nil {
-		return nil, fmt.Errorf("booking not found")
+		return nil, err
 	}
 
 	// Check authorization
 	if userRole != "admin" &&
 		booking.UserID != userID &&
 		booking.ExpertID != userID {
-		return nil, fmt.Errorf("access denied")
+		return nil, model.ErrBookingAccessDenied
 	}
 
 	// Get status history
@@ -141,7 +152,7 @@ func (s *StatusService) ValidateStatusTransition(currentStatus, newStatus model.
 		}
 	}
 
-	return fmt.Errorf("invalid status transition from %s to %s", currentStatus, newStatus)
+	return model.ErrInvalidStatusTransition.Wrap(i18n.NewError("status_transition", currentStatus, newStatus))
 }
 
 // checkStatusUpdateAuthorization checks if the user is authorized to update the booking status.
@@ -152,5 +163,5 @@ func (s *StatusService) checkStatusUpdateAuthorization(booking *model.Booking, u
 	if booking.UserID == userID || booking.ExpertID == userID {
 		return nil
 	}
-	return fmt.Errorf("user is not authorized to update this booking status")
+	return model.ErrBookingAccessDenied
 }
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"services/booking-service/internal/model"
+	"services/booking-service/internal/repository"
+)
+
+// VerificationPolicyInterface decides whether a user may create a booking
+// given the state of their email verification
+type VerificationPolicyInterface interface {
+	CheckCanBook(userID uuid.UUID) error
+}
+
+type VerificationPolicy struct {
+	bookingRepo repository.BookingRepositoryInterface
+	maxPending  int64
+}
+
+// NewVerificationPolicy lets users who have not verified their email hold at
+// most maxPending pending bookings
+func NewVerificationPolicy(bookingRepo repository.BookingRepositoryInterface, maxPending int) VerificationPolicyInterface {
+	if maxPending < 0 {
+		maxPending = 0
+	}
+	return &VerificationPolicy{
+		bookingRepo: bookingRepo,
+		maxPending:  int64(maxPending),
+	}
+}
+
+func (p *VerificationPolicy) CheckCanBook(userID uuid.UUID) error {
+	verified, err := p.bookingRepo.IsUserEmailVerified(userID)
+	if err != nil {
+		return fmt.Errorf("failed to check email verification: %v", err)
+	}
+	if verified {
+		return nil
+	}
+
+	pending, err := p.bookingRepo.CountPendingByUser(userID)
+	if err != nil {
+		return fmt.Errorf("failed to count pending bookings: %v", err)
+	}
+	if pending >= p.maxPending {
+		return model.ErrEmailNotVerified
+	}
+	return nil
+}
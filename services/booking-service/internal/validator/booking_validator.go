@@ -35,22 +35,21 @@ func NewBookingValidator() *BookingValidator {
 	}
 }
 
-// ValidateCreateBooking validate request tạo booking
-func (bv *BookingValidator) ValidateCreateBooking(req *model.CreateBookingRequest) error {
+// ValidateCreateBooking validate request tạo booking theo dịch vụ đã chọn
+func (bv *BookingValidator) ValidateCreateBooking(req *model.CreateBookingRequest, durationMinutes int, meetingType model.BookingType) error {
 	if err := bv.validator.Struct(req); err != nil {
 		return bv.formatValidationError(err)
 	}
 
-	// Calculate end time from scheduled time and duration
-	endTime := req.ScheduledTime.Add(time.Duration(req.DurationMinutes) * time.Minute)
+	// Calculate end time from scheduled time and the service duration
+	endTime := req.ScheduledTime.Add(time.Duration(durationMinutes) * time.Minute)
 
 	// Custom validations
 	if err := bv.validateBookingTimeRange(req.ScheduledTime, endTime); err != nil {
 		return err
 	}
 
-	// Convert string to BookingType and handle nil pointers
-	meetingType := model.BookingType(req.MeetingType)
+	// Handle nil pointers
 	var meetingAddress, meetingURL string
 	if req.MeetingAddress != nil {
 		meetingAddress = *req.MeetingAddress
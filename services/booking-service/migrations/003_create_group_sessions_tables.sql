@@ -0,0 +1,43 @@
+-- Group sessions: one expert, many attendees, seats sold per attendee
+CREATE TABLE IF NOT EXISTS group_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    expert_id UUID NOT NULL REFERENCES experts(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    scheduled_datetime TIMESTAMP WITH TIME ZONE NOT NULL,
+    duration_minutes INTEGER NOT NULL DEFAULT 60 CHECK (duration_minutes BETWEEN 15 AND 480),
+    meeting_type VARCHAR(20) NOT NULL DEFAULT 'online' CHECK (meeting_type IN ('online', 'offline')),
+    meeting_url TEXT,
+    meeting_address TEXT,
+    capacity INTEGER NOT NULL CHECK (capacity > 1),
+    price_per_seat DECIMAL(10,2) NOT NULL DEFAULT 0 CHECK (price_per_seat >= 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'scheduled' CHECK (status IN ('scheduled', 'cancelled', 'completed')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    cancelled_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_group_sessions_expert_time ON group_sessions(expert_id, scheduled_datetime);
+CREATE INDEX idx_group_sessions_status_time ON group_sessions(status, scheduled_datetime);
+
+-- Seat reservations; waitlisted rows hold no seats until promoted
+CREATE TABLE IF NOT EXISTS group_session_attendees (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL REFERENCES group_sessions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    seats INTEGER NOT NULL DEFAULT 1 CHECK (seats > 0),
+    price DECIMAL(10,2) NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'registered' CHECK (status IN ('registered', 'waitlisted', 'cancelled', 'attended')),
+    registered_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    cancelled_at TIMESTAMP WITH TIME ZONE,
+    attended_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A user holds at most one active reservation per session
+CREATE UNIQUE INDEX idx_group_session_attendees_active
+    ON group_session_attendees(session_id, user_id)
+    WHERE status IN ('registered', 'waitlisted');
+CREATE INDEX idx_group_session_attendees_user ON group_session_attendees(user_id, status);
+CREATE INDEX idx_group_session_attendees_waitlist ON group_session_attendees(session_id, status, registered_at);
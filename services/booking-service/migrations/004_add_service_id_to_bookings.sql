@@ -0,0 +1,6 @@
+-- Bookings reference the expert service they were made for; duration,
+-- price and meeting type are copied from it at booking time
+ALTER TABLE bookings
+    ADD COLUMN IF NOT EXISTS service_id UUID REFERENCES expert_services(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_service_id ON bookings(service_id);
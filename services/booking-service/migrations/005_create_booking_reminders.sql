@@ -0,0 +1,11 @@
+-- Reminders already sent, one per booking, recipient and offset (minutes
+-- before the start). The start time is part of the key so a rescheduled
+-- booking is reminded again.
+CREATE TABLE IF NOT EXISTS booking_reminders (
+    booking_id UUID NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    offset_minutes INTEGER NOT NULL,
+    scheduled_datetime TIMESTAMP NOT NULL,
+    sent_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (booking_id, user_id, offset_minutes, scheduled_datetime)
+);
@@ -2,49 +2,36 @@ package utils
 
 import (
 	"net/http"
-	"os"
 	"strings"
 
+	"booking-system/shared/pkg/jwks"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
 )
 
-func JWTAuthMiddleware() gin.HandlerFunc {
+// JWTAuthMiddleware verifies access tokens with the public keys user-service
+// publishes and puts the caller's ID, role and saved language in the context
+// as "user_id", "user_role" and "user_lang"
+func JWTAuthMiddleware(verifier *jwks.Verifier) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		header := c.GetHeader("Authorization")
 		if header == "" || !strings.HasPrefix(header, "Bearer ") {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid Authorization header"})
+			RespondError(c, http.StatusUnauthorized, "missing_bearer_token")
 			return
 		}
-		tokenString := strings.TrimPrefix(header, "Bearer ")
-		secret := os.Getenv("JWT_SECRET")
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			return []byte(secret), nil
-		})
-		if err != nil || !token.Valid {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-			return
-		}
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+		claims, err := verifier.Verify(strings.TrimPrefix(header, "Bearer "))
+		if err != nil {
+			RespondError(c, http.StatusUnauthorized, "invalid_token")
 			return
 		}
-		userIDStr, ok := claims["user_id"].(string)
-		if !ok {
-			userIDStr, ok = claims["sub"].(string)
-			if !ok {
-				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "user_id missing in token"})
-				return
-			}
-		}
-		userID, err := uuid.Parse(userIDStr)
+		userID, err := uuid.Parse(claims.Subject)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "user_id invalid format"})
+			RespondError(c, http.StatusUnauthorized, "invalid_token")
 			return
 		}
 		c.Set("user_id", userID)
+		c.Set("user_role", claims.Role)
+		c.Set("user_lang", claims.Language)
 		c.Next()
 	}
 }
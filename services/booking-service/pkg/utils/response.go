@@ -2,10 +2,26 @@ package utils
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+
+	"booking-system/shared/pkg/apperr"
+	"booking-system/shared/pkg/i18n"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"services/booking-service/pkg/logger"
 )
 
-// Response represents a standard API response
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(apperr.JSONFieldName)
+	}
+}
+
+// Response represents a standard API response. Errors are answered with an
+// apperr.Problem instead.
 type Response struct {
 	Success bool        `json:"success"`
 	Message string      `json:"message"`
@@ -13,22 +29,63 @@ type Response struct {
 	Error   string      `json:"error,omitempty"`
 }
 
-// SuccessResponse creates a success response
-func SuccessResponse(message string, data interface{}) Response {
+// Language returns the language of the response: the caller's saved
+// preference from the access token, otherwise Accept-Language
+func Language(c *gin.Context) i18n.Lang {
+	return i18n.Resolve(c.GetString("user_lang"), c.GetHeader("Accept-Language"))
+}
+
+// SuccessResponse creates a success response with the message code in the
+// caller's language
+func SuccessResponse(c *gin.Context, code string, data interface{}) Response {
 	return Response{
 		Success: true,
-		Message: message,
+		Message: i18n.Message(Language(c), code),
 		Data:    data,
 	}
 }
 
-// ErrorResponse creates an error response
-func ErrorResponse(message string) Response {
-	return Response{
-		Success: false,
-		Message: message,
-		Error:   message,
+// RespondError answers with the message code as a problem of the kind that
+// goes with statusCode
+func RespondError(c *gin.Context, statusCode int, code string, args ...interface{}) {
+	RespondErr(c, apperr.New(apperr.KindFor(statusCode), code, args...))
+}
+
+// RespondErr answers with the problem err describes and stops the chain.
+// Errors that are not domain errors get a generic message; log them first.
+func RespondErr(c *gin.Context, err error) {
+	problem := apperr.NewProblem(Language(c), err)
+	problem.Instance = c.Request.URL.Path
+	apperr.Write(c.Writer, problem)
+	c.Abort()
+}
+
+// RespondFailed answers with the problem err describes when it is a domain
+// error. Other errors are logged and answered with fallback, the code of a
+// message saying what failed.
+func RespondFailed(c *gin.Context, log logger.LoggerInterface, err error, fallback string) {
+	var domain *apperr.Error
+	if errors.As(err, &domain) {
+		RespondErr(c, err)
+		return
+	}
+	log.Error(i18n.Message(i18n.Default, fallback), err)
+	RespondErr(c, apperr.New(apperr.KindInternal, fallback))
+}
+
+// RespondInvalid answers for a request body or query that failed binding,
+// listing the rejected fields when validation rules were broken
+func RespondInvalid(c *gin.Context, err error) {
+	var broken validator.ValidationErrors
+	if !errors.As(err, &broken) {
+		RespondErr(c, apperr.Validation("invalid_request_format"))
+		return
+	}
+	fields := make([]apperr.FieldError, 0, len(broken))
+	for _, fe := range broken {
+		fields = append(fields, apperr.FieldError{Field: fe.Field(), Rule: fe.Tag(), Param: fe.Param()})
 	}
+	RespondErr(c, apperr.Validation("invalid_fields").WithFields(fields...))
 }
 
 // JSONResponse sends a JSON response
@@ -5,14 +5,21 @@ import (
 	"database/sql"
 	"log"
 	"os"
+	"strconv"
 	"time"
 
 	"expert-service/internal/cache"
+	"expert-service/internal/events"
 	"expert-service/internal/handler"
+	"expert-service/internal/middleware"
 	"expert-service/internal/repository"
 	"expert-service/internal/routes"
 	"expert-service/internal/service"
 
+	"booking-system/shared/pkg/jwks"
+	"booking-system/shared/pkg/mailer"
+	"booking-system/shared/pkg/notify"
+	"booking-system/shared/pkg/storage"
 	"github.com/gin-gonic/gin"
 	_ "github.com/lib/pq"
 	"github.com/redis/go-redis/v9"
@@ -51,25 +58,61 @@ func main() {
 		log.Fatalf("Failed to connect to Redis: %v", err)
 	}
 	availabilityCache := cache.NewAvailabilityCache(redisClient, time.Hour)
+	publisher := events.NewPublisher(redisClient)
+
+	// Drop cached slots when bookings or calendars change
+	invalidator := events.NewCacheInvalidator(redisClient, availabilityCache)
+	go invalidator.Run(ctx)
 
 	// Repository & Service
 	expertRepo := repository.NewExpertRepository(db)
 	scheduleRepo := repository.NewScheduleRepository(db)
 	offTimeRepo := repository.NewOffTimeRepository(db)
+	bookingRepo := repository.NewBookingRepository(db)
+	bookingRuleRepo := repository.NewBookingRuleRepository(db)
+	consultationServiceRepo := repository.NewConsultationServiceRepository(db)
+	reviewRepo := repository.NewReviewRepository(db)
+	availabilityRepo := repository.NewAvailabilityRepository(db)
+	availabilityRuleRepo := repository.NewAvailabilityRuleRepository(db)
+	holidayRepo := repository.NewHolidayRepository(db)
+	applicationRepo := repository.NewExpertApplicationRepository(db)
+	certificationRepo := repository.NewCertificationRepository(db)
 	expertSvc := service.NewExpertService(expertRepo)
-	scheduleSvc := service.NewScheduleService(scheduleRepo)
-	availabilitySvc := service.NewExpertAvailabilityService(expertRepo, scheduleRepo, offTimeRepo, availabilityCache)
+	consultationServiceSvc := service.NewConsultationServiceService(expertRepo, consultationServiceRepo)
+	scheduleSvc := service.NewScheduleService(scheduleRepo, publisher)
+	holidaySvc := service.NewHolidayService(holidayRepo, expertRepo, availabilityCache, publisher, defaultHolidayCalendar())
+	if err := holidaySvc.SeedBundledCalendars(); err != nil {
+		log.Printf("Failed to seed holiday calendars: %v", err)
+	}
+	availabilitySvc := service.NewExpertAvailabilityService(expertRepo, scheduleRepo, offTimeRepo, availabilityRepo, availabilityRuleRepo, holidaySvc, availabilityCache, publisher)
+	reviewSvc := service.NewReviewService(reviewRepo, bookingRepo, expertRepo, reviewEditWindow())
+	slotSvc := service.NewSlotService(expertRepo, scheduleRepo, offTimeRepo, bookingRepo, bookingRuleRepo, availabilitySvc, holidaySvc, availabilityCache, publisher)
+
+	searchSvc := service.NewExpertSearchService(expertRepo, slotSvc)
+	onboardingSvc := service.NewOnboardingService(applicationRepo, expertRepo)
+	certificationSvc := service.NewCertificationService(certificationRepo, expertRepo, fileStore(),
+		notify.NewNotifier(db, notify.SendersFromEnv(mailer.FromEnv())), certificationWarningPeriod())
+	privacySvc := service.NewPrivacyService(reviewRepo, applicationRepo, expertRepo)
+	go runDaily(ctx, "certification expiry check", certificationSvc.CheckExpiries)
 
 	// Handler
-	expertHandler := handler.NewExpertHandler(expertSvc)
+	expertHandler := handler.NewExpertHandler(expertSvc, searchSvc)
 	scheduleHandler := handler.NewScheduleHandler(scheduleSvc)
 	availabilityHandler := handler.NewAvailabilityHandler(availabilitySvc)
+	slotHandler := handler.NewSlotHandler(slotSvc)
+	consultationServiceHandler := handler.NewConsultationServiceHandler(consultationServiceSvc)
+	reviewHandler := handler.NewReviewHandler(reviewSvc)
+	holidayHandler := handler.NewHolidayHandler(holidaySvc)
+	onboardingHandler := handler.NewOnboardingHandler(onboardingSvc)
+	certificationHandler := handler.NewCertificationHandler(certificationSvc)
+	privacyHandler := handler.NewPrivacyHandler(privacySvc)
 
 	// Router
 	router := gin.Default()
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
-	routes.SetupRoutes(router, expertHandler, scheduleHandler, availabilityHandler)
+	routes.SetupRoutes(router, expertHandler, scheduleHandler, availabilityHandler, slotHandler, consultationServiceHandler,
+		reviewHandler, holidayHandler, onboardingHandler, certificationHandler, privacyHandler, middleware.AuthMiddleware(tokenVerifier()), expertRepo)
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -77,3 +120,74 @@ func main() {
 	}
 	router.Run(":" + port)
 }
+
+// tokenVerifier checks access tokens against the keys user-service publishes
+// (JWKS_URL, default http://localhost:8080/.well-known/jwks.json)
+func tokenVerifier() *jwks.Verifier {
+	url := os.Getenv("JWKS_URL")
+	if url == "" {
+		url = "http://localhost:8080/.well-known/jwks.json"
+	}
+	issuer := os.Getenv("JWT_ISSUER")
+	if issuer == "" {
+		issuer = "user-service"
+	}
+	return jwks.NewVerifier(jwks.VerifierConfig{URL: url, Issuer: issuer})
+}
+
+// reviewEditWindow reads how long authors may edit a review (REVIEW_EDIT_WINDOW_HOURS, default 72)
+func reviewEditWindow() time.Duration {
+	hours, err := strconv.Atoi(os.Getenv("REVIEW_EDIT_WINDOW_HOURS"))
+	if err != nil || hours <= 0 {
+		hours = 72
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// fileStore keeps uploaded files below FILE_STORAGE_DIR (default ./uploads)
+func fileStore() storage.FileStore {
+	dir := os.Getenv("FILE_STORAGE_DIR")
+	if dir == "" {
+		dir = "./uploads"
+	}
+	return storage.NewLocalFileStore(dir)
+}
+
+// certificationWarningPeriod reads how long before expiry experts are warned
+// about a certification (CERTIFICATION_EXPIRY_WARNING_DAYS, default 30)
+func certificationWarningPeriod() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("CERTIFICATION_EXPIRY_WARNING_DAYS"))
+	if err != nil || days <= 0 {
+		days = 30
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// runDaily runs job now and then once a day until ctx is done
+func runDaily(ctx context.Context, name string, job func() error) {
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+	for {
+		if err := job(); err != nil {
+			log.Printf("%s failed: %v", name, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// defaultHolidayCalendar is the calendar observed by experts who never chose
+// one (DEFAULT_HOLIDAY_CALENDAR, default VN; "none" disables it)
+func defaultHolidayCalendar() string {
+	code, ok := os.LookupEnv("DEFAULT_HOLIDAY_CALENDAR")
+	if !ok {
+		return "VN"
+	}
+	if code == "none" {
+		return ""
+	}
+	return code
+}
@@ -13,6 +13,8 @@ type AvailabilityCache interface {
 	SetAvailability(key string, value []byte) error
 	GetAvailability(key string) ([]byte, error)
 	InvalidateExpert(expertID string) error
+	InvalidateComputed(expertID string) error
+	InvalidateAvailabilityDays(expertID string, dates ...string) error
 }
 
 type availabilityCache struct {
@@ -43,31 +45,63 @@ func (c *availabilityCache) GetAvailability(key string) ([]byte, error) {
 }
 
 func (c *availabilityCache) InvalidateExpert(expertID string) error {
-	// Invalidate availability check cache keys (e.g., "expertID:date")
-	pattern1 := fmt.Sprintf("%s:*", expertID)
-	keys1, err := c.client.Keys(context.Background(), pattern1).Result()
-	if err != nil {
-		return fmt.Errorf("failed to get keys for pattern %s: %w", pattern1, err)
-	}
-	if len(keys1) > 0 {
-		if err := c.client.Del(context.Background(), keys1...).Err(); err != nil {
-			return fmt.Errorf("failed to delete keys for pattern %s: %w", pattern1, err)
+	return c.deleteByPatterns(
+		fmt.Sprintf("%s:*", expertID),     // availability check keys, e.g. "expertID:date"
+		AvailabilityDayKey(expertID, "*"), // cached availability slots, e.g. "availability:expertID:date"
+		SlotKeyPattern(expertID),          // computed bookable slots
+	)
+}
+
+// InvalidateComputed drops only results derived from the calendar (availability
+// checks and computed slots). Cached availability days only change when an
+// availability row changes, and those writes drop them directly.
+func (c *availabilityCache) InvalidateComputed(expertID string) error {
+	return c.deleteByPatterns(
+		fmt.Sprintf("%s:*", expertID),
+		SlotKeyPattern(expertID),
+	)
+}
+
+func (c *availabilityCache) deleteByPatterns(patterns ...string) error {
+	for _, pattern := range patterns {
+		keys, err := c.client.Keys(context.Background(), pattern).Result()
+		if err != nil {
+			return fmt.Errorf("failed to get keys for pattern %s: %w", pattern, err)
+		}
+		if len(keys) > 0 {
+			if err := c.client.Del(context.Background(), keys...).Err(); err != nil {
+				return fmt.Errorf("failed to delete keys for pattern %s: %w", pattern, err)
+			}
 		}
 	}
+	return nil
+}
 
-	// Invalidate availability slot keys (e.g., "availability:expertID:date")
-	pattern2 := fmt.Sprintf("availability:%s:*", expertID)
-	keys2, err := c.client.Keys(context.Background(), pattern2).Result()
-	if err != nil {
-		return fmt.Errorf("failed to get keys for pattern %s: %w", pattern2, err)
+// InvalidateAvailabilityDays drops the cached availability slots of the given days
+func (c *availabilityCache) InvalidateAvailabilityDays(expertID string, dates ...string) error {
+	if len(dates) == 0 {
+		return nil
 	}
-	if len(keys2) > 0 {
-		if err := c.client.Del(context.Background(), keys2...).Err(); err != nil {
-			return fmt.Errorf("failed to delete keys for pattern %s: %w", pattern2, err)
-		}
+	keys := make([]string, len(dates))
+	for i, date := range dates {
+		keys[i] = AvailabilityDayKey(expertID, date)
 	}
+	return c.client.Del(context.Background(), keys...).Err()
+}
 
-	return nil
+// AvailabilityDayKey builds the cache key for the availability slots of one day
+func AvailabilityDayKey(expertID, date string) string {
+	return fmt.Sprintf("availability:%s:%s", expertID, date)
+}
+
+// SlotKey builds the cache key for a slot computation
+func SlotKey(expertID, startDate, endDate string, duration, granularity int) string {
+	return fmt.Sprintf("slots:%s:%s:%s:%d:%d", expertID, startDate, endDate, duration, granularity)
+}
+
+// SlotKeyPattern matches every cached slot computation of an expert
+func SlotKeyPattern(expertID string) string {
+	return fmt.Sprintf("slots:%s:*", expertID)
 }
 
 func (c *availabilityCache) SetAvailabilityRedis(expertID int, date string, isAvailable bool) error {
@@ -0,0 +1,29 @@
+package events
+
+import "time"
+
+// Redis pub/sub channels. booking-service publishes on BookingChannel,
+// expert-service publishes its own calendar changes on AvailabilityChannel.
+const (
+	BookingChannel      = "booking.events"
+	AvailabilityChannel = "expert.availability.events"
+)
+
+// Event types published on AvailabilityChannel
+const (
+	TypeOffTimeCreated      = "off_time.created"
+	TypeOffTimeDeleted      = "off_time.deleted"
+	TypeAvailabilityChanged = "availability.changed"
+	TypeScheduleChanged     = "schedule.changed"
+	TypeBookingRuleChanged  = "booking_rule.changed"
+	TypeHolidaysChanged     = "holidays.changed"
+)
+
+// Event is the payload carried on both channels. Consumers only rely on
+// ExpertID; the other fields are informational.
+type Event struct {
+	Type       string    `json:"type"`
+	ExpertID   string    `json:"expert_id"`
+	BookingID  string    `json:"booking_id,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
@@ -0,0 +1,39 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type Publisher interface {
+	Publish(eventType, expertID string)
+}
+
+type redisPublisher struct {
+	client *redis.Client
+}
+
+func NewPublisher(client *redis.Client) Publisher {
+	return &redisPublisher{client: client}
+}
+
+// Publish sends an availability event. Failures are logged and swallowed:
+// the cache TTL bounds how stale slots can get if an event is lost.
+func (p *redisPublisher) Publish(eventType, expertID string) {
+	data, err := json.Marshal(Event{
+		Type:       eventType,
+		ExpertID:   expertID,
+		OccurredAt: time.Now(),
+	})
+	if err != nil {
+		log.Printf("failed to marshal %s event: %v", eventType, err)
+		return
+	}
+	if err := p.client.Publish(context.Background(), AvailabilityChannel, data).Err(); err != nil {
+		log.Printf("failed to publish %s event for expert %s: %v", eventType, expertID, err)
+	}
+}
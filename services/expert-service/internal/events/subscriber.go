@@ -0,0 +1,51 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"expert-service/internal/cache"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// CacheInvalidator drops an expert's cached availability checks and slots
+// whenever a booking or calendar event for that expert is received.
+type CacheInvalidator struct {
+	client *redis.Client
+	cache  cache.AvailabilityCache
+}
+
+func NewCacheInvalidator(client *redis.Client, cache cache.AvailabilityCache) *CacheInvalidator {
+	return &CacheInvalidator{client: client, cache: cache}
+}
+
+// Run blocks until ctx is cancelled
+func (s *CacheInvalidator) Run(ctx context.Context) {
+	pubsub := s.client.Subscribe(ctx, BookingChannel, AvailabilityChannel)
+	defer pubsub.Close()
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			var event Event
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				log.Printf("ignoring malformed event on %s: %v", msg.Channel, err)
+				continue
+			}
+			if event.ExpertID == "" {
+				continue
+			}
+			if err := s.cache.InvalidateComputed(event.ExpertID); err != nil {
+				log.Printf("failed to invalidate cache for expert %s: %v", event.ExpertID, err)
+			}
+		}
+	}
+}
@@ -1,13 +1,14 @@
 package handler
 
 import (
-	"encoding/json"
 	"expert-service/internal/model"
 	"expert-service/internal/service"
 	"net/http"
 	"time"
 
+	"booking-system/shared/pkg/apperr"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 // AvailabilityHandler handles availability-related requests
@@ -22,24 +23,9 @@ func NewAvailabilityHandler(availabilityService service.ExpertAvailabilityServic
 	}
 }
 
-// ErrorResponse represents an error response
-type ErrorResponse struct {
-	Message string `json:"message"`
-}
-
-// respondWithError sends an error response
-func respondWithError(w http.ResponseWriter, code int, message string) {
-	respondWithJSON(w, code, ErrorResponse{Message: message})
-}
-
-// respondWithJSON sends a JSON response
-func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
-	if payload != nil {
-		json.NewEncoder(w).Encode(payload)
-	}
-}
+// ErrorResponse is the body of error responses, an application/problem+json
+// document
+type ErrorResponse = apperr.Problem
 
 // Availability represents expert availability
 type Availability struct {
@@ -84,13 +70,13 @@ type UpdateAvailabilityRequest struct {
 func (h *AvailabilityHandler) CreateAvailability(c *gin.Context) {
 	var req model.CreateAvailabilityRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "Invalid request body"})
+		respondInvalid(c, err)
 		return
 	}
 
 	availability, err := h.availabilityService.CreateAvailability(&req)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: err.Error()})
+		respondErr(c, err)
 		return
 	}
 
@@ -114,29 +100,29 @@ func (h *AvailabilityHandler) CreateAvailability(c *gin.Context) {
 func (h *AvailabilityHandler) GetAvailabilities(c *gin.Context) {
 	expertID := c.Query("expert_id")
 	if expertID == "" {
-		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "Expert ID is required"})
+		respondError(c, http.StatusBadRequest, "expert_id_required")
 		return
 	}
 
 	startDateStr := c.Query("start_date")
 	if startDateStr == "" {
-		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "Start date is required"})
+		respondError(c, http.StatusBadRequest, "start_date_required")
 		return
 	}
 	startDate, err := time.Parse("2006-01-02", startDateStr)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "Invalid start date format"})
+		respondError(c, http.StatusBadRequest, "invalid_start_date")
 		return
 	}
 
 	endDateStr := c.Query("end_date")
 	if endDateStr == "" {
-		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "End date is required"})
+		respondError(c, http.StatusBadRequest, "end_date_required")
 		return
 	}
 	endDate, err := time.Parse("2006-01-02", endDateStr)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "Invalid end date format"})
+		respondError(c, http.StatusBadRequest, "invalid_end_date")
 		return
 	}
 
@@ -148,7 +134,7 @@ func (h *AvailabilityHandler) GetAvailabilities(c *gin.Context) {
 
 	availabilities, err := h.availabilityService.GetAvailabilities(expertID, startDate, endDate, isBooked)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: err.Error()})
+		respondErr(c, err)
 		return
 	}
 
@@ -172,11 +158,11 @@ func (h *AvailabilityHandler) GetAvailabilityByID(c *gin.Context) {
 
 	availability, err := h.availabilityService.GetAvailabilityByID(id)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: err.Error()})
+		respondErr(c, err)
 		return
 	}
 	if availability == nil {
-		c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: "Availability not found"})
+		respondError(c, http.StatusNotFound, "availability_not_found")
 		return
 	}
 
@@ -201,17 +187,17 @@ func (h *AvailabilityHandler) UpdateAvailability(c *gin.Context) {
 
 	var req model.UpdateAvailabilityRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "Invalid request body"})
+		respondInvalid(c, err)
 		return
 	}
 
 	availability, err := h.availabilityService.UpdateAvailability(id, &req)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: err.Error()})
+		respondErr(c, err)
 		return
 	}
 	if availability == nil {
-		c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: "Availability not found"})
+		respondError(c, http.StatusNotFound, "availability_not_found")
 		return
 	}
 
@@ -234,7 +220,7 @@ func (h *AvailabilityHandler) DeleteAvailability(c *gin.Context) {
 	id := c.Param("id")
 
 	if err := h.availabilityService.DeleteAvailability(id); err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: err.Error()})
+		respondErr(c, err)
 		return
 	}
 
@@ -243,11 +229,11 @@ func (h *AvailabilityHandler) DeleteAvailability(c *gin.Context) {
 
 // BookAvailability godoc
 // @Summary Book availability
-// @Description Book an availability slot
+// @Description Book an availability slot, or an occurrence of a recurring rule by its "<rule_id>_<date>" ID
 // @Tags availability
 // @Accept json
 // @Produce json
-// @Param id path string true "Availability ID"
+// @Param id path string true "Availability or occurrence ID"
 // @Success 200 {object} model.Availability
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
@@ -257,7 +243,7 @@ func (h *AvailabilityHandler) BookAvailability(c *gin.Context) {
 	id := c.Param("id")
 
 	if err := h.availabilityService.BookAvailability(id); err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: err.Error()})
+		respondErr(c, err)
 		return
 	}
 
@@ -266,12 +252,12 @@ func (h *AvailabilityHandler) BookAvailability(c *gin.Context) {
 
 // CreateRecurringAvailability godoc
 // @Summary Create recurring availability
-// @Description Create multiple availability slots for recurring schedules
+// @Description Create a weekly availability rule; its occurrences are expanded when availability is read
 // @Tags availability
 // @Accept json
 // @Produce json
 // @Param availability body model.CreateRecurringAvailabilityRequest true "Recurring availability details"
-// @Success 201 {array} model.Availability
+// @Success 201 {object} model.AvailabilityRule
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
@@ -279,17 +265,161 @@ func (h *AvailabilityHandler) BookAvailability(c *gin.Context) {
 func (h *AvailabilityHandler) CreateRecurringAvailability(c *gin.Context) {
 	var req model.CreateRecurringAvailabilityRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "Invalid request body"})
+		respondInvalid(c, err)
+		return
+	}
+
+	rule, err := h.availabilityService.CreateRecurringAvailability(&req, c.MustGet("user_id").(uuid.UUID), isAdmin(c))
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// GetAvailabilityRules godoc
+// @Summary Get recurring availability rules
+// @Description Get all recurring availability rules of an expert, past and future
+// @Tags availability
+// @Produce json
+// @Param expert_id query string true "Expert ID"
+// @Success 200 {array} model.AvailabilityRule
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/availability/rules [get]
+func (h *AvailabilityHandler) GetAvailabilityRules(c *gin.Context) {
+	expertID := c.Query("expert_id")
+	if expertID == "" {
+		respondError(c, http.StatusBadRequest, "expert_id_required")
+		return
+	}
+
+	rules, err := h.availabilityService.GetAvailabilityRules(expertID)
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// GetAvailabilityRule godoc
+// @Summary Get a recurring availability rule
+// @Description Get a recurring availability rule with its exceptions
+// @Tags availability
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Success 200 {object} model.AvailabilityRule
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/availability/rules/{id} [get]
+func (h *AvailabilityHandler) GetAvailabilityRule(c *gin.Context) {
+	rule, err := h.availabilityService.GetAvailabilityRule(c.Param("id"))
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// UpdateAvailabilityRule godoc
+// @Summary Update a recurring availability rule
+// @Description Update a rule entirely, or with from_date only from that date forward, keeping past occurrences
+// @Tags availability
+// @Accept json
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Param rule body model.UpdateAvailabilityRuleRequest true "Rule changes"
+// @Success 200 {object} model.AvailabilityRule
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/availability/rules/{id} [put]
+func (h *AvailabilityHandler) UpdateAvailabilityRule(c *gin.Context) {
+	var req model.UpdateAvailabilityRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
 		return
 	}
 
-	availabilities, err := h.availabilityService.CreateRecurringAvailability(&req)
+	rule, err := h.availabilityService.UpdateAvailabilityRule(c.Param("id"), &req, c.MustGet("user_id").(uuid.UUID), isAdmin(c))
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: err.Error()})
+		respondErr(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, availabilities)
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteAvailabilityRule godoc
+// @Summary Delete a recurring availability rule
+// @Description Delete a rule entirely, or with from_date only from that date forward
+// @Tags availability
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Param from_date query string false "First date to remove (YYYY-MM-DD)"
+// @Success 204 "No Content"
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/availability/rules/{id} [delete]
+func (h *AvailabilityHandler) DeleteAvailabilityRule(c *gin.Context) {
+	if err := h.availabilityService.DeleteAvailabilityRule(c.Param("id"), c.Query("from_date"), c.MustGet("user_id").(uuid.UUID), isAdmin(c)); err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AddAvailabilityRuleException godoc
+// @Summary Skip one occurrence of a recurring rule
+// @Description Add a date on which the rule does not apply
+// @Tags availability
+// @Accept json
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Param exception body model.CreateAvailabilityRuleExceptionRequest true "Exception date"
+// @Success 201 {object} model.AvailabilityRule
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/availability/rules/{id}/exceptions [post]
+func (h *AvailabilityHandler) AddAvailabilityRuleException(c *gin.Context) {
+	var req model.CreateAvailabilityRuleExceptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	rule, err := h.availabilityService.AddAvailabilityRuleException(c.Param("id"), &req, c.MustGet("user_id").(uuid.UUID), isAdmin(c))
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// DeleteAvailabilityRuleException godoc
+// @Summary Restore a skipped occurrence
+// @Description Remove an exception date from a recurring rule
+// @Tags availability
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Param date path string true "Exception date (YYYY-MM-DD)"
+// @Success 204 "No Content"
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/availability/rules/{id}/exceptions/{date} [delete]
+func (h *AvailabilityHandler) DeleteAvailabilityRuleException(c *gin.Context) {
+	if err := h.availabilityService.DeleteAvailabilityRuleException(c.Param("id"), c.Param("date"), c.MustGet("user_id").(uuid.UUID), isAdmin(c)); err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
 }
 
 // CheckAvailability godoc
@@ -306,13 +436,13 @@ func (h *AvailabilityHandler) CreateRecurringAvailability(c *gin.Context) {
 func (h *AvailabilityHandler) CheckAvailability(c *gin.Context) {
 	var req model.CheckAvailabilityRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "Invalid request body"})
+		respondInvalid(c, err)
 		return
 	}
 
 	isAvailable, err := h.availabilityService.CheckAvailability(&req)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: err.Error()})
+		respondErr(c, err)
 		return
 	}
 
@@ -333,13 +463,13 @@ func (h *AvailabilityHandler) CheckAvailability(c *gin.Context) {
 func (h *AvailabilityHandler) CreateOffTime(c *gin.Context) {
 	var req model.CreateOffTimeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "Invalid request body"})
+		respondInvalid(c, err)
 		return
 	}
 
-	offTime, err := h.availabilityService.CreateOffTime(&req)
+	offTime, err := h.availabilityService.CreateOffTime(&req, c.MustGet("user_id").(uuid.UUID), isAdmin(c))
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: err.Error()})
+		respondErr(c, err)
 		return
 	}
 
@@ -348,11 +478,14 @@ func (h *AvailabilityHandler) CreateOffTime(c *gin.Context) {
 
 // GetExpertOffTimes godoc
 // @Summary Get off-times for an expert
-// @Description Get all off-time periods for a specific expert
+// @Description Get all off-time periods for a specific expert. With start_date and end_date,
+// @Description list the occurrences in that range instead, with recurring off-times expanded.
 // @Tags availability
 // @Accept json
 // @Produce json
 // @Param expert_id path string true "Expert ID"
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
 // @Success 200 {array} model.OffTime
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
@@ -361,19 +494,91 @@ func (h *AvailabilityHandler) CreateOffTime(c *gin.Context) {
 func (h *AvailabilityHandler) GetExpertOffTimes(c *gin.Context) {
 	expertID := c.Param("expert_id")
 	if expertID == "" {
-		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "Expert ID is required"})
+		respondError(c, http.StatusBadRequest, "expert_id_required")
+		return
+	}
+
+	startDateStr, endDateStr := c.Query("start_date"), c.Query("end_date")
+	if startDateStr == "" && endDateStr == "" {
+		offTimes, err := h.availabilityService.GetExpertOffTimes(expertID)
+		if err != nil {
+			respondErr(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, offTimes)
+		return
+	}
+
+	startDate, err := time.Parse("2006-01-02", startDateStr)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "invalid_start_date")
+		return
+	}
+	endDate, err := time.Parse("2006-01-02", endDateStr)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "invalid_end_date")
 		return
 	}
 
-	offTimes, err := h.availabilityService.GetExpertOffTimes(expertID)
+	offTimes, err := h.availabilityService.GetExpertOffTimesInRange(expertID, startDate, endDate)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: err.Error()})
+		respondErr(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, offTimes)
 }
 
+// AddOffTimeException godoc
+// @Summary Skip one occurrence of a recurring off-time
+// @Description Add a date on which the recurring off-time does not occur
+// @Tags availability
+// @Accept json
+// @Produce json
+// @Param id path string true "Off-time ID"
+// @Param exception body model.CreateOffTimeExceptionRequest true "Exception date"
+// @Success 201 {object} model.OffTime
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/availability/off-time/{id}/exceptions [post]
+func (h *AvailabilityHandler) AddOffTimeException(c *gin.Context) {
+	var req model.CreateOffTimeExceptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	offTime, err := h.availabilityService.AddOffTimeException(c.Param("id"), &req, c.MustGet("user_id").(uuid.UUID), isAdmin(c))
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, offTime)
+}
+
+// DeleteOffTimeException godoc
+// @Summary Restore a skipped off-time occurrence
+// @Description Remove an exception date from a recurring off-time
+// @Tags availability
+// @Produce json
+// @Param id path string true "Off-time ID"
+// @Param date path string true "Exception date (YYYY-MM-DD)"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/availability/off-time/{id}/exceptions/{date} [delete]
+func (h *AvailabilityHandler) DeleteOffTimeException(c *gin.Context) {
+	if err := h.availabilityService.DeleteOffTimeException(c.Param("id"), c.Param("date"), c.MustGet("user_id").(uuid.UUID), isAdmin(c)); err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 // DeleteOffTime godoc
 // @Summary Delete an off-time entry
 // @Description Delete a specific off-time entry by its ID
@@ -389,8 +594,8 @@ func (h *AvailabilityHandler) GetExpertOffTimes(c *gin.Context) {
 func (h *AvailabilityHandler) DeleteOffTime(c *gin.Context) {
 	id := c.Param("id")
 
-	if err := h.availabilityService.DeleteOffTime(id); err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: err.Error()})
+	if err := h.availabilityService.DeleteOffTime(id, c.MustGet("user_id").(uuid.UUID), isAdmin(c)); err != nil {
+		respondErr(c, err)
 		return
 	}
 
@@ -406,8 +611,16 @@ func (h *AvailabilityHandler) RegisterRoutes(router *gin.RouterGroup) {
 	router.DELETE("/availability/:id", h.DeleteAvailability)
 	router.POST("/availability/:id/book", h.BookAvailability)
 	router.POST("/availability/recurring", h.CreateRecurringAvailability)
+	router.GET("/availability/rules", h.GetAvailabilityRules)
+	router.GET("/availability/rules/:id", h.GetAvailabilityRule)
+	router.PUT("/availability/rules/:id", h.UpdateAvailabilityRule)
+	router.DELETE("/availability/rules/:id", h.DeleteAvailabilityRule)
+	router.POST("/availability/rules/:id/exceptions", h.AddAvailabilityRuleException)
+	router.DELETE("/availability/rules/:id/exceptions/:date", h.DeleteAvailabilityRuleException)
 	router.POST("/availability/check", h.CheckAvailability)
 	router.POST("/availability/off-time", h.CreateOffTime)
 	router.GET("/availability/off-time/:expert_id", h.GetExpertOffTimes)
 	router.DELETE("/availability/off-time/:id", h.DeleteOffTime)
+	router.POST("/availability/off-time/:id/exceptions", h.AddOffTimeException)
+	router.DELETE("/availability/off-time/:id/exceptions/:date", h.DeleteOffTimeException)
 }
@@ -0,0 +1,268 @@
+package handler
+
+import (
+	"errors"
+	"expert-service/internal/middleware"
+	"expert-service/internal/model"
+	"expert-service/internal/service"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CertificationHandler handles HTTP requests for expert certifications.
+type CertificationHandler struct {
+	certificationService service.CertificationService
+}
+
+// NewCertificationHandler creates a new CertificationHandler.
+func NewCertificationHandler(certificationService service.CertificationService) *CertificationHandler {
+	return &CertificationHandler{
+		certificationService: certificationService,
+	}
+}
+
+// GetExpertCertifications lists the verified, unexpired certifications shown on an expert's profile.
+func (h *CertificationHandler) GetExpertCertifications(c *gin.Context) {
+	expertID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "invalid_expert_id")
+		return
+	}
+
+	certifications, err := h.certificationService.GetPublicCertifications(expertID)
+	if err != nil {
+		respondFailed(c, "get_certifications_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"certifications": certifications})
+}
+
+// GetMyCertifications lists all of the calling expert's certifications with their proof files.
+func (h *CertificationHandler) GetMyCertifications(c *gin.Context) {
+	certifications, err := h.certificationService.GetMyCertifications(c.MustGet("user_id").(uuid.UUID))
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"certifications": certifications})
+}
+
+// CreateCertification adds a certification to the calling expert's profile, pending verification.
+func (h *CertificationHandler) CreateCertification(c *gin.Context) {
+	var req model.CertificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	certification, err := h.certificationService.CreateCertification(c.MustGet("user_id").(uuid.UUID), &req)
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, certification)
+}
+
+// GetCertification returns one certification to its expert or an admin.
+func (h *CertificationHandler) GetCertification(c *gin.Context) {
+	id, ok := parseCertificationID(c)
+	if !ok {
+		return
+	}
+
+	certification, err := h.certificationService.GetCertification(id, c.MustGet("user_id").(uuid.UUID), isAdmin(c))
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, certification)
+}
+
+// UpdateCertification changes a certification's details and sends it back for verification.
+func (h *CertificationHandler) UpdateCertification(c *gin.Context) {
+	id, ok := parseCertificationID(c)
+	if !ok {
+		return
+	}
+
+	var req model.CertificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	certification, err := h.certificationService.UpdateCertification(id, c.MustGet("user_id").(uuid.UUID), &req)
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, certification)
+}
+
+// DeleteCertification removes a certification and its proof files.
+func (h *CertificationHandler) DeleteCertification(c *gin.Context) {
+	id, ok := parseCertificationID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.certificationService.DeleteCertification(id, c.MustGet("user_id").(uuid.UUID), isAdmin(c)); err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message(c, "certification_deleted")})
+}
+
+// UploadFile attaches a proof file (multipart field "file") to a certification.
+func (h *CertificationHandler) UploadFile(c *gin.Context) {
+	id, ok := parseCertificationID(c)
+	if !ok {
+		return
+	}
+
+	// Leave room for the multipart framing around the file
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, service.MaxCertificationFileSize+1<<20)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondErr(c, service.ErrFileTooLarge)
+			return
+		}
+		respondError(c, http.StatusBadRequest, "file_required")
+		return
+	}
+	if header.Size > service.MaxCertificationFileSize {
+		respondErr(c, service.ErrFileTooLarge)
+		return
+	}
+	src, err := header.Open()
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "read_file_failed")
+		return
+	}
+	defer src.Close()
+	data, err := io.ReadAll(src)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "read_file_failed")
+		return
+	}
+
+	file, err := h.certificationService.AddFile(id, c.MustGet("user_id").(uuid.UUID), header.Filename, data)
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, file)
+}
+
+// DownloadFile serves a proof file to the certification's expert or an admin.
+func (h *CertificationHandler) DownloadFile(c *gin.Context) {
+	id, fileID, ok := parseCertificationFileID(c)
+	if !ok {
+		return
+	}
+
+	file, content, err := h.certificationService.OpenFile(id, fileID, c.MustGet("user_id").(uuid.UUID), isAdmin(c))
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, file.SizeBytes, file.ContentType, content, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": file.FileName}),
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// DeleteFile removes a proof file from a certification.
+func (h *CertificationHandler) DeleteFile(c *gin.Context) {
+	id, fileID, ok := parseCertificationFileID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.certificationService.DeleteFile(id, fileID, c.MustGet("user_id").(uuid.UUID)); err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message(c, "file_deleted")})
+}
+
+// GetCertificationsByStatus lists certifications for admins, by default those waiting for verification.
+func (h *CertificationHandler) GetCertificationsByStatus(c *gin.Context) {
+	var req model.GetCertificationsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	certifications, err := h.certificationService.GetCertificationsByStatus(&req)
+	if err != nil {
+		respondFailed(c, "get_certifications_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, certifications)
+}
+
+// VerifyCertification lets an admin verify or reject a certification.
+func (h *CertificationHandler) VerifyCertification(c *gin.Context) {
+	id, ok := parseCertificationID(c)
+	if !ok {
+		return
+	}
+
+	var req model.VerifyCertificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	certification, err := h.certificationService.VerifyCertification(id, c.MustGet("user_id").(uuid.UUID), &req)
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, certification)
+}
+
+func isAdmin(c *gin.Context) bool {
+	return c.GetString("user_role") == middleware.RoleAdmin
+}
+
+func parseCertificationID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "invalid_certification_id")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func parseCertificationFileID(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	id, ok := parseCertificationID(c)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+	fileID, err := uuid.Parse(c.Param("file_id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "invalid_file_id")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return id, fileID, true
+}
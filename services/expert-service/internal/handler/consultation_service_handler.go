@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"expert-service/internal/model"
+	"expert-service/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ConsultationServiceHandler handles HTTP requests for an expert's service catalog.
+type ConsultationServiceHandler struct {
+	serviceService service.ConsultationServiceService
+}
+
+// NewConsultationServiceHandler creates a new ConsultationServiceHandler.
+func NewConsultationServiceHandler(serviceService service.ConsultationServiceService) *ConsultationServiceHandler {
+	return &ConsultationServiceHandler{
+		serviceService: serviceService,
+	}
+}
+
+// CreateService adds a consultation type to an expert's catalog.
+func (h *ConsultationServiceHandler) CreateService(c *gin.Context) {
+	expertID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "invalid_expert_id")
+		return
+	}
+
+	var req model.CreateConsultationServiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	svc, err := h.serviceService.CreateService(expertID, &req)
+	if err != nil {
+		respondFailed(c, "create_service_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, svc)
+}
+
+// GetServices lists an expert's consultation types.
+func (h *ConsultationServiceHandler) GetServices(c *gin.Context) {
+	expertID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "invalid_expert_id")
+		return
+	}
+	includeInactive := c.Query("include_inactive") == "true"
+
+	services, err := h.serviceService.GetServicesByExpertID(expertID, includeInactive)
+	if err != nil {
+		respondFailed(c, "get_services_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"services": services})
+}
+
+// GetService returns one consultation type of an expert.
+func (h *ConsultationServiceHandler) GetService(c *gin.Context) {
+	expertID, serviceID, ok := parseServicePath(c)
+	if !ok {
+		return
+	}
+
+	svc, err := h.serviceService.GetService(expertID, serviceID)
+	if err != nil {
+		respondError(c, http.StatusNotFound, "consultation_service_not_found")
+		return
+	}
+
+	c.JSON(http.StatusOK, svc)
+}
+
+// UpdateService updates a consultation type of an expert.
+func (h *ConsultationServiceHandler) UpdateService(c *gin.Context) {
+	expertID, serviceID, ok := parseServicePath(c)
+	if !ok {
+		return
+	}
+
+	var req model.UpdateConsultationServiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	svc, err := h.serviceService.UpdateService(expertID, serviceID, &req)
+	if err != nil {
+		respondFailed(c, "update_service_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, svc)
+}
+
+// DeleteService deactivates a consultation type of an expert.
+func (h *ConsultationServiceHandler) DeleteService(c *gin.Context) {
+	expertID, serviceID, ok := parseServicePath(c)
+	if !ok {
+		return
+	}
+
+	if err := h.serviceService.DeleteService(expertID, serviceID); err != nil {
+		respondFailed(c, "delete_service_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message(c, "service_deleted")})
+}
+
+func parseServicePath(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	expertID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "invalid_expert_id")
+		return uuid.Nil, uuid.Nil, false
+	}
+	serviceID, err := uuid.Parse(c.Param("service_id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "invalid_service_id")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return expertID, serviceID, true
+}
@@ -1,15 +1,11 @@
 package handler
 
 import (
-	"database/sql"
 	"expert-service/internal/model"
 	"expert-service/internal/service"
 	"net/http"
-	"os"
 	"strconv"
 
-	_ "github.com/lib/pq"
-
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -17,48 +13,29 @@ import (
 // ExpertHandler handles HTTP requests for expert resources.
 type ExpertHandler struct {
 	expertService service.ExpertService
+	searchService service.ExpertSearchService
 }
 
 // NewExpertHandler creates a new ExpertHandler.
-func NewExpertHandler(expertService service.ExpertService) *ExpertHandler {
+func NewExpertHandler(expertService service.ExpertService, searchService service.ExpertSearchService) *ExpertHandler {
 	return &ExpertHandler{
 		expertService: expertService,
+		searchService: searchService,
 	}
 }
 
-func isValidExpertUserID(db *sql.DB, userID string) (bool, error) {
-	var role string
-	err := db.QueryRow("SELECT role FROM users WHERE id = $1", userID).Scan(&role)
-	if err != nil {
-		return false, err
-	}
-	return role == "expert", nil
-}
-
-// CreateExpert handles expert creation.
+// CreateExpert lets an admin create an expert profile for a user directly;
+// everyone else goes through the onboarding workflow.
 func (h *ExpertHandler) CreateExpert(c *gin.Context) {
 	var req model.CreateExpertRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
-		return
-	}
-
-	db, err := sql.Open("postgres", os.Getenv("EXPERT_SERVICE_DSN"))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "DB connection error"})
-		return
-	}
-	defer db.Close()
-
-	valid, err := isValidExpertUserID(db, req.UserID)
-	if err != nil || !valid {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID không hợp lệ hoặc không phải role expert"})
+		respondInvalid(c, err)
 		return
 	}
 
 	expert, err := h.expertService.CreateExpert(&req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create expert: " + err.Error()})
+		respondErr(c, err)
 		return
 	}
 
@@ -70,13 +47,13 @@ func (h *ExpertHandler) GetExpert(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expert ID"})
+		respondError(c, http.StatusBadRequest, "invalid_expert_id")
 		return
 	}
 
 	expert, err := h.expertService.GetExpertByID(id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Expert not found"})
+		respondError(c, http.StatusNotFound, "expert_not_found")
 		return
 	}
 
@@ -90,19 +67,19 @@ func (h *ExpertHandler) GetExperts(c *gin.Context) {
 
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil || limit < 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+		respondError(c, http.StatusBadRequest, "invalid_limit")
 		return
 	}
 
 	offset, err := strconv.Atoi(offsetStr)
 	if err != nil || offset < 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset parameter"})
+		respondError(c, http.StatusBadRequest, "invalid_offset")
 		return
 	}
 
 	experts, err := h.expertService.GetAllExperts(limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get experts: " + err.Error()})
+		respondFailed(c, "get_experts_failed", err)
 		return
 	}
 
@@ -118,22 +95,22 @@ func (h *ExpertHandler) UpdateExpert(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expert ID"})
+		respondError(c, http.StatusBadRequest, "invalid_expert_id")
 		return
 	}
 
 	var req model.UpdateExpertRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		respondInvalid(c, err)
 		return
 	}
 
 	if err := h.expertService.UpdateExpert(id, &req); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update expert: " + err.Error()})
+		respondFailed(c, "update_expert_failed", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Expert updated successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": message(c, "expert_updated")})
 }
 
 // DeleteExpert deletes expert by ID.
@@ -141,31 +118,48 @@ func (h *ExpertHandler) DeleteExpert(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expert ID"})
+		respondError(c, http.StatusBadRequest, "invalid_expert_id")
 		return
 	}
 
 	if err := h.expertService.DeleteExpert(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete expert: " + err.Error()})
+		respondFailed(c, "delete_expert_failed", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Expert deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": message(c, "expert_deleted")})
 }
 
 // GetExpertsByExpertise returns experts by expertise.
 func (h *ExpertHandler) GetExpertsByExpertise(c *gin.Context) {
 	expertise := c.Query("expertise")
 	if expertise == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Expertise parameter is required"})
+		respondError(c, http.StatusBadRequest, "expertise_required")
 		return
 	}
 
 	experts, err := h.expertService.GetExpertsByExpertise(expertise)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get experts: " + err.Error()})
+		respondFailed(c, "get_experts_failed", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"experts": experts})
 }
+
+// SearchExperts returns experts matching faceted filters, ranked and paged, with facet counts.
+func (h *ExpertHandler) SearchExperts(c *gin.Context) {
+	var req model.SearchExpertsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	result, err := h.searchService.SearchExperts(&req)
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,277 @@
+package handler
+
+import (
+	"expert-service/internal/model"
+	"expert-service/internal/service"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HolidayHandler handles holiday calendars and the holidays experts observe
+type HolidayHandler struct {
+	holidayService service.HolidayService
+}
+
+// NewHolidayHandler creates a new holiday handler
+func NewHolidayHandler(holidayService service.HolidayService) *HolidayHandler {
+	return &HolidayHandler{
+		holidayService: holidayService,
+	}
+}
+
+// GetCalendars godoc
+// @Summary List holiday calendars
+// @Tags holidays
+// @Produce json
+// @Success 200 {array} model.HolidayCalendar
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/holiday-calendars [get]
+func (h *HolidayHandler) GetCalendars(c *gin.Context) {
+	calendars, err := h.holidayService.GetCalendars()
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, calendars)
+}
+
+// GetCalendar godoc
+// @Summary Get a holiday calendar
+// @Description Get a calendar with its holidays, optionally of one year only
+// @Tags holidays
+// @Produce json
+// @Param code path string true "Calendar code, e.g. VN"
+// @Param year query int false "Year"
+// @Success 200 {object} model.HolidayCalendar
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/holiday-calendars/{code} [get]
+func (h *HolidayHandler) GetCalendar(c *gin.Context) {
+	year := 0
+	if yearStr := c.Query("year"); yearStr != "" {
+		var err error
+		year, err = strconv.Atoi(yearStr)
+		if err != nil || year < 1 {
+			respondError(c, http.StatusBadRequest, "invalid_year")
+			return
+		}
+	}
+
+	calendar, err := h.holidayService.GetCalendar(c.Param("code"), year)
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, calendar)
+}
+
+// UpdateCalendar godoc
+// @Summary Create or replace a holiday calendar
+// @Description Admin only. Replaces all holidays of the calendar.
+// @Tags holidays
+// @Accept json
+// @Produce json
+// @Param code path string true "Calendar code, e.g. VN"
+// @Param calendar body model.UpdateHolidayCalendarRequest true "Calendar"
+// @Success 200 {object} model.HolidayCalendar
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/holiday-calendars/{code} [put]
+func (h *HolidayHandler) UpdateCalendar(c *gin.Context) {
+	var req model.UpdateHolidayCalendarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	calendar, err := h.holidayService.UpdateCalendar(c.Param("code"), &req)
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, calendar)
+}
+
+// AddHoliday godoc
+// @Summary Add a holiday to a calendar
+// @Description Admin only. Renames the holiday if the date already exists.
+// @Tags holidays
+// @Accept json
+// @Produce json
+// @Param code path string true "Calendar code, e.g. VN"
+// @Param holiday body model.HolidayRequest true "Holiday"
+// @Success 201 {object} model.Holiday
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/holiday-calendars/{code}/holidays [post]
+func (h *HolidayHandler) AddHoliday(c *gin.Context) {
+	var req model.HolidayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	holiday, err := h.holidayService.AddHoliday(c.Param("code"), &req)
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, holiday)
+}
+
+// DeleteHoliday godoc
+// @Summary Remove a holiday from a calendar
+// @Description Admin only
+// @Tags holidays
+// @Produce json
+// @Param code path string true "Calendar code, e.g. VN"
+// @Param date path string true "Holiday date (YYYY-MM-DD)"
+// @Success 204 "No Content"
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/holiday-calendars/{code}/holidays/{date} [delete]
+func (h *HolidayHandler) DeleteHoliday(c *gin.Context) {
+	if err := h.holidayService.DeleteHoliday(c.Param("code"), c.Param("date")); err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetExpertSettings godoc
+// @Summary Get an expert's holiday settings
+// @Description Get the calendars an expert observes and the holidays they work on
+// @Tags holidays
+// @Produce json
+// @Param expert_id path string true "Expert ID"
+// @Success 200 {object} model.ExpertHolidaySettings
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/availability/holidays/{expert_id} [get]
+func (h *HolidayHandler) GetExpertSettings(c *gin.Context) {
+	settings, err := h.holidayService.GetExpertSettings(c.Param("expert_id"))
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// SetExpertCalendars godoc
+// @Summary Choose the holiday calendars an expert observes
+// @Description An empty list opts the expert out of all holidays
+// @Tags holidays
+// @Accept json
+// @Produce json
+// @Param expert_id path string true "Expert ID"
+// @Param calendars body model.UpdateExpertHolidayCalendarsRequest true "Calendar codes"
+// @Success 200 {object} model.ExpertHolidaySettings
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/availability/holidays/{expert_id} [put]
+func (h *HolidayHandler) SetExpertCalendars(c *gin.Context) {
+	var req model.UpdateExpertHolidayCalendarsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	settings, err := h.holidayService.SetExpertCalendars(c.Param("expert_id"), &req)
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// GetExpertHolidays godoc
+// @Summary List the holidays that block an expert
+// @Description List observed holidays between two dates, leaving out the ones the expert works on
+// @Tags holidays
+// @Produce json
+// @Param expert_id path string true "Expert ID"
+// @Param start_date query string true "Start date (YYYY-MM-DD)"
+// @Param end_date query string true "End date (YYYY-MM-DD)"
+// @Success 200 {array} model.Holiday
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/availability/holidays/{expert_id}/dates [get]
+func (h *HolidayHandler) GetExpertHolidays(c *gin.Context) {
+	startDate, err := time.Parse("2006-01-02", c.Query("start_date"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "invalid_start_date")
+		return
+	}
+	endDate, err := time.Parse("2006-01-02", c.Query("end_date"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "invalid_end_date")
+		return
+	}
+
+	holidays, err := h.holidayService.GetExpertHolidays(c.Param("expert_id"), startDate, endDate)
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, holidays)
+}
+
+// AddOverride godoc
+// @Summary Work on a holiday
+// @Description Let the expert take bookings on a holiday they otherwise observe
+// @Tags holidays
+// @Accept json
+// @Produce json
+// @Param expert_id path string true "Expert ID"
+// @Param override body model.CreateHolidayOverrideRequest true "Holiday date"
+// @Success 201 {object} model.ExpertHolidaySettings
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/availability/holidays/{expert_id}/overrides [post]
+func (h *HolidayHandler) AddOverride(c *gin.Context) {
+	var req model.CreateHolidayOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	settings, err := h.holidayService.AddOverride(c.Param("expert_id"), &req)
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, settings)
+}
+
+// DeleteOverride godoc
+// @Summary Stop working on a holiday
+// @Tags holidays
+// @Produce json
+// @Param expert_id path string true "Expert ID"
+// @Param date path string true "Holiday date (YYYY-MM-DD)"
+// @Success 204 "No Content"
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/availability/holidays/{expert_id}/overrides/{date} [delete]
+func (h *HolidayHandler) DeleteOverride(c *gin.Context) {
+	if err := h.holidayService.DeleteOverride(c.Param("expert_id"), c.Param("date")); err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
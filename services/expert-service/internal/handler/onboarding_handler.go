@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"errors"
+	"expert-service/internal/model"
+	"expert-service/internal/service"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// OnboardingHandler handles HTTP requests for expert applications.
+type OnboardingHandler struct {
+	onboardingService service.OnboardingService
+}
+
+// NewOnboardingHandler creates a new OnboardingHandler.
+func NewOnboardingHandler(onboardingService service.OnboardingService) *OnboardingHandler {
+	return &OnboardingHandler{
+		onboardingService: onboardingService,
+	}
+}
+
+// Apply lets the caller apply to become an expert, or apply again after a rejection.
+func (h *OnboardingHandler) Apply(c *gin.Context) {
+	var req model.CreateApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	application, err := h.onboardingService.Apply(c.MustGet("user_id").(uuid.UUID), &req)
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, application)
+}
+
+// GetMyApplication returns the caller's application and its documents.
+func (h *OnboardingHandler) GetMyApplication(c *gin.Context) {
+	application, err := h.onboardingService.GetMyApplication(c.MustGet("user_id").(uuid.UUID))
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, application)
+}
+
+// AddDocument attaches a document to the caller's application.
+func (h *OnboardingHandler) AddDocument(c *gin.Context) {
+	var req model.AddApplicationDocumentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	document, err := h.onboardingService.AddDocument(c.MustGet("user_id").(uuid.UUID), &req)
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, document)
+}
+
+// Submit sends the caller's application for review.
+func (h *OnboardingHandler) Submit(c *gin.Context) {
+	application, err := h.onboardingService.Submit(c.MustGet("user_id").(uuid.UUID))
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, application)
+}
+
+// GetApplications lists applications for admins, optionally filtered by status.
+func (h *OnboardingHandler) GetApplications(c *gin.Context) {
+	var req model.GetApplicationsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	applications, err := h.onboardingService.GetApplications(&req)
+	if err != nil {
+		respondFailed(c, "get_applications_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, applications)
+}
+
+// GetApplication returns one application with its documents.
+func (h *OnboardingHandler) GetApplication(c *gin.Context) {
+	id, ok := parseApplicationID(c)
+	if !ok {
+		return
+	}
+
+	application, err := h.onboardingService.GetApplication(id)
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, application)
+}
+
+// StartReview lets an admin take a submitted application under review.
+func (h *OnboardingHandler) StartReview(c *gin.Context) {
+	id, ok := parseApplicationID(c)
+	if !ok {
+		return
+	}
+
+	application, err := h.onboardingService.StartReview(id, c.MustGet("user_id").(uuid.UUID))
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, application)
+}
+
+// ApproveApplication approves an application under review and creates the expert profile.
+func (h *OnboardingHandler) ApproveApplication(c *gin.Context) {
+	id, ok := parseApplicationID(c)
+	if !ok {
+		return
+	}
+
+	var req model.ApproveApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondInvalid(c, err)
+		return
+	}
+
+	application, err := h.onboardingService.Approve(id, c.MustGet("user_id").(uuid.UUID), &req)
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, application)
+}
+
+// RejectApplication rejects an application with a reason shown to the applicant.
+func (h *OnboardingHandler) RejectApplication(c *gin.Context) {
+	id, ok := parseApplicationID(c)
+	if !ok {
+		return
+	}
+
+	var req model.RejectApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	application, err := h.onboardingService.Reject(id, c.MustGet("user_id").(uuid.UUID), &req)
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, application)
+}
+
+func parseApplicationID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "invalid_application_id")
+		return uuid.Nil, false
+	}
+	return id, true
+}
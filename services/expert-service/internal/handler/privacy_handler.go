@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"expert-service/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PrivacyHandler handles data export and erasure requests for a user.
+type PrivacyHandler struct {
+	privacyService service.PrivacyService
+}
+
+// NewPrivacyHandler creates a new PrivacyHandler.
+func NewPrivacyHandler(privacyService service.PrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{
+		privacyService: privacyService,
+	}
+}
+
+// ExportUserData returns the reviews and expert application of a user to that user or an admin.
+func (h *PrivacyHandler) ExportUserData(c *gin.Context) {
+	userID, ok := parsePrivacyUserID(c)
+	if !ok {
+		return
+	}
+
+	export, err := h.privacyService.ExportUserData(userID)
+	if err != nil {
+		respondFailed(c, "export_data_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, export)
+}
+
+// EraseUserData deletes a user's expert application as part of erasing their account.
+func (h *PrivacyHandler) EraseUserData(c *gin.Context) {
+	userID, ok := parsePrivacyUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.privacyService.EraseUserData(userID); err != nil {
+		respondFailed(c, "erase_data_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message(c, "data_erased")})
+}
+
+// parsePrivacyUserID reads the user a privacy request is about; only that
+// user and admins may make it
+func parsePrivacyUserID(c *gin.Context) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "invalid_user_id")
+		return uuid.Nil, false
+	}
+	if userID != c.MustGet("user_id").(uuid.UUID) && !isAdmin(c) {
+		respondError(c, http.StatusForbidden, "access_denied")
+		return uuid.Nil, false
+	}
+	return userID, true
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"errors"
+	"log"
+
+	"booking-system/shared/pkg/apperr"
+	"booking-system/shared/pkg/i18n"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(apperr.JSONFieldName)
+	}
+}
+
+// language is the language responses are written in: the caller's saved
+// preference when they are logged in, otherwise Accept-Language
+func language(c *gin.Context) i18n.Lang {
+	return i18n.Resolve(c.GetString("user_lang"), c.GetHeader("Accept-Language"))
+}
+
+// message returns the catalog message code in the caller's language
+func message(c *gin.Context, code string, args ...interface{}) string {
+	return i18n.Message(language(c), code, args...)
+}
+
+// respondError answers with the message code as a problem of the kind that
+// goes with status
+func respondError(c *gin.Context, status int, code string, args ...interface{}) {
+	respondErr(c, apperr.New(apperr.KindFor(status), code, args...))
+}
+
+// respondErr answers with the problem err describes and stops the chain.
+// Errors that are not domain errors are logged and answered with a generic
+// message.
+func respondErr(c *gin.Context, err error) {
+	var domain *apperr.Error
+	if !errors.As(err, &domain) {
+		log.Printf("%s %s failed: %v", c.Request.Method, c.FullPath(), err)
+	}
+	problem := apperr.NewProblem(language(c), err)
+	problem.Instance = c.Request.URL.Path
+	apperr.Write(c.Writer, problem)
+	c.Abort()
+}
+
+// respondFailed answers with the problem err describes when it is a domain
+// error. Other errors are logged and answered with the message code saying
+// what failed, followed by the reason when err carries a message code.
+func respondFailed(c *gin.Context, code string, err error) {
+	var domain *apperr.Error
+	if errors.As(err, &domain) {
+		respondErr(c, err)
+		return
+	}
+	log.Printf("%s %s failed: %v", c.Request.Method, c.FullPath(), err)
+	respondErr(c, apperr.New(apperr.KindInternal, code).Wrap(err))
+}
+
+// respondInvalid answers for a request body or query that failed binding,
+// listing the rejected fields when validation rules were broken
+func respondInvalid(c *gin.Context, err error) {
+	var broken validator.ValidationErrors
+	if !errors.As(err, &broken) {
+		respondErr(c, apperr.Validation("invalid_request", err.Error()))
+		return
+	}
+	fields := make([]apperr.FieldError, 0, len(broken))
+	for _, fe := range broken {
+		fields = append(fields, apperr.FieldError{Field: fe.Field(), Rule: fe.Tag(), Param: fe.Param()})
+	}
+	respondErr(c, apperr.Validation("invalid_fields").WithFields(fields...))
+}
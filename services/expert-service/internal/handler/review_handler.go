@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"expert-service/internal/model"
+	"expert-service/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ReviewHandler handles HTTP requests for reviews of experts.
+type ReviewHandler struct {
+	reviewService service.ReviewService
+}
+
+// NewReviewHandler creates a new ReviewHandler.
+func NewReviewHandler(reviewService service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{
+		reviewService: reviewService,
+	}
+}
+
+// CreateReview lets a user review one of their completed bookings.
+func (h *ReviewHandler) CreateReview(c *gin.Context) {
+	var req model.CreateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	review, err := h.reviewService.CreateReview(c.MustGet("user_id").(uuid.UUID), &req)
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, review)
+}
+
+// GetReview returns one review. Hidden and flagged reviews are only found by
+// their author, the reviewed expert and admins.
+func (h *ReviewHandler) GetReview(c *gin.Context) {
+	id, ok := parseReviewID(c)
+	if !ok {
+		return
+	}
+
+	viewerID, _ := c.Get("user_id")
+	callerID, _ := viewerID.(uuid.UUID)
+	review, err := h.reviewService.GetReview(id, callerID, isAdmin(c))
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// GetExpertReviews lists the public reviews of an expert, newest first.
+func (h *ReviewHandler) GetExpertReviews(c *gin.Context) {
+	expertID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "invalid_expert_id")
+		return
+	}
+
+	var req model.GetReviewsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "invalid_query")
+		return
+	}
+
+	reviews, err := h.reviewService.GetExpertReviews(expertID, &req)
+	if err != nil {
+		respondFailed(c, "get_reviews_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// GetFlaggedReviews lists the reviews waiting for moderation.
+func (h *ReviewHandler) GetFlaggedReviews(c *gin.Context) {
+	var req model.GetReviewsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "invalid_query")
+		return
+	}
+
+	reviews, err := h.reviewService.GetReviewsByStatus(model.ReviewStatusFlagged, &req)
+	if err != nil {
+		respondFailed(c, "get_reviews_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// UpdateReview lets the author change their review within the edit window.
+func (h *ReviewHandler) UpdateReview(c *gin.Context) {
+	id, ok := parseReviewID(c)
+	if !ok {
+		return
+	}
+
+	var req model.UpdateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	review, err := h.reviewService.UpdateReview(id, c.MustGet("user_id").(uuid.UUID), &req)
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// ReplyToReview lets the reviewed expert answer a review.
+func (h *ReviewHandler) ReplyToReview(c *gin.Context) {
+	id, ok := parseReviewID(c)
+	if !ok {
+		return
+	}
+
+	var req model.ReplyReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	review, err := h.reviewService.ReplyToReview(id, c.MustGet("user_id").(uuid.UUID), &req)
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// ModerateReview lets an admin hide, flag or restore a review.
+func (h *ReviewHandler) ModerateReview(c *gin.Context) {
+	id, ok := parseReviewID(c)
+	if !ok {
+		return
+	}
+
+	var req model.ModerateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	review, err := h.reviewService.ModerateReview(id, &req)
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+func parseReviewID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "invalid_review_id")
+		return uuid.Nil, false
+	}
+	return id, true
+}
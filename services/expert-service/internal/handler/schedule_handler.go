@@ -73,20 +73,20 @@ const (
 func (h *ScheduleHandler) CreateSchedule(c *gin.Context) {
 	var req model.CreateScheduleRequest // Using the model's struct
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		respondInvalid(c, err)
 		return
 	}
 
 	schedule, err := h.scheduleService.CreateSchedule(&req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create schedule", "details": err.Error()})
+		respondFailed(c, "create_schedule_failed", err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
 		"success": true,
 		"data":    schedule,
-		"message": "Schedule created successfully",
+		"message": message(c, "schedule_created"),
 	})
 }
 
@@ -111,7 +111,7 @@ func (h *ScheduleHandler) GetSchedules(c *gin.Context) {
 
 	schedules, err := h.scheduleService.GetSchedules(&req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve schedules", "details": err.Error()})
+		respondFailed(c, "get_schedules_failed", err)
 		return
 	}
 
@@ -133,18 +133,18 @@ func (h *ScheduleHandler) GetSchedules(c *gin.Context) {
 func (h *ScheduleHandler) GetScheduleByID(c *gin.Context) {
 	id := c.Param("id")                       // ID is already a string (UUID)
 	if _, err := uuid.Parse(id); err != nil { // Validate if it's a valid UUID
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid schedule ID format"})
+		respondError(c, http.StatusBadRequest, "invalid_schedule_id")
 		return
 	}
 
 	schedule, err := h.scheduleService.GetScheduleByID(uuid.MustParse(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve schedule", "details": err.Error()})
+		respondFailed(c, "get_schedule_failed", err)
 		return
 	}
 
 	if schedule == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Schedule not found"})
+		respondError(c, http.StatusNotFound, "schedule_not_found")
 		return
 	}
 
@@ -158,29 +158,29 @@ func (h *ScheduleHandler) GetScheduleByID(c *gin.Context) {
 func (h *ScheduleHandler) UpdateSchedule(c *gin.Context) {
 	id := c.Param("id")                       // ID is already a string (UUID)
 	if _, err := uuid.Parse(id); err != nil { // Validate if it's a valid UUID
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid schedule ID format"})
+		respondError(c, http.StatusBadRequest, "invalid_schedule_id")
 		return
 	}
 
 	var req model.UpdateScheduleRequest // Use the model's struct
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		respondInvalid(c, err)
 		return
 	}
 
 	if req.Status != nil && !isValidStatus(*req.Status) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status. Use: pending, confirmed, cancelled, completed"})
+		respondError(c, http.StatusBadRequest, "invalid_schedule_status")
 		return
 	}
 
 	if err := h.scheduleService.UpdateSchedule(uuid.MustParse(id), &req); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update schedule", "details": err.Error()})
+		respondFailed(c, "update_schedule_failed", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"message": "Schedule updated successfully",
+		"message": message(c, "schedule_updated"),
 	})
 }
 
@@ -188,18 +188,18 @@ func (h *ScheduleHandler) UpdateSchedule(c *gin.Context) {
 func (h *ScheduleHandler) CancelSchedule(c *gin.Context) {
 	id := c.Param("id")
 	if _, err := uuid.Parse(id); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid schedule ID format"})
+		respondError(c, http.StatusBadRequest, "invalid_schedule_id")
 		return
 	}
 
 	if err := h.scheduleService.CancelSchedule(uuid.MustParse(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel schedule", "details": err.Error()})
+		respondFailed(c, "cancel_schedule_failed", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"message": "Schedule cancelled successfully",
+		"message": message(c, "schedule_cancelled"),
 	})
 }
 
@@ -207,18 +207,18 @@ func (h *ScheduleHandler) CancelSchedule(c *gin.Context) {
 func (h *ScheduleHandler) ConfirmSchedule(c *gin.Context) {
 	id := c.Param("id")
 	if _, err := uuid.Parse(id); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid schedule ID format"})
+		respondError(c, http.StatusBadRequest, "invalid_schedule_id")
 		return
 	}
 
 	if err := h.scheduleService.ConfirmSchedule(uuid.MustParse(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to confirm schedule", "details": err.Error()})
+		respondFailed(c, "confirm_schedule_failed", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"message": "Schedule confirmed successfully",
+		"message": message(c, "schedule_confirmed"),
 	})
 }
 
@@ -226,18 +226,18 @@ func (h *ScheduleHandler) ConfirmSchedule(c *gin.Context) {
 func (h *ScheduleHandler) CompleteSchedule(c *gin.Context) {
 	id := c.Param("id")
 	if _, err := uuid.Parse(id); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid schedule ID format"})
+		respondError(c, http.StatusBadRequest, "invalid_schedule_id")
 		return
 	}
 
 	if err := h.scheduleService.CompleteSchedule(uuid.MustParse(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to complete schedule", "details": err.Error()})
+		respondFailed(c, "complete_schedule_failed", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"message": "Schedule marked as completed",
+		"message": message(c, "schedule_completed"),
 	})
 }
 
@@ -245,7 +245,7 @@ func (h *ScheduleHandler) CompleteSchedule(c *gin.Context) {
 func (h *ScheduleHandler) GetExpertSchedules(c *gin.Context) {
 	expertID := c.Param("expert_id")
 	if _, err := uuid.Parse(expertID); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expert ID format"})
+		respondError(c, http.StatusBadRequest, "invalid_expert_id")
 		return
 	}
 
@@ -267,7 +267,7 @@ func (h *ScheduleHandler) GetExpertSchedules(c *gin.Context) {
 
 	schedules, err := h.scheduleService.GetSchedules(&req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve expert schedules", "details": err.Error()})
+		respondFailed(c, "get_expert_schedules_failed", err)
 		return
 	}
 
@@ -288,7 +288,7 @@ func (h *ScheduleHandler) GetExpertSchedules(c *gin.Context) {
 func (h *ScheduleHandler) GetUserSchedules(c *gin.Context) {
 	userID := c.Param("user_id")
 	if _, err := uuid.Parse(userID); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		respondError(c, http.StatusBadRequest, "invalid_user_id")
 		return
 	}
 
@@ -310,7 +310,7 @@ func (h *ScheduleHandler) GetUserSchedules(c *gin.Context) {
 
 	schedules, err := h.scheduleService.GetSchedules(&req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve user schedules", "details": err.Error()})
+		respondFailed(c, "get_user_schedules_failed", err)
 		return
 	}
 
@@ -338,7 +338,7 @@ func (h *ScheduleHandler) GetTodaySchedules(c *gin.Context) {
 
 	schedules, err := h.scheduleService.GetSchedules(&req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve today's schedules", "details": err.Error()})
+		respondFailed(c, "get_today_schedules_failed", err)
 		return
 	}
 
@@ -358,18 +358,18 @@ func (h *ScheduleHandler) DeleteSchedule(c *gin.Context) {
 	id := c.Param("id")              // ID is already a string (UUID)
 	offTimeID, err := uuid.Parse(id) // Use offTimeID instead of id for consistency with the Availability handler
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid schedule ID format"})
+		respondError(c, http.StatusBadRequest, "invalid_schedule_id")
 		return
 	}
 
 	if err := h.scheduleService.DeleteSchedule(offTimeID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete schedule"})
+		respondError(c, http.StatusInternalServerError, "delete_schedule_failed")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"message": "Schedule deleted successfully",
+		"message": message(c, "schedule_deleted"),
 	})
 }
 
@@ -398,7 +398,7 @@ func (h *ScheduleHandler) GetUpcomingSchedules(c *gin.Context) {
 
 	schedules, err := h.scheduleService.GetSchedules(&req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve upcoming schedules", "details": err.Error()})
+		respondFailed(c, "get_upcoming_schedules_failed", err)
 		return
 	}
 
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"expert-service/internal/model"
+	"expert-service/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SlotHandler handles bookable slot and booking rule requests
+type SlotHandler struct {
+	slotService service.SlotService
+}
+
+// NewSlotHandler creates a new slot handler
+func NewSlotHandler(slotService service.SlotService) *SlotHandler {
+	return &SlotHandler{
+		slotService: slotService,
+	}
+}
+
+// GetSlots godoc
+// @Summary Get bookable slots
+// @Description Get the free start times of an expert after subtracting off-times, bookings, buffers and booking rules
+// @Tags availability
+// @Accept json
+// @Produce json
+// @Param expert_id query string true "Expert ID"
+// @Param start_date query string true "Start date (YYYY-MM-DD)"
+// @Param end_date query string true "End date (YYYY-MM-DD)"
+// @Param duration query int true "Session duration in minutes"
+// @Param granularity query int false "Step between start times in minutes (default 30)"
+// @Success 200 {array} model.Slot
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/availability/slots [get]
+func (h *SlotHandler) GetSlots(c *gin.Context) {
+	var req model.GetSlotsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	slots, err := h.slotService.GetAvailableSlots(&req)
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, slots)
+}
+
+// GetBookingRule godoc
+// @Summary Get booking rules of an expert
+// @Description Get buffers, notice and limits applied when computing slots
+// @Tags availability
+// @Produce json
+// @Param expert_id path string true "Expert ID"
+// @Success 200 {object} model.BookingRule
+// @Failure 400 {object} ErrorResponse
+// @Router /api/v1/availability/booking-rules/{expert_id} [get]
+func (h *SlotHandler) GetBookingRule(c *gin.Context) {
+	rule, err := h.slotService.GetBookingRule(c.Param("expert_id"))
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// UpdateBookingRule godoc
+// @Summary Update booking rules of an expert
+// @Description Update buffers, notice and limits applied when computing slots
+// @Tags availability
+// @Accept json
+// @Produce json
+// @Param expert_id path string true "Expert ID"
+// @Param rule body model.UpdateBookingRuleRequest true "Booking rule fields"
+// @Success 200 {object} model.BookingRule
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/availability/booking-rules/{expert_id} [put]
+func (h *SlotHandler) UpdateBookingRule(c *gin.Context) {
+	var req model.UpdateBookingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	rule, err := h.slotService.UpdateBookingRule(c.Param("expert_id"), &req)
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// CheckBookingWindow godoc
+// @Summary Check a booking window
+// @Description Check that an expert exists, accepts bookings and works during the whole requested window without off-time
+// @Tags availability
+// @Accept json
+// @Produce json
+// @Param window body model.CheckBookingWindowRequest true "Booking window"
+// @Success 200 {object} model.BookingWindowCheck
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/availability/check-window [post]
+func (h *SlotHandler) CheckBookingWindow(c *gin.Context) {
+	var req model.CheckBookingWindowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	result, err := h.slotService.CheckBookingWindow(&req)
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,44 @@
+// Package holidays holds the public holiday calendars bundled with the service.
+// Each data/<CODE>.json file is one calendar; they seed the database on
+// startup and can be edited through the admin API afterwards.
+package holidays
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"time"
+
+	"expert-service/internal/model"
+)
+
+//go:embed data/*.json
+var files embed.FS
+
+// Bundled parses every bundled calendar
+func Bundled() ([]*model.HolidayCalendar, error) {
+	entries, err := files.ReadDir("data")
+	if err != nil {
+		return nil, err
+	}
+
+	var calendars []*model.HolidayCalendar
+	for _, entry := range entries {
+		data, err := files.ReadFile(path.Join("data", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		calendar := &model.HolidayCalendar{}
+		if err := json.Unmarshal(data, calendar); err != nil {
+			return nil, fmt.Errorf("invalid holiday calendar %s: %w", entry.Name(), err)
+		}
+		for _, holiday := range calendar.Holidays {
+			if _, err := time.Parse("2006-01-02", holiday.Date); err != nil {
+				return nil, fmt.Errorf("invalid holiday date %q in %s", holiday.Date, entry.Name())
+			}
+		}
+		calendars = append(calendars, calendar)
+	}
+	return calendars, nil
+}
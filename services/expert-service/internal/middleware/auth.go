@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"booking-system/shared/pkg/apperr"
+	"booking-system/shared/pkg/i18n"
+	"booking-system/shared/pkg/jwks"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const RoleAdmin = "admin"
+
+// AuthMiddleware validates the access token issued by user-service and puts
+// the caller's ID ("user_id", uuid.UUID), role ("user_role", string) and saved
+// language ("user_lang", string) in the context.
+func AuthMiddleware(verifier *jwks.Verifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if header == "" || !strings.HasPrefix(header, "Bearer ") {
+			abort(c, http.StatusUnauthorized, "missing_bearer_token")
+			return
+		}
+
+		claims, err := verifier.Verify(strings.TrimPrefix(header, "Bearer "))
+		if err != nil {
+			abort(c, http.StatusUnauthorized, "invalid_token")
+			return
+		}
+
+		userID, err := uuid.Parse(claims.Subject)
+		if err != nil {
+			abort(c, http.StatusUnauthorized, "user_id_missing")
+			return
+		}
+
+		c.Set("user_id", userID)
+		c.Set("user_role", claims.Role)
+		c.Set("user_lang", claims.Language)
+		c.Next()
+	}
+}
+
+// Optional runs auth only for requests that carry an Authorization header,
+// so public routes can still tell who is calling
+func Optional(auth gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		auth(c)
+	}
+}
+
+// RequireRole rejects callers whose role is not one of roles. It must run
+// after AuthMiddleware.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("user_role")
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+		abort(c, http.StatusForbidden, "access_denied")
+	}
+}
+
+// abort answers with a problem for the message code in the caller's
+// language, which is the saved one for RequireRole and Accept-Language
+// before the token is verified
+func abort(c *gin.Context, status int, code string) {
+	lang := i18n.Resolve(c.GetString("user_lang"), c.GetHeader("Accept-Language"))
+	problem := apperr.NewProblem(lang, apperr.New(apperr.KindFor(status), code))
+	problem.Instance = c.Request.URL.Path
+	apperr.Write(c.Writer, problem)
+	c.Abort()
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"expert-service/internal/repository"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequireExpertOwner lets through admins and the account owning the expert
+// whose ID is the path parameter param. It must run after AuthMiddleware.
+func RequireExpertOwner(expertRepo repository.ExpertRepository, param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("user_role") == RoleAdmin {
+			c.Next()
+			return
+		}
+		expertID, err := uuid.Parse(c.Param(param))
+		if err != nil {
+			abort(c, http.StatusBadRequest, "invalid_expert_id")
+			return
+		}
+		expert, err := expertRepo.GetByID(expertID)
+		if err != nil {
+			log.Printf("%s %s failed: %v", c.Request.Method, c.FullPath(), err)
+			abort(c, http.StatusInternalServerError, "internal_error")
+			return
+		}
+		if expert == nil {
+			abort(c, http.StatusNotFound, "expert_not_found")
+			return
+		}
+		userID, _ := c.Get("user_id")
+		if callerID, _ := userID.(uuid.UUID); expert.UserID != callerID {
+			abort(c, http.StatusForbidden, "access_denied")
+			return
+		}
+		c.Next()
+	}
+}
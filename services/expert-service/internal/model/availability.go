@@ -1,18 +1,67 @@
 package model
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+// Availability is a one-off availability slot, or an occurrence expanded from
+// an AvailabilityRule. Occurrences carry RuleID and are identified by their
+// rule and date, see OccurrenceID.
 type Availability struct {
-	ID        uuid.UUID `json:"id" db:"id"`
-	ExpertID  string    `json:"expert_id" db:"expert_id"`
-	Date      string    `json:"date" db:"date"`
-	StartTime string    `json:"start_time" db:"start_time"`
-	EndTime   string    `json:"end_time" db:"end_time"`
-	IsBooked  bool      `json:"is_booked" db:"is_booked"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	ID        string     `json:"id" db:"id"`
+	RuleID    *uuid.UUID `json:"rule_id,omitempty"`
+	ExpertID  string     `json:"expert_id" db:"expert_id"`
+	Date      string     `json:"date" db:"date"`
+	StartTime string     `json:"start_time" db:"start_time"`
+	EndTime   string     `json:"end_time" db:"end_time"`
+	IsBooked  bool       `json:"is_booked" db:"is_booked"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// AvailabilityRule is a weekly availability pattern valid from EffectiveFrom
+// until EffectiveTo (inclusive, nil = open-ended)
+type AvailabilityRule struct {
+	ID            uuid.UUID                   `json:"id" db:"id"`
+	ExpertID      uuid.UUID                   `json:"expert_id" db:"expert_id"`
+	DaysOfWeek    pq.Int64Array               `json:"days_of_week" db:"days_of_week"`
+	StartTime     string                      `json:"start_time" db:"start_time"`
+	EndTime       string                      `json:"end_time" db:"end_time"`
+	EffectiveFrom string                      `json:"effective_from" db:"effective_from"`
+	EffectiveTo   *string                     `json:"effective_to,omitempty" db:"effective_to"`
+	Exceptions    []AvailabilityRuleException `json:"exceptions"`
+	CreatedAt     time.Time                   `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time                   `json:"updated_at" db:"updated_at"`
+}
+
+// OccurrenceID identifies the occurrence of a rule on date ("YYYY-MM-DD")
+func OccurrenceID(ruleID uuid.UUID, date string) string {
+	return ruleID.String() + "_" + date
+}
+
+// ParseOccurrenceID splits an ID made by OccurrenceID; ok is false for the IDs
+// of one-off slots
+func ParseOccurrenceID(id string) (ruleID uuid.UUID, date string, ok bool) {
+	rule, date, found := strings.Cut(id, "_")
+	if !found {
+		return uuid.Nil, "", false
+	}
+	ruleID, err := uuid.Parse(rule)
+	if err != nil {
+		return uuid.Nil, "", false
+	}
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return uuid.Nil, "", false
+	}
+	return ruleID, date, true
+}
+
+// AvailabilityRuleException is a date on which a rule does not apply
+type AvailabilityRuleException struct {
+	Date   string `json:"date" db:"date"`
+	Reason string `json:"reason,omitempty" db:"reason"`
 }
@@ -0,0 +1,83 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Certification verification states. Only verified certifications that
+// have not expired are shown on public profiles and used by search.
+const (
+	CertificationStatusPending  = "pending"
+	CertificationStatusVerified = "verified"
+	CertificationStatusRejected = "rejected"
+)
+
+// ExpertCertification is a qualification an expert claims, with the proof
+// they uploaded for it
+type ExpertCertification struct {
+	ID               uuid.UUID            `json:"id" db:"id"`
+	ExpertID         uuid.UUID            `json:"expert_id" db:"expert_id"`
+	Name             string               `json:"name" db:"name"`
+	Issuer           string               `json:"issuer" db:"issuer"`
+	CredentialNumber string               `json:"credential_number,omitempty" db:"credential_number"`
+	IssuedOn         *string              `json:"issued_on,omitempty" db:"issued_on"`   // YYYY-MM-DD
+	ExpiresOn        *string              `json:"expires_on,omitempty" db:"expires_on"` // YYYY-MM-DD
+	Expired          bool                 `json:"expired"`
+	Status           string               `json:"status" db:"status"`
+	VerifiedBy       *uuid.UUID           `json:"verified_by,omitempty" db:"verified_by"`
+	VerifiedAt       *time.Time           `json:"verified_at,omitempty" db:"verified_at"`
+	RejectionReason  string               `json:"rejection_reason,omitempty" db:"rejection_reason"`
+	ExpiryWarnedAt   *time.Time           `json:"expiry_warned_at,omitempty" db:"expiry_warned_at"`
+	Files            []*CertificationFile `json:"files,omitempty"`
+	CreatedAt        time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time            `json:"updated_at" db:"updated_at"`
+}
+
+// CertificationFile is an uploaded proof document, e.g. a scan of the
+// certificate. The content is only served to the expert and to admins.
+type CertificationFile struct {
+	ID              uuid.UUID `json:"id" db:"id"`
+	CertificationID uuid.UUID `json:"certification_id" db:"certification_id"`
+	FileName        string    `json:"file_name" db:"file_name"`
+	ContentType     string    `json:"content_type" db:"content_type"`
+	SizeBytes       int64     `json:"size_bytes" db:"size_bytes"`
+	StorageKey      string    `json:"-" db:"storage_key"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+}
+
+// ExpiringCertification is a verified certification about to expire, with
+// the account of the expert to warn
+type ExpiringCertification struct {
+	CertificationID uuid.UUID
+	Name            string
+	ExpiresOn       string // YYYY-MM-DD
+	UserID          uuid.UUID
+}
+
+type CertificationRequest struct {
+	Name             string  `json:"name" binding:"required,max=255"`
+	Issuer           string  `json:"issuer" binding:"required,max=255"`
+	CredentialNumber string  `json:"credential_number" binding:"max=100"`
+	IssuedOn         *string `json:"issued_on" binding:"omitempty,datetime=2006-01-02"`
+	ExpiresOn        *string `json:"expires_on" binding:"omitempty,datetime=2006-01-02"`
+}
+
+type VerifyCertificationRequest struct {
+	Status string `json:"status" binding:"required,oneof=verified rejected"`
+	Reason string `json:"reason"`
+}
+
+type GetCertificationsRequest struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending verified rejected"`
+	Page   int    `form:"page"`
+	Limit  int    `form:"limit"`
+}
+
+type CertificationListResponse struct {
+	Certifications []*ExpertCertification `json:"certifications"`
+	Total          int                    `json:"total"`
+	Page           int                    `json:"page"`
+	Limit          int                    `json:"limit"`
+}
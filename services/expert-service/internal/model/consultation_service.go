@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ConsultationService is a named consultation type offered by an expert,
+// e.g. "30-min intro" or "90-min deep dive"
+type ConsultationService struct {
+	ID              uuid.UUID `json:"id" db:"id"`
+	ExpertID        uuid.UUID `json:"expert_id" db:"expert_id"`
+	Name            string    `json:"name" db:"name"`
+	Description     string    `json:"description" db:"description"`
+	DurationMinutes int       `json:"duration_minutes" db:"duration_minutes"`
+	Price           float64   `json:"price" db:"price"`
+	MeetingType     string    `json:"meeting_type" db:"meeting_type"`
+	IsActive        bool      `json:"is_active" db:"is_active"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// HolidayCalendar is a country's list of public holidays
+type HolidayCalendar struct {
+	Code        string    `json:"code" db:"code"`
+	Name        string    `json:"name" db:"name"`
+	CountryCode string    `json:"country_code" db:"country_code"`
+	Holidays    []Holiday `json:"holidays,omitempty"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Holiday is one public holiday of a calendar
+type Holiday struct {
+	CalendarCode string `json:"calendar_code,omitempty" db:"calendar_code"`
+	Date         string `json:"date" db:"date"` // YYYY-MM-DD
+	Name         string `json:"name" db:"name"`
+}
+
+// ExpertHolidaySettings lists the calendars an expert observes and the
+// holidays on which they work anyway. IsDefault is set when the expert never
+// chose calendars and observes the service's default calendar.
+type ExpertHolidaySettings struct {
+	ExpertID      uuid.UUID         `json:"expert_id" db:"expert_id"`
+	CalendarCodes pq.StringArray    `json:"calendar_codes" db:"calendar_codes"`
+	IsDefault     bool              `json:"is_default"`
+	Overrides     []HolidayOverride `json:"overrides"`
+}
+
+// HolidayOverride is a holiday on which an expert accepts bookings
+type HolidayOverride struct {
+	Date string `json:"date" db:"date"` // YYYY-MM-DD
+	Note string `json:"note,omitempty" db:"note"`
+}
@@ -4,14 +4,136 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+// Off-time recurrence frequencies
+const (
+	RecurrenceDaily   = "daily"
+	RecurrenceWeekly  = "weekly"
+	RecurrenceMonthly = "monthly"
+)
+
+// OffTime is a period when an expert is unavailable. For a recurring off-time
+// StartDateTime/EndDateTime hold the first occurrence and the recurrence
+// fields describe the rest; Occurrences expands them.
 type OffTime struct {
-	ID            uuid.UUID `json:"id" db:"id"`
-	ExpertID      uuid.UUID `json:"expert_id" db:"expert_id"`
-	StartDateTime time.Time `json:"start_datetime" db:"start_datetime"`
-	EndDateTime   time.Time `json:"end_datetime" db:"end_datetime"`
-	Reason        string    `json:"reason" db:"reason"`
-	IsRecurring   bool      `json:"is_recurring" db:"is_recurring"`
-	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	ID                   uuid.UUID          `json:"id" db:"id"`
+	ExpertID             uuid.UUID          `json:"expert_id" db:"expert_id"`
+	StartDateTime        time.Time          `json:"start_datetime" db:"start_datetime"`
+	EndDateTime          time.Time          `json:"end_datetime" db:"end_datetime"`
+	Reason               string             `json:"reason" db:"reason"`
+	IsRecurring          bool               `json:"is_recurring" db:"is_recurring"`
+	RecurrenceFrequency  string             `json:"recurrence_frequency,omitempty" db:"recurrence_frequency"`
+	RecurrenceInterval   int                `json:"recurrence_interval,omitempty" db:"recurrence_interval"`
+	RecurrenceDaysOfWeek pq.Int64Array      `json:"recurrence_days_of_week,omitempty" db:"recurrence_days_of_week"`
+	RecurrenceUntil      *string            `json:"recurrence_until,omitempty" db:"recurrence_until"`
+	Exceptions           []OffTimeException `json:"exceptions,omitempty"`
+	// OccurrenceDate is set on occurrences expanded from a recurring off-time
+	OccurrenceDate string    `json:"occurrence_date,omitempty"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
+
+// OffTimeException is a date on which a recurring off-time does not occur
+type OffTimeException struct {
+	Date   string `json:"date" db:"date"`
+	Reason string `json:"reason,omitempty" db:"reason"`
+}
+
+// Occurrences returns the occurrences of the off-time that overlap [from, to).
+// A one-off off-time is its own single occurrence. Occurrences of a recurring
+// off-time keep its ID, start at the same clock time as the first one and last
+// as long. Exception dates and RecurrenceUntil are in the first occurrence's
+// location.
+func (o *OffTime) Occurrences(from, to time.Time) []*OffTime {
+	if !o.IsRecurring {
+		if o.StartDateTime.Before(to) && o.EndDateTime.After(from) {
+			return []*OffTime{o}
+		}
+		return nil
+	}
+
+	loc := o.StartDateTime.Location()
+	duration := o.EndDateTime.Sub(o.StartDateTime)
+	first := o.StartDateTime
+	skipped := make(map[string]bool, len(o.Exceptions))
+	for _, exception := range o.Exceptions {
+		skipped[exception.Date] = true
+	}
+
+	// An occurrence overlapping the range starts at most one duration before it
+	day := from.Add(-duration).In(loc)
+	if day.Before(first) {
+		day = first
+	}
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+
+	var occurrences []*OffTime
+	for ; day.Before(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		if o.RecurrenceUntil != nil && date > *o.RecurrenceUntil {
+			break
+		}
+		if skipped[date] || !o.occursOn(day) {
+			continue
+		}
+		start := time.Date(day.Year(), day.Month(), day.Day(),
+			first.Hour(), first.Minute(), first.Second(), first.Nanosecond(), loc)
+		end := start.Add(duration)
+		if start.Before(first) || !start.Before(to) || !end.After(from) {
+			continue
+		}
+		occurrence := *o
+		occurrence.StartDateTime = start
+		occurrence.EndDateTime = end
+		occurrence.OccurrenceDate = date
+		occurrence.Exceptions = nil
+		occurrences = append(occurrences, &occurrence)
+	}
+	return occurrences
+}
+
+// occursOn reports whether the recurrence pattern has an occurrence on day,
+// a midnight in the first occurrence's location
+func (o *OffTime) occursOn(day time.Time) bool {
+	first := o.StartDateTime
+	interval := o.RecurrenceInterval
+	if interval < 1 {
+		interval = 1
+	}
+	days := daysBetween(first, day)
+	if days < 0 {
+		return false
+	}
+
+	switch o.RecurrenceFrequency {
+	case RecurrenceDaily:
+		return days%interval == 0
+	case RecurrenceMonthly:
+		months := (day.Year()-first.Year())*12 + int(day.Month()-first.Month())
+		return day.Day() == first.Day() && months%interval == 0
+	default:
+		// Weekly, also for off-times marked recurring before frequencies existed
+		weekdays := o.RecurrenceDaysOfWeek
+		if len(weekdays) == 0 {
+			weekdays = pq.Int64Array{int64(first.Weekday())}
+		}
+		matches := false
+		for _, weekday := range weekdays {
+			if int(weekday) == int(day.Weekday()) {
+				matches = true
+				break
+			}
+		}
+		// Weeks start on Sunday, counted from the week of the first occurrence
+		weeks := (days + int(first.Weekday())) / 7
+		return matches && weeks%interval == 0
+	}
+}
+
+// daysBetween counts calendar days from a to b, ignoring clock time and DST
+func daysBetween(a, b time.Time) int {
+	da := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	db := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(db.Sub(da).Hours() / 24)
 }
@@ -0,0 +1,145 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func TestOffTimeOccurrences(t *testing.T) {
+	at := func(value string) time.Time {
+		parsed, err := time.ParseInLocation("2006-01-02 15:04", value, time.UTC)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+	until := func(date string) *string { return &date }
+
+	tests := []struct {
+		name    string
+		offTime OffTime
+		from    string
+		to      string
+		// Start of each expected occurrence
+		want []string
+	}{
+		{
+			name:    "one-off inside range",
+			offTime: OffTime{StartDateTime: at("2026-01-05 09:00"), EndDateTime: at("2026-01-05 10:00")},
+			from:    "2026-01-01 00:00",
+			to:      "2026-01-10 00:00",
+			want:    []string{"2026-01-05 09:00"},
+		},
+		{
+			name:    "one-off outside range",
+			offTime: OffTime{StartDateTime: at("2026-01-05 09:00"), EndDateTime: at("2026-01-05 10:00")},
+			from:    "2026-01-05 10:00",
+			to:      "2026-01-10 00:00",
+		},
+		{
+			name: "daily from first occurrence",
+			offTime: OffTime{
+				StartDateTime: at("2026-01-05 09:00"), EndDateTime: at("2026-01-05 10:00"),
+				IsRecurring: true, RecurrenceFrequency: RecurrenceDaily,
+			},
+			from: "2026-01-01 00:00",
+			to:   "2026-01-07 00:00",
+			want: []string{"2026-01-05 09:00", "2026-01-06 09:00"},
+		},
+		{
+			name: "weekly on several days",
+			offTime: OffTime{
+				StartDateTime: at("2026-01-05 09:00"), EndDateTime: at("2026-01-05 10:00"),
+				IsRecurring: true, RecurrenceFrequency: RecurrenceWeekly,
+				RecurrenceDaysOfWeek: pq.Int64Array{1, 3},
+			},
+			from: "2026-01-05 00:00",
+			to:   "2026-01-12 00:00",
+			want: []string{"2026-01-05 09:00", "2026-01-07 09:00"},
+		},
+		{
+			name: "weekly every other week",
+			offTime: OffTime{
+				StartDateTime: at("2026-01-05 09:00"), EndDateTime: at("2026-01-05 10:00"),
+				IsRecurring: true, RecurrenceFrequency: RecurrenceWeekly, RecurrenceInterval: 2,
+				RecurrenceDaysOfWeek: pq.Int64Array{1},
+			},
+			from: "2026-01-01 00:00",
+			to:   "2026-02-01 00:00",
+			want: []string{"2026-01-05 09:00", "2026-01-19 09:00"},
+		},
+		{
+			name: "weekly defaults to the first occurrence's weekday",
+			offTime: OffTime{
+				StartDateTime: at("2026-01-07 09:00"), EndDateTime: at("2026-01-07 10:00"),
+				IsRecurring: true,
+			},
+			from: "2026-01-01 00:00",
+			to:   "2026-01-20 00:00",
+			want: []string{"2026-01-07 09:00", "2026-01-14 09:00"},
+		},
+		{
+			name: "monthly every other month",
+			offTime: OffTime{
+				StartDateTime: at("2026-01-15 10:00"), EndDateTime: at("2026-01-15 12:00"),
+				IsRecurring: true, RecurrenceFrequency: RecurrenceMonthly, RecurrenceInterval: 2,
+			},
+			from: "2026-01-01 00:00",
+			to:   "2026-07-01 00:00",
+			want: []string{"2026-01-15 10:00", "2026-03-15 10:00", "2026-05-15 10:00"},
+		},
+		{
+			name: "until is inclusive",
+			offTime: OffTime{
+				StartDateTime: at("2026-01-01 09:00"), EndDateTime: at("2026-01-01 10:00"),
+				IsRecurring: true, RecurrenceFrequency: RecurrenceDaily,
+				RecurrenceUntil: until("2026-01-03"),
+			},
+			from: "2026-01-01 00:00",
+			to:   "2026-01-10 00:00",
+			want: []string{"2026-01-01 09:00", "2026-01-02 09:00", "2026-01-03 09:00"},
+		},
+		{
+			name: "exceptions are skipped",
+			offTime: OffTime{
+				StartDateTime: at("2026-01-01 09:00"), EndDateTime: at("2026-01-01 10:00"),
+				IsRecurring: true, RecurrenceFrequency: RecurrenceDaily,
+				Exceptions: []OffTimeException{{Date: "2026-01-02"}},
+			},
+			from: "2026-01-01 00:00",
+			to:   "2026-01-04 00:00",
+			want: []string{"2026-01-01 09:00", "2026-01-03 09:00"},
+		},
+		{
+			name: "occurrence spanning midnight overlaps the next day",
+			offTime: OffTime{
+				StartDateTime: at("2026-01-01 22:00"), EndDateTime: at("2026-01-02 02:00"),
+				IsRecurring: true, RecurrenceFrequency: RecurrenceDaily,
+			},
+			from: "2026-01-03 00:00",
+			to:   "2026-01-03 12:00",
+			want: []string{"2026-01-02 22:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			occurrences := tt.offTime.Occurrences(at(tt.from), at(tt.to))
+			var got []string
+			for _, occurrence := range occurrences {
+				got = append(got, occurrence.StartDateTime.Format("2006-01-02 15:04"))
+				duration := tt.offTime.EndDateTime.Sub(tt.offTime.StartDateTime)
+				if occurrence.EndDateTime.Sub(occurrence.StartDateTime) != duration {
+					t.Errorf("occurrence %s lasts %s, want %s", got[len(got)-1],
+						occurrence.EndDateTime.Sub(occurrence.StartDateTime), duration)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Occurrences() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Expert application states, in the order an application normally moves
+// through them. A rejected applicant may apply again.
+const (
+	ApplicationStatusApplied            = "applied"
+	ApplicationStatusDocumentsSubmitted = "documents_submitted"
+	ApplicationStatusUnderReview        = "under_review"
+	ApplicationStatusApproved           = "approved"
+	ApplicationStatusRejected           = "rejected"
+)
+
+// applicationTransitions lists the states each state may move to
+var applicationTransitions = map[string][]string{
+	ApplicationStatusApplied:            {ApplicationStatusDocumentsSubmitted},
+	ApplicationStatusDocumentsSubmitted: {ApplicationStatusUnderReview, ApplicationStatusRejected},
+	ApplicationStatusUnderReview:        {ApplicationStatusApproved, ApplicationStatusRejected},
+	ApplicationStatusRejected:           {ApplicationStatusApplied},
+}
+
+// CanTransitionApplication reports whether an application may move from one
+// state to another
+func CanTransitionApplication(from, to string) bool {
+	for _, next := range applicationTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpertApplication is a user's request to become an expert
+type ExpertApplication struct {
+	ID              uuid.UUID              `json:"id" db:"id"`
+	UserID          uuid.UUID              `json:"user_id" db:"user_id"`
+	Status          string                 `json:"status" db:"status"`
+	Specialization  string                 `json:"specialization" db:"specialization"`
+	ExperienceYears int                    `json:"experience_years" db:"experience_years"`
+	HourlyRate      float64                `json:"hourly_rate" db:"hourly_rate"`
+	Certifications  pq.StringArray         `json:"certifications" db:"certifications"`
+	Bio             string                 `json:"bio,omitempty" db:"bio"`
+	ReviewerID      *uuid.UUID             `json:"reviewer_id,omitempty" db:"reviewer_id"`
+	ReviewNotes     string                 `json:"review_notes,omitempty" db:"review_notes"`
+	RejectionReason string                 `json:"rejection_reason,omitempty" db:"rejection_reason"`
+	ExpertID        *uuid.UUID             `json:"expert_id,omitempty" db:"expert_id"`
+	SubmittedAt     *time.Time             `json:"submitted_at,omitempty" db:"submitted_at"`
+	ReviewedAt      *time.Time             `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	CreatedAt       time.Time              `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time              `json:"updated_at" db:"updated_at"`
+	Documents       []*ApplicationDocument `json:"documents"`
+}
+
+type ApplicationDocument struct {
+	ID            uuid.UUID `json:"id" db:"id"`
+	ApplicationID uuid.UUID `json:"application_id" db:"application_id"`
+	Name          string    `json:"name" db:"name"`
+	URL           string    `json:"url" db:"url"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+type CreateApplicationRequest struct {
+	Specialization  string   `json:"specialization" binding:"required"`
+	ExperienceYears int      `json:"experience_years" binding:"min=0"`
+	HourlyRate      float64  `json:"hourly_rate" binding:"required,gt=0"`
+	Certifications  []string `json:"certifications"`
+	Bio             string   `json:"bio"`
+}
+
+type AddApplicationDocumentRequest struct {
+	Name string `json:"name" binding:"required"`
+	URL  string `json:"url" binding:"required,url"`
+}
+
+type ApproveApplicationRequest struct {
+	Notes string `json:"notes"`
+}
+
+type RejectApplicationRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type GetApplicationsRequest struct {
+	Status string `form:"status" binding:"omitempty,oneof=applied documents_submitted under_review approved rejected"`
+	Page   int    `form:"page"`
+	Limit  int    `form:"limit"`
+}
+
+type ApplicationListResponse struct {
+	Applications []*ExpertApplication `json:"applications"`
+	Total        int                  `json:"total"`
+	Page         int                  `json:"page"`
+	Limit        int                  `json:"limit"`
+}
@@ -0,0 +1,11 @@
+package model
+
+import "github.com/google/uuid"
+
+// UserDataExport holds what expert-service stores about a user, for the
+// user's data export
+type UserDataExport struct {
+	UserID            uuid.UUID          `json:"user_id"`
+	Reviews           []*Review          `json:"reviews"`
+	ExpertApplication *ExpertApplication `json:"expert_application"`
+}
@@ -1,7 +1,9 @@
 package model
 
+import "time"
+
 type CreateExpertRequest struct {
-	UserID          string   `json:"user_id" binding:"required"`
+	UserID          string   `json:"user_id" binding:"required,uuid"`
 	Specialization  string   `json:"specialization" binding:"required"`
 	ExperienceYears int      `json:"experience_years" binding:"required"`
 	HourlyRate      float64  `json:"hourly_rate" binding:"required"`
@@ -29,6 +31,16 @@ type CreateOffTimeRequest struct {
 	EndDateTime   string `json:"end_datetime" binding:"required"`
 	Reason        string `json:"reason"`
 	IsRecurring   bool   `json:"is_recurring"`
+	// Recurrence of a recurring off-time; the first occurrence is StartDateTime-EndDateTime
+	RecurrenceFrequency  string `json:"recurrence_frequency,omitempty" binding:"omitempty,oneof=daily weekly monthly"` // default weekly
+	RecurrenceInterval   int    `json:"recurrence_interval,omitempty" binding:"omitempty,min=1"`                       // every N days/weeks/months
+	RecurrenceDaysOfWeek []int  `json:"recurrence_days_of_week,omitempty" binding:"omitempty,dive,min=0,max=6"`        // weekly only, default the first occurrence's weekday
+	RecurrenceUntil      string `json:"recurrence_until,omitempty"`                                                    // YYYY-MM-DD, empty = open-ended
+}
+
+type CreateOffTimeExceptionRequest struct {
+	Date   string `json:"date" binding:"required"` // YYYY-MM-DD
+	Reason string `json:"reason"`
 }
 
 type CheckAvailabilityRequest struct {
@@ -41,17 +53,32 @@ type UpdateExpertRequest struct {
 	Specialization  *string  `json:"specialization,omitempty"`
 	ExperienceYears *int     `json:"experience_years,omitempty"`
 	HourlyRate      *float64 `json:"hourly_rate,omitempty"`
-	Certifications  []string `json:"certifications,omitempty"`
 	IsAvailable     *bool    `json:"is_available,omitempty"`
 }
 
 type CreateRecurringAvailabilityRequest struct {
 	ExpertID   string `json:"expert_id" binding:"required"`
-	DaysOfWeek []int  `json:"days_of_week" binding:"required"` // 0=Sunday, 6=Saturday
+	DaysOfWeek []int  `json:"days_of_week" binding:"required,min=1,dive,min=0,max=6"` // 0=Sunday, 6=Saturday
 	StartTime  string `json:"start_time" binding:"required"`
 	EndTime    string `json:"end_time" binding:"required"`
 	StartDate  string `json:"start_date" binding:"required"` // YYYY-MM-DD
-	EndDate    string `json:"end_date" binding:"required"`   // YYYY-MM-DD
+	EndDate    string `json:"end_date,omitempty"`            // YYYY-MM-DD, empty = open-ended
+}
+
+// UpdateAvailabilityRuleRequest changes a recurring rule. With FromDate set
+// after the rule's start, occurrences before FromDate are kept as they are and
+// the change applies from FromDate on.
+type UpdateAvailabilityRuleRequest struct {
+	FromDate   string  `json:"from_date,omitempty"` // YYYY-MM-DD
+	DaysOfWeek []int   `json:"days_of_week,omitempty" binding:"omitempty,min=1,dive,min=0,max=6"`
+	StartTime  *string `json:"start_time,omitempty"`
+	EndTime    *string `json:"end_time,omitempty"`
+	EndDate    *string `json:"end_date,omitempty"` // YYYY-MM-DD, "" = open-ended
+}
+
+type CreateAvailabilityRuleExceptionRequest struct {
+	Date   string `json:"date" binding:"required"` // YYYY-MM-DD
+	Reason string `json:"reason"`
 }
 
 type GetSchedulesRequest struct {
@@ -92,3 +119,85 @@ type UpdateAvailabilityRequest struct {
 	EndTime   *string `json:"end_time,omitempty"`
 	IsBooked  *bool   `json:"is_booked,omitempty"`
 }
+
+type GetSlotsRequest struct {
+	ExpertID           string `form:"expert_id" binding:"required"`
+	StartDate          string `form:"start_date" binding:"required"` // YYYY-MM-DD
+	EndDate            string `form:"end_date" binding:"required"`   // YYYY-MM-DD
+	DurationMinutes    int    `form:"duration" binding:"required,min=15,max=480"`
+	GranularityMinutes int    `form:"granularity"`
+}
+
+type UpdateBookingRuleRequest struct {
+	BufferBeforeMinutes *int `json:"buffer_before_minutes,omitempty" binding:"omitempty,min=0,max=240"`
+	BufferAfterMinutes  *int `json:"buffer_after_minutes,omitempty" binding:"omitempty,min=0,max=240"`
+	MinNoticeMinutes    *int `json:"min_notice_minutes,omitempty" binding:"omitempty,min=0"`
+	MaxAdvanceDays      *int `json:"max_advance_days,omitempty" binding:"omitempty,min=1,max=365"`
+	MaxBookingsPerDay   *int `json:"max_bookings_per_day,omitempty" binding:"omitempty,min=0"`
+}
+
+type CheckBookingWindowRequest struct {
+	ExpertID        string    `json:"expert_id" binding:"required"`
+	StartTime       time.Time `json:"start_time" binding:"required"`
+	DurationMinutes int       `json:"duration_minutes" binding:"required,min=1,max=1440"`
+}
+
+type CreateConsultationServiceRequest struct {
+	Name            string  `json:"name" binding:"required,max=255"`
+	Description     string  `json:"description"`
+	DurationMinutes int     `json:"duration_minutes" binding:"required,min=15,max=480"`
+	Price           float64 `json:"price" binding:"min=0"`
+	MeetingType     string  `json:"meeting_type" binding:"required,oneof=online offline"`
+}
+
+type UpdateConsultationServiceRequest struct {
+	Name            *string  `json:"name,omitempty" binding:"omitempty,max=255"`
+	Description     *string  `json:"description,omitempty"`
+	DurationMinutes *int     `json:"duration_minutes,omitempty" binding:"omitempty,min=15,max=480"`
+	Price           *float64 `json:"price,omitempty" binding:"omitempty,min=0"`
+	MeetingType     *string  `json:"meeting_type,omitempty" binding:"omitempty,oneof=online offline"`
+	IsActive        *bool    `json:"is_active,omitempty"`
+}
+
+// SearchExpertsRequest holds the filters, sort and page of an expert search.
+// AvailableFrom/AvailableTo (RFC3339) keep only experts with a free slot of
+// DurationMinutes in that window.
+type SearchExpertsRequest struct {
+	Query          string    `form:"q"`
+	Specialization string    `form:"specialization"`
+	MinRate        *float64  `form:"min_rate" binding:"omitempty,min=0"`
+	MaxRate        *float64  `form:"max_rate" binding:"omitempty,min=0"`
+	MinRating      *float64  `form:"min_rating" binding:"omitempty,min=0,max=5"`
+	MinExperience  *int      `form:"min_experience" binding:"omitempty,min=0"`
+	Certifications []string  `form:"certifications"`
+	MeetingType    string    `form:"meeting_type" binding:"omitempty,oneof=online offline"`
+	AvailableFrom  time.Time `form:"available_from"`
+	AvailableTo    time.Time `form:"available_to"`
+	Duration       int       `form:"duration" binding:"omitempty,min=15,max=480"`
+	Sort           string    `form:"sort" binding:"omitempty,oneof=relevance price_asc price_desc rating availability"`
+	Page           int       `form:"page"`
+	Limit          int       `form:"limit"`
+}
+
+type HolidayRequest struct {
+	Date string `json:"date" binding:"required"` // YYYY-MM-DD
+	Name string `json:"name" binding:"required"`
+}
+
+// UpdateHolidayCalendarRequest creates a calendar or replaces all of its holidays
+type UpdateHolidayCalendarRequest struct {
+	Name        string           `json:"name" binding:"required"`
+	CountryCode string           `json:"country_code" binding:"required,len=2"`
+	Holidays    []HolidayRequest `json:"holidays" binding:"dive"`
+}
+
+// UpdateExpertHolidayCalendarsRequest sets the calendars an expert observes;
+// an empty list opts out of all holidays
+type UpdateExpertHolidayCalendarsRequest struct {
+	CalendarCodes []string `json:"calendar_codes" binding:"required"`
+}
+
+type CreateHolidayOverrideRequest struct {
+	Date string `json:"date" binding:"required"` // YYYY-MM-DD
+	Note string `json:"note"`
+}
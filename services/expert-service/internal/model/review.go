@@ -0,0 +1,72 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Review moderation states. Hidden reviews are excluded from public listings
+// and from the expert's rating; flagged reviews stay visible until an admin
+// decides.
+const (
+	ReviewStatusVisible = "visible"
+	ReviewStatusHidden  = "hidden"
+	ReviewStatusFlagged = "flagged"
+)
+
+// Review is a user's rating of a completed booking
+type Review struct {
+	ID               uuid.UUID  `json:"id" db:"id"`
+	BookingID        uuid.UUID  `json:"booking_id" db:"booking_id"`
+	UserID           uuid.UUID  `json:"user_id" db:"user_id"`
+	ExpertID         uuid.UUID  `json:"expert_id" db:"expert_id"`
+	Rating           int        `json:"rating" db:"rating"`
+	Comment          string     `json:"comment" db:"comment"`
+	ExpertReply      string     `json:"expert_reply,omitempty" db:"expert_reply"`
+	RepliedAt        *time.Time `json:"replied_at,omitempty" db:"replied_at"`
+	Status           string     `json:"status" db:"status"`
+	ModerationReason string     `json:"moderation_reason,omitempty" db:"moderation_reason"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// ReviewableBooking is the part of a booking needed to decide whether it can be reviewed
+type ReviewableBooking struct {
+	ID       uuid.UUID `db:"id"`
+	UserID   uuid.UUID `db:"user_id"`
+	ExpertID uuid.UUID `db:"expert_id"`
+	Status   string    `db:"status"`
+}
+
+type CreateReviewRequest struct {
+	BookingID string `json:"booking_id" binding:"required,uuid"`
+	Rating    int    `json:"rating" binding:"required,min=1,max=5"`
+	Comment   string `json:"comment"`
+}
+
+type UpdateReviewRequest struct {
+	Rating  *int    `json:"rating,omitempty" binding:"omitempty,min=1,max=5"`
+	Comment *string `json:"comment,omitempty"`
+}
+
+type ReplyReviewRequest struct {
+	Reply string `json:"reply" binding:"required"`
+}
+
+type ModerateReviewRequest struct {
+	Status string `json:"status" binding:"required,oneof=visible hidden flagged"`
+	Reason string `json:"reason"`
+}
+
+type GetReviewsRequest struct {
+	Page  int `form:"page"`
+	Limit int `form:"limit"`
+}
+
+type ReviewListResponse struct {
+	Reviews []*Review `json:"reviews"`
+	Total   int       `json:"total"`
+	Page    int       `json:"page"`
+	Limit   int       `json:"limit"`
+}
@@ -0,0 +1,47 @@
+package model
+
+// Sort orders accepted by the expert search
+const (
+	SearchSortRelevance    = "relevance"
+	SearchSortPriceAsc     = "price_asc"
+	SearchSortPriceDesc    = "price_desc"
+	SearchSortRating       = "rating"
+	SearchSortAvailability = "availability"
+)
+
+// ExpertSearchResult is an expert matched by a search. WeightedRating is the
+// Bayesian average used for ranking, so a single 5-star review does not beat
+// a long record of 4.8s. NextAvailableSlot is the first free slot in the
+// requested window, or in the coming two weeks when none was requested.
+type ExpertSearchResult struct {
+	*Expert
+	FullName          string   `json:"fullname"`
+	MeetingTypes      []string `json:"meeting_types"`
+	WeightedRating    float64  `json:"weighted_rating"`
+	Relevance         float64  `json:"relevance"`
+	NextAvailableSlot *Slot    `json:"next_available_slot,omitempty"`
+}
+
+// FacetCount is the number of matching experts sharing a facet value
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// SearchFacets summarizes all experts matching the filters, before paging
+type SearchFacets struct {
+	Specializations  []FacetCount `json:"specializations"`
+	Certifications   []FacetCount `json:"certifications"`
+	MeetingTypes     []FacetCount `json:"meeting_types"`
+	PriceRanges      []FacetCount `json:"price_ranges"`
+	RatingRanges     []FacetCount `json:"rating_ranges"`
+	ExperienceRanges []FacetCount `json:"experience_ranges"`
+}
+
+type SearchExpertsResponse struct {
+	Experts []*ExpertSearchResult `json:"experts"`
+	Facets  SearchFacets          `json:"facets"`
+	Total   int                   `json:"total"`
+	Page    int                   `json:"page"`
+	Limit   int                   `json:"limit"`
+}
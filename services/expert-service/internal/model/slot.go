@@ -0,0 +1,59 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Slot is a bookable start time returned by the slot computation
+type Slot struct {
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+// BookedInterval is the part of a booking that blocks an expert's calendar
+type BookedInterval struct {
+	BookingID uuid.UUID `json:"booking_id" db:"id"`
+	StartTime time.Time `json:"start_time" db:"scheduled_datetime"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+// BookingRule holds the per-expert constraints applied when computing slots
+type BookingRule struct {
+	ExpertID            uuid.UUID `json:"expert_id" db:"expert_id"`
+	BufferBeforeMinutes int       `json:"buffer_before_minutes" db:"buffer_before_minutes"`
+	BufferAfterMinutes  int       `json:"buffer_after_minutes" db:"buffer_after_minutes"`
+	MinNoticeMinutes    int       `json:"min_notice_minutes" db:"min_notice_minutes"`
+	MaxAdvanceDays      int       `json:"max_advance_days" db:"max_advance_days"`
+	MaxBookingsPerDay   int       `json:"max_bookings_per_day" db:"max_bookings_per_day"`
+	CreatedAt           time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// DefaultBookingRule returns the rule used for experts that have not configured one
+func DefaultBookingRule(expertID uuid.UUID) *BookingRule {
+	return &BookingRule{
+		ExpertID:          expertID,
+		MinNoticeMinutes:  60,
+		MaxAdvanceDays:    90,
+		MaxBookingsPerDay: 0, // 0 = unlimited
+	}
+}
+
+// Reasons returned when a booking window is rejected
+const (
+	WindowReasonExpertNotFound      = "expert_not_found"
+	WindowReasonExpertUnavailable   = "expert_unavailable"
+	WindowReasonExpertUnverified    = "expert_unverified"
+	WindowReasonExpertNotApproved   = "expert_not_approved"
+	WindowReasonOutsideWorkingHours = "outside_working_hours"
+	WindowReasonOffTime             = "off_time"
+)
+
+// BookingWindowCheck is the answer to "can this expert take a session from
+// StartTime for DurationMinutes", ignoring bookings owned by booking-service
+type BookingWindowCheck struct {
+	Available bool   `json:"available"`
+	Reason    string `json:"reason,omitempty"`
+}
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"database/sql"
+	"expert-service/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AvailabilityRepository interface {
+	Create(availability *model.Availability) error
+	CreateBatch(availabilities []*model.Availability) error
+	GetByID(id uuid.UUID) (*model.Availability, error)
+	GetByExpertIDAndDateRange(expertID uuid.UUID, startDate, endDate time.Time) ([]*model.Availability, error)
+	HasOverlap(availability *model.Availability) (bool, error)
+	HasRuleOverlap(rule *model.AvailabilityRule) (bool, error)
+	Update(availability *model.Availability) error
+	Delete(id string) error
+	Book(id string) (bool, error)
+}
+
+type availabilityRepository struct {
+	db *sql.DB
+}
+
+func NewAvailabilityRepository(db *sql.DB) AvailabilityRepository {
+	return &availabilityRepository{db: db}
+}
+
+// Dates and times are formatted in SQL so the model keeps the
+// "YYYY-MM-DD" / "HH:MM" strings used by the API
+const availabilityColumns = `id, expert_id, to_char(date, 'YYYY-MM-DD'), to_char(start_time, 'HH24:MI'), to_char(end_time, 'HH24:MI'), is_booked, created_at, updated_at`
+
+const insertAvailabilityQuery = `
+	INSERT INTO expert_availabilities (id, expert_id, date, start_time, end_time, is_booked, created_at, updated_at)
+	VALUES ($1, $2, $3::date, $4::time, $5::time, $6, $7, $8)
+	RETURNING id, created_at, updated_at`
+
+func scanAvailability(row interface {
+	Scan(dest ...interface{}) error
+}) (*model.Availability, error) {
+	availability := &model.Availability{}
+	err := row.Scan(
+		&availability.ID, &availability.ExpertID, &availability.Date,
+		&availability.StartTime, &availability.EndTime, &availability.IsBooked,
+		&availability.CreatedAt, &availability.UpdatedAt)
+	return availability, err
+}
+
+func (r *availabilityRepository) Create(availability *model.Availability) error {
+	availability.ID = uuid.New().String()
+	availability.CreatedAt = time.Now()
+	availability.UpdatedAt = time.Now()
+
+	return r.db.QueryRow(insertAvailabilityQuery,
+		availability.ID, availability.ExpertID, availability.Date,
+		availability.StartTime, availability.EndTime, availability.IsBooked,
+		availability.CreatedAt, availability.UpdatedAt).
+		Scan(&availability.ID, &availability.CreatedAt, &availability.UpdatedAt)
+}
+
+// CreateBatch inserts all slots or none of them
+func (r *availabilityRepository) CreateBatch(availabilities []*model.Availability) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(insertAvailabilityQuery)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, availability := range availabilities {
+		availability.ID = uuid.New().String()
+		availability.CreatedAt = time.Now()
+		availability.UpdatedAt = time.Now()
+		err := stmt.QueryRow(
+			availability.ID, availability.ExpertID, availability.Date,
+			availability.StartTime, availability.EndTime, availability.IsBooked,
+			availability.CreatedAt, availability.UpdatedAt).
+			Scan(&availability.ID, &availability.CreatedAt, &availability.UpdatedAt)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *availabilityRepository) GetByID(id uuid.UUID) (*model.Availability, error) {
+	query := `SELECT ` + availabilityColumns + ` FROM expert_availabilities WHERE id = $1`
+	availability, err := scanAvailability(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return availability, nil
+}
+
+func (r *availabilityRepository) GetByExpertIDAndDateRange(expertID uuid.UUID, startDate, endDate time.Time) ([]*model.Availability, error) {
+	query := `
+		SELECT ` + availabilityColumns + `
+		FROM expert_availabilities
+		WHERE expert_id = $1 AND date BETWEEN $2::date AND $3::date
+		ORDER BY date, start_time`
+
+	rows, err := r.db.Query(query, expertID, startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var availabilities []*model.Availability
+	for rows.Next() {
+		availability, err := scanAvailability(rows)
+		if err != nil {
+			return nil, err
+		}
+		availabilities = append(availabilities, availability)
+	}
+	return availabilities, rows.Err()
+}
+
+// HasOverlap reports whether another slot of the same expert and day
+// intersects the given one
+func (r *availabilityRepository) HasOverlap(availability *model.Availability) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM expert_availabilities
+			WHERE expert_id = $1 AND date = $2::date AND id <> $3
+			  AND start_time < $5::time AND end_time > $4::time
+		)`
+	// New slots have no ID yet
+	excludeID := availability.ID
+	if excludeID == "" {
+		excludeID = uuid.Nil.String()
+	}
+	var exists bool
+	err := r.db.QueryRow(query,
+		availability.ExpertID, availability.Date, excludeID,
+		availability.StartTime, availability.EndTime).Scan(&exists)
+	return exists, err
+}
+
+// HasRuleOverlap reports whether a slot of the rule's expert intersects an
+// occurrence of the rule, leaving out the rule's exception dates
+func (r *availabilityRepository) HasRuleOverlap(rule *model.AvailabilityRule) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM expert_availabilities
+			WHERE expert_id = $1 AND date >= $2::date AND ($3::date IS NULL OR date <= $3::date)
+			  AND EXTRACT(DOW FROM date)::int = ANY($4)
+			  AND start_time < $6::time AND end_time > $5::time
+			  AND date NOT IN (SELECT date FROM expert_availability_rule_exceptions WHERE rule_id = $7)
+		)`
+	var exists bool
+	err := r.db.QueryRow(query,
+		rule.ExpertID, rule.EffectiveFrom, rule.EffectiveTo, rule.DaysOfWeek,
+		rule.StartTime, rule.EndTime, rule.ID).Scan(&exists)
+	return exists, err
+}
+
+func (r *availabilityRepository) Update(availability *model.Availability) error {
+	query := `
+		UPDATE expert_availabilities
+		SET date = $1::date, start_time = $2::time, end_time = $3::time, is_booked = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $5
+		RETURNING updated_at`
+	return r.db.QueryRow(query,
+		availability.Date, availability.StartTime, availability.EndTime,
+		availability.IsBooked, availability.ID).
+		Scan(&availability.UpdatedAt)
+}
+
+func (r *availabilityRepository) Delete(id string) error {
+	res, err := r.db.Exec(`DELETE FROM expert_availabilities WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Book marks a free slot as booked in a single conditional update, so two
+// concurrent requests cannot both book it. It returns false when the slot
+// does not exist or was already booked.
+func (r *availabilityRepository) Book(id string) (bool, error) {
+	res, err := r.db.Exec(`
+		UPDATE expert_availabilities
+		SET is_booked = true, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND is_booked = false`, id)
+	if err != nil {
+		return false, err
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return count == 1, nil
+}
@@ -0,0 +1,292 @@
+package repository
+
+import (
+	"database/sql"
+	"expert-service/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type AvailabilityRuleRepository interface {
+	Create(rule *model.AvailabilityRule) error
+	GetByID(id uuid.UUID) (*model.AvailabilityRule, error)
+	GetByExpertID(expertID uuid.UUID) ([]*model.AvailabilityRule, error)
+	GetActiveByExpertIDAndRange(expertID uuid.UUID, startDate, endDate time.Time) ([]*model.AvailabilityRule, error)
+	Update(rule *model.AvailabilityRule) error
+	Split(current, next *model.AvailabilityRule) error
+	Delete(id uuid.UUID) error
+	AddException(ruleID uuid.UUID, exception *model.AvailabilityRuleException) error
+	DeleteException(ruleID uuid.UUID, date string) error
+	BookOccurrence(ruleID uuid.UUID, date string) (bool, error)
+	GetBookedDates(ruleIDs []uuid.UUID, startDate, endDate time.Time) (map[uuid.UUID]map[string]bool, error)
+}
+
+type availabilityRuleRepository struct {
+	db *sql.DB
+}
+
+func NewAvailabilityRuleRepository(db *sql.DB) AvailabilityRuleRepository {
+	return &availabilityRuleRepository{db: db}
+}
+
+const availabilityRuleColumns = `id, expert_id, days_of_week, to_char(start_time, 'HH24:MI'), to_char(end_time, 'HH24:MI'), to_char(effective_from, 'YYYY-MM-DD'), to_char(effective_to, 'YYYY-MM-DD'), created_at, updated_at`
+
+const insertAvailabilityRuleQuery = `
+	INSERT INTO expert_availability_rules (id, expert_id, days_of_week, start_time, end_time, effective_from, effective_to, created_at, updated_at)
+	VALUES ($1, $2, $3, $4::time, $5::time, $6::date, $7::date, $8, $9)
+	RETURNING id, created_at, updated_at`
+
+// execer is satisfied by both *sql.DB and *sql.Tx
+type execer interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// uuidArray passes IDs as a Postgres uuid[] parameter
+func uuidArray(ids []uuid.UUID) pq.StringArray {
+	array := make(pq.StringArray, len(ids))
+	for i, id := range ids {
+		array[i] = id.String()
+	}
+	return array
+}
+
+func insertAvailabilityRule(db execer, rule *model.AvailabilityRule) error {
+	rule.ID = uuid.New()
+	rule.CreatedAt = time.Now()
+	rule.UpdatedAt = time.Now()
+	return db.QueryRow(insertAvailabilityRuleQuery,
+		rule.ID, rule.ExpertID, rule.DaysOfWeek, rule.StartTime, rule.EndTime,
+		rule.EffectiveFrom, rule.EffectiveTo, rule.CreatedAt, rule.UpdatedAt).
+		Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt)
+}
+
+func (r *availabilityRuleRepository) Create(rule *model.AvailabilityRule) error {
+	return insertAvailabilityRule(r.db, rule)
+}
+
+func (r *availabilityRuleRepository) GetByID(id uuid.UUID) (*model.AvailabilityRule, error) {
+	rules, err := r.query(`SELECT `+availabilityRuleColumns+` FROM expert_availability_rules WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	return rules[0], nil
+}
+
+func (r *availabilityRuleRepository) GetByExpertID(expertID uuid.UUID) ([]*model.AvailabilityRule, error) {
+	return r.query(`
+		SELECT `+availabilityRuleColumns+`
+		FROM expert_availability_rules
+		WHERE expert_id = $1
+		ORDER BY effective_from, start_time`, expertID)
+}
+
+// GetActiveByExpertIDAndRange returns the rules in effect on at least one day of the range
+func (r *availabilityRuleRepository) GetActiveByExpertIDAndRange(expertID uuid.UUID, startDate, endDate time.Time) ([]*model.AvailabilityRule, error) {
+	return r.query(`
+		SELECT `+availabilityRuleColumns+`
+		FROM expert_availability_rules
+		WHERE expert_id = $1 AND effective_from <= $3::date
+		  AND (effective_to IS NULL OR effective_to >= $2::date)
+		ORDER BY effective_from, start_time`,
+		expertID, startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+}
+
+// query loads rules together with their exceptions
+func (r *availabilityRuleRepository) query(query string, args ...interface{}) ([]*model.AvailabilityRule, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*model.AvailabilityRule
+	byID := make(map[uuid.UUID]*model.AvailabilityRule)
+	var ids []string
+	for rows.Next() {
+		rule := &model.AvailabilityRule{Exceptions: []model.AvailabilityRuleException{}}
+		var effectiveTo sql.NullString
+		err := rows.Scan(
+			&rule.ID, &rule.ExpertID, &rule.DaysOfWeek, &rule.StartTime, &rule.EndTime,
+			&rule.EffectiveFrom, &effectiveTo, &rule.CreatedAt, &rule.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if effectiveTo.Valid {
+			rule.EffectiveTo = &effectiveTo.String
+		}
+		rules = append(rules, rule)
+		byID[rule.ID] = rule
+		ids = append(ids, rule.ID.String())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return rules, nil
+	}
+
+	exceptionRows, err := r.db.Query(`
+		SELECT rule_id, to_char(date, 'YYYY-MM-DD'), COALESCE(reason, '')
+		FROM expert_availability_rule_exceptions
+		WHERE rule_id = ANY($1::uuid[])
+		ORDER BY date`, pq.StringArray(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer exceptionRows.Close()
+	for exceptionRows.Next() {
+		var ruleID uuid.UUID
+		var exception model.AvailabilityRuleException
+		if err := exceptionRows.Scan(&ruleID, &exception.Date, &exception.Reason); err != nil {
+			return nil, err
+		}
+		if rule, ok := byID[ruleID]; ok {
+			rule.Exceptions = append(rule.Exceptions, exception)
+		}
+	}
+	return rules, exceptionRows.Err()
+}
+
+func (r *availabilityRuleRepository) Update(rule *model.AvailabilityRule) error {
+	query := `
+		UPDATE expert_availability_rules
+		SET days_of_week = $1, start_time = $2::time, end_time = $3::time,
+		    effective_from = $4::date, effective_to = $5::date, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $6
+		RETURNING updated_at`
+	return r.db.QueryRow(query,
+		rule.DaysOfWeek, rule.StartTime, rule.EndTime,
+		rule.EffectiveFrom, rule.EffectiveTo, rule.ID).
+		Scan(&rule.UpdatedAt)
+}
+
+// Split ends current on its (already shortened) EffectiveTo and inserts next,
+// moving the exceptions and booked occurrences from next.EffectiveFrom on to
+// the new rule. Both happen in one transaction so no day is ever covered by
+// neither or both.
+func (r *availabilityRuleRepository) Split(current, next *model.AvailabilityRule) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		UPDATE expert_availability_rules
+		SET effective_to = $1::date, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2`, current.EffectiveTo, current.ID)
+	if err != nil {
+		return err
+	}
+	if err := insertAvailabilityRule(tx, next); err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+		UPDATE expert_availability_rule_exceptions
+		SET rule_id = $1
+		WHERE rule_id = $2 AND date >= $3::date`, next.ID, current.ID, next.EffectiveFrom)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+		UPDATE expert_availability_rule_bookings
+		SET rule_id = $1
+		WHERE rule_id = $2 AND date >= $3::date`, next.ID, current.ID, next.EffectiveFrom)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *availabilityRuleRepository) Delete(id uuid.UUID) error {
+	res, err := r.db.Exec(`DELETE FROM expert_availability_rules WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *availabilityRuleRepository) AddException(ruleID uuid.UUID, exception *model.AvailabilityRuleException) error {
+	_, err := r.db.Exec(`
+		INSERT INTO expert_availability_rule_exceptions (rule_id, date, reason)
+		VALUES ($1, $2::date, $3)
+		ON CONFLICT (rule_id, date) DO UPDATE SET reason = EXCLUDED.reason`,
+		ruleID, exception.Date, exception.Reason)
+	return err
+}
+
+func (r *availabilityRuleRepository) DeleteException(ruleID uuid.UUID, date string) error {
+	res, err := r.db.Exec(`DELETE FROM expert_availability_rule_exceptions WHERE rule_id = $1 AND date = $2::date`, ruleID, date)
+	if err != nil {
+		return err
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// BookOccurrence marks the occurrence of a rule on date as booked. The
+// primary key makes concurrent requests race on the insert, so it returns
+// false when the occurrence was already booked.
+func (r *availabilityRuleRepository) BookOccurrence(ruleID uuid.UUID, date string) (bool, error) {
+	res, err := r.db.Exec(`
+		INSERT INTO expert_availability_rule_bookings (rule_id, date)
+		VALUES ($1, $2::date)
+		ON CONFLICT (rule_id, date) DO NOTHING`, ruleID, date)
+	if err != nil {
+		return false, err
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return count == 1, nil
+}
+
+// GetBookedDates returns the booked occurrence dates of the rules within
+// [startDate, endDate], keyed by rule ID
+func (r *availabilityRuleRepository) GetBookedDates(ruleIDs []uuid.UUID, startDate, endDate time.Time) (map[uuid.UUID]map[string]bool, error) {
+	booked := make(map[uuid.UUID]map[string]bool)
+	if len(ruleIDs) == 0 {
+		return booked, nil
+	}
+	rows, err := r.db.Query(`
+		SELECT rule_id, to_char(date, 'YYYY-MM-DD')
+		FROM expert_availability_rule_bookings
+		WHERE rule_id = ANY($1::uuid[]) AND date BETWEEN $2::date AND $3::date`,
+		uuidArray(ruleIDs), startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var ruleID uuid.UUID
+		var date string
+		if err := rows.Scan(&ruleID, &date); err != nil {
+			return nil, err
+		}
+		if booked[ruleID] == nil {
+			booked[ruleID] = make(map[string]bool)
+		}
+		booked[ruleID][date] = true
+	}
+	return booked, rows.Err()
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"database/sql"
+	"expert-service/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BookingRepository reads the bookings and group sessions owned by
+// booking-service so that slot computation can subtract them from an
+// expert's working hours, and to check that a review belongs to a
+// completed booking.
+type BookingRepository interface {
+	GetActiveByExpertIDAndRange(expertID uuid.UUID, from, to time.Time) ([]*model.BookedInterval, error)
+	GetActiveByExpertIDsAndRange(expertIDs []uuid.UUID, from, to time.Time) (map[uuid.UUID][]*model.BookedInterval, error)
+	GetReviewableByID(id uuid.UUID) (*model.ReviewableBooking, error)
+}
+
+type bookingRepository struct {
+	db *sql.DB
+}
+
+func NewBookingRepository(db *sql.DB) BookingRepository {
+	return &bookingRepository{db: db}
+}
+
+func (r *bookingRepository) GetActiveByExpertIDAndRange(expertID uuid.UUID, from, to time.Time) ([]*model.BookedInterval, error) {
+	byExpert, err := r.GetActiveByExpertIDsAndRange([]uuid.UUID{expertID}, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return byExpert[expertID], nil
+}
+
+// GetActiveByExpertIDsAndRange reads the bookings of several experts in one
+// query, keyed by expert ID
+func (r *bookingRepository) GetActiveByExpertIDsAndRange(expertIDs []uuid.UUID, from, to time.Time) (map[uuid.UUID][]*model.BookedInterval, error) {
+	// Group sessions block the expert once, like a single booking
+	query := `
+		SELECT expert_id, id, scheduled_datetime, COALESCE(duration_minutes, 60) AS duration_minutes
+		FROM bookings
+		WHERE expert_id = ANY($1::uuid[]) AND status IN ('pending', 'confirmed')
+		  AND scheduled_datetime < $3
+		  AND scheduled_datetime + (COALESCE(duration_minutes, 60) || ' minutes')::interval > $2
+		UNION ALL
+		SELECT expert_id, id, scheduled_datetime, duration_minutes
+		FROM group_sessions
+		WHERE expert_id = ANY($1::uuid[]) AND status = 'scheduled'
+		  AND scheduled_datetime < $3
+		  AND scheduled_datetime + (duration_minutes || ' minutes')::interval > $2
+		ORDER BY scheduled_datetime`
+	rows, err := r.db.Query(query, uuidArray(expertIDs), from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byExpert := make(map[uuid.UUID][]*model.BookedInterval)
+	for rows.Next() {
+		interval := &model.BookedInterval{}
+		var expertID uuid.UUID
+		var durationMinutes int
+		if err := rows.Scan(&expertID, &interval.BookingID, &interval.StartTime, &durationMinutes); err != nil {
+			return nil, err
+		}
+		interval.EndTime = interval.StartTime.Add(time.Duration(durationMinutes) * time.Minute)
+		byExpert[expertID] = append(byExpert[expertID], interval)
+	}
+	return byExpert, rows.Err()
+}
+
+func (r *bookingRepository) GetReviewableByID(id uuid.UUID) (*model.ReviewableBooking, error) {
+	booking := &model.ReviewableBooking{}
+	query := `SELECT id, user_id, expert_id, status FROM bookings WHERE id = $1`
+	err := r.db.QueryRow(query, id).Scan(&booking.ID, &booking.UserID, &booking.ExpertID, &booking.Status)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return booking, nil
+}
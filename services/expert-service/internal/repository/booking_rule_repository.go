@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"database/sql"
+	"expert-service/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BookingRuleRepository interface {
+	GetByExpertID(expertID uuid.UUID) (*model.BookingRule, error)
+	GetByExpertIDs(expertIDs []uuid.UUID) (map[uuid.UUID]*model.BookingRule, error)
+	Upsert(rule *model.BookingRule) error
+}
+
+type bookingRuleRepository struct {
+	db *sql.DB
+}
+
+func NewBookingRuleRepository(db *sql.DB) BookingRuleRepository {
+	return &bookingRuleRepository{db: db}
+}
+
+func (r *bookingRuleRepository) GetByExpertID(expertID uuid.UUID) (*model.BookingRule, error) {
+	rule := &model.BookingRule{}
+	query := `
+		SELECT expert_id, buffer_before_minutes, buffer_after_minutes, min_notice_minutes,
+		       max_advance_days, max_bookings_per_day, created_at, updated_at
+		FROM expert_booking_rules WHERE expert_id = $1`
+
+	err := r.db.QueryRow(query, expertID).Scan(
+		&rule.ExpertID, &rule.BufferBeforeMinutes, &rule.BufferAfterMinutes,
+		&rule.MinNoticeMinutes, &rule.MaxAdvanceDays, &rule.MaxBookingsPerDay,
+		&rule.CreatedAt, &rule.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return rule, err
+}
+
+// GetByExpertIDs returns the configured rules of several experts, keyed by
+// expert ID; experts without one are missing from the map
+func (r *bookingRuleRepository) GetByExpertIDs(expertIDs []uuid.UUID) (map[uuid.UUID]*model.BookingRule, error) {
+	query := `
+		SELECT expert_id, buffer_before_minutes, buffer_after_minutes, min_notice_minutes,
+		       max_advance_days, max_bookings_per_day, created_at, updated_at
+		FROM expert_booking_rules WHERE expert_id = ANY($1::uuid[])`
+	rows, err := r.db.Query(query, uuidArray(expertIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := make(map[uuid.UUID]*model.BookingRule)
+	for rows.Next() {
+		rule := &model.BookingRule{}
+		err := rows.Scan(
+			&rule.ExpertID, &rule.BufferBeforeMinutes, &rule.BufferAfterMinutes,
+			&rule.MinNoticeMinutes, &rule.MaxAdvanceDays, &rule.MaxBookingsPerDay,
+			&rule.CreatedAt, &rule.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		rules[rule.ExpertID] = rule
+	}
+	return rules, rows.Err()
+}
+
+func (r *bookingRuleRepository) Upsert(rule *model.BookingRule) error {
+	query := `
+		INSERT INTO expert_booking_rules (expert_id, buffer_before_minutes, buffer_after_minutes,
+			min_notice_minutes, max_advance_days, max_bookings_per_day, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+		ON CONFLICT (expert_id) DO UPDATE
+		SET buffer_before_minutes = EXCLUDED.buffer_before_minutes,
+		    buffer_after_minutes = EXCLUDED.buffer_after_minutes,
+		    min_notice_minutes = EXCLUDED.min_notice_minutes,
+		    max_advance_days = EXCLUDED.max_advance_days,
+		    max_bookings_per_day = EXCLUDED.max_bookings_per_day,
+		    updated_at = EXCLUDED.updated_at
+		RETURNING created_at, updated_at`
+
+	return r.db.QueryRow(query,
+		rule.ExpertID, rule.BufferBeforeMinutes, rule.BufferAfterMinutes,
+		rule.MinNoticeMinutes, rule.MaxAdvanceDays, rule.MaxBookingsPerDay, time.Now()).
+		Scan(&rule.CreatedAt, &rule.UpdatedAt)
+}
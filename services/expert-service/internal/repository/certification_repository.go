@@ -0,0 +1,313 @@
+package repository
+
+import (
+	"database/sql"
+	"expert-service/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type CertificationRepository interface {
+	Create(certification *model.ExpertCertification) error
+	GetByID(id uuid.UUID) (*model.ExpertCertification, error)
+	GetByExpertID(expertID uuid.UUID, publicOnly bool) ([]*model.ExpertCertification, error)
+	GetByStatus(status string, limit, offset int) ([]*model.ExpertCertification, int, error)
+	Update(certification *model.ExpertCertification) error
+	UpdateStatus(certification *model.ExpertCertification) error
+	Delete(certification *model.ExpertCertification) error
+
+	AddFile(file *model.CertificationFile) error
+	DeleteFile(id uuid.UUID) error
+
+	GetExpiring(until string) ([]*model.ExpiringCertification, error)
+	MarkExpiryWarned(id uuid.UUID) error
+	SyncPublicCertifications() (int64, error)
+}
+
+type certificationRepository struct {
+	db *sql.DB
+}
+
+func NewCertificationRepository(db *sql.DB) CertificationRepository {
+	return &certificationRepository{db: db}
+}
+
+const certificationColumns = `id, expert_id, name, issuer, COALESCE(credential_number, ''),
+		to_char(issued_on, 'YYYY-MM-DD'), to_char(expires_on, 'YYYY-MM-DD'), COALESCE(expires_on < CURRENT_DATE, false),
+		status, verified_by, verified_at, COALESCE(rejection_reason, ''), expiry_warned_at, created_at, updated_at`
+
+// publicCertificationNames is the list experts.certifications must hold: the
+// names of the expert's verified certifications that have not expired
+const publicCertificationNames = `ARRAY(
+		SELECT DISTINCT c.name::text FROM expert_certifications c
+		WHERE c.expert_id = experts.id AND c.status = 'verified'
+		  AND (c.expires_on IS NULL OR c.expires_on >= CURRENT_DATE)
+		ORDER BY 1)`
+
+func scanCertification(row interface {
+	Scan(dest ...interface{}) error
+}) (*model.ExpertCertification, error) {
+	certification := &model.ExpertCertification{}
+	err := row.Scan(
+		&certification.ID, &certification.ExpertID, &certification.Name, &certification.Issuer,
+		&certification.CredentialNumber, &certification.IssuedOn, &certification.ExpiresOn, &certification.Expired,
+		&certification.Status, &certification.VerifiedBy, &certification.VerifiedAt, &certification.RejectionReason,
+		&certification.ExpiryWarnedAt, &certification.CreatedAt, &certification.UpdatedAt)
+	return certification, err
+}
+
+func (r *certificationRepository) Create(certification *model.ExpertCertification) error {
+	certification.ID = uuid.New()
+	return r.db.QueryRow(`
+		INSERT INTO expert_certifications (id, expert_id, name, issuer, credential_number, issued_on, expires_on, status)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8)
+		RETURNING created_at, updated_at`,
+		certification.ID, certification.ExpertID, certification.Name, certification.Issuer,
+		certification.CredentialNumber, certification.IssuedOn, certification.ExpiresOn, certification.Status).
+		Scan(&certification.CreatedAt, &certification.UpdatedAt)
+}
+
+func (r *certificationRepository) GetByID(id uuid.UUID) (*model.ExpertCertification, error) {
+	certification, err := scanCertification(r.db.QueryRow(`SELECT `+certificationColumns+` FROM expert_certifications WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadFiles([]*model.ExpertCertification{certification}); err != nil {
+		return nil, err
+	}
+	return certification, nil
+}
+
+// GetByExpertID lists an expert's certifications. The public list holds only
+// verified, unexpired certifications and leaves out the proof files.
+func (r *certificationRepository) GetByExpertID(expertID uuid.UUID, publicOnly bool) ([]*model.ExpertCertification, error) {
+	query := `SELECT ` + certificationColumns + ` FROM expert_certifications WHERE expert_id = $1`
+	if publicOnly {
+		query += ` AND status = 'verified' AND (expires_on IS NULL OR expires_on >= CURRENT_DATE)`
+	}
+	query += ` ORDER BY expires_on NULLS LAST, name`
+
+	certifications, err := r.query(query, expertID)
+	if err != nil || publicOnly {
+		return certifications, err
+	}
+	if err := r.loadFiles(certifications); err != nil {
+		return nil, err
+	}
+	return certifications, nil
+}
+
+// GetByStatus lists certifications in a verification state, oldest first,
+// e.g. the queue of certifications waiting for an admin
+func (r *certificationRepository) GetByStatus(status string, limit, offset int) ([]*model.ExpertCertification, int, error) {
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM expert_certifications WHERE status = $1`, status).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	certifications, err := r.query(`
+		SELECT `+certificationColumns+` FROM expert_certifications
+		WHERE status = $1
+		ORDER BY created_at
+		LIMIT $2 OFFSET $3`, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := r.loadFiles(certifications); err != nil {
+		return nil, 0, err
+	}
+	return certifications, total, nil
+}
+
+func (r *certificationRepository) query(query string, args ...interface{}) ([]*model.ExpertCertification, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	certifications := []*model.ExpertCertification{}
+	for rows.Next() {
+		certification, err := scanCertification(rows)
+		if err != nil {
+			return nil, err
+		}
+		certifications = append(certifications, certification)
+	}
+	return certifications, rows.Err()
+}
+
+func (r *certificationRepository) loadFiles(certifications []*model.ExpertCertification) error {
+	if len(certifications) == 0 {
+		return nil
+	}
+	byID := make(map[uuid.UUID]*model.ExpertCertification, len(certifications))
+	ids := make(pq.StringArray, 0, len(certifications))
+	for _, certification := range certifications {
+		certification.Files = []*model.CertificationFile{}
+		byID[certification.ID] = certification
+		ids = append(ids, certification.ID.String())
+	}
+
+	rows, err := r.db.Query(`
+		SELECT id, certification_id, file_name, content_type, size_bytes, storage_key, created_at
+		FROM expert_certification_files
+		WHERE certification_id = ANY($1::uuid[])
+		ORDER BY created_at`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		file := &model.CertificationFile{}
+		err := rows.Scan(&file.ID, &file.CertificationID, &file.FileName, &file.ContentType,
+			&file.SizeBytes, &file.StorageKey, &file.CreatedAt)
+		if err != nil {
+			return err
+		}
+		certification := byID[file.CertificationID]
+		certification.Files = append(certification.Files, file)
+	}
+	return rows.Err()
+}
+
+// Update stores edited details. The certification goes back to pending and
+// off the public profile until an admin verifies it again.
+func (r *certificationRepository) Update(certification *model.ExpertCertification) error {
+	return r.withPublicSync(certification.ExpertID, func(tx *sql.Tx) error {
+		return tx.QueryRow(`
+			UPDATE expert_certifications
+			SET name = $1, issuer = $2, credential_number = NULLIF($3, ''), issued_on = $4, expires_on = $5,
+			    status = $6, verified_by = NULL, verified_at = NULL, rejection_reason = NULL, expiry_warned_at = NULL
+			WHERE id = $7
+			RETURNING updated_at`,
+			certification.Name, certification.Issuer, certification.CredentialNumber,
+			certification.IssuedOn, certification.ExpiresOn, certification.Status, certification.ID).
+			Scan(&certification.UpdatedAt)
+	})
+}
+
+// UpdateStatus stores an admin's verification decision
+func (r *certificationRepository) UpdateStatus(certification *model.ExpertCertification) error {
+	return r.withPublicSync(certification.ExpertID, func(tx *sql.Tx) error {
+		return tx.QueryRow(`
+			UPDATE expert_certifications
+			SET status = $1, verified_by = $2, verified_at = $3, rejection_reason = NULLIF($4, '')
+			WHERE id = $5
+			RETURNING updated_at`,
+			certification.Status, certification.VerifiedBy, certification.VerifiedAt,
+			certification.RejectionReason, certification.ID).
+			Scan(&certification.UpdatedAt)
+	})
+}
+
+// Delete removes a certification and its file records; the caller removes
+// the stored files
+func (r *certificationRepository) Delete(certification *model.ExpertCertification) error {
+	return r.withPublicSync(certification.ExpertID, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`DELETE FROM expert_certifications WHERE id = $1`, certification.ID)
+		return err
+	})
+}
+
+// withPublicSync runs fn and refreshes the expert's public certification
+// names in the same transaction
+func (r *certificationRepository) withPublicSync(expertID uuid.UUID, fn func(tx *sql.Tx) error) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	_, err = tx.Exec(`UPDATE experts SET certifications = `+publicCertificationNames+` WHERE id = $1`, expertID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *certificationRepository) AddFile(file *model.CertificationFile) error {
+	file.ID = uuid.New()
+	return r.db.QueryRow(`
+		INSERT INTO expert_certification_files (id, certification_id, file_name, content_type, size_bytes, storage_key)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at`,
+		file.ID, file.CertificationID, file.FileName, file.ContentType, file.SizeBytes, file.StorageKey).
+		Scan(&file.CreatedAt)
+}
+
+func (r *certificationRepository) DeleteFile(id uuid.UUID) error {
+	_, err := r.db.Exec(`DELETE FROM expert_certification_files WHERE id = $1`, id)
+	return err
+}
+
+// GetExpiring lists verified certifications expiring between today and until
+// (YYYY-MM-DD, inclusive) whose expert has not been warned yet
+func (r *certificationRepository) GetExpiring(until string) ([]*model.ExpiringCertification, error) {
+	rows, err := r.db.Query(`
+		SELECT c.id, c.name, to_char(c.expires_on, 'YYYY-MM-DD'), e.user_id
+		FROM expert_certifications c
+		JOIN experts e ON c.expert_id = e.id
+		WHERE c.status = 'verified' AND c.expiry_warned_at IS NULL
+		  AND c.expires_on BETWEEN CURRENT_DATE AND $1::date
+		ORDER BY c.expires_on`, until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var expiring []*model.ExpiringCertification
+	for rows.Next() {
+		certification := &model.ExpiringCertification{}
+		err := rows.Scan(&certification.CertificationID, &certification.Name, &certification.ExpiresOn, &certification.UserID)
+		if err != nil {
+			return nil, err
+		}
+		expiring = append(expiring, certification)
+	}
+	return expiring, rows.Err()
+}
+
+func (r *certificationRepository) MarkExpiryWarned(id uuid.UUID) error {
+	_, err := r.db.Exec(`UPDATE expert_certifications SET expiry_warned_at = CURRENT_TIMESTAMP WHERE id = $1`, id)
+	return err
+}
+
+// SyncPublicCertifications refreshes the public certification names of every
+// expert whose list is out of date, which drops certifications that expired
+// since the last run. It returns the number of experts updated.
+func (r *certificationRepository) SyncPublicCertifications() (int64, error) {
+	res, err := r.db.Exec(`
+		UPDATE experts SET certifications = ` + publicCertificationNames + `
+		WHERE certifications IS DISTINCT FROM ` + publicCertificationNames)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// insertVerifiedCertifications records certification names an admin
+// accepted when creating an expert
+func insertVerifiedCertifications(tx *sql.Tx, expertID uuid.UUID, names []string) error {
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		_, err := tx.Exec(`
+			INSERT INTO expert_certifications (expert_id, name, status, verified_at)
+			VALUES ($1, $2, 'verified', CURRENT_TIMESTAMP)`, expertID, name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"database/sql"
+	"expert-service/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ConsultationServiceRepository interface {
+	Create(svc *model.ConsultationService) error
+	GetByID(id uuid.UUID) (*model.ConsultationService, error)
+	GetByExpertID(expertID uuid.UUID, activeOnly bool) ([]*model.ConsultationService, error)
+	Update(svc *model.ConsultationService) error
+	Deactivate(id uuid.UUID) error
+}
+
+type consultationServiceRepository struct {
+	db *sql.DB
+}
+
+func NewConsultationServiceRepository(db *sql.DB) ConsultationServiceRepository {
+	return &consultationServiceRepository{db: db}
+}
+
+const consultationServiceColumns = `id, expert_id, name, COALESCE(description, ''), duration_minutes, price, meeting_type, is_active, created_at, updated_at`
+
+func scanConsultationService(row interface {
+	Scan(dest ...interface{}) error
+}) (*model.ConsultationService, error) {
+	svc := &model.ConsultationService{}
+	err := row.Scan(
+		&svc.ID, &svc.ExpertID, &svc.Name, &svc.Description,
+		&svc.DurationMinutes, &svc.Price, &svc.MeetingType, &svc.IsActive,
+		&svc.CreatedAt, &svc.UpdatedAt)
+	return svc, err
+}
+
+func (r *consultationServiceRepository) Create(svc *model.ConsultationService) error {
+	query := `
+		INSERT INTO expert_services (id, expert_id, name, description, duration_minutes, price, meeting_type, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at, updated_at`
+
+	svc.ID = uuid.New()
+	svc.CreatedAt = time.Now()
+	svc.UpdatedAt = time.Now()
+
+	return r.db.QueryRow(query,
+		svc.ID, svc.ExpertID, svc.Name, svc.Description,
+		svc.DurationMinutes, svc.Price, svc.MeetingType, svc.IsActive,
+		svc.CreatedAt, svc.UpdatedAt).
+		Scan(&svc.ID, &svc.CreatedAt, &svc.UpdatedAt)
+}
+
+func (r *consultationServiceRepository) GetByID(id uuid.UUID) (*model.ConsultationService, error) {
+	query := `SELECT ` + consultationServiceColumns + ` FROM expert_services WHERE id = $1`
+	svc, err := scanConsultationService(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return svc, nil
+}
+
+func (r *consultationServiceRepository) GetByExpertID(expertID uuid.UUID, activeOnly bool) ([]*model.ConsultationService, error) {
+	query := `SELECT ` + consultationServiceColumns + ` FROM expert_services WHERE expert_id = $1`
+	if activeOnly {
+		query += ` AND is_active = true`
+	}
+	query += ` ORDER BY duration_minutes, name`
+
+	rows, err := r.db.Query(query, expertID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var services []*model.ConsultationService
+	for rows.Next() {
+		svc, err := scanConsultationService(rows)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, svc)
+	}
+	return services, rows.Err()
+}
+
+func (r *consultationServiceRepository) Update(svc *model.ConsultationService) error {
+	query := `
+		UPDATE expert_services
+		SET name = $1, description = $2, duration_minutes = $3, price = $4,
+		    meeting_type = $5, is_active = $6, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $7`
+
+	_, err := r.db.Exec(query,
+		svc.Name, svc.Description, svc.DurationMinutes, svc.Price,
+		svc.MeetingType, svc.IsActive, svc.ID)
+	return err
+}
+
+// Deactivate hides a service from new bookings; existing bookings keep referencing it
+func (r *consultationServiceRepository) Deactivate(id uuid.UUID) error {
+	query := `UPDATE expert_services SET is_active = false, updated_at = CURRENT_TIMESTAMP WHERE id = $1`
+	res, err := r.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,293 @@
+package repository
+
+import (
+	"database/sql"
+	"expert-service/internal/model"
+
+	"github.com/google/uuid"
+)
+
+type ExpertApplicationRepository interface {
+	Create(application *model.ExpertApplication) error
+	GetByID(id uuid.UUID) (*model.ExpertApplication, error)
+	GetByUserID(userID uuid.UUID) (*model.ExpertApplication, error)
+	List(status string, limit, offset int) ([]*model.ExpertApplication, int, error)
+	Reapply(application *model.ExpertApplication) (bool, error)
+	AddDocument(document *model.ApplicationDocument) error
+	UpdateStatus(application *model.ExpertApplication, from string) (bool, error)
+	Approve(application *model.ExpertApplication, expert *model.Expert) (bool, error)
+	Reject(application *model.ExpertApplication, from string) (bool, error)
+	DeleteByUserID(userID uuid.UUID) error
+}
+
+type expertApplicationRepository struct {
+	db *sql.DB
+}
+
+func NewExpertApplicationRepository(db *sql.DB) ExpertApplicationRepository {
+	return &expertApplicationRepository{db: db}
+}
+
+const applicationColumns = `id, user_id, status, specialization, experience_years, hourly_rate, certifications, COALESCE(bio, ''),
+		reviewer_id, COALESCE(review_notes, ''), COALESCE(rejection_reason, ''), expert_id, submitted_at, reviewed_at, created_at, updated_at`
+
+func scanApplication(row interface {
+	Scan(dest ...interface{}) error
+}) (*model.ExpertApplication, error) {
+	application := &model.ExpertApplication{Documents: []*model.ApplicationDocument{}}
+	err := row.Scan(
+		&application.ID, &application.UserID, &application.Status, &application.Specialization,
+		&application.ExperienceYears, &application.HourlyRate, &application.Certifications, &application.Bio,
+		&application.ReviewerID, &application.ReviewNotes, &application.RejectionReason, &application.ExpertID,
+		&application.SubmittedAt, &application.ReviewedAt, &application.CreatedAt, &application.UpdatedAt)
+	return application, err
+}
+
+// Create stores a new application and records on the user account that the
+// expert role was requested
+func (r *expertApplicationRepository) Create(application *model.ExpertApplication) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	application.ID = uuid.New()
+	err = tx.QueryRow(`
+		INSERT INTO expert_applications (id, user_id, status, specialization, experience_years, hourly_rate, certifications, bio)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING created_at, updated_at`,
+		application.ID, application.UserID, application.Status, application.Specialization,
+		application.ExperienceYears, application.HourlyRate, application.Certifications, application.Bio).
+		Scan(&application.CreatedAt, &application.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	if err := setRequestedRole(tx, application.UserID, true); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *expertApplicationRepository) GetByID(id uuid.UUID) (*model.ExpertApplication, error) {
+	return r.get(`id = $1`, id)
+}
+
+func (r *expertApplicationRepository) GetByUserID(userID uuid.UUID) (*model.ExpertApplication, error) {
+	return r.get(`user_id = $1`, userID)
+}
+
+func (r *expertApplicationRepository) get(where string, arg interface{}) (*model.ExpertApplication, error) {
+	application, err := scanApplication(r.db.QueryRow(`SELECT `+applicationColumns+` FROM expert_applications WHERE `+where, arg))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	application.Documents, err = r.getDocuments(application.ID)
+	if err != nil {
+		return nil, err
+	}
+	return application, nil
+}
+
+func (r *expertApplicationRepository) getDocuments(applicationID uuid.UUID) ([]*model.ApplicationDocument, error) {
+	rows, err := r.db.Query(`
+		SELECT id, application_id, name, url, created_at
+		FROM expert_application_documents
+		WHERE application_id = $1
+		ORDER BY created_at`, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	documents := []*model.ApplicationDocument{}
+	for rows.Next() {
+		document := &model.ApplicationDocument{}
+		if err := rows.Scan(&document.ID, &document.ApplicationID, &document.Name, &document.URL, &document.CreatedAt); err != nil {
+			return nil, err
+		}
+		documents = append(documents, document)
+	}
+	return documents, rows.Err()
+}
+
+// List returns applications in a state, or all of them when status is empty,
+// oldest submission first so reviewers work through the queue in order.
+// Documents are not loaded.
+func (r *expertApplicationRepository) List(status string, limit, offset int) ([]*model.ExpertApplication, int, error) {
+	where := `($1 = '' OR status = $1)`
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM expert_applications WHERE `+where, status).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT `+applicationColumns+` FROM expert_applications
+		WHERE `+where+`
+		ORDER BY submitted_at NULLS LAST, created_at
+		LIMIT $2 OFFSET $3`, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	applications := []*model.ExpertApplication{}
+	for rows.Next() {
+		application, err := scanApplication(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		applications = append(applications, application)
+	}
+	return applications, total, rows.Err()
+}
+
+// Reapply resets a rejected application with new details. It reports false
+// if the application is no longer rejected.
+func (r *expertApplicationRepository) Reapply(application *model.ExpertApplication) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		UPDATE expert_applications
+		SET status = $1, specialization = $2, experience_years = $3, hourly_rate = $4, certifications = $5, bio = $6,
+		    reviewer_id = NULL, review_notes = NULL, rejection_reason = NULL, submitted_at = NULL, reviewed_at = NULL
+		WHERE id = $7 AND status = $8
+		RETURNING updated_at`,
+		model.ApplicationStatusApplied, application.Specialization, application.ExperienceYears,
+		application.HourlyRate, application.Certifications, application.Bio,
+		application.ID, model.ApplicationStatusRejected).
+		Scan(&application.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := setRequestedRole(tx, application.UserID, true); err != nil {
+		return false, err
+	}
+
+	application.Status = model.ApplicationStatusApplied
+	application.ReviewerID = nil
+	application.ReviewNotes = ""
+	application.RejectionReason = ""
+	application.SubmittedAt = nil
+	application.ReviewedAt = nil
+	return true, tx.Commit()
+}
+
+func (r *expertApplicationRepository) AddDocument(document *model.ApplicationDocument) error {
+	document.ID = uuid.New()
+	return r.db.QueryRow(`
+		INSERT INTO expert_application_documents (id, application_id, name, url)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at`,
+		document.ID, document.ApplicationID, document.Name, document.URL).
+		Scan(&document.CreatedAt)
+}
+
+// UpdateStatus moves an application to application.Status and stores its
+// review fields, provided it is still in state from. It reports false if the
+// application changed state in the meantime.
+func (r *expertApplicationRepository) UpdateStatus(application *model.ExpertApplication, from string) (bool, error) {
+	return r.updateStatus(r.db, application, from)
+}
+
+// Approve marks an under-review application approved, creates the expert
+// profile and grants the user the expert role in one transaction
+func (r *expertApplicationRepository) Approve(application *model.ExpertApplication, expert *model.Expert) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if err := insertExpert(tx, expert); err != nil {
+		return false, err
+	}
+	if err := grantExpertRole(tx, expert.UserID); err != nil {
+		return false, err
+	}
+	application.ExpertID = &expert.ID
+	ok, err := r.updateStatus(tx, application, model.ApplicationStatusUnderReview)
+	if err != nil || !ok {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// Reject marks an application rejected and withdraws the user's request for
+// the expert role
+func (r *expertApplicationRepository) Reject(application *model.ExpertApplication, from string) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	ok, err := r.updateStatus(tx, application, from)
+	if err != nil || !ok {
+		return false, err
+	}
+	if err := setRequestedRole(tx, application.UserID, false); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// DeleteByUserID removes a user's application with its documents and
+// withdraws their request for the expert role
+func (r *expertApplicationRepository) DeleteByUserID(userID uuid.UUID) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM expert_applications WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	if err := setRequestedRole(tx, userID, false); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *expertApplicationRepository) updateStatus(db execer, application *model.ExpertApplication, from string) (bool, error) {
+	err := db.QueryRow(`
+		UPDATE expert_applications
+		SET status = $1, reviewer_id = $2, review_notes = NULLIF($3, ''), rejection_reason = NULLIF($4, ''),
+		    expert_id = $5, submitted_at = $6, reviewed_at = $7
+		WHERE id = $8 AND status = $9
+		RETURNING updated_at`,
+		application.Status, application.ReviewerID, application.ReviewNotes, application.RejectionReason,
+		application.ExpertID, application.SubmittedAt, application.ReviewedAt,
+		application.ID, from).
+		Scan(&application.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// setRequestedRole records or withdraws a pending request for the expert
+// role on a user who is not an expert yet
+func setRequestedRole(tx *sql.Tx, userID uuid.UUID, requested bool) error {
+	var role interface{}
+	if requested {
+		role = "expert"
+	}
+	_, err := tx.Exec(`
+		UPDATE users SET requested_role = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND role = 'user'`, role, userID)
+	return err
+}
@@ -3,18 +3,27 @@ package repository
 import (
 	"database/sql"
 	"expert-service/internal/model"
+	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type ExpertRepository interface {
 	Create(expert *model.Expert) error
 	GetByID(id uuid.UUID) (*model.Expert, error)
+	GetByUserID(userID uuid.UUID) (*model.Expert, error)
 	GetByEmail(email string) (*model.Expert, error)
 	GetAll() ([]*model.Expert, error)
 	Update(expert *model.Expert) error
 	Delete(id uuid.UUID) error
 	GetByExpertise(expertise string) ([]*model.Expert, error)
+	Search(req *model.SearchExpertsRequest) ([]*model.ExpertSearchResult, error)
+	GetReviewWeightedRating() (float64, error)
+	IsEmailVerified(expertID uuid.UUID) (bool, error)
+	IsApproved(expertID uuid.UUID) (bool, error)
+	GetUserRole(userID uuid.UUID) (string, error)
 }
 
 type expertRepository struct {
@@ -25,18 +34,61 @@ func NewExpertRepository(db *sql.DB) ExpertRepository {
 	return &expertRepository{db: db}
 }
 
+// Create stores the expert profile and grants its user the expert role in
+// one transaction, so a profile never exists without the role
 func (r *expertRepository) Create(expert *model.Expert) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertExpert(tx, expert); err != nil {
+		return err
+	}
+	if err := grantExpertRole(tx, expert.UserID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// insertExpert stores a new expert profile. Its certification names were
+// accepted by an admin and are recorded as verified certifications.
+func insertExpert(tx *sql.Tx, expert *model.Expert) error {
 	query := `
         INSERT INTO experts (id, user_id, specialization, experience_years, hourly_rate, certifications, is_available, rating, total_reviews, created_at, updated_at)
         VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
         RETURNING id, created_at, updated_at`
 
-	return r.db.QueryRow(query,
+	err := tx.QueryRow(query,
 		expert.ID, expert.UserID, expert.Specialization,
 		expert.ExperienceYears, expert.HourlyRate, expert.Certifications,
 		expert.IsAvailable, expert.Rating, expert.TotalReviews,
 		expert.CreatedAt, expert.UpdatedAt).
 		Scan(&expert.ID, &expert.CreatedAt, &expert.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	return insertVerifiedCertifications(tx, expert.ID, expert.Certifications)
+}
+
+// grantExpertRole makes the user an expert and clears the role they asked
+// for at registration
+func grantExpertRole(tx *sql.Tx, userID uuid.UUID) error {
+	res, err := tx.Exec(`
+        UPDATE users SET role = 'expert', requested_role = NULL, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1`, userID)
+	if err != nil {
+		return err
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
 }
 
 func (r *expertRepository) GetByID(id uuid.UUID) (*model.Expert, error) {
@@ -57,6 +109,24 @@ func (r *expertRepository) GetByID(id uuid.UUID) (*model.Expert, error) {
 	return expert, err
 }
 
+func (r *expertRepository) GetByUserID(userID uuid.UUID) (*model.Expert, error) {
+	expert := &model.Expert{}
+	query := `
+        SELECT id, user_id, specialization, experience_years, hourly_rate, certifications, is_available, rating, total_reviews, created_at, updated_at
+        FROM experts WHERE user_id = $1`
+
+	err := r.db.QueryRow(query, userID).Scan(
+		&expert.ID, &expert.UserID, &expert.Specialization,
+		&expert.ExperienceYears, &expert.HourlyRate, &expert.Certifications,
+		&expert.IsAvailable, &expert.Rating, &expert.TotalReviews,
+		&expert.CreatedAt, &expert.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return expert, err
+}
+
 func (r *expertRepository) GetByEmail(email string) (*model.Expert, error) {
 	expert := &model.Expert{}
 	query := `
@@ -104,16 +174,18 @@ func (r *expertRepository) GetAll() ([]*model.Expert, error) {
 	return experts, nil
 }
 
+// Update stores the profile fields an expert can edit. Certifications are
+// managed separately and synced by CertificationRepository.
 func (r *expertRepository) Update(expert *model.Expert) error {
 	query := `
         UPDATE experts 
         SET specialization = $1, experience_years = $2, hourly_rate = $3, 
-            certifications = $4, is_available = $5, updated_at = CURRENT_TIMESTAMP
-        WHERE id = $6`
+            is_available = $4, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $5`
 
 	_, err := r.db.Exec(query,
 		expert.Specialization, expert.ExperienceYears, expert.HourlyRate,
-		expert.Certifications, expert.IsAvailable, expert.ID)
+		expert.IsAvailable, expert.ID)
 	return err
 }
 
@@ -135,9 +207,11 @@ func (r *expertRepository) Delete(id uuid.UUID) error {
 
 func (r *expertRepository) GetByExpertise(expertise string) ([]*model.Expert, error) {
 	query := `
-        SELECT id, user_id, specialization, experience_years, hourly_rate, certifications, is_available, rating, total_reviews, created_at, updated_at
-        FROM experts WHERE specialization = $1 AND is_available = true
-        ORDER BY created_at DESC`
+        SELECT e.id, e.user_id, e.specialization, e.experience_years, e.hourly_rate, e.certifications, e.is_available, e.rating, e.total_reviews, e.created_at, e.updated_at
+        FROM experts e
+        JOIN users u ON e.user_id = u.id
+        WHERE e.specialization = $1 AND e.is_available = true AND u.role = 'expert'
+        ORDER BY e.created_at DESC`
 
 	rows, err := r.db.Query(query, expertise)
 	if err != nil {
@@ -160,3 +234,134 @@ func (r *expertRepository) GetByExpertise(expertise string) ([]*model.Expert, er
 	}
 	return experts, nil
 }
+
+// Search returns every available, approved expert matching the attribute filters of req.
+// Availability, ranking and paging are applied by the caller because they
+// depend on computed slots and on facets over the whole match set.
+func (r *expertRepository) Search(req *model.SearchExpertsRequest) ([]*model.ExpertSearchResult, error) {
+	conditions := []string{"e.is_available = true", "u.role = 'expert'", "COALESCE(u.email_verified, false) = true"}
+	var args []interface{}
+	addArg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if req.Specialization != "" {
+		conditions = append(conditions, "LOWER(e.specialization) = LOWER("+addArg(req.Specialization)+")")
+	}
+	if req.MinRate != nil {
+		conditions = append(conditions, "e.hourly_rate >= "+addArg(*req.MinRate))
+	}
+	if req.MaxRate != nil {
+		conditions = append(conditions, "e.hourly_rate <= "+addArg(*req.MaxRate))
+	}
+	if req.MinRating != nil {
+		conditions = append(conditions, "e.rating >= "+addArg(*req.MinRating))
+	}
+	if req.MinExperience != nil {
+		conditions = append(conditions, "e.experience_years >= "+addArg(*req.MinExperience))
+	}
+	if len(req.Certifications) > 0 {
+		conditions = append(conditions, "e.certifications @> "+addArg(pq.StringArray(req.Certifications)))
+	}
+	if req.MeetingType != "" {
+		conditions = append(conditions, `EXISTS (
+            SELECT 1 FROM expert_services s
+            WHERE s.expert_id = e.id AND s.is_active = true AND s.meeting_type = `+addArg(req.MeetingType)+`)`)
+	}
+	if req.Query != "" {
+		pattern := addArg("%" + req.Query + "%")
+		conditions = append(conditions, `(e.specialization ILIKE `+pattern+`
+            OR array_to_string(e.certifications, ' ') ILIKE `+pattern+`
+            OR u.fullname ILIKE `+pattern+`)`)
+	}
+
+	query := `
+        SELECT e.id, e.user_id, e.specialization, e.experience_years, e.hourly_rate, e.certifications, e.is_available, e.rating, e.total_reviews, e.created_at, e.updated_at,
+               COALESCE(u.fullname, ''),
+               ARRAY(SELECT DISTINCT s.meeting_type FROM expert_services s WHERE s.expert_id = e.id AND s.is_active = true)
+        FROM experts e
+        LEFT JOIN users u ON e.user_id = u.id
+        WHERE ` + strings.Join(conditions, " AND ") + `
+        ORDER BY e.created_at DESC`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*model.ExpertSearchResult
+	for rows.Next() {
+		expert := &model.Expert{}
+		result := &model.ExpertSearchResult{Expert: expert}
+		var meetingTypes pq.StringArray
+		err := rows.Scan(
+			&expert.ID, &expert.UserID, &expert.Specialization,
+			&expert.ExperienceYears, &expert.HourlyRate, &expert.Certifications,
+			&expert.IsAvailable, &expert.Rating, &expert.TotalReviews,
+			&expert.CreatedAt, &expert.UpdatedAt,
+			&result.FullName, &meetingTypes)
+		if err != nil {
+			return nil, err
+		}
+		result.MeetingTypes = meetingTypes
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// IsEmailVerified reports whether the expert's user account has a verified
+// email; experts cannot be booked until it does
+func (r *expertRepository) IsEmailVerified(expertID uuid.UUID) (bool, error) {
+	query := `
+        SELECT COALESCE(u.email_verified, false)
+        FROM experts e
+        LEFT JOIN users u ON e.user_id = u.id
+        WHERE e.id = $1`
+
+	var verified bool
+	err := r.db.QueryRow(query, expertID).Scan(&verified)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return verified, err
+}
+
+// IsApproved reports whether the expert's user account still holds the expert
+// role; experts whose role was withdrawn cannot be booked
+func (r *expertRepository) IsApproved(expertID uuid.UUID) (bool, error) {
+	query := `
+        SELECT COALESCE(u.role = 'expert', false)
+        FROM experts e
+        LEFT JOIN users u ON e.user_id = u.id
+        WHERE e.id = $1`
+
+	var approved bool
+	err := r.db.QueryRow(query, expertID).Scan(&approved)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return approved, err
+}
+
+// GetUserRole returns the role of a user account, or "" if it does not exist
+func (r *expertRepository) GetUserRole(userID uuid.UUID) (string, error) {
+	var role string
+	err := r.db.QueryRow(`SELECT role FROM users WHERE id = $1`, userID).Scan(&role)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return role, err
+}
+
+// GetReviewWeightedRating returns the mean rating over all reviews, used as
+// the prior of the Bayesian rating
+func (r *expertRepository) GetReviewWeightedRating() (float64, error) {
+	var mean float64
+	query := `
+        SELECT COALESCE(SUM(rating * total_reviews) / NULLIF(SUM(total_reviews), 0), 0)
+        FROM experts WHERE total_reviews > 0`
+	err := r.db.QueryRow(query).Scan(&mean)
+	return mean, err
+}
@@ -0,0 +1,305 @@
+package repository
+
+import (
+	"database/sql"
+	"expert-service/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type HolidayRepository interface {
+	GetCalendars() ([]*model.HolidayCalendar, error)
+	GetCalendar(code string) (*model.HolidayCalendar, error)
+	GetHolidays(code string, startDate, endDate string) ([]model.Holiday, error)
+	SeedCalendar(calendar *model.HolidayCalendar) (bool, error)
+	SaveCalendar(calendar *model.HolidayCalendar) error
+	AddHoliday(holiday *model.Holiday) error
+	DeleteHoliday(code string, date string) error
+	GetExpertIDsObserving(code string, defaultCalendar string) ([]uuid.UUID, error)
+
+	GetExpertSettings(expertID uuid.UUID) (*model.ExpertHolidaySettings, error)
+	SetExpertCalendars(expertID uuid.UUID, codes []string) error
+	AddOverride(expertID uuid.UUID, override *model.HolidayOverride) error
+	DeleteOverride(expertID uuid.UUID, date string) error
+	GetExpertHolidays(expertID uuid.UUID, defaultCalendar string, startDate, endDate string) ([]model.Holiday, error)
+}
+
+type holidayRepository struct {
+	db *sql.DB
+}
+
+func NewHolidayRepository(db *sql.DB) HolidayRepository {
+	return &holidayRepository{db: db}
+}
+
+func (r *holidayRepository) GetCalendars() ([]*model.HolidayCalendar, error) {
+	rows, err := r.db.Query(`
+		SELECT code, name, country_code, created_at, updated_at
+		FROM holiday_calendars
+		ORDER BY code`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	calendars := []*model.HolidayCalendar{}
+	for rows.Next() {
+		calendar := &model.HolidayCalendar{}
+		err := rows.Scan(&calendar.Code, &calendar.Name, &calendar.CountryCode, &calendar.CreatedAt, &calendar.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		calendars = append(calendars, calendar)
+	}
+	return calendars, rows.Err()
+}
+
+func (r *holidayRepository) GetCalendar(code string) (*model.HolidayCalendar, error) {
+	calendar := &model.HolidayCalendar{}
+	err := r.db.QueryRow(`
+		SELECT code, name, country_code, created_at, updated_at
+		FROM holiday_calendars WHERE code = $1`, code).
+		Scan(&calendar.Code, &calendar.Name, &calendar.CountryCode, &calendar.CreatedAt, &calendar.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return calendar, nil
+}
+
+// GetHolidays lists the holidays of a calendar between two dates (inclusive)
+func (r *holidayRepository) GetHolidays(code string, startDate, endDate string) ([]model.Holiday, error) {
+	return r.queryHolidays(`
+		SELECT calendar_code, to_char(date, 'YYYY-MM-DD'), name
+		FROM holidays
+		WHERE calendar_code = $1 AND date BETWEEN $2::date AND $3::date
+		ORDER BY date`, code, startDate, endDate)
+}
+
+func (r *holidayRepository) queryHolidays(query string, args ...interface{}) ([]model.Holiday, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	holidays := []model.Holiday{}
+	for rows.Next() {
+		var holiday model.Holiday
+		if err := rows.Scan(&holiday.CalendarCode, &holiday.Date, &holiday.Name); err != nil {
+			return nil, err
+		}
+		holidays = append(holidays, holiday)
+	}
+	return holidays, rows.Err()
+}
+
+// SeedCalendar stores a bundled calendar unless a calendar with the same code
+// exists, so that admin edits survive restarts. It reports whether it was stored.
+func (r *holidayRepository) SeedCalendar(calendar *model.HolidayCalendar) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`
+		INSERT INTO holiday_calendars (code, name, country_code)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (code) DO NOTHING`, calendar.Code, calendar.Name, calendar.CountryCode)
+	if err != nil {
+		return false, err
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if count == 0 {
+		return false, nil
+	}
+	if err := insertHolidays(tx, calendar); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// SaveCalendar creates or renames a calendar and replaces all of its holidays
+func (r *holidayRepository) SaveCalendar(calendar *model.HolidayCalendar) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		INSERT INTO holiday_calendars (code, name, country_code)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (code) DO UPDATE SET name = EXCLUDED.name, country_code = EXCLUDED.country_code
+		RETURNING created_at, updated_at`, calendar.Code, calendar.Name, calendar.CountryCode).
+		Scan(&calendar.CreatedAt, &calendar.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM holidays WHERE calendar_code = $1`, calendar.Code); err != nil {
+		return err
+	}
+	if err := insertHolidays(tx, calendar); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertHolidays(tx *sql.Tx, calendar *model.HolidayCalendar) error {
+	stmt, err := tx.Prepare(`
+		INSERT INTO holidays (calendar_code, date, name)
+		VALUES ($1, $2::date, $3)
+		ON CONFLICT (calendar_code, date) DO UPDATE SET name = EXCLUDED.name`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, holiday := range calendar.Holidays {
+		if _, err := stmt.Exec(calendar.Code, holiday.Date, holiday.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *holidayRepository) AddHoliday(holiday *model.Holiday) error {
+	_, err := r.db.Exec(`
+		INSERT INTO holidays (calendar_code, date, name)
+		VALUES ($1, $2::date, $3)
+		ON CONFLICT (calendar_code, date) DO UPDATE SET name = EXCLUDED.name`,
+		holiday.CalendarCode, holiday.Date, holiday.Name)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Exec(`UPDATE holiday_calendars SET updated_at = CURRENT_TIMESTAMP WHERE code = $1`, holiday.CalendarCode)
+	return err
+}
+
+func (r *holidayRepository) DeleteHoliday(code string, date string) error {
+	res, err := r.db.Exec(`DELETE FROM holidays WHERE calendar_code = $1 AND date = $2::date`, code, date)
+	if err != nil {
+		return err
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+	_, err = r.db.Exec(`UPDATE holiday_calendars SET updated_at = CURRENT_TIMESTAMP WHERE code = $1`, code)
+	return err
+}
+
+// GetExpertIDsObserving lists the experts whose availability depends on a
+// calendar, including experts on the default calendar
+func (r *holidayRepository) GetExpertIDsObserving(code string, defaultCalendar string) ([]uuid.UUID, error) {
+	rows, err := r.db.Query(`
+		SELECT e.id
+		FROM experts e
+		LEFT JOIN expert_holiday_calendars c ON c.expert_id = e.id
+		WHERE $1 = ANY(c.calendar_codes) OR (c.expert_id IS NULL AND $1 = $2)`, code, defaultCalendar)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// GetExpertSettings returns the expert's chosen calendars and overrides.
+// CalendarCodes is nil when the expert never chose calendars.
+func (r *holidayRepository) GetExpertSettings(expertID uuid.UUID) (*model.ExpertHolidaySettings, error) {
+	settings := &model.ExpertHolidaySettings{ExpertID: expertID, Overrides: []model.HolidayOverride{}}
+	err := r.db.QueryRow(`SELECT calendar_codes FROM expert_holiday_calendars WHERE expert_id = $1`, expertID).
+		Scan(&settings.CalendarCodes)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if err == nil && settings.CalendarCodes == nil {
+		settings.CalendarCodes = pq.StringArray{}
+	}
+
+	rows, err := r.db.Query(`
+		SELECT to_char(date, 'YYYY-MM-DD'), COALESCE(note, '')
+		FROM expert_holiday_overrides
+		WHERE expert_id = $1
+		ORDER BY date`, expertID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var override model.HolidayOverride
+		if err := rows.Scan(&override.Date, &override.Note); err != nil {
+			return nil, err
+		}
+		settings.Overrides = append(settings.Overrides, override)
+	}
+	return settings, rows.Err()
+}
+
+func (r *holidayRepository) SetExpertCalendars(expertID uuid.UUID, codes []string) error {
+	_, err := r.db.Exec(`
+		INSERT INTO expert_holiday_calendars (expert_id, calendar_codes)
+		VALUES ($1, $2)
+		ON CONFLICT (expert_id) DO UPDATE SET calendar_codes = EXCLUDED.calendar_codes`,
+		expertID, pq.StringArray(codes))
+	return err
+}
+
+func (r *holidayRepository) AddOverride(expertID uuid.UUID, override *model.HolidayOverride) error {
+	_, err := r.db.Exec(`
+		INSERT INTO expert_holiday_overrides (expert_id, date, note)
+		VALUES ($1, $2::date, $3)
+		ON CONFLICT (expert_id, date) DO UPDATE SET note = EXCLUDED.note`,
+		expertID, override.Date, override.Note)
+	return err
+}
+
+func (r *holidayRepository) DeleteOverride(expertID uuid.UUID, date string) error {
+	res, err := r.db.Exec(`DELETE FROM expert_holiday_overrides WHERE expert_id = $1 AND date = $2::date`, expertID, date)
+	if err != nil {
+		return err
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetExpertHolidays lists the holidays the expert observes between two dates
+// (inclusive), one per date, leaving out dates the expert overrode
+func (r *holidayRepository) GetExpertHolidays(expertID uuid.UUID, defaultCalendar string, startDate, endDate string) ([]model.Holiday, error) {
+	return r.queryHolidays(`
+		SELECT DISTINCT ON (h.date) h.calendar_code, to_char(h.date, 'YYYY-MM-DD'), h.name
+		FROM holidays h
+		WHERE h.calendar_code = ANY(COALESCE(
+		        (SELECT calendar_codes FROM expert_holiday_calendars WHERE expert_id = $1),
+		        ARRAY[$2::text]))
+		  AND h.date BETWEEN $3::date AND $4::date
+		  AND NOT EXISTS (
+		        SELECT 1 FROM expert_holiday_overrides o
+		        WHERE o.expert_id = $1 AND o.date = h.date)
+		ORDER BY h.date, h.calendar_code`, expertID, defaultCalendar, startDate, endDate)
+}
@@ -3,16 +3,22 @@ package repository
 import (
 	"database/sql"
 	"expert-service/internal/model"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type OffTimeRepository interface {
 	Create(offTime *model.OffTime) error
+	GetByID(id uuid.UUID) (*model.OffTime, error)
 	GetByExpertID(expertID uuid.UUID) ([]*model.OffTime, error)
-	GetByExpertIDAndDateRange(expertID uuid.UUID, date time.Time) ([]*model.OffTime, error)
+	GetOverlapping(expertID uuid.UUID, from, to time.Time) ([]*model.OffTime, error)
+	GetOverlappingByExpertIDs(expertIDs []uuid.UUID, from, to time.Time) (map[uuid.UUID][]*model.OffTime, error)
 	Delete(id uuid.UUID) error
+	AddException(offTimeID uuid.UUID, exception *model.OffTimeException) error
+	DeleteException(offTimeID uuid.UUID, date string) error
 }
 
 type offTimeRepository struct {
@@ -23,75 +29,144 @@ func NewOffTimeRepository(db *sql.DB) OffTimeRepository {
 	return &offTimeRepository{db: db}
 }
 
+const offTimeColumns = `id, expert_id, start_datetime, end_datetime, COALESCE(reason, ''), COALESCE(is_recurring, false), COALESCE(recurrence_frequency, ''), recurrence_interval, recurrence_days_of_week, to_char(recurrence_until, 'YYYY-MM-DD'), created_at`
+
 func (r *offTimeRepository) Create(offTime *model.OffTime) error {
 	query := `
-		INSERT INTO expert_off_times (id, expert_id, start_datetime, end_datetime, reason, is_recurring, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO expert_off_times (id, expert_id, start_datetime, end_datetime, reason, is_recurring,
+			recurrence_frequency, recurrence_interval, recurrence_days_of_week, recurrence_until, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9, $10::date, $11)
 		RETURNING id, created_at`
 
 	offTime.ID = uuid.New()
 	offTime.CreatedAt = time.Now()
+	if offTime.RecurrenceInterval < 1 {
+		offTime.RecurrenceInterval = 1
+	}
 
 	return r.db.QueryRow(query,
 		offTime.ID, offTime.ExpertID, offTime.StartDateTime, offTime.EndDateTime,
-		offTime.Reason, offTime.IsRecurring, offTime.CreatedAt).
+		offTime.Reason, offTime.IsRecurring, offTime.RecurrenceFrequency, offTime.RecurrenceInterval,
+		offTime.RecurrenceDaysOfWeek, offTime.RecurrenceUntil, offTime.CreatedAt).
 		Scan(&offTime.ID, &offTime.CreatedAt)
 }
 
+func (r *offTimeRepository) GetByID(id uuid.UUID) (*model.OffTime, error) {
+	offTimes, err := r.query(`SELECT `+offTimeColumns+` FROM expert_off_times WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(offTimes) == 0 {
+		return nil, nil
+	}
+	return offTimes[0], nil
+}
+
 func (r *offTimeRepository) GetByExpertID(expertID uuid.UUID) ([]*model.OffTime, error) {
-	query := `
-		SELECT id, expert_id, start_datetime, end_datetime, reason, is_recurring, created_at
+	return r.query(`
+		SELECT `+offTimeColumns+`
 		FROM expert_off_times WHERE expert_id = $1
-		ORDER BY start_datetime DESC`
-	rows, err := r.db.Query(query, expertID)
+		ORDER BY start_datetime DESC`, expertID)
+}
+
+// GetOverlapping returns the concrete off-time occurrences overlapping
+// [from, to), with recurring off-times expanded, ordered by start
+func (r *offTimeRepository) GetOverlapping(expertID uuid.UUID, from, to time.Time) ([]*model.OffTime, error) {
+	byExpert, err := r.GetOverlappingByExpertIDs([]uuid.UUID{expertID}, from, to)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	return byExpert[expertID], nil
+}
 
-	var offTimes []*model.OffTime
-	for rows.Next() {
-		offTime := &model.OffTime{}
-		err := rows.Scan(
-			&offTime.ID, &offTime.ExpertID,
-			&offTime.StartDateTime, &offTime.EndDateTime,
-			&offTime.Reason, &offTime.IsRecurring,
-			&offTime.CreatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		offTimes = append(offTimes, offTime)
+// GetOverlappingByExpertIDs is GetOverlapping for several experts in one
+// query, keyed by expert ID
+func (r *offTimeRepository) GetOverlappingByExpertIDs(expertIDs []uuid.UUID, from, to time.Time) (map[uuid.UUID][]*model.OffTime, error) {
+	// Recurring rows whose series has started are narrowed down in Go, since
+	// whether one of their occurrences overlaps depends on the pattern
+	offTimes, err := r.query(`
+		SELECT `+offTimeColumns+`
+		FROM expert_off_times
+		WHERE expert_id = ANY($1::uuid[]) AND start_datetime < $3
+		  AND (end_datetime > $2 OR (is_recurring AND (recurrence_until IS NULL OR recurrence_until >= ($2::timestamp - (end_datetime - start_datetime))::date)))
+		ORDER BY start_datetime`, uuidArray(expertIDs), from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	byExpert := make(map[uuid.UUID][]*model.OffTime)
+	for _, offTime := range offTimes {
+		byExpert[offTime.ExpertID] = append(byExpert[offTime.ExpertID], offTime.Occurrences(from, to)...)
 	}
-	return offTimes, nil
+	for _, occurrences := range byExpert {
+		sort.SliceStable(occurrences, func(i, j int) bool {
+			return occurrences[i].StartDateTime.Before(occurrences[j].StartDateTime)
+		})
+	}
+	return byExpert, nil
 }
 
-func (r *offTimeRepository) GetByExpertIDAndDateRange(expertID uuid.UUID, date time.Time) ([]*model.OffTime, error) {
-	query := `
-		SELECT id, expert_id, start_datetime, end_datetime, reason, is_recurring, created_at
-		FROM expert_off_times 
-		WHERE expert_id = $1 AND start_datetime <= $2 AND end_datetime >= $2`
-	rows, err := r.db.Query(query, expertID, date)
+// query loads off-times together with their exceptions
+func (r *offTimeRepository) query(query string, args ...interface{}) ([]*model.OffTime, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	var offTimes []*model.OffTime
+	byID := make(map[uuid.UUID]*model.OffTime)
+	var ids []string
 	for rows.Next() {
 		offTime := &model.OffTime{}
+		var until sql.NullString
 		err := rows.Scan(
 			&offTime.ID, &offTime.ExpertID,
 			&offTime.StartDateTime, &offTime.EndDateTime,
 			&offTime.Reason, &offTime.IsRecurring,
+			&offTime.RecurrenceFrequency, &offTime.RecurrenceInterval,
+			&offTime.RecurrenceDaysOfWeek, &until,
 			&offTime.CreatedAt,
 		)
 		if err != nil {
 			return nil, err
 		}
+		if until.Valid {
+			offTime.RecurrenceUntil = &until.String
+		}
 		offTimes = append(offTimes, offTime)
+		if offTime.IsRecurring {
+			byID[offTime.ID] = offTime
+			ids = append(ids, offTime.ID.String())
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return offTimes, nil
+	}
+
+	exceptionRows, err := r.db.Query(`
+		SELECT off_time_id, to_char(date, 'YYYY-MM-DD'), COALESCE(reason, '')
+		FROM expert_off_time_exceptions
+		WHERE off_time_id = ANY($1::uuid[])
+		ORDER BY date`, pq.StringArray(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer exceptionRows.Close()
+	for exceptionRows.Next() {
+		var offTimeID uuid.UUID
+		var exception model.OffTimeException
+		if err := exceptionRows.Scan(&offTimeID, &exception.Date, &exception.Reason); err != nil {
+			return nil, err
+		}
+		if offTime, ok := byID[offTimeID]; ok {
+			offTime.Exceptions = append(offTime.Exceptions, exception)
+		}
 	}
-	return offTimes, nil
+	return offTimes, exceptionRows.Err()
 }
 
 func (r *offTimeRepository) Delete(id uuid.UUID) error {
@@ -109,3 +184,27 @@ func (r *offTimeRepository) Delete(id uuid.UUID) error {
 	}
 	return nil
 }
+
+func (r *offTimeRepository) AddException(offTimeID uuid.UUID, exception *model.OffTimeException) error {
+	_, err := r.db.Exec(`
+		INSERT INTO expert_off_time_exceptions (off_time_id, date, reason)
+		VALUES ($1, $2::date, $3)
+		ON CONFLICT (off_time_id, date) DO UPDATE SET reason = EXCLUDED.reason`,
+		offTimeID, exception.Date, exception.Reason)
+	return err
+}
+
+func (r *offTimeRepository) DeleteException(offTimeID uuid.UUID, date string) error {
+	res, err := r.db.Exec(`DELETE FROM expert_off_time_exceptions WHERE off_time_id = $1 AND date = $2::date`, offTimeID, date)
+	if err != nil {
+		return err
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
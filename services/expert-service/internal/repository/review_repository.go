@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"database/sql"
+	"expert-service/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReviewRepository persists reviews. Every write that can change an expert's
+// rating runs in one transaction with the recomputation of experts.rating and
+// experts.total_reviews, so the aggregates never drift from the reviews.
+type ReviewRepository interface {
+	Create(review *model.Review) error
+	GetByID(id uuid.UUID) (*model.Review, error)
+	GetByBookingID(bookingID uuid.UUID) (*model.Review, error)
+	GetByExpertID(expertID uuid.UUID, limit, offset int) ([]*model.Review, int, error)
+	GetByStatus(status string, limit, offset int) ([]*model.Review, int, error)
+	GetByUserID(userID uuid.UUID) ([]*model.Review, error)
+	Update(review *model.Review) error
+	UpdateReply(review *model.Review) error
+	UpdateStatus(review *model.Review) error
+}
+
+type reviewRepository struct {
+	db *sql.DB
+}
+
+func NewReviewRepository(db *sql.DB) ReviewRepository {
+	return &reviewRepository{db: db}
+}
+
+const reviewColumns = `id, booking_id, user_id, expert_id, rating, COALESCE(comment, ''), COALESCE(expert_reply, ''), replied_at, status, COALESCE(moderation_reason, ''), created_at, updated_at`
+
+func scanReview(row interface {
+	Scan(dest ...interface{}) error
+}) (*model.Review, error) {
+	review := &model.Review{}
+	err := row.Scan(
+		&review.ID, &review.BookingID, &review.UserID, &review.ExpertID,
+		&review.Rating, &review.Comment, &review.ExpertReply, &review.RepliedAt,
+		&review.Status, &review.ModerationReason, &review.CreatedAt, &review.UpdatedAt)
+	return review, err
+}
+
+func (r *reviewRepository) Create(review *model.Review) error {
+	query := `
+		INSERT INTO reviews (id, booking_id, user_id, expert_id, rating, comment, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at, updated_at`
+
+	review.ID = uuid.New()
+	review.CreatedAt = time.Now()
+	review.UpdatedAt = time.Now()
+
+	return r.withRatingRefresh(review.ExpertID, func(tx *sql.Tx) error {
+		return tx.QueryRow(query,
+			review.ID, review.BookingID, review.UserID, review.ExpertID,
+			review.Rating, review.Comment, review.Status,
+			review.CreatedAt, review.UpdatedAt).
+			Scan(&review.ID, &review.CreatedAt, &review.UpdatedAt)
+	})
+}
+
+func (r *reviewRepository) GetByID(id uuid.UUID) (*model.Review, error) {
+	query := `SELECT ` + reviewColumns + ` FROM reviews WHERE id = $1`
+	review, err := scanReview(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+func (r *reviewRepository) GetByBookingID(bookingID uuid.UUID) (*model.Review, error) {
+	query := `SELECT ` + reviewColumns + ` FROM reviews WHERE booking_id = $1`
+	review, err := scanReview(r.db.QueryRow(query, bookingID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// GetByExpertID lists the reviews shown publicly on an expert's profile
+func (r *reviewRepository) GetByExpertID(expertID uuid.UUID, limit, offset int) ([]*model.Review, int, error) {
+	return r.list(`expert_id = $1 AND status <> 'hidden'`, expertID, limit, offset)
+}
+
+// GetByStatus lists reviews in a moderation state, e.g. the flagged queue
+func (r *reviewRepository) GetByStatus(status string, limit, offset int) ([]*model.Review, int, error) {
+	return r.list(`status = $1`, status, limit, offset)
+}
+
+// GetByUserID lists every review a user wrote, hidden ones included, for the
+// user's data export
+func (r *reviewRepository) GetByUserID(userID uuid.UUID) ([]*model.Review, error) {
+	rows, err := r.db.Query(`SELECT `+reviewColumns+` FROM reviews WHERE user_id = $1 ORDER BY created_at`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := []*model.Review{}
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, rows.Err()
+}
+
+func (r *reviewRepository) list(where string, arg interface{}, limit, offset int) ([]*model.Review, int, error) {
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM reviews WHERE `+where, arg).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + reviewColumns + ` FROM reviews WHERE ` + where + ` ORDER BY created_at DESC LIMIT $2 OFFSET $3`
+	rows, err := r.db.Query(query, arg, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var reviews []*model.Review
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, total, rows.Err()
+}
+
+func (r *reviewRepository) Update(review *model.Review) error {
+	query := `UPDATE reviews SET rating = $1, comment = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3 RETURNING updated_at`
+	return r.withRatingRefresh(review.ExpertID, func(tx *sql.Tx) error {
+		return tx.QueryRow(query, review.Rating, review.Comment, review.ID).Scan(&review.UpdatedAt)
+	})
+}
+
+// UpdateReply stores the expert's answer; it does not affect the rating
+func (r *reviewRepository) UpdateReply(review *model.Review) error {
+	query := `UPDATE reviews SET expert_reply = $1, replied_at = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3 RETURNING updated_at`
+	return r.db.QueryRow(query, review.ExpertReply, review.RepliedAt, review.ID).Scan(&review.UpdatedAt)
+}
+
+func (r *reviewRepository) UpdateStatus(review *model.Review) error {
+	query := `UPDATE reviews SET status = $1, moderation_reason = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3 RETURNING updated_at`
+	return r.withRatingRefresh(review.ExpertID, func(tx *sql.Tx) error {
+		return tx.QueryRow(query, review.Status, review.ModerationReason, review.ID).Scan(&review.UpdatedAt)
+	})
+}
+
+// withRatingRefresh runs fn and recomputes the expert's aggregates in the same
+// transaction. The expert row is locked first so concurrent reviews of the
+// same expert are applied one after the other.
+func (r *reviewRepository) withRatingRefresh(expertID uuid.UUID, fn func(tx *sql.Tx) error) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT 1 FROM experts WHERE id = $1 FOR UPDATE`, expertID); err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE experts e
+		SET rating = COALESCE(agg.avg_rating, 0), total_reviews = agg.total, updated_at = CURRENT_TIMESTAMP
+		FROM (
+			SELECT ROUND(AVG(rating)::numeric, 2) AS avg_rating, COUNT(*) AS total
+			FROM reviews
+			WHERE expert_id = $1 AND status <> 'hidden'
+		) agg
+		WHERE e.id = $1`
+	if _, err := tx.Exec(query, expertID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
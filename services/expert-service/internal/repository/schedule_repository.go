@@ -14,6 +14,7 @@ type ScheduleRepository interface {
 	Create(schedule *model.Schedule) error
 	GetByExpertID(expertID string) ([]*model.Schedule, error)
 	GetByExpertIDAndDay(expertID string, dayOfWeek int) ([]*model.Schedule, error)
+	GetByExpertIDs(expertIDs []uuid.UUID) ([]*model.Schedule, error)
 	Update(schedule *model.Schedule) error
 	Delete(id uuid.UUID) error
 	GetByID(id uuid.UUID) (*model.Schedule, error)
@@ -100,6 +101,35 @@ func (r *scheduleRepository) GetByExpertIDAndDay(expertID string, dayOfWeek int)
 	return schedules, nil
 }
 
+// GetByExpertIDs returns the active weekly schedules of several experts in
+// one query
+func (r *scheduleRepository) GetByExpertIDs(expertIDs []uuid.UUID) ([]*model.Schedule, error) {
+	query := `
+		SELECT id, expert_id, day_of_week, start_time, end_time, is_active, created_at, updated_at
+		FROM schedules WHERE expert_id = ANY($1::uuid[]) AND is_active = true
+		ORDER BY expert_id, day_of_week, start_time`
+	rows, err := r.db.Query(query, uuidArray(expertIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schedules []*model.Schedule
+	for rows.Next() {
+		schedule := &model.Schedule{}
+		err := rows.Scan(
+			&schedule.ID, &schedule.ExpertID, &schedule.DayOfWeek,
+			&schedule.StartTime, &schedule.EndTime,
+			&schedule.IsActive, &schedule.CreatedAt, &schedule.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, rows.Err()
+}
+
 func (r *scheduleRepository) Update(schedule *model.Schedule) error {
 	query := `
 		UPDATE schedules 
@@ -2,6 +2,8 @@ package routes
 
 import (
 	"expert-service/internal/handler"
+	"expert-service/internal/middleware"
+	"expert-service/internal/repository"
 
 	"github.com/gin-gonic/gin"
 )
@@ -11,16 +13,96 @@ func SetupRoutes(
 	expertHandler *handler.ExpertHandler,
 	scheduleHandler *handler.ScheduleHandler,
 	availabilityHandler *handler.AvailabilityHandler,
+	slotHandler *handler.SlotHandler,
+	consultationServiceHandler *handler.ConsultationServiceHandler,
+	reviewHandler *handler.ReviewHandler,
+	holidayHandler *handler.HolidayHandler,
+	onboardingHandler *handler.OnboardingHandler,
+	certificationHandler *handler.CertificationHandler,
+	privacyHandler *handler.PrivacyHandler,
+	authMiddleware gin.HandlerFunc,
+	expertRepo repository.ExpertRepository,
 ) {
-	// Expert routes
+	// Expert routes; experts are normally created by approving an application
 	experts := router.Group("/api/experts")
 	{
-		experts.POST("", expertHandler.CreateExpert)
+		experts.POST("", authMiddleware, middleware.RequireRole(middleware.RoleAdmin), expertHandler.CreateExpert)
 		experts.GET("", expertHandler.GetExperts)
 		experts.GET("/:id", expertHandler.GetExpert)
 		experts.PUT("/:id", expertHandler.UpdateExpert)
 		experts.DELETE("/:id", expertHandler.DeleteExpert)
 		experts.GET("/expertise", expertHandler.GetExpertsByExpertise)
+		experts.GET("/search", expertHandler.SearchExperts)
+
+		// Service catalog; bookings take their price and duration from it, so
+		// only the expert or an admin may change it
+		experts.GET("/:id/services", consultationServiceHandler.GetServices)
+		experts.GET("/:id/services/:service_id", consultationServiceHandler.GetService)
+		catalog := experts.Group("/:id/services", authMiddleware, middleware.RequireExpertOwner(expertRepo, "id"))
+		catalog.POST("", consultationServiceHandler.CreateService)
+		catalog.PUT("/:service_id", consultationServiceHandler.UpdateService)
+		catalog.DELETE("/:service_id", consultationServiceHandler.DeleteService)
+
+		// Public reviews
+		experts.GET("/:id/reviews", reviewHandler.GetExpertReviews)
+
+		// Verified, unexpired certifications
+		experts.GET("/:id/certifications", certificationHandler.GetExpertCertifications)
+	}
+
+	// Certifications of the calling expert; verifying them is admin only
+	certifications := router.Group("/api/certifications", authMiddleware)
+	{
+		certifications.GET("/me", certificationHandler.GetMyCertifications)
+		certifications.POST("", certificationHandler.CreateCertification)
+		certifications.GET("/:id", certificationHandler.GetCertification)
+		certifications.PUT("/:id", certificationHandler.UpdateCertification)
+		certifications.DELETE("/:id", certificationHandler.DeleteCertification)
+		certifications.POST("/:id/files", certificationHandler.UploadFile)
+		certifications.GET("/:id/files/:file_id", certificationHandler.DownloadFile)
+		certifications.DELETE("/:id/files/:file_id", certificationHandler.DeleteFile)
+
+		admin := certifications.Group("", middleware.RequireRole(middleware.RoleAdmin))
+		admin.GET("", certificationHandler.GetCertificationsByStatus)
+		admin.PUT("/:id/verification", certificationHandler.VerifyCertification)
+	}
+
+	// Expert onboarding; reviewing applications is admin only
+	applications := router.Group("/api/expert-applications", authMiddleware)
+	{
+		applications.POST("", onboardingHandler.Apply)
+		applications.GET("/me", onboardingHandler.GetMyApplication)
+		applications.POST("/me/documents", onboardingHandler.AddDocument)
+		applications.POST("/me/submit", onboardingHandler.Submit)
+
+		admin := applications.Group("", middleware.RequireRole(middleware.RoleAdmin))
+		admin.GET("", onboardingHandler.GetApplications)
+		admin.GET("/:id", onboardingHandler.GetApplication)
+		admin.POST("/:id/review", onboardingHandler.StartReview)
+		admin.POST("/:id/approve", onboardingHandler.ApproveApplication)
+		admin.POST("/:id/reject", onboardingHandler.RejectApplication)
+	}
+
+	// Data export and erasure of a user's data, for the user or an admin
+	users := router.Group("/api/users", authMiddleware)
+	{
+		users.GET("/:id/data-export", privacyHandler.ExportUserData)
+		users.POST("/:id/erase", privacyHandler.EraseUserData)
+	}
+
+	// Review routes
+	reviews := router.Group("/api/reviews")
+	{
+		reviews.GET("/:id", middleware.Optional(authMiddleware), reviewHandler.GetReview)
+
+		authorized := reviews.Group("", authMiddleware)
+		authorized.POST("", reviewHandler.CreateReview)
+		authorized.PUT("/:id", reviewHandler.UpdateReview)
+		authorized.PUT("/:id/reply", reviewHandler.ReplyToReview)
+
+		admin := authorized.Group("", middleware.RequireRole(middleware.RoleAdmin))
+		admin.GET("/flagged", reviewHandler.GetFlaggedReviews)
+		admin.PUT("/:id/moderation", reviewHandler.ModerateReview)
 	}
 
 	// Schedule routes
@@ -43,10 +125,52 @@ func SetupRoutes(
 		availability.PUT("/:id", availabilityHandler.UpdateAvailability)
 		availability.DELETE("/:id", availabilityHandler.DeleteAvailability)
 		availability.POST("/:id/book", availabilityHandler.BookAvailability)
-		availability.POST("/recurring", availabilityHandler.CreateRecurringAvailability)
+		availability.GET("/rules", availabilityHandler.GetAvailabilityRules)
+		availability.GET("/rules/:id", availabilityHandler.GetAvailabilityRule)
+
+		// Recurring availability; the service lets only the expert or an admin
+		// change it, since rules are looked up by their own ID
+		availability.POST("/recurring", authMiddleware, availabilityHandler.CreateRecurringAvailability)
+		availability.PUT("/rules/:id", authMiddleware, availabilityHandler.UpdateAvailabilityRule)
+		availability.DELETE("/rules/:id", authMiddleware, availabilityHandler.DeleteAvailabilityRule)
+		availability.POST("/rules/:id/exceptions", authMiddleware, availabilityHandler.AddAvailabilityRuleException)
+		availability.DELETE("/rules/:id/exceptions/:date", authMiddleware, availabilityHandler.DeleteAvailabilityRuleException)
+
 		availability.POST("/check", availabilityHandler.CheckAvailability)
-		availability.POST("/off-time", availabilityHandler.CreateOffTime)
 		availability.GET("/off-time/:expert_id", availabilityHandler.GetExpertOffTimes)
-		availability.DELETE("/off-time/:id", availabilityHandler.DeleteOffTime)
+
+		// Off-times, checked by the service the same way
+		availability.POST("/off-time", authMiddleware, availabilityHandler.CreateOffTime)
+		availability.DELETE("/off-time/:id", authMiddleware, availabilityHandler.DeleteOffTime)
+		availability.POST("/off-time/:id/exceptions", authMiddleware, availabilityHandler.AddOffTimeException)
+		availability.DELETE("/off-time/:id/exceptions/:date", authMiddleware, availabilityHandler.DeleteOffTimeException)
+
+		availability.GET("/slots", slotHandler.GetSlots)
+		availability.POST("/check-window", slotHandler.CheckBookingWindow)
+
+		// Booking rules and holidays of an expert; only the expert or an admin
+		// changes them
+		expertOwner := middleware.RequireExpertOwner(expertRepo, "expert_id")
+		availability.GET("/booking-rules/:expert_id", slotHandler.GetBookingRule)
+		availability.PUT("/booking-rules/:expert_id", authMiddleware, expertOwner, slotHandler.UpdateBookingRule)
+
+		// Holidays observed by an expert
+		availability.GET("/holidays/:expert_id", holidayHandler.GetExpertSettings)
+		availability.PUT("/holidays/:expert_id", authMiddleware, expertOwner, holidayHandler.SetExpertCalendars)
+		availability.GET("/holidays/:expert_id/dates", holidayHandler.GetExpertHolidays)
+		availability.POST("/holidays/:expert_id/overrides", authMiddleware, expertOwner, holidayHandler.AddOverride)
+		availability.DELETE("/holidays/:expert_id/overrides/:date", authMiddleware, expertOwner, holidayHandler.DeleteOverride)
+	}
+
+	// Holiday calendars; editing them is admin only
+	holidayCalendars := router.Group("/api/v1/holiday-calendars")
+	{
+		holidayCalendars.GET("", holidayHandler.GetCalendars)
+		holidayCalendars.GET("/:code", holidayHandler.GetCalendar)
+
+		admin := holidayCalendars.Group("", authMiddleware, middleware.RequireRole(middleware.RoleAdmin))
+		admin.PUT("/:code", holidayHandler.UpdateCalendar)
+		admin.POST("/:code/holidays", holidayHandler.AddHoliday)
+		admin.DELETE("/:code/holidays/:date", holidayHandler.DeleteHoliday)
 	}
 }
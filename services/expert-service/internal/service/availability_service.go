@@ -1,14 +1,18 @@
 package service
 
 import (
+	"database/sql"
 	"encoding/json"
 	"expert-service/internal/cache"
+	"expert-service/internal/events"
 	"expert-service/internal/model"
 	"expert-service/internal/repository"
 	"fmt"
 	"time"
 
+	"booking-system/shared/pkg/apperr"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // isTimeInRange checks if a time string falls within a start and end time range
@@ -19,38 +23,75 @@ func (s *expertAvailabilityService) isTimeInRange(timeStr, startTimeStr, endTime
 	return (t.Equal(start) || t.After(start)) && t.Before(end)
 }
 
+var (
+	ErrAvailabilityNotFound      = apperr.NotFound("availability_not_found")
+	ErrAvailabilityAlreadyBooked = apperr.Conflict("availability_already_booked")
+	ErrAvailabilityOverlap       = apperr.Conflict("availability_overlap")
+	ErrAvailabilityRecurring     = apperr.Validation("availability_is_recurring")
+	ErrAvailabilityRuleNotFound  = apperr.NotFound("availability_rule_not_found")
+	ErrExpertCalendarForbidden   = apperr.Forbidden("access_denied")
+	ErrRuleExceptionNotFound     = apperr.NotFound("rule_exception_not_found")
+	ErrOffTimeNotFound           = apperr.NotFound("off_time_not_found")
+	ErrOffTimeNotRecurring       = apperr.Validation("off_time_not_recurring")
+	ErrOffTimeExceptionNotFound  = apperr.NotFound("off_time_exception_not_found")
+)
+
+// maxOffTimeRangeDays caps how far recurring off-times are expanded in one listing
+const maxOffTimeRangeDays = 366
+
 type ExpertAvailabilityService interface {
 	CheckAvailability(req *model.CheckAvailabilityRequest) (bool, error)
-	CreateOffTime(req *model.CreateOffTimeRequest) (*model.OffTime, error)
+	CreateOffTime(req *model.CreateOffTimeRequest, userID uuid.UUID, isAdmin bool) (*model.OffTime, error)
 	GetExpertOffTimes(expertID string) ([]*model.OffTime, error)
-	DeleteOffTime(id string) error
+	GetExpertOffTimesInRange(expertID string, startDate, endDate time.Time) ([]*model.OffTime, error)
+	DeleteOffTime(id string, userID uuid.UUID, isAdmin bool) error
+	AddOffTimeException(id string, req *model.CreateOffTimeExceptionRequest, userID uuid.UUID, isAdmin bool) (*model.OffTime, error)
+	DeleteOffTimeException(id string, date string, userID uuid.UUID, isAdmin bool) error
 	CreateAvailability(req *model.CreateAvailabilityRequest) (*model.Availability, error)
 	GetAvailabilityByID(id string) (*model.Availability, error)
 	UpdateAvailability(id string, req *model.UpdateAvailabilityRequest) (*model.Availability, error)
 	DeleteAvailability(id string) error
 	GetAvailabilities(expertID string, startDate, endDate time.Time, isBooked *bool) ([]*model.Availability, error)
 	BookAvailability(id string) error
-	CreateRecurringAvailability(req *model.CreateRecurringAvailabilityRequest) ([]*model.Availability, error)
+	CreateRecurringAvailability(req *model.CreateRecurringAvailabilityRequest, userID uuid.UUID, isAdmin bool) (*model.AvailabilityRule, error)
+	GetAvailabilityRules(expertID string) ([]*model.AvailabilityRule, error)
+	GetAvailabilityRule(id string) (*model.AvailabilityRule, error)
+	UpdateAvailabilityRule(id string, req *model.UpdateAvailabilityRuleRequest, userID uuid.UUID, isAdmin bool) (*model.AvailabilityRule, error)
+	DeleteAvailabilityRule(id string, fromDate string, userID uuid.UUID, isAdmin bool) error
+	AddAvailabilityRuleException(id string, req *model.CreateAvailabilityRuleExceptionRequest, userID uuid.UUID, isAdmin bool) (*model.AvailabilityRule, error)
+	DeleteAvailabilityRuleException(id string, date string, userID uuid.UUID, isAdmin bool) error
 }
 
 type expertAvailabilityService struct {
-	expertRepo   repository.ExpertRepository
-	scheduleRepo repository.ScheduleRepository
-	offTimeRepo  repository.OffTimeRepository
-	cache        cache.AvailabilityCache
+	expertRepo       repository.ExpertRepository
+	scheduleRepo     repository.ScheduleRepository
+	offTimeRepo      repository.OffTimeRepository
+	availabilityRepo repository.AvailabilityRepository
+	ruleRepo         repository.AvailabilityRuleRepository
+	holidaySvc       HolidayService
+	cache            cache.AvailabilityCache
+	publisher        events.Publisher
 }
 
 func NewExpertAvailabilityService(
 	expertRepo repository.ExpertRepository,
 	scheduleRepo repository.ScheduleRepository,
 	offTimeRepo repository.OffTimeRepository,
+	availabilityRepo repository.AvailabilityRepository,
+	ruleRepo repository.AvailabilityRuleRepository,
+	holidaySvc HolidayService,
 	cache cache.AvailabilityCache,
+	publisher events.Publisher,
 ) ExpertAvailabilityService {
 	return &expertAvailabilityService{
-		expertRepo:   expertRepo,
-		scheduleRepo: scheduleRepo,
-		offTimeRepo:  offTimeRepo,
-		cache:        cache,
+		expertRepo:       expertRepo,
+		scheduleRepo:     scheduleRepo,
+		offTimeRepo:      offTimeRepo,
+		availabilityRepo: availabilityRepo,
+		ruleRepo:         ruleRepo,
+		holidaySvc:       holidaySvc,
+		cache:            cache,
+		publisher:        publisher,
 	}
 }
 
@@ -58,24 +99,24 @@ func NewExpertAvailabilityService(
 func (s *expertAvailabilityService) CheckAvailability(req *model.CheckAvailabilityRequest) (bool, error) {
 	expertUUID, err := uuid.Parse(req.ExpertID)
 	if err != nil {
-		return false, fmt.Errorf("invalid expert ID format: %v", err)
+		return false, apperr.Validation("invalid_expert_id")
 	}
 	expert, err := s.expertRepo.GetByID(expertUUID)
 	if err != nil {
-		return false, fmt.Errorf("không thể kiểm tra chuyên gia: %v", err)
+		return false, fmt.Errorf("failed to get expert: %w", err)
 	}
 	if expert == nil {
-		return false, fmt.Errorf("không tìm thấy chuyên gia với ID %s", req.ExpertID)
+		return false, apperr.NotFound("expert_not_found")
 	}
 
 	// Parse date and time
 	date, err := time.Parse("2006-01-02", req.Date)
 	if err != nil {
-		return false, fmt.Errorf("định dạng ngày không hợp lệ")
+		return false, apperr.Validation("invalid_date")
 	}
 
 	// Check cache first
-	cacheKey := fmt.Sprintf("%s:%s", req.ExpertID, req.Date)
+	cacheKey := fmt.Sprintf("%s:%s:%s", req.ExpertID, req.Date, req.Time)
 	if cached, err := s.cache.GetAvailability(cacheKey); err == nil && cached != nil {
 		var isAvailable bool
 		if err := json.Unmarshal(cached, &isAvailable); err == nil {
@@ -83,11 +124,21 @@ func (s *expertAvailabilityService) CheckAvailability(req *model.CheckAvailabili
 		}
 	}
 
-	// Check if expert is on off-time
-	offTimes, err := s.offTimeRepo.GetByExpertIDAndDateRange(expertUUID, date)
+	// Check if expert is on off-time at the requested minute, recurring
+	// off-times and observed holidays included
+	moment, err := time.ParseInLocation("2006-01-02 15:04", req.Date+" "+req.Time, time.Local)
+	if err != nil {
+		return false, apperr.Validation("invalid_time")
+	}
+	offTimes, err := s.offTimeRepo.GetOverlapping(expertUUID, moment, moment.Add(time.Minute))
 	if err != nil {
-		return false, fmt.Errorf("không thể kiểm tra thời gian nghỉ: %v", err)
+		return false, fmt.Errorf("failed to check off-times: %w", err)
 	}
+	holidays, err := s.holidaySvc.HolidayOffTimes(expertUUID, moment, moment.Add(time.Minute))
+	if err != nil {
+		return false, err
+	}
+	offTimes = append(offTimes, holidays...)
 	if len(offTimes) > 0 {
 		data, _ := json.Marshal(false)
 		if err := s.cache.SetAvailability(cacheKey, data); err != nil {
@@ -100,7 +151,7 @@ func (s *expertAvailabilityService) CheckAvailability(req *model.CheckAvailabili
 	dayOfWeek := int(date.Weekday())
 	schedules, err := s.scheduleRepo.GetByExpertIDAndDay(req.ExpertID, dayOfWeek)
 	if err != nil {
-		return false, fmt.Errorf("không thể lấy lịch làm việc: %v", err)
+		return false, fmt.Errorf("failed to get schedules: %w", err)
 	}
 
 	// Check if the requested time falls within any schedule
@@ -121,31 +172,27 @@ func (s *expertAvailabilityService) CheckAvailability(req *model.CheckAvailabili
 }
 
 // CreateOffTime tạo thời gian nghỉ cho chuyên gia
-func (s *expertAvailabilityService) CreateOffTime(req *model.CreateOffTimeRequest) (*model.OffTime, error) {
+func (s *expertAvailabilityService) CreateOffTime(req *model.CreateOffTimeRequest, userID uuid.UUID, isAdmin bool) (*model.OffTime, error) {
 	expertUUID, err := uuid.Parse(req.ExpertID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid expert ID format: %v", err)
-	}
-	expert, err := s.expertRepo.GetByID(expertUUID)
-	if err != nil {
-		return nil, fmt.Errorf("không thể kiểm tra chuyên gia: %v", err)
+		return nil, apperr.Validation("invalid_expert_id")
 	}
-	if expert == nil {
-		return nil, fmt.Errorf("không tìm thấy chuyên gia với ID %s", req.ExpertID)
+	if err := s.authorizeExpert(expertUUID, userID, isAdmin); err != nil {
+		return nil, err
 	}
 
 	startDateTime, err := time.Parse("2006-01-02T15:04:05Z", req.StartDateTime)
 	if err != nil {
-		return nil, fmt.Errorf("định dạng thời gian bắt đầu không hợp lệ")
+		return nil, apperr.Validation("invalid_start_time")
 	}
 
 	endDateTime, err := time.Parse("2006-01-02T15:04:05Z", req.EndDateTime)
 	if err != nil {
-		return nil, fmt.Errorf("định dạng thời gian kết thúc không hợp lệ")
+		return nil, apperr.Validation("invalid_end_time")
 	}
 
 	if endDateTime.Before(startDateTime) {
-		return nil, fmt.Errorf("thời gian kết thúc phải sau thời gian bắt đầu")
+		return nil, apperr.Validation("end_before_start")
 	}
 
 	offTime := &model.OffTime{
@@ -155,14 +202,16 @@ func (s *expertAvailabilityService) CreateOffTime(req *model.CreateOffTimeReques
 		Reason:        req.Reason,
 		IsRecurring:   req.IsRecurring,
 	}
+	if err := applyOffTimeRecurrence(offTime, req); err != nil {
+		return nil, err
+	}
 
 	err = s.offTimeRepo.Create(offTime)
 	if err != nil {
-		return nil, fmt.Errorf("không thể tạo thời gian nghỉ: %v", err)
+		return nil, fmt.Errorf("failed to create off-time: %w", err)
 	}
 
-	// Invalidate cache
-	s.cache.InvalidateExpert(req.ExpertID)
+	s.offTimeChanged(req.ExpertID, events.TypeOffTimeCreated)
 	return offTime, nil
 }
 
@@ -170,190 +219,892 @@ func (s *expertAvailabilityService) CreateOffTime(req *model.CreateOffTimeReques
 func (s *expertAvailabilityService) GetExpertOffTimes(expertID string) ([]*model.OffTime, error) {
 	expertUUID, err := uuid.Parse(expertID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid expert ID format: %v", err)
+		return nil, apperr.Validation("invalid_expert_id")
 	}
 	expert, err := s.expertRepo.GetByID(expertUUID)
 	if err != nil {
-		return nil, fmt.Errorf("không thể kiểm tra chuyên gia: %v", err)
+		return nil, fmt.Errorf("failed to get expert: %w", err)
 	}
 	if expert == nil {
-		return nil, fmt.Errorf("không tìm thấy chuyên gia với ID %s", expertID)
+		return nil, apperr.NotFound("expert_not_found")
 	}
 
 	offTimes, err := s.offTimeRepo.GetByExpertID(expertUUID)
 	if err != nil {
-		return nil, fmt.Errorf("không thể lấy danh sách thời gian nghỉ: %v", err)
+		return nil, fmt.Errorf("failed to get off-times: %w", err)
 	}
 
 	return offTimes, nil
 }
 
-// DeleteOffTime xóa thời gian nghỉ
-func (s *expertAvailabilityService) DeleteOffTime(id string) error {
-	offTimeID, err := uuid.Parse(id)
+// GetExpertOffTimesInRange lists the off-time occurrences overlapping the days
+// [startDate, endDate], with recurring off-times expanded
+func (s *expertAvailabilityService) GetExpertOffTimesInRange(expertID string, startDate, endDate time.Time) ([]*model.OffTime, error) {
+	expertUUID, err := s.requireExpert(expertID)
+	if err != nil {
+		return nil, err
+	}
+	if endDate.Before(startDate) {
+		return nil, apperr.Validation("end_date_before_start")
+	}
+	if endDate.Sub(startDate) > maxOffTimeRangeDays*24*time.Hour {
+		return nil, apperr.Validation("date_range_too_long", maxOffTimeRangeDays)
+	}
+
+	from := time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, time.Local)
+	to := time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, 1)
+	offTimes, err := s.offTimeRepo.GetOverlapping(expertUUID, from, to)
 	if err != nil {
-		return fmt.Errorf("invalid off time ID format: %v", err)
+		return nil, fmt.Errorf("failed to get off-times: %w", err)
 	}
+	if offTimes == nil {
+		offTimes = []*model.OffTime{}
+	}
+	return offTimes, nil
+}
+
+// AddOffTimeException skips one occurrence of a recurring off-time
+func (s *expertAvailabilityService) AddOffTimeException(id string, req *model.CreateOffTimeExceptionRequest, userID uuid.UUID, isAdmin bool) (*model.OffTime, error) {
+	offTime, err := s.getRecurringOffTime(id, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := time.Parse("2006-01-02", req.Date); err != nil {
+		return nil, apperr.Validation("invalid_date")
+	}
+
+	exception := &model.OffTimeException{Date: req.Date, Reason: req.Reason}
+	if err := s.offTimeRepo.AddException(offTime.ID, exception); err != nil {
+		return nil, fmt.Errorf("failed to add exception: %w", err)
+	}
+
+	s.offTimeChanged(offTime.ExpertID.String(), events.TypeOffTimeDeleted)
+	return s.offTimeRepo.GetByID(offTime.ID)
+}
 
-	err = s.offTimeRepo.Delete(offTimeID)
+// DeleteOffTimeException restores a skipped occurrence of a recurring off-time
+func (s *expertAvailabilityService) DeleteOffTimeException(id string, date string, userID uuid.UUID, isAdmin bool) error {
+	offTime, err := s.getRecurringOffTime(id, userID, isAdmin)
 	if err != nil {
-		return fmt.Errorf("không thể xóa thời gian nghỉ: %v", err)
+		return err
 	}
+
+	if err := s.offTimeRepo.DeleteException(offTime.ID, date); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrOffTimeExceptionNotFound
+		}
+		return fmt.Errorf("failed to delete exception: %w", err)
+	}
+
+	s.offTimeChanged(offTime.ExpertID.String(), events.TypeOffTimeCreated)
 	return nil
 }
 
-// CreateAvailability creates a new availability slot
-func (s *expertAvailabilityService) CreateAvailability(req *model.CreateAvailabilityRequest) (*model.Availability, error) {
-	expertUUID, err := uuid.Parse(req.ExpertID)
+func (s *expertAvailabilityService) getRecurringOffTime(id string, userID uuid.UUID, isAdmin bool) (*model.OffTime, error) {
+	offTime, err := s.getOwnedOffTime(id, userID, isAdmin)
 	if err != nil {
-		return nil, fmt.Errorf("invalid expert ID format: %v", err)
+		return nil, err
 	}
-	expert, err := s.expertRepo.GetByID(expertUUID)
+	if !offTime.IsRecurring {
+		return nil, ErrOffTimeNotRecurring
+	}
+	return offTime, nil
+}
+
+// getOwnedOffTime loads an off-time that the caller may change
+func (s *expertAvailabilityService) getOwnedOffTime(id string, userID uuid.UUID, isAdmin bool) (*model.OffTime, error) {
+	offTimeID, err := uuid.Parse(id)
 	if err != nil {
-		return nil, fmt.Errorf("không thể kiểm tra chuyên gia: %v", err)
+		return nil, apperr.Validation("invalid_off_time_id")
 	}
-	if expert == nil {
-		return nil, fmt.Errorf("không tìm thấy chuyên gia với ID %s", req.ExpertID)
+	offTime, err := s.offTimeRepo.GetByID(offTimeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get off-time: %w", err)
+	}
+	if offTime == nil {
+		return nil, ErrOffTimeNotFound
+	}
+	if err := s.authorizeExpert(offTime.ExpertID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	return offTime, nil
+}
+
+// offTimeChanged drops cached availability of the expert and tells other
+// services; eventType says whether time was taken away or given back
+func (s *expertAvailabilityService) offTimeChanged(expertID string, eventType string) {
+	s.cache.InvalidateExpert(expertID)
+	s.publisher.Publish(eventType, expertID)
+}
+
+// DeleteOffTime xóa thời gian nghỉ
+func (s *expertAvailabilityService) DeleteOffTime(id string, userID uuid.UUID, isAdmin bool) error {
+	offTime, err := s.getOwnedOffTime(id, userID, isAdmin)
+	if err != nil {
+		return err
+	}
+
+	err = s.offTimeRepo.Delete(offTime.ID)
+	if err == sql.ErrNoRows {
+		return ErrOffTimeNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete off-time: %w", err)
+	}
+
+	s.offTimeChanged(offTime.ExpertID.String(), events.TypeOffTimeDeleted)
+	return nil
+}
+
+// CreateAvailability creates a new availability slot
+func (s *expertAvailabilityService) CreateAvailability(req *model.CreateAvailabilityRequest) (*model.Availability, error) {
+	expertUUID, err := s.requireExpert(req.ExpertID)
+	if err != nil {
+		return nil, err
 	}
 
 	availability := &model.Availability{
-		ID:        uuid.New(),
-		ExpertID:  req.ExpertID,
+		ExpertID:  expertUUID.String(),
 		Date:      req.Date,
 		StartTime: req.StartTime,
 		EndTime:   req.EndTime,
-		IsBooked:  false,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
 	}
-
-	// Lưu vào Redis với key pattern: availability:{expert_id}:{date}
-	key := fmt.Sprintf("availability:%s:%s", req.ExpertID, req.Date)
-	data, err := json.Marshal(availability)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal availability: %w", err)
+	if err := s.validateAvailability(availability); err != nil {
+		return nil, err
 	}
 
-	if err := s.cache.SetAvailability(key, data); err != nil {
-		return nil, fmt.Errorf("failed to cache availability: %w", err)
+	if err := s.availabilityRepo.Create(availability); err != nil {
+		return nil, fmt.Errorf("failed to create availability: %w", err)
 	}
 
+	s.availabilityChanged(availability.ExpertID, availability.Date)
 	return availability, nil
 }
 
-// GetAvailabilityByID retrieves an availability slot by ID
+// GetAvailabilityByID retrieves an availability slot, or an occurrence of a
+// rule, by ID; it returns nil when not found
 func (s *expertAvailabilityService) GetAvailabilityByID(id string) (*model.Availability, error) {
-	// Tìm trong Redis với pattern: availability:*:*
-	// TODO: Implement search by ID in Redis
-	// For now, return error as this might need a different Redis structure
-	return nil, fmt.Errorf("get availability by ID not implemented with Redis")
+	if ruleID, date, ok := model.ParseOccurrenceID(id); ok {
+		return s.getOccurrence(ruleID, date)
+	}
+	availabilityID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, apperr.Validation("invalid_availability_id")
+	}
+	availability, err := s.availabilityRepo.GetByID(availabilityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get availability: %w", err)
+	}
+	return availability, nil
 }
 
-// UpdateAvailability updates an existing availability slot
+// UpdateAvailability updates an existing availability slot; it returns nil when not found
 func (s *expertAvailabilityService) UpdateAvailability(id string, req *model.UpdateAvailabilityRequest) (*model.Availability, error) {
-	// TODO: Implement update in Redis
-	// This might need to fetch all availabilities for the expert and date,
-	// update the specific one, and save back
-	return nil, fmt.Errorf("update availability not implemented with Redis")
+	availability, err := s.GetAvailabilityByID(id)
+	if err != nil || availability == nil {
+		return nil, err
+	}
+	if availability.RuleID != nil {
+		return nil, ErrAvailabilityRecurring
+	}
+	previousDate := availability.Date
+
+	if req.Date != nil {
+		availability.Date = *req.Date
+	}
+	if req.StartTime != nil {
+		availability.StartTime = *req.StartTime
+	}
+	if req.EndTime != nil {
+		availability.EndTime = *req.EndTime
+	}
+	if req.IsBooked != nil {
+		availability.IsBooked = *req.IsBooked
+	}
+	if err := s.validateAvailability(availability); err != nil {
+		return nil, err
+	}
+
+	if err := s.availabilityRepo.Update(availability); err != nil {
+		return nil, fmt.Errorf("failed to update availability: %w", err)
+	}
+
+	s.availabilityChanged(availability.ExpertID, previousDate, availability.Date)
+	return availability, nil
 }
 
 // DeleteAvailability deletes an availability slot
 func (s *expertAvailabilityService) DeleteAvailability(id string) error {
-	// TODO: Implement delete in Redis
-	return fmt.Errorf("delete availability not implemented with Redis")
+	availability, err := s.GetAvailabilityByID(id)
+	if err != nil {
+		return err
+	}
+	if availability == nil {
+		return ErrAvailabilityNotFound
+	}
+	if availability.RuleID != nil {
+		return ErrAvailabilityRecurring
+	}
+
+	if err := s.availabilityRepo.Delete(availability.ID); err != nil {
+		return fmt.Errorf("failed to delete availability: %w", err)
+	}
+
+	s.availabilityChanged(availability.ExpertID, availability.Date)
+	return nil
 }
 
-// GetAvailabilities retrieves filtered availability slots
+// GetAvailabilities retrieves filtered availability slots, one-off slots and
+// occurrences of recurring rules alike. Each day is read from the cache first;
+// days that miss are loaded from Postgres at once and cached, including days
+// without any slot.
 func (s *expertAvailabilityService) GetAvailabilities(expertID string, startDate, endDate time.Time, isBooked *bool) ([]*model.Availability, error) {
-	var availabilities []*model.Availability
+	expertUUID, err := uuid.Parse(expertID)
+	if err != nil {
+		return nil, apperr.Validation("invalid_expert_id")
+	}
 
-	// Lặp qua từng ngày trong khoảng thời gian
+	byDay := make(map[string][]*model.Availability)
+	var missing []string
 	for d := startDate; !d.After(endDate); d = d.AddDate(0, 0, 1) {
-		key := fmt.Sprintf("availability:%s:%s", expertID, d.Format("2006-01-02"))
-		data, err := s.cache.GetAvailability(key)
-		if err != nil || data == nil {
-			continue // Skip if error or not found, try next date
+		date := d.Format("2006-01-02")
+		data, err := s.cache.GetAvailability(cache.AvailabilityDayKey(expertID, date))
+		if err == nil && data != nil {
+			var day []*model.Availability
+			if err := json.Unmarshal(data, &day); err == nil {
+				byDay[date] = day
+				continue
+			}
 		}
+		missing = append(missing, date)
+	}
 
-		var availability model.Availability
-		if err := json.Unmarshal(data, &availability); err != nil {
-			continue // Skip if error, try next date
+	if len(missing) > 0 {
+		first, _ := time.Parse("2006-01-02", missing[0])
+		last, _ := time.Parse("2006-01-02", missing[len(missing)-1])
+		loaded, err := s.availabilityRepo.GetByExpertIDAndDateRange(expertUUID, first, last)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get availability: %w", err)
 		}
-
-		if isBooked == nil || availability.IsBooked == *isBooked {
-			availabilities = append(availabilities, &availability)
+		loadedByDay, err := s.occurrences(expertUUID, first, last)
+		if err != nil {
+			return nil, err
+		}
+		for _, availability := range loaded {
+			loadedByDay[availability.Date] = append(loadedByDay[availability.Date], availability)
+		}
+		for _, date := range missing {
+			day := loadedByDay[date]
+			if day == nil {
+				day = []*model.Availability{}
+			}
+			byDay[date] = day
+			// A failed cache write only costs a database read next time
+			if data, err := json.Marshal(day); err == nil {
+				s.cache.SetAvailability(cache.AvailabilityDayKey(expertID, date), data)
+			}
 		}
 	}
 
+	availabilities := []*model.Availability{}
+	for d := startDate; !d.After(endDate); d = d.AddDate(0, 0, 1) {
+		for _, availability := range byDay[d.Format("2006-01-02")] {
+			if isBooked == nil || availability.IsBooked == *isBooked {
+				availabilities = append(availabilities, availability)
+			}
+		}
+	}
 	return availabilities, nil
 }
 
-// BookAvailability books an availability slot
+// BookAvailability books an availability slot or an occurrence of a rule. The
+// repositories book them atomically, so only one of several concurrent
+// requests succeeds.
 func (s *expertAvailabilityService) BookAvailability(id string) error {
-	// TODO: Implement booking in Redis
-	// This might need to fetch all availabilities for the expert and date,
-	// update the specific one's IsBooked status, and save back
-	return fmt.Errorf("book availability not implemented with Redis")
+	availability, err := s.GetAvailabilityByID(id)
+	if err != nil {
+		return err
+	}
+	if availability == nil {
+		return ErrAvailabilityNotFound
+	}
+
+	var booked bool
+	if availability.RuleID != nil {
+		booked, err = s.ruleRepo.BookOccurrence(*availability.RuleID, availability.Date)
+	} else {
+		booked, err = s.availabilityRepo.Book(availability.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to book availability: %w", err)
+	}
+	if !booked {
+		return ErrAvailabilityAlreadyBooked
+	}
+
+	s.availabilityChanged(availability.ExpertID, availability.Date)
+	return nil
 }
 
-// CreateRecurringAvailability creates multiple availability slots for recurring schedules
-func (s *expertAvailabilityService) CreateRecurringAvailability(req *model.CreateRecurringAvailabilityRequest) ([]*model.Availability, error) {
+// CreateRecurringAvailability stores a weekly availability rule; its
+// occurrences are expanded when availability is read. The rule may not
+// overlap other rules or one-off slots of the expert.
+func (s *expertAvailabilityService) CreateRecurringAvailability(req *model.CreateRecurringAvailabilityRequest, userID uuid.UUID, isAdmin bool) (*model.AvailabilityRule, error) {
 	expertUUID, err := uuid.Parse(req.ExpertID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid expert ID format: %v", err)
+		return nil, apperr.Validation("invalid_expert_id")
+	}
+	if err := s.authorizeExpert(expertUUID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+
+	rule := &model.AvailabilityRule{
+		ExpertID:      expertUUID,
+		DaysOfWeek:    toInt64Array(req.DaysOfWeek),
+		StartTime:     req.StartTime,
+		EndTime:       req.EndTime,
+		EffectiveFrom: req.StartDate,
+		Exceptions:    []model.AvailabilityRuleException{},
+	}
+	if req.EndDate != "" {
+		rule.EffectiveTo = &req.EndDate
+	}
+	if err := validateAvailabilityRule(rule); err != nil {
+		return nil, err
+	}
+	if err := s.checkRuleOverlap(rule, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	if err := s.ruleRepo.Create(rule); err != nil {
+		return nil, fmt.Errorf("failed to create availability rule: %w", err)
+	}
+
+	s.rulesChanged(rule.ExpertID.String())
+	return rule, nil
+}
+
+func (s *expertAvailabilityService) GetAvailabilityRules(expertID string) ([]*model.AvailabilityRule, error) {
+	expertUUID, err := uuid.Parse(expertID)
+	if err != nil {
+		return nil, apperr.Validation("invalid_expert_id")
+	}
+	rules, err := s.ruleRepo.GetByExpertID(expertUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get availability rules: %w", err)
+	}
+	if rules == nil {
+		rules = []*model.AvailabilityRule{}
+	}
+	return rules, nil
+}
+
+func (s *expertAvailabilityService) GetAvailabilityRule(id string) (*model.AvailabilityRule, error) {
+	ruleID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, apperr.Validation("invalid_rule_id")
+	}
+	rule, err := s.ruleRepo.GetByID(ruleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get availability rules: %w", err)
+	}
+	if rule == nil {
+		return nil, ErrAvailabilityRuleNotFound
+	}
+	return rule, nil
+}
+
+// UpdateAvailabilityRule changes a rule. When req.FromDate falls after the
+// rule's first day, the rule is split: the current rule ends the day before
+// FromDate and a new rule with the changes starts on FromDate. It returns the
+// rule in effect from FromDate on.
+func (s *expertAvailabilityService) UpdateAvailabilityRule(id string, req *model.UpdateAvailabilityRuleRequest, userID uuid.UUID, isAdmin bool) (*model.AvailabilityRule, error) {
+	rule, err := s.getOwnedRule(id, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.FromDate == "" || req.FromDate <= rule.EffectiveFrom {
+		applyAvailabilityRuleChanges(rule, req)
+		if err := validateAvailabilityRule(rule); err != nil {
+			return nil, err
+		}
+		if err := s.checkRuleOverlap(rule, uuid.Nil); err != nil {
+			return nil, err
+		}
+		if err := s.ruleRepo.Update(rule); err != nil {
+			return nil, fmt.Errorf("failed to update availability rule: %w", err)
+		}
+		s.rulesChanged(rule.ExpertID.String())
+		return rule, nil
+	}
+
+	fromDate, err := time.Parse("2006-01-02", req.FromDate)
+	if err != nil {
+		return nil, apperr.Validation("invalid_effective_date")
+	}
+	if rule.EffectiveTo != nil && req.FromDate > *rule.EffectiveTo {
+		return nil, apperr.Validation("from_date_after_rule_end")
+	}
+
+	next := *rule
+	next.DaysOfWeek = append(pq.Int64Array{}, rule.DaysOfWeek...)
+	next.EffectiveFrom = req.FromDate
+	applyAvailabilityRuleChanges(&next, req)
+	if err := validateAvailabilityRule(&next); err != nil {
+		return nil, err
+	}
+	if err := s.checkRuleOverlap(&next, rule.ID); err != nil {
+		return nil, err
+	}
+	previousDay := fromDate.AddDate(0, 0, -1).Format("2006-01-02")
+	rule.EffectiveTo = &previousDay
+
+	if err := s.ruleRepo.Split(rule, &next); err != nil {
+		return nil, fmt.Errorf("failed to update availability rule: %w", err)
+	}
+
+	s.rulesChanged(rule.ExpertID.String())
+	return s.GetAvailabilityRule(next.ID.String())
+}
+
+// DeleteAvailabilityRule removes a rule entirely, or only from fromDate on
+// when fromDate falls after the rule's first day
+func (s *expertAvailabilityService) DeleteAvailabilityRule(id string, fromDate string, userID uuid.UUID, isAdmin bool) error {
+	rule, err := s.getOwnedRule(id, userID, isAdmin)
+	if err != nil {
+		return err
+	}
+
+	if fromDate == "" || fromDate <= rule.EffectiveFrom {
+		if err := s.ruleRepo.Delete(rule.ID); err != nil {
+			return fmt.Errorf("failed to delete availability rule: %w", err)
+		}
+		s.rulesChanged(rule.ExpertID.String())
+		return nil
+	}
+
+	from, err := time.Parse("2006-01-02", fromDate)
+	if err != nil {
+		return apperr.Validation("invalid_effective_date")
+	}
+	if rule.EffectiveTo != nil && fromDate > *rule.EffectiveTo {
+		return nil // the rule has already ended
+	}
+	previousDay := from.AddDate(0, 0, -1).Format("2006-01-02")
+	rule.EffectiveTo = &previousDay
+	if err := s.ruleRepo.Update(rule); err != nil {
+		return fmt.Errorf("failed to delete availability rule: %w", err)
+	}
+
+	s.rulesChanged(rule.ExpertID.String())
+	return nil
+}
+
+// AddAvailabilityRuleException skips one occurrence of a rule
+func (s *expertAvailabilityService) AddAvailabilityRuleException(id string, req *model.CreateAvailabilityRuleExceptionRequest, userID uuid.UUID, isAdmin bool) (*model.AvailabilityRule, error) {
+	rule, err := s.getOwnedRule(id, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := time.Parse("2006-01-02", req.Date); err != nil {
+		return nil, apperr.Validation("invalid_date")
+	}
+
+	exception := &model.AvailabilityRuleException{Date: req.Date, Reason: req.Reason}
+	if err := s.ruleRepo.AddException(rule.ID, exception); err != nil {
+		return nil, fmt.Errorf("failed to add exception: %w", err)
+	}
+
+	s.rulesChanged(rule.ExpertID.String())
+	return s.GetAvailabilityRule(id)
+}
+
+// DeleteAvailabilityRuleException restores a skipped occurrence
+func (s *expertAvailabilityService) DeleteAvailabilityRuleException(id string, date string, userID uuid.UUID, isAdmin bool) error {
+	rule, err := s.getOwnedRule(id, userID, isAdmin)
+	if err != nil {
+		return err
+	}
+
+	if err := s.ruleRepo.DeleteException(rule.ID, date); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrRuleExceptionNotFound
+		}
+		return fmt.Errorf("failed to delete exception: %w", err)
+	}
+
+	s.rulesChanged(rule.ExpertID.String())
+	return nil
+}
+
+// getOwnedRule loads a rule that the caller may change
+func (s *expertAvailabilityService) getOwnedRule(id string, userID uuid.UUID, isAdmin bool) (*model.AvailabilityRule, error) {
+	rule, err := s.GetAvailabilityRule(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeExpert(rule.ExpertID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// authorizeExpert lets admins and the account owning the expert change the
+// expert's calendar, as middleware.RequireExpertOwner does for routes that
+// carry the expert ID
+func (s *expertAvailabilityService) authorizeExpert(expertID, userID uuid.UUID, isAdmin bool) error {
+	expert, err := s.expertRepo.GetByID(expertID)
+	if err != nil {
+		return fmt.Errorf("failed to get expert: %w", err)
+	}
+	if expert == nil {
+		return apperr.NotFound("expert_not_found")
+	}
+	if !isAdmin && expert.UserID != userID {
+		return ErrExpertCalendarForbidden
+	}
+	return nil
+}
+
+func (s *expertAvailabilityService) requireExpert(expertID string) (uuid.UUID, error) {
+	expertUUID, err := uuid.Parse(expertID)
+	if err != nil {
+		return uuid.Nil, apperr.Validation("invalid_expert_id")
 	}
 	expert, err := s.expertRepo.GetByID(expertUUID)
 	if err != nil {
-		return nil, fmt.Errorf("không thể kiểm tra chuyên gia: %v", err)
+		return uuid.Nil, fmt.Errorf("failed to get expert: %w", err)
 	}
 	if expert == nil {
-		return nil, fmt.Errorf("không tìm thấy chuyên gia với ID %s", req.ExpertID)
+		return uuid.Nil, apperr.NotFound("expert_not_found")
 	}
+	return expertUUID, nil
+}
 
-	startDate, err := time.Parse("2006-01-02", req.StartDate)
+// validateAvailability checks the date and time formats and that the slot
+// does not overlap another slot or a rule occurrence of the same expert
+func (s *expertAvailabilityService) validateAvailability(availability *model.Availability) error {
+	if _, err := time.Parse("2006-01-02", availability.Date); err != nil {
+		return apperr.Validation("invalid_date")
+	}
+	start, err := parseClock(availability.StartTime)
 	if err != nil {
-		return nil, fmt.Errorf("định dạng ngày bắt đầu không hợp lệ")
+		return apperr.Validation("invalid_start_time")
+	}
+	end, err := parseClock(availability.EndTime)
+	if err != nil {
+		return apperr.Validation("invalid_end_time")
+	}
+	if !end.After(start) {
+		return apperr.Validation("end_before_start")
 	}
 
-	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	overlap, err := s.availabilityRepo.HasOverlap(availability)
 	if err != nil {
-		return nil, fmt.Errorf("định dạng ngày kết thúc không hợp lệ")
+		return fmt.Errorf("failed to check availability overlap: %w", err)
+	}
+	if overlap {
+		return ErrAvailabilityOverlap
 	}
 
-	if endDate.Before(startDate) {
-		return nil, fmt.Errorf("ngày kết thúc phải sau ngày bắt đầu")
+	expertUUID, err := uuid.Parse(availability.ExpertID)
+	if err != nil {
+		return apperr.Validation("invalid_expert_id")
+	}
+	date, _ := time.Parse("2006-01-02", availability.Date)
+	rules, err := s.ruleRepo.GetActiveByExpertIDAndRange(expertUUID, date, date)
+	if err != nil {
+		return fmt.Errorf("failed to get availability rules: %w", err)
+	}
+	for _, occurrence := range expandRules(rules, nil, date, date)[availability.Date] {
+		if clocksOverlap(availability.StartTime, availability.EndTime, occurrence.StartTime, occurrence.EndTime) {
+			return ErrAvailabilityOverlap
+		}
 	}
+	return nil
+}
 
-	var createdAvailabilities []*model.Availability
-	for d := startDate; !d.After(endDate); d = d.AddDate(0, 0, 1) {
-		weekday := int(d.Weekday())
-		for _, w := range req.DaysOfWeek {
-			if weekday == w {
-				availability := &model.Availability{
-					ID:        uuid.New(),
-					ExpertID:  req.ExpertID,
-					Date:      d.Format("2006-01-02"),
-					StartTime: req.StartTime,
-					EndTime:   req.EndTime,
-					IsBooked:  false,
-					CreatedAt: time.Now(),
-					UpdatedAt: time.Now(),
-				}
+// checkRuleOverlap rejects a rule whose occurrences would intersect those of
+// another rule of the expert, except the rule ignore, or a one-off slot
+func (s *expertAvailabilityService) checkRuleOverlap(rule *model.AvailabilityRule, ignore uuid.UUID) error {
+	others, err := s.ruleRepo.GetByExpertID(rule.ExpertID)
+	if err != nil {
+		return fmt.Errorf("failed to get availability rules: %w", err)
+	}
+	for _, other := range others {
+		if other.ID == rule.ID || other.ID == ignore {
+			continue
+		}
+		if rulesOverlap(rule, other) {
+			return ErrAvailabilityOverlap
+		}
+	}
 
-				// Lưu vào Redis
-				key := fmt.Sprintf("availability:%s:%s", req.ExpertID, d.Format("2006-01-02"))
-				data, err := json.Marshal(availability)
-				if err != nil {
-					return nil, fmt.Errorf("failed to marshal availability: %w", err)
-				}
+	overlap, err := s.availabilityRepo.HasRuleOverlap(rule)
+	if err != nil {
+		return fmt.Errorf("failed to check availability overlap: %w", err)
+	}
+	if overlap {
+		return ErrAvailabilityOverlap
+	}
+	return nil
+}
 
-				if err := s.cache.SetAvailability(key, data); err != nil {
-					return nil, fmt.Errorf("failed to cache availability: %w", err)
+// occurrences expands the expert's rules over [startDate, endDate] with their
+// booked state, keyed by date
+func (s *expertAvailabilityService) occurrences(expertID uuid.UUID, startDate, endDate time.Time) (map[string][]*model.Availability, error) {
+	rules, err := s.ruleRepo.GetActiveByExpertIDAndRange(expertID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get availability rules: %w", err)
+	}
+	ruleIDs := make([]uuid.UUID, len(rules))
+	for i, rule := range rules {
+		ruleIDs[i] = rule.ID
+	}
+	booked, err := s.ruleRepo.GetBookedDates(ruleIDs, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booked occurrences: %w", err)
+	}
+	return expandRules(rules, booked, startDate, endDate), nil
+}
+
+// getOccurrence returns the occurrence of a rule on date; it returns nil when
+// the rule does not exist or does not apply that day
+func (s *expertAvailabilityService) getOccurrence(ruleID uuid.UUID, date string) (*model.Availability, error) {
+	rule, err := s.ruleRepo.GetByID(ruleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get availability rule: %w", err)
+	}
+	if rule == nil {
+		return nil, nil
+	}
+	day, _ := time.Parse("2006-01-02", date)
+	booked, err := s.ruleRepo.GetBookedDates([]uuid.UUID{rule.ID}, day, day)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booked occurrences: %w", err)
+	}
+	occurrences := expandRules([]*model.AvailabilityRule{rule}, booked, day, day)[date]
+	if len(occurrences) == 0 {
+		return nil, nil
+	}
+	return occurrences[0], nil
+}
+
+// parseClock accepts "HH:MM" and "HH:MM:SS"
+func parseClock(value string) (time.Time, error) {
+	if t, err := time.Parse("15:04", value); err == nil {
+		return t, nil
+	}
+	return time.Parse("15:04:05", value)
+}
+
+// availabilityChanged drops the cached days and notifies the slot cache
+func (s *expertAvailabilityService) availabilityChanged(expertID string, dates ...string) {
+	s.cache.InvalidateAvailabilityDays(expertID, dates...)
+	s.publisher.Publish(events.TypeAvailabilityChanged, expertID)
+}
+
+// rulesChanged drops every cached availability day of the expert, since a
+// rule can affect any date, and notifies the slot cache
+func (s *expertAvailabilityService) rulesChanged(expertID string) {
+	s.cache.InvalidateExpert(expertID)
+	s.publisher.Publish(events.TypeAvailabilityChanged, expertID)
+}
+
+func applyAvailabilityRuleChanges(rule *model.AvailabilityRule, req *model.UpdateAvailabilityRuleRequest) {
+	if req.DaysOfWeek != nil {
+		rule.DaysOfWeek = toInt64Array(req.DaysOfWeek)
+	}
+	if req.StartTime != nil {
+		rule.StartTime = *req.StartTime
+	}
+	if req.EndTime != nil {
+		rule.EndTime = *req.EndTime
+	}
+	if req.EndDate != nil {
+		if *req.EndDate == "" {
+			rule.EffectiveTo = nil
+		} else {
+			endDate := *req.EndDate
+			rule.EffectiveTo = &endDate
+		}
+	}
+}
+
+func validateAvailabilityRule(rule *model.AvailabilityRule) error {
+	if len(rule.DaysOfWeek) == 0 {
+		return apperr.Validation("days_of_week_required")
+	}
+	for _, day := range rule.DaysOfWeek {
+		if day < 0 || day > 6 {
+			return apperr.Validation("invalid_days_of_week")
+		}
+	}
+	start, err := parseClock(rule.StartTime)
+	if err != nil {
+		return apperr.Validation("invalid_start_time")
+	}
+	end, err := parseClock(rule.EndTime)
+	if err != nil {
+		return apperr.Validation("invalid_end_time")
+	}
+	if !end.After(start) {
+		return apperr.Validation("end_before_start")
+	}
+	if _, err := time.Parse("2006-01-02", rule.EffectiveFrom); err != nil {
+		return apperr.Validation("invalid_start_date")
+	}
+	if rule.EffectiveTo != nil {
+		if _, err := time.Parse("2006-01-02", *rule.EffectiveTo); err != nil {
+			return apperr.Validation("invalid_end_date")
+		}
+		if *rule.EffectiveTo < rule.EffectiveFrom {
+			return apperr.Validation("end_date_before_start")
+		}
+	}
+	return nil
+}
+
+// expandRules lists the occurrences of rules on each day of [startDate, endDate],
+// keyed by date. booked holds the booked dates of each rule and may be nil.
+func expandRules(rules []*model.AvailabilityRule, booked map[uuid.UUID]map[string]bool, startDate, endDate time.Time) map[string][]*model.Availability {
+	occurrences := make(map[string][]*model.Availability)
+	for _, rule := range rules {
+		skipped := make(map[string]bool, len(rule.Exceptions))
+		for _, exception := range rule.Exceptions {
+			skipped[exception.Date] = true
+		}
+		for d := startDate; !d.After(endDate); d = d.AddDate(0, 0, 1) {
+			date := d.Format("2006-01-02")
+			if date < rule.EffectiveFrom || (rule.EffectiveTo != nil && date > *rule.EffectiveTo) || skipped[date] {
+				continue
+			}
+			for _, day := range rule.DaysOfWeek {
+				if int(day) == int(d.Weekday()) {
+					ruleID := rule.ID
+					occurrences[date] = append(occurrences[date], &model.Availability{
+						ID:        model.OccurrenceID(rule.ID, date),
+						RuleID:    &ruleID,
+						ExpertID:  rule.ExpertID.String(),
+						Date:      date,
+						StartTime: rule.StartTime,
+						EndTime:   rule.EndTime,
+						IsBooked:  booked[rule.ID][date],
+						CreatedAt: rule.CreatedAt,
+						UpdatedAt: rule.UpdatedAt,
+					})
+					break
 				}
+			}
+		}
+	}
+	return occurrences
+}
+
+// rulesOverlap reports whether a and b have an occurrence on the same day at
+// intersecting times, leaving out the exception dates of both
+func rulesOverlap(a, b *model.AvailabilityRule) bool {
+	if !clocksOverlap(a.StartTime, a.EndTime, b.StartTime, b.EndTime) {
+		return false
+	}
+	var commonDays [7]bool
+	common := false
+	for _, day := range a.DaysOfWeek {
+		for _, other := range b.DaysOfWeek {
+			if day == other && day >= 0 && day <= 6 {
+				commonDays[day], common = true, true
+			}
+		}
+	}
+	if !common {
+		return false
+	}
+
+	from, _ := time.Parse("2006-01-02", a.EffectiveFrom)
+	if b.EffectiveFrom > a.EffectiveFrom {
+		from, _ = time.Parse("2006-01-02", b.EffectiveFrom)
+	}
+	var to *time.Time
+	for _, end := range []*string{a.EffectiveTo, b.EffectiveTo} {
+		if end == nil {
+			continue
+		}
+		if t, err := time.Parse("2006-01-02", *end); err == nil && (to == nil || t.Before(*to)) {
+			to = &t
+		}
+	}
+	skipped := make(map[string]bool, len(a.Exceptions)+len(b.Exceptions))
+	for _, exception := range append(append([]model.AvailabilityRuleException{}, a.Exceptions...), b.Exceptions...) {
+		skipped[exception.Date] = true
+	}
+	// Every week has a common day, so past the exceptions one must be free
+	limit := from.AddDate(0, 0, 7*(len(skipped)+1))
+	for d := from; d.Before(limit) && (to == nil || !d.After(*to)); d = d.AddDate(0, 0, 1) {
+		if commonDays[d.Weekday()] && !skipped[d.Format("2006-01-02")] {
+			return true
+		}
+	}
+	return false
+}
+
+// clocksOverlap reports whether the times [startA, endA) and [startB, endB)
+// intersect
+func clocksOverlap(startA, endA, startB, endB string) bool {
+	sa, _ := parseClock(startA)
+	ea, _ := parseClock(endA)
+	sb, _ := parseClock(startB)
+	eb, _ := parseClock(endB)
+	return sa.Before(eb) && sb.Before(ea)
+}
+
+func toInt64Array(values []int) pq.Int64Array {
+	result := make(pq.Int64Array, len(values))
+	for i, value := range values {
+		result[i] = int64(value)
+	}
+	return result
+}
 
-				createdAvailabilities = append(createdAvailabilities, availability)
+// applyOffTimeRecurrence copies and validates the recurrence of a new off-time
+func applyOffTimeRecurrence(offTime *model.OffTime, req *model.CreateOffTimeRequest) error {
+	hasRecurrence := req.RecurrenceFrequency != "" || req.RecurrenceInterval != 0 ||
+		len(req.RecurrenceDaysOfWeek) > 0 || req.RecurrenceUntil != ""
+	if !req.IsRecurring {
+		if hasRecurrence {
+			return apperr.Validation("recurrence_requires_recurring")
+		}
+		return nil
+	}
+
+	offTime.RecurrenceFrequency = req.RecurrenceFrequency
+	if offTime.RecurrenceFrequency == "" {
+		offTime.RecurrenceFrequency = model.RecurrenceWeekly
+	}
+	offTime.RecurrenceInterval = req.RecurrenceInterval
+	if offTime.RecurrenceInterval == 0 {
+		offTime.RecurrenceInterval = 1
+	}
+	if len(req.RecurrenceDaysOfWeek) > 0 {
+		if offTime.RecurrenceFrequency != model.RecurrenceWeekly {
+			return apperr.Validation("recurrence_days_weekly_only")
+		}
+		for _, day := range req.RecurrenceDaysOfWeek {
+			if day < 0 || day > 6 {
+				return apperr.Validation("invalid_recurrence_days_of_week")
 			}
 		}
+		offTime.RecurrenceDaysOfWeek = toInt64Array(req.RecurrenceDaysOfWeek)
 	}
-	return createdAvailabilities, nil
+	if req.RecurrenceUntil != "" {
+		if _, err := time.Parse("2006-01-02", req.RecurrenceUntil); err != nil {
+			return apperr.Validation("invalid_recurrence_end")
+		}
+		if req.RecurrenceUntil < offTime.StartDateTime.Format("2006-01-02") {
+			return apperr.Validation("recurrence_end_before_start")
+		}
+		until := req.RecurrenceUntil
+		offTime.RecurrenceUntil = &until
+	}
+	return nil
 }
@@ -0,0 +1,398 @@
+package service
+
+import (
+	"errors"
+	"expert-service/internal/model"
+	"expert-service/internal/repository"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"booking-system/shared/pkg/apperr"
+	"booking-system/shared/pkg/notify"
+	"booking-system/shared/pkg/storage"
+
+	"github.com/google/uuid"
+)
+
+// MaxCertificationFileSize is the largest proof file an expert may upload
+const MaxCertificationFileSize = 10 << 20
+
+const (
+	defaultCertificationPageSize = 20
+	maxCertificationPageSize     = 100
+)
+
+// certificationFileTypes maps the accepted proof file types to the extension
+// they are stored with
+var certificationFileTypes = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+var (
+	ErrCertificationNotFound     = apperr.NotFound("certification_not_found")
+	ErrCertificationFileNotFound = apperr.NotFound("certification_file_not_found")
+	ErrCertificationForbidden    = apperr.Forbidden("certification_forbidden")
+	ErrExpertProfileNotFound     = apperr.NotFound("expert_profile_not_found")
+	ErrInvalidCertificationDates = apperr.Validation("invalid_certification_dates")
+	ErrUnsupportedFileType       = apperr.UnsupportedMedia("unsupported_file_type")
+	ErrFileTooLarge              = apperr.TooLarge("file_too_large", MaxCertificationFileSize>>20)
+)
+
+// CertificationService manages experts' certifications. Experts add and edit
+// their certifications and upload proof; an admin verifies them. Only
+// verified, unexpired certifications are public.
+type CertificationService interface {
+	GetPublicCertifications(expertID uuid.UUID) ([]*model.ExpertCertification, error)
+	GetMyCertifications(userID uuid.UUID) ([]*model.ExpertCertification, error)
+	CreateCertification(userID uuid.UUID, req *model.CertificationRequest) (*model.ExpertCertification, error)
+	GetCertification(id, userID uuid.UUID, isAdmin bool) (*model.ExpertCertification, error)
+	UpdateCertification(id, userID uuid.UUID, req *model.CertificationRequest) (*model.ExpertCertification, error)
+	DeleteCertification(id, userID uuid.UUID, isAdmin bool) error
+
+	AddFile(id, userID uuid.UUID, fileName string, data []byte) (*model.CertificationFile, error)
+	OpenFile(id, fileID, userID uuid.UUID, isAdmin bool) (*model.CertificationFile, io.ReadCloser, error)
+	DeleteFile(id, fileID, userID uuid.UUID) error
+
+	GetCertificationsByStatus(req *model.GetCertificationsRequest) (*model.CertificationListResponse, error)
+	VerifyCertification(id, reviewerID uuid.UUID, req *model.VerifyCertificationRequest) (*model.ExpertCertification, error)
+
+	CheckExpiries() error
+}
+
+type certificationService struct {
+	certificationRepo repository.CertificationRepository
+	expertRepo        repository.ExpertRepository
+	store             storage.FileStore
+	notifier          *notify.Notifier
+	warningPeriod     time.Duration
+}
+
+// NewCertificationService creates a certification service. Experts are
+// warned warningPeriod before one of their certifications expires.
+func NewCertificationService(
+	certificationRepo repository.CertificationRepository,
+	expertRepo repository.ExpertRepository,
+	store storage.FileStore,
+	notifier *notify.Notifier,
+	warningPeriod time.Duration,
+) CertificationService {
+	return &certificationService{
+		certificationRepo: certificationRepo,
+		expertRepo:        expertRepo,
+		store:             store,
+		notifier:          notifier,
+		warningPeriod:     warningPeriod,
+	}
+}
+
+func (s *certificationService) GetPublicCertifications(expertID uuid.UUID) ([]*model.ExpertCertification, error) {
+	certifications, err := s.certificationRepo.GetByExpertID(expertID, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get certifications: %w", err)
+	}
+	return certifications, nil
+}
+
+func (s *certificationService) GetMyCertifications(userID uuid.UUID) ([]*model.ExpertCertification, error) {
+	expert, err := s.expertFor(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	certifications, err := s.certificationRepo.GetByExpertID(expert.ID, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get certifications: %w", err)
+	}
+	return certifications, nil
+}
+
+func (s *certificationService) CreateCertification(userID uuid.UUID, req *model.CertificationRequest) (*model.ExpertCertification, error) {
+	expert, err := s.expertFor(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateCertificationDates(req); err != nil {
+		return nil, err
+	}
+
+	certification := &model.ExpertCertification{
+		ExpertID:         expert.ID,
+		Name:             req.Name,
+		Issuer:           req.Issuer,
+		CredentialNumber: req.CredentialNumber,
+		IssuedOn:         req.IssuedOn,
+		ExpiresOn:        req.ExpiresOn,
+		Status:           model.CertificationStatusPending,
+		Files:            []*model.CertificationFile{},
+	}
+	if err := s.certificationRepo.Create(certification); err != nil {
+		return nil, fmt.Errorf("failed to create certification: %w", err)
+	}
+	return certification, nil
+}
+
+func (s *certificationService) GetCertification(id, userID uuid.UUID, isAdmin bool) (*model.ExpertCertification, error) {
+	return s.getAuthorized(id, userID, isAdmin)
+}
+
+// UpdateCertification replaces the details of a certification, which sends
+// it back for verification
+func (s *certificationService) UpdateCertification(id, userID uuid.UUID, req *model.CertificationRequest) (*model.ExpertCertification, error) {
+	certification, err := s.getAuthorized(id, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateCertificationDates(req); err != nil {
+		return nil, err
+	}
+
+	certification.Name = req.Name
+	certification.Issuer = req.Issuer
+	certification.CredentialNumber = req.CredentialNumber
+	certification.IssuedOn = req.IssuedOn
+	certification.ExpiresOn = req.ExpiresOn
+	certification.Expired = false
+	certification.Status = model.CertificationStatusPending
+	certification.VerifiedBy = nil
+	certification.VerifiedAt = nil
+	certification.RejectionReason = ""
+	certification.ExpiryWarnedAt = nil
+	if err := s.certificationRepo.Update(certification); err != nil {
+		return nil, fmt.Errorf("failed to update certification: %w", err)
+	}
+	return certification, nil
+}
+
+func (s *certificationService) DeleteCertification(id, userID uuid.UUID, isAdmin bool) error {
+	certification, err := s.getAuthorized(id, userID, isAdmin)
+	if err != nil {
+		return err
+	}
+
+	if err := s.certificationRepo.Delete(certification); err != nil {
+		return fmt.Errorf("failed to delete certification: %w", err)
+	}
+	for _, file := range certification.Files {
+		if err := s.store.Delete(file.StorageKey); err != nil {
+			log.Printf("failed to delete certification file %s: %v", file.StorageKey, err)
+		}
+	}
+	return nil
+}
+
+// AddFile stores a proof file for a certification. The type is detected from
+// the content, not taken from the client.
+func (s *certificationService) AddFile(id, userID uuid.UUID, fileName string, data []byte) (*model.CertificationFile, error) {
+	certification, err := s.getAuthorized(id, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxCertificationFileSize {
+		return nil, ErrFileTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	extension, ok := certificationFileTypes[contentType]
+	if !ok {
+		return nil, ErrUnsupportedFileType
+	}
+
+	fileName = filepath.Base(fileName)
+	if len(fileName) > 255 {
+		fileName = fileName[len(fileName)-255:]
+	}
+	fileID := uuid.New()
+	file := &model.CertificationFile{
+		CertificationID: certification.ID,
+		FileName:        fileName,
+		ContentType:     contentType,
+		SizeBytes:       int64(len(data)),
+		StorageKey:      fmt.Sprintf("certifications/%s/%s%s", certification.ID, fileID, extension),
+	}
+	if err := s.store.Save(file.StorageKey, data); err != nil {
+		return nil, fmt.Errorf("failed to store file: %w", err)
+	}
+	if err := s.certificationRepo.AddFile(file); err != nil {
+		s.store.Delete(file.StorageKey)
+		return nil, fmt.Errorf("failed to add file: %w", err)
+	}
+	return file, nil
+}
+
+// OpenFile returns a proof file and its content; the caller closes it
+func (s *certificationService) OpenFile(id, fileID, userID uuid.UUID, isAdmin bool) (*model.CertificationFile, io.ReadCloser, error) {
+	certification, err := s.getAuthorized(id, userID, isAdmin)
+	if err != nil {
+		return nil, nil, err
+	}
+	file := findCertificationFile(certification, fileID)
+	if file == nil {
+		return nil, nil, ErrCertificationFileNotFound
+	}
+
+	content, err := s.store.Open(file.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, ErrCertificationFileNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	return file, content, nil
+}
+
+func (s *certificationService) DeleteFile(id, fileID, userID uuid.UUID) error {
+	certification, err := s.getAuthorized(id, userID, false)
+	if err != nil {
+		return err
+	}
+	file := findCertificationFile(certification, fileID)
+	if file == nil {
+		return ErrCertificationFileNotFound
+	}
+
+	if err := s.certificationRepo.DeleteFile(file.ID); err != nil {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	if err := s.store.Delete(file.StorageKey); err != nil {
+		log.Printf("failed to delete certification file %s: %v", file.StorageKey, err)
+	}
+	return nil
+}
+
+func (s *certificationService) GetCertificationsByStatus(req *model.GetCertificationsRequest) (*model.CertificationListResponse, error) {
+	status := req.Status
+	if status == "" {
+		status = model.CertificationStatusPending
+	}
+	page, limit := req.Page, req.Limit
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > maxCertificationPageSize {
+		limit = defaultCertificationPageSize
+	}
+
+	certifications, total, err := s.certificationRepo.GetByStatus(status, limit, (page-1)*limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get certifications: %w", err)
+	}
+	return &model.CertificationListResponse{Certifications: certifications, Total: total, Page: page, Limit: limit}, nil
+}
+
+// VerifyCertification records an admin's decision after checking the proof
+func (s *certificationService) VerifyCertification(id, reviewerID uuid.UUID, req *model.VerifyCertificationRequest) (*model.ExpertCertification, error) {
+	certification, err := s.certificationRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get certification: %w", err)
+	}
+	if certification == nil {
+		return nil, ErrCertificationNotFound
+	}
+
+	now := time.Now()
+	certification.Status = req.Status
+	certification.VerifiedBy = &reviewerID
+	certification.VerifiedAt = &now
+	certification.RejectionReason = ""
+	if req.Status == model.CertificationStatusRejected {
+		certification.RejectionReason = req.Reason
+	}
+	if err := s.certificationRepo.UpdateStatus(certification); err != nil {
+		return nil, fmt.Errorf("failed to update certification: %w", err)
+	}
+	return certification, nil
+}
+
+// CheckExpiries is the daily certification job: it warns experts whose
+// verified certifications expire within the warning period, through the
+// channels they chose for it, and removes expired certifications from public
+// profiles
+func (s *certificationService) CheckExpiries() error {
+	until := time.Now().Add(s.warningPeriod).Format("2006-01-02")
+	expiring, err := s.certificationRepo.GetExpiring(until)
+	if err != nil {
+		return fmt.Errorf("failed to get expiring certifications: %w", err)
+	}
+	for _, certification := range expiring {
+		err := s.notifier.Notify(certification.UserID.String(), notify.Message{
+			Event: notify.EventCertificationExpiring,
+			Title: "notification_certification_expiring_title",
+			Body:  "notification_certification_expiring_body",
+			Args:  []interface{}{certification.Name, certification.ExpiresOn},
+		})
+		if err != nil {
+			log.Printf("failed to warn about expiring certification %s: %v", certification.CertificationID, err)
+			continue
+		}
+		if err := s.certificationRepo.MarkExpiryWarned(certification.CertificationID); err != nil {
+			return fmt.Errorf("failed to mark certification warned: %w", err)
+		}
+	}
+
+	updated, err := s.certificationRepo.SyncPublicCertifications()
+	if err != nil {
+		return fmt.Errorf("failed to hide expired certifications: %w", err)
+	}
+	if len(expiring) > 0 || updated > 0 {
+		log.Printf("certification expiry check: warned %d, updated %d expert profiles", len(expiring), updated)
+	}
+	return nil
+}
+
+// expertFor returns the expert profile of a user
+func (s *certificationService) expertFor(userID uuid.UUID) (*model.Expert, error) {
+	expert, err := s.expertRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expert: %w", err)
+	}
+	if expert == nil {
+		return nil, ErrExpertProfileNotFound
+	}
+	return expert, nil
+}
+
+// getAuthorized loads a certification the caller may access: their own, or
+// any when isAdmin is set
+func (s *certificationService) getAuthorized(id, userID uuid.UUID, isAdmin bool) (*model.ExpertCertification, error) {
+	certification, err := s.certificationRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get certification: %w", err)
+	}
+	if certification == nil {
+		return nil, ErrCertificationNotFound
+	}
+	if isAdmin {
+		return certification, nil
+	}
+
+	expert, err := s.expertRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expert: %w", err)
+	}
+	if expert == nil || expert.ID != certification.ExpertID {
+		return nil, ErrCertificationForbidden
+	}
+	return certification, nil
+}
+
+func validateCertificationDates(req *model.CertificationRequest) error {
+	// Dates are validated as YYYY-MM-DD, so they compare as strings
+	if req.IssuedOn != nil && req.ExpiresOn != nil && *req.ExpiresOn < *req.IssuedOn {
+		return ErrInvalidCertificationDates
+	}
+	return nil
+}
+
+func findCertificationFile(certification *model.ExpertCertification, fileID uuid.UUID) *model.CertificationFile {
+	for _, file := range certification.Files {
+		if file.ID == fileID {
+			return file
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"expert-service/internal/model"
+	"expert-service/internal/repository"
+	"fmt"
+
+	"booking-system/shared/pkg/apperr"
+	"github.com/google/uuid"
+)
+
+type ConsultationServiceService interface {
+	CreateService(expertID uuid.UUID, req *model.CreateConsultationServiceRequest) (*model.ConsultationService, error)
+	GetService(expertID, serviceID uuid.UUID) (*model.ConsultationService, error)
+	GetServicesByExpertID(expertID uuid.UUID, includeInactive bool) ([]*model.ConsultationService, error)
+	UpdateService(expertID, serviceID uuid.UUID, req *model.UpdateConsultationServiceRequest) (*model.ConsultationService, error)
+	DeleteService(expertID, serviceID uuid.UUID) error
+}
+
+type consultationServiceService struct {
+	expertRepo  repository.ExpertRepository
+	serviceRepo repository.ConsultationServiceRepository
+}
+
+func NewConsultationServiceService(
+	expertRepo repository.ExpertRepository,
+	serviceRepo repository.ConsultationServiceRepository,
+) ConsultationServiceService {
+	return &consultationServiceService{
+		expertRepo:  expertRepo,
+		serviceRepo: serviceRepo,
+	}
+}
+
+func (s *consultationServiceService) CreateService(expertID uuid.UUID, req *model.CreateConsultationServiceRequest) (*model.ConsultationService, error) {
+	expert, err := s.expertRepo.GetByID(expertID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expert: %w", err)
+	}
+	if expert == nil {
+		return nil, apperr.NotFound("expert_not_found")
+	}
+
+	svc := &model.ConsultationService{
+		ExpertID:        expertID,
+		Name:            req.Name,
+		Description:     req.Description,
+		DurationMinutes: req.DurationMinutes,
+		Price:           req.Price,
+		MeetingType:     req.MeetingType,
+		IsActive:        true,
+	}
+	if err := s.serviceRepo.Create(svc); err != nil {
+		return nil, fmt.Errorf("failed to create service: %w", err)
+	}
+	return svc, nil
+}
+
+// GetService returns a service only if it belongs to the given expert
+func (s *consultationServiceService) GetService(expertID, serviceID uuid.UUID) (*model.ConsultationService, error) {
+	svc, err := s.serviceRepo.GetByID(serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %w", err)
+	}
+	if svc == nil || svc.ExpertID != expertID {
+		return nil, apperr.NotFound("consultation_service_not_found")
+	}
+	return svc, nil
+}
+
+func (s *consultationServiceService) GetServicesByExpertID(expertID uuid.UUID, includeInactive bool) ([]*model.ConsultationService, error) {
+	return s.serviceRepo.GetByExpertID(expertID, !includeInactive)
+}
+
+func (s *consultationServiceService) UpdateService(expertID, serviceID uuid.UUID, req *model.UpdateConsultationServiceRequest) (*model.ConsultationService, error) {
+	svc, err := s.GetService(expertID, serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		svc.Name = *req.Name
+	}
+	if req.Description != nil {
+		svc.Description = *req.Description
+	}
+	if req.DurationMinutes != nil {
+		svc.DurationMinutes = *req.DurationMinutes
+	}
+	if req.Price != nil {
+		svc.Price = *req.Price
+	}
+	if req.MeetingType != nil {
+		svc.MeetingType = *req.MeetingType
+	}
+	if req.IsActive != nil {
+		svc.IsActive = *req.IsActive
+	}
+
+	if err := s.serviceRepo.Update(svc); err != nil {
+		return nil, fmt.Errorf("failed to update service: %w", err)
+	}
+	return svc, nil
+}
+
+func (s *consultationServiceService) DeleteService(expertID, serviceID uuid.UUID) error {
+	if _, err := s.GetService(expertID, serviceID); err != nil {
+		return err
+	}
+	return s.serviceRepo.Deactivate(serviceID)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"booking-system/shared/pkg/apperr"
 	"github.com/google/uuid"
 )
 
@@ -29,13 +30,34 @@ func NewExpertService(expertRepo repository.ExpertRepository) ExpertService {
 	}
 }
 
+// CreateExpert lets an admin turn a user into an expert directly, skipping
+// the onboarding workflow. The user is granted the expert role together with
+// the profile.
 func (s *expertService) CreateExpert(req *model.CreateExpertRequest) (*model.Expert, error) {
-	// Check if expert with user_id already exists
 	userID, err := uuid.Parse(req.UserID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid user ID format: %w", err)
 	}
 
+	role, err := s.expertRepo.GetUserRole(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if role != "expert" {
+		if err := checkExpertEligible(role); err != nil {
+			return nil, err
+		}
+	}
+
+	// Check if expert with user_id already exists
+	existing, err := s.expertRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expert: %w", err)
+	}
+	if existing != nil {
+		return nil, ErrAlreadyExpert
+	}
+
 	expert := &model.Expert{
 		ID:              uuid.New(),
 		UserID:          userID,
@@ -63,7 +85,7 @@ func (s *expertService) GetExpertByID(id uuid.UUID) (*model.Expert, error) {
 		return nil, fmt.Errorf("failed to get expert: %w", err)
 	}
 	if expert == nil {
-		return nil, fmt.Errorf("expert not found")
+		return nil, apperr.NotFound("expert_not_found")
 	}
 	return expert, nil
 }
@@ -89,7 +111,7 @@ func (s *expertService) UpdateExpert(id uuid.UUID, req *model.UpdateExpertReques
 		return fmt.Errorf("failed to get expert: %w", err)
 	}
 	if expert == nil {
-		return fmt.Errorf("expert not found")
+		return apperr.NotFound("expert_not_found")
 	}
 
 	// Update expert fields
@@ -102,9 +124,6 @@ func (s *expertService) UpdateExpert(id uuid.UUID, req *model.UpdateExpertReques
 	if req.HourlyRate != nil {
 		expert.HourlyRate = *req.HourlyRate
 	}
-	if req.Certifications != nil {
-		expert.Certifications = req.Certifications
-	}
 	if req.IsAvailable != nil {
 		expert.IsAvailable = *req.IsAvailable
 	}
@@ -120,7 +139,7 @@ func (s *expertService) DeleteExpert(id uuid.UUID) error {
 		return fmt.Errorf("failed to get expert: %w", err)
 	}
 	if expert == nil {
-		return fmt.Errorf("expert not found")
+		return apperr.NotFound("expert_not_found")
 	}
 
 	return s.expertRepo.Delete(id)
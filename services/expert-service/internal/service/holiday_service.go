@@ -0,0 +1,350 @@
+package service
+
+import (
+	"database/sql"
+	"expert-service/internal/cache"
+	"expert-service/internal/events"
+	"expert-service/internal/holidays"
+	"expert-service/internal/model"
+	"expert-service/internal/repository"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"booking-system/shared/pkg/apperr"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrHolidayCalendarNotFound = apperr.NotFound("holiday_calendar_not_found")
+	ErrHolidayNotFound         = apperr.NotFound("holiday_not_found")
+	ErrHolidayOverrideNotFound = apperr.NotFound("holiday_override_not_found")
+)
+
+type HolidayService interface {
+	SeedBundledCalendars() error
+	GetCalendars() ([]*model.HolidayCalendar, error)
+	GetCalendar(code string, year int) (*model.HolidayCalendar, error)
+	UpdateCalendar(code string, req *model.UpdateHolidayCalendarRequest) (*model.HolidayCalendar, error)
+	AddHoliday(code string, req *model.HolidayRequest) (*model.Holiday, error)
+	DeleteHoliday(code string, date string) error
+	GetExpertSettings(expertID string) (*model.ExpertHolidaySettings, error)
+	SetExpertCalendars(expertID string, req *model.UpdateExpertHolidayCalendarsRequest) (*model.ExpertHolidaySettings, error)
+	AddOverride(expertID string, req *model.CreateHolidayOverrideRequest) (*model.ExpertHolidaySettings, error)
+	DeleteOverride(expertID string, date string) error
+	GetExpertHolidays(expertID string, startDate, endDate time.Time) ([]model.Holiday, error)
+	HolidayOffTimes(expertID uuid.UUID, from, to time.Time) ([]*model.OffTime, error)
+}
+
+type holidayService struct {
+	holidayRepo     repository.HolidayRepository
+	expertRepo      repository.ExpertRepository
+	cache           cache.AvailabilityCache
+	publisher       events.Publisher
+	defaultCalendar string
+}
+
+// NewHolidayService creates the holiday service. Experts who never chose
+// calendars observe defaultCalendar; an empty defaultCalendar means none.
+func NewHolidayService(
+	holidayRepo repository.HolidayRepository,
+	expertRepo repository.ExpertRepository,
+	cache cache.AvailabilityCache,
+	publisher events.Publisher,
+	defaultCalendar string,
+) HolidayService {
+	return &holidayService{
+		holidayRepo:     holidayRepo,
+		expertRepo:      expertRepo,
+		cache:           cache,
+		publisher:       publisher,
+		defaultCalendar: strings.ToUpper(defaultCalendar),
+	}
+}
+
+// SeedBundledCalendars stores the bundled calendars that are not in the database yet
+func (s *holidayService) SeedBundledCalendars() error {
+	calendars, err := holidays.Bundled()
+	if err != nil {
+		return fmt.Errorf("failed to load bundled holiday calendars: %w", err)
+	}
+	for _, calendar := range calendars {
+		seeded, err := s.holidayRepo.SeedCalendar(calendar)
+		if err != nil {
+			return fmt.Errorf("failed to seed holiday calendar %s: %w", calendar.Code, err)
+		}
+		if seeded {
+			log.Printf("seeded holiday calendar %s with %d holidays", calendar.Code, len(calendar.Holidays))
+			s.calendarChanged(calendar.Code)
+		}
+	}
+	return nil
+}
+
+func (s *holidayService) GetCalendars() ([]*model.HolidayCalendar, error) {
+	calendars, err := s.holidayRepo.GetCalendars()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holiday calendars: %w", err)
+	}
+	return calendars, nil
+}
+
+// GetCalendar returns a calendar with its holidays of the given year, or all
+// of them when year is 0
+func (s *holidayService) GetCalendar(code string, year int) (*model.HolidayCalendar, error) {
+	calendar, err := s.requireCalendar(code)
+	if err != nil {
+		return nil, err
+	}
+
+	startDate, endDate := "0001-01-01", "9999-12-31"
+	if year != 0 {
+		startDate, endDate = fmt.Sprintf("%04d-01-01", year), fmt.Sprintf("%04d-12-31", year)
+	}
+	calendar.Holidays, err = s.holidayRepo.GetHolidays(calendar.Code, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holidays: %w", err)
+	}
+	return calendar, nil
+}
+
+// UpdateCalendar creates the calendar or replaces all of its holidays
+func (s *holidayService) UpdateCalendar(code string, req *model.UpdateHolidayCalendarRequest) (*model.HolidayCalendar, error) {
+	calendar := &model.HolidayCalendar{
+		Code:        strings.ToUpper(code),
+		Name:        req.Name,
+		CountryCode: strings.ToUpper(req.CountryCode),
+		Holidays:    make([]model.Holiday, 0, len(req.Holidays)),
+	}
+	for _, holiday := range req.Holidays {
+		if _, err := time.Parse("2006-01-02", holiday.Date); err != nil {
+			return nil, apperr.Validation("invalid_holiday_date", holiday.Date)
+		}
+		calendar.Holidays = append(calendar.Holidays, model.Holiday{
+			CalendarCode: calendar.Code,
+			Date:         holiday.Date,
+			Name:         holiday.Name,
+		})
+	}
+
+	if err := s.holidayRepo.SaveCalendar(calendar); err != nil {
+		return nil, fmt.Errorf("failed to save holiday calendar: %w", err)
+	}
+
+	s.calendarChanged(calendar.Code)
+	return s.GetCalendar(calendar.Code, 0)
+}
+
+func (s *holidayService) AddHoliday(code string, req *model.HolidayRequest) (*model.Holiday, error) {
+	calendar, err := s.requireCalendar(code)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := time.Parse("2006-01-02", req.Date); err != nil {
+		return nil, apperr.Validation("invalid_holiday_date", req.Date)
+	}
+
+	holiday := &model.Holiday{CalendarCode: calendar.Code, Date: req.Date, Name: req.Name}
+	if err := s.holidayRepo.AddHoliday(holiday); err != nil {
+		return nil, fmt.Errorf("failed to add holiday: %w", err)
+	}
+
+	s.calendarChanged(calendar.Code)
+	return holiday, nil
+}
+
+func (s *holidayService) DeleteHoliday(code string, date string) error {
+	calendar, err := s.requireCalendar(code)
+	if err != nil {
+		return err
+	}
+
+	if err := s.holidayRepo.DeleteHoliday(calendar.Code, date); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrHolidayNotFound
+		}
+		return fmt.Errorf("failed to delete holiday: %w", err)
+	}
+
+	s.calendarChanged(calendar.Code)
+	return nil
+}
+
+// GetExpertSettings lấy các lịch nghỉ lễ chuyên gia áp dụng và các ngày làm bù
+func (s *holidayService) GetExpertSettings(expertID string) (*model.ExpertHolidaySettings, error) {
+	expertUUID, err := s.requireExpert(expertID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.holidayRepo.GetExpertSettings(expertUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holiday settings: %w", err)
+	}
+	if settings.CalendarCodes == nil {
+		settings.IsDefault = true
+		settings.CalendarCodes = []string{}
+		if s.defaultCalendar != "" {
+			settings.CalendarCodes = []string{s.defaultCalendar}
+		}
+	}
+	return settings, nil
+}
+
+// SetExpertCalendars chọn các lịch nghỉ lễ chuyên gia áp dụng; danh sách rỗng
+// nghĩa là nhận lịch hẹn cả vào ngày lễ
+func (s *holidayService) SetExpertCalendars(expertID string, req *model.UpdateExpertHolidayCalendarsRequest) (*model.ExpertHolidaySettings, error) {
+	expertUUID, err := s.requireExpert(expertID)
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, len(req.CalendarCodes))
+	seen := make(map[string]bool, len(req.CalendarCodes))
+	for _, code := range req.CalendarCodes {
+		calendar, err := s.requireCalendar(code)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[calendar.Code] {
+			seen[calendar.Code] = true
+			codes = append(codes, calendar.Code)
+		}
+	}
+
+	if err := s.holidayRepo.SetExpertCalendars(expertUUID, codes); err != nil {
+		return nil, fmt.Errorf("failed to save holiday calendars: %w", err)
+	}
+
+	s.expertChanged(expertUUID)
+	return s.GetExpertSettings(expertID)
+}
+
+// AddOverride cho phép chuyên gia nhận lịch hẹn vào một ngày lễ
+func (s *holidayService) AddOverride(expertID string, req *model.CreateHolidayOverrideRequest) (*model.ExpertHolidaySettings, error) {
+	expertUUID, err := s.requireExpert(expertID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := time.Parse("2006-01-02", req.Date); err != nil {
+		return nil, apperr.Validation("invalid_date")
+	}
+
+	override := &model.HolidayOverride{Date: req.Date, Note: req.Note}
+	if err := s.holidayRepo.AddOverride(expertUUID, override); err != nil {
+		return nil, fmt.Errorf("failed to add holiday override: %w", err)
+	}
+
+	s.expertChanged(expertUUID)
+	return s.GetExpertSettings(expertID)
+}
+
+func (s *holidayService) DeleteOverride(expertID string, date string) error {
+	expertUUID, err := s.requireExpert(expertID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.holidayRepo.DeleteOverride(expertUUID, date); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrHolidayOverrideNotFound
+		}
+		return fmt.Errorf("failed to delete holiday override: %w", err)
+	}
+
+	s.expertChanged(expertUUID)
+	return nil
+}
+
+// GetExpertHolidays lists the holidays that block the expert between two dates (inclusive)
+func (s *holidayService) GetExpertHolidays(expertID string, startDate, endDate time.Time) ([]model.Holiday, error) {
+	expertUUID, err := s.requireExpert(expertID)
+	if err != nil {
+		return nil, err
+	}
+	if endDate.Before(startDate) {
+		return nil, apperr.Validation("end_date_before_start")
+	}
+	holidays, err := s.holidayRepo.GetExpertHolidays(expertUUID, s.defaultCalendar,
+		startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holidays: %w", err)
+	}
+	return holidays, nil
+}
+
+// HolidayOffTimes returns the holidays the expert observes that overlap
+// [from, to) as whole-day off-times in local time, so that availability and
+// booking checks can treat them like any other off-time
+func (s *holidayService) HolidayOffTimes(expertID uuid.UUID, from, to time.Time) ([]*model.OffTime, error) {
+	from, to = from.In(time.Local), to.In(time.Local)
+	holidays, err := s.holidayRepo.GetExpertHolidays(expertID, s.defaultCalendar,
+		from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check holidays: %w", err)
+	}
+
+	var offTimes []*model.OffTime
+	for _, holiday := range holidays {
+		day, err := time.ParseInLocation("2006-01-02", holiday.Date, time.Local)
+		if err != nil {
+			continue
+		}
+		start, end := day, day.AddDate(0, 0, 1)
+		if !start.Before(to) || !end.After(from) {
+			continue
+		}
+		offTimes = append(offTimes, &model.OffTime{
+			ExpertID:       expertID,
+			StartDateTime:  start,
+			EndDateTime:    end,
+			Reason:         holiday.Name,
+			OccurrenceDate: holiday.Date,
+		})
+	}
+	return offTimes, nil
+}
+
+func (s *holidayService) requireCalendar(code string) (*model.HolidayCalendar, error) {
+	calendar, err := s.holidayRepo.GetCalendar(strings.ToUpper(code))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holiday calendar: %w", err)
+	}
+	if calendar == nil {
+		return nil, ErrHolidayCalendarNotFound
+	}
+	return calendar, nil
+}
+
+func (s *holidayService) requireExpert(expertID string) (uuid.UUID, error) {
+	expertUUID, err := uuid.Parse(expertID)
+	if err != nil {
+		return uuid.Nil, apperr.Validation("invalid_expert_id")
+	}
+	expert, err := s.expertRepo.GetByID(expertUUID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to get expert: %w", err)
+	}
+	if expert == nil {
+		return uuid.Nil, apperr.NotFound("expert_not_found")
+	}
+	return expertUUID, nil
+}
+
+// calendarChanged drops the computed availability of every expert observing the calendar
+func (s *holidayService) calendarChanged(code string) {
+	expertIDs, err := s.holidayRepo.GetExpertIDsObserving(code, s.defaultCalendar)
+	if err != nil {
+		log.Printf("failed to list experts observing holiday calendar %s: %v", code, err)
+		return
+	}
+	for _, expertID := range expertIDs {
+		s.expertChanged(expertID)
+	}
+}
+
+func (s *holidayService) expertChanged(expertID uuid.UUID) {
+	s.cache.InvalidateComputed(expertID.String())
+	s.publisher.Publish(events.TypeHolidaysChanged, expertID.String())
+}
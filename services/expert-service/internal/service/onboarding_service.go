@@ -0,0 +1,303 @@
+package service
+
+import (
+	"expert-service/internal/model"
+	"expert-service/internal/repository"
+	"fmt"
+	"time"
+
+	"booking-system/shared/pkg/apperr"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultApplicationPageSize = 20
+	maxApplicationPageSize     = 100
+)
+
+var (
+	ErrUserNotFound                 = apperr.NotFound("user_not_found")
+	ErrUserNotEligible              = apperr.Forbidden("user_not_eligible")
+	ErrApplicationNotFound          = apperr.NotFound("application_not_found")
+	ErrApplicationExists            = apperr.Conflict("application_exists")
+	ErrAlreadyExpert                = apperr.Conflict("already_expert")
+	ErrInvalidApplicationTransition = apperr.InvalidTransition("invalid_application_transition")
+	ErrApplicationLocked            = apperr.InvalidTransition("application_locked")
+	ErrDocumentsRequired            = apperr.InvalidTransition("documents_required")
+)
+
+// OnboardingService runs the expert onboarding workflow: a user applies,
+// attaches proof of their qualifications and submits; an admin reviews the
+// application and approves or rejects it. Approval creates the expert
+// profile from the reviewed application and grants the expert role.
+type OnboardingService interface {
+	Apply(userID uuid.UUID, req *model.CreateApplicationRequest) (*model.ExpertApplication, error)
+	GetMyApplication(userID uuid.UUID) (*model.ExpertApplication, error)
+	AddDocument(userID uuid.UUID, req *model.AddApplicationDocumentRequest) (*model.ApplicationDocument, error)
+	Submit(userID uuid.UUID) (*model.ExpertApplication, error)
+
+	GetApplications(req *model.GetApplicationsRequest) (*model.ApplicationListResponse, error)
+	GetApplication(id uuid.UUID) (*model.ExpertApplication, error)
+	StartReview(id, reviewerID uuid.UUID) (*model.ExpertApplication, error)
+	Approve(id, reviewerID uuid.UUID, req *model.ApproveApplicationRequest) (*model.ExpertApplication, error)
+	Reject(id, reviewerID uuid.UUID, req *model.RejectApplicationRequest) (*model.ExpertApplication, error)
+}
+
+type onboardingService struct {
+	applicationRepo repository.ExpertApplicationRepository
+	expertRepo      repository.ExpertRepository
+}
+
+func NewOnboardingService(applicationRepo repository.ExpertApplicationRepository, expertRepo repository.ExpertRepository) OnboardingService {
+	return &onboardingService{
+		applicationRepo: applicationRepo,
+		expertRepo:      expertRepo,
+	}
+}
+
+// Apply starts an application, or restarts a rejected one with new details
+func (s *onboardingService) Apply(userID uuid.UUID, req *model.CreateApplicationRequest) (*model.ExpertApplication, error) {
+	role, err := s.expertRepo.GetUserRole(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if err := checkExpertEligible(role); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.applicationRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+
+	certifications := req.Certifications
+	if certifications == nil {
+		certifications = []string{}
+	}
+
+	if existing != nil {
+		if existing.Status != model.ApplicationStatusRejected {
+			return nil, ErrApplicationExists
+		}
+		existing.Specialization = req.Specialization
+		existing.ExperienceYears = req.ExperienceYears
+		existing.HourlyRate = req.HourlyRate
+		existing.Certifications = certifications
+		existing.Bio = req.Bio
+		ok, err := s.applicationRepo.Reapply(existing)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update application: %w", err)
+		}
+		if !ok {
+			return nil, ErrApplicationExists
+		}
+		return existing, nil
+	}
+
+	application := &model.ExpertApplication{
+		UserID:          userID,
+		Status:          model.ApplicationStatusApplied,
+		Specialization:  req.Specialization,
+		ExperienceYears: req.ExperienceYears,
+		HourlyRate:      req.HourlyRate,
+		Certifications:  certifications,
+		Bio:             req.Bio,
+		Documents:       []*model.ApplicationDocument{},
+	}
+	if err := s.applicationRepo.Create(application); err != nil {
+		return nil, fmt.Errorf("failed to create application: %w", err)
+	}
+	return application, nil
+}
+
+func (s *onboardingService) GetMyApplication(userID uuid.UUID) (*model.ExpertApplication, error) {
+	application, err := s.applicationRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	if application == nil {
+		return nil, ErrApplicationNotFound
+	}
+	return application, nil
+}
+
+// AddDocument attaches proof of a qualification while the application is
+// still being prepared
+func (s *onboardingService) AddDocument(userID uuid.UUID, req *model.AddApplicationDocumentRequest) (*model.ApplicationDocument, error) {
+	application, err := s.GetMyApplication(userID)
+	if err != nil {
+		return nil, err
+	}
+	if application.Status != model.ApplicationStatusApplied {
+		return nil, ErrApplicationLocked
+	}
+
+	document := &model.ApplicationDocument{
+		ApplicationID: application.ID,
+		Name:          req.Name,
+		URL:           req.URL,
+	}
+	if err := s.applicationRepo.AddDocument(document); err != nil {
+		return nil, fmt.Errorf("failed to add document: %w", err)
+	}
+	return document, nil
+}
+
+// Submit hands the application and its documents over for review
+func (s *onboardingService) Submit(userID uuid.UUID) (*model.ExpertApplication, error) {
+	application, err := s.GetMyApplication(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(application.Documents) == 0 && application.Status == model.ApplicationStatusApplied {
+		return nil, ErrDocumentsRequired
+	}
+
+	now := time.Now()
+	application.SubmittedAt = &now
+	return s.transition(application, model.ApplicationStatusDocumentsSubmitted)
+}
+
+func (s *onboardingService) GetApplications(req *model.GetApplicationsRequest) (*model.ApplicationListResponse, error) {
+	page, limit := req.Page, req.Limit
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > maxApplicationPageSize {
+		limit = defaultApplicationPageSize
+	}
+
+	applications, total, err := s.applicationRepo.List(req.Status, limit, (page-1)*limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get applications: %w", err)
+	}
+	return &model.ApplicationListResponse{Applications: applications, Total: total, Page: page, Limit: limit}, nil
+}
+
+func (s *onboardingService) GetApplication(id uuid.UUID) (*model.ExpertApplication, error) {
+	application, err := s.applicationRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	if application == nil {
+		return nil, ErrApplicationNotFound
+	}
+	return application, nil
+}
+
+// StartReview assigns a submitted application to the reviewing admin
+func (s *onboardingService) StartReview(id, reviewerID uuid.UUID) (*model.ExpertApplication, error) {
+	application, err := s.GetApplication(id)
+	if err != nil {
+		return nil, err
+	}
+
+	application.ReviewerID = &reviewerID
+	return s.transition(application, model.ApplicationStatusUnderReview)
+}
+
+// Approve creates the expert profile from the reviewed application. The
+// profile starts unavailable so the new expert can set up their schedule and
+// services before clients can find them.
+func (s *onboardingService) Approve(id, reviewerID uuid.UUID, req *model.ApproveApplicationRequest) (*model.ExpertApplication, error) {
+	application, err := s.GetApplication(id)
+	if err != nil {
+		return nil, err
+	}
+	if !model.CanTransitionApplication(application.Status, model.ApplicationStatusApproved) {
+		return nil, ErrInvalidApplicationTransition
+	}
+
+	existing, err := s.expertRepo.GetByUserID(application.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expert: %w", err)
+	}
+	if existing != nil {
+		return nil, ErrAlreadyExpert
+	}
+
+	now := time.Now()
+	expert := &model.Expert{
+		ID:              uuid.New(),
+		UserID:          application.UserID,
+		Specialization:  application.Specialization,
+		ExperienceYears: application.ExperienceYears,
+		HourlyRate:      application.HourlyRate,
+		Certifications:  application.Certifications,
+		IsAvailable:     false,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	application.Status = model.ApplicationStatusApproved
+	application.ReviewerID = &reviewerID
+	application.ReviewNotes = req.Notes
+	application.ReviewedAt = &now
+	ok, err := s.applicationRepo.Approve(application, expert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to approve application: %w", err)
+	}
+	if !ok {
+		return nil, ErrInvalidApplicationTransition
+	}
+	return application, nil
+}
+
+func (s *onboardingService) Reject(id, reviewerID uuid.UUID, req *model.RejectApplicationRequest) (*model.ExpertApplication, error) {
+	application, err := s.GetApplication(id)
+	if err != nil {
+		return nil, err
+	}
+	if !model.CanTransitionApplication(application.Status, model.ApplicationStatusRejected) {
+		return nil, ErrInvalidApplicationTransition
+	}
+
+	now := time.Now()
+	from := application.Status
+	application.Status = model.ApplicationStatusRejected
+	application.ReviewerID = &reviewerID
+	application.RejectionReason = req.Reason
+	application.ReviewedAt = &now
+	ok, err := s.applicationRepo.Reject(application, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reject application: %w", err)
+	}
+	if !ok {
+		return nil, ErrInvalidApplicationTransition
+	}
+	return application, nil
+}
+
+// checkExpertEligible reports why a user with the given role cannot become an
+// expert, if they cannot
+func checkExpertEligible(role string) error {
+	switch role {
+	case "":
+		return ErrUserNotFound
+	case "expert":
+		return ErrAlreadyExpert
+	case "user":
+		return nil
+	default:
+		return ErrUserNotEligible
+	}
+}
+
+// transition moves an application to the next state, failing if the
+// workflow does not allow it or the application changed concurrently
+func (s *onboardingService) transition(application *model.ExpertApplication, to string) (*model.ExpertApplication, error) {
+	if !model.CanTransitionApplication(application.Status, to) {
+		return nil, ErrInvalidApplicationTransition
+	}
+
+	from := application.Status
+	application.Status = to
+	ok, err := s.applicationRepo.UpdateStatus(application, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update application: %w", err)
+	}
+	if !ok {
+		return nil, ErrInvalidApplicationTransition
+	}
+	return application, nil
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"expert-service/internal/model"
+	"expert-service/internal/repository"
+	"fmt"
+
+	"booking-system/shared/pkg/apperr"
+	"github.com/google/uuid"
+)
+
+var ErrExpertErasure = apperr.Conflict("expert_erasure_blocked")
+
+// PrivacyService exports and erases a user's data for user-service's data
+// export and account erasure
+type PrivacyService interface {
+	ExportUserData(userID uuid.UUID) (*model.UserDataExport, error)
+	EraseUserData(userID uuid.UUID) error
+}
+
+type privacyService struct {
+	reviewRepo      repository.ReviewRepository
+	applicationRepo repository.ExpertApplicationRepository
+	expertRepo      repository.ExpertRepository
+}
+
+func NewPrivacyService(reviewRepo repository.ReviewRepository, applicationRepo repository.ExpertApplicationRepository, expertRepo repository.ExpertRepository) PrivacyService {
+	return &privacyService{
+		reviewRepo:      reviewRepo,
+		applicationRepo: applicationRepo,
+		expertRepo:      expertRepo,
+	}
+}
+
+func (s *privacyService) ExportUserData(userID uuid.UUID) (*model.UserDataExport, error) {
+	reviews, err := s.reviewRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reviews: %w", err)
+	}
+	application, err := s.applicationRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	return &model.UserDataExport{UserID: userID, Reviews: reviews, ExpertApplication: application}, nil
+}
+
+// EraseUserData deletes the user's expert application and its documents.
+// Reviews stay so experts keep their ratings; once user-service anonymises
+// the account they no longer point to a person.
+func (s *privacyService) EraseUserData(userID uuid.UUID) error {
+	expert, err := s.expertRepo.GetByUserID(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get expert: %w", err)
+	}
+	if expert != nil {
+		return ErrExpertErasure
+	}
+
+	if err := s.applicationRepo.DeleteByUserID(userID); err != nil {
+		return fmt.Errorf("failed to delete application: %w", err)
+	}
+	return nil
+}
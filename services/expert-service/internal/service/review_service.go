@@ -0,0 +1,232 @@
+package service
+
+import (
+	"expert-service/internal/model"
+	"expert-service/internal/repository"
+	"fmt"
+	"time"
+
+	"booking-system/shared/pkg/apperr"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultReviewPageSize = 10
+	maxReviewPageSize     = 100
+)
+
+var (
+	ErrReviewNotFound        = apperr.NotFound("review_not_found")
+	ErrBookingNotFound       = apperr.NotFound("booking_not_found")
+	ErrBookingNotCompleted   = apperr.InvalidTransition("booking_not_completed")
+	ErrReviewAlreadyExists   = apperr.Conflict("review_already_exists")
+	ErrReviewForbidden       = apperr.Forbidden("review_forbidden")
+	ErrReviewEditWindowEnded = apperr.InvalidTransition("review_edit_window_ended")
+)
+
+type ReviewService interface {
+	CreateReview(userID uuid.UUID, req *model.CreateReviewRequest) (*model.Review, error)
+	// GetReview returns a review; hidden and flagged reviews are shown only
+	// to their author, the reviewed expert and admins. viewerID is uuid.Nil
+	// for anonymous callers.
+	GetReview(id, viewerID uuid.UUID, isAdmin bool) (*model.Review, error)
+	GetExpertReviews(expertID uuid.UUID, req *model.GetReviewsRequest) (*model.ReviewListResponse, error)
+	GetReviewsByStatus(status string, req *model.GetReviewsRequest) (*model.ReviewListResponse, error)
+	UpdateReview(id, userID uuid.UUID, req *model.UpdateReviewRequest) (*model.Review, error)
+	ReplyToReview(id, userID uuid.UUID, req *model.ReplyReviewRequest) (*model.Review, error)
+	ModerateReview(id uuid.UUID, req *model.ModerateReviewRequest) (*model.Review, error)
+}
+
+type reviewService struct {
+	reviewRepo  repository.ReviewRepository
+	bookingRepo repository.BookingRepository
+	expertRepo  repository.ExpertRepository
+	editWindow  time.Duration
+}
+
+// NewReviewService creates a review service. Authors may edit their review
+// for editWindow after posting it.
+func NewReviewService(
+	reviewRepo repository.ReviewRepository,
+	bookingRepo repository.BookingRepository,
+	expertRepo repository.ExpertRepository,
+	editWindow time.Duration,
+) ReviewService {
+	return &reviewService{
+		reviewRepo:  reviewRepo,
+		bookingRepo: bookingRepo,
+		expertRepo:  expertRepo,
+		editWindow:  editWindow,
+	}
+}
+
+func (s *reviewService) CreateReview(userID uuid.UUID, req *model.CreateReviewRequest) (*model.Review, error) {
+	bookingID, err := uuid.Parse(req.BookingID)
+	if err != nil {
+		return nil, apperr.Validation("invalid_booking_id")
+	}
+
+	booking, err := s.bookingRepo.GetReviewableByID(bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking: %w", err)
+	}
+	if booking == nil {
+		return nil, ErrBookingNotFound
+	}
+	if booking.UserID != userID {
+		return nil, ErrReviewForbidden
+	}
+	if booking.Status != "completed" {
+		return nil, ErrBookingNotCompleted
+	}
+
+	existing, err := s.reviewRepo.GetByBookingID(bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing review: %w", err)
+	}
+	if existing != nil {
+		return nil, ErrReviewAlreadyExists
+	}
+
+	review := &model.Review{
+		BookingID: bookingID,
+		UserID:    userID,
+		ExpertID:  booking.ExpertID,
+		Rating:    req.Rating,
+		Comment:   req.Comment,
+		Status:    model.ReviewStatusVisible,
+	}
+	if err := s.reviewRepo.Create(review); err != nil {
+		return nil, fmt.Errorf("failed to create review: %w", err)
+	}
+	return review, nil
+}
+
+func (s *reviewService) GetReview(id, viewerID uuid.UUID, isAdmin bool) (*model.Review, error) {
+	review, err := s.findReview(id)
+	if err != nil {
+		return nil, err
+	}
+	if review.Status == model.ReviewStatusVisible || isAdmin {
+		return review, nil
+	}
+	if viewerID == uuid.Nil {
+		return nil, ErrReviewNotFound
+	}
+	if review.UserID == viewerID {
+		return review, nil
+	}
+	expert, err := s.expertRepo.GetByID(review.ExpertID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expert: %w", err)
+	}
+	if expert == nil || expert.UserID != viewerID {
+		return nil, ErrReviewNotFound
+	}
+	return review, nil
+}
+
+// findReview returns a review whatever its status
+func (s *reviewService) findReview(id uuid.UUID) (*model.Review, error) {
+	review, err := s.reviewRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+	if review == nil {
+		return nil, ErrReviewNotFound
+	}
+	return review, nil
+}
+
+func (s *reviewService) GetExpertReviews(expertID uuid.UUID, req *model.GetReviewsRequest) (*model.ReviewListResponse, error) {
+	page, limit := normalizeReviewPage(req)
+	reviews, total, err := s.reviewRepo.GetByExpertID(expertID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reviews: %w", err)
+	}
+	return &model.ReviewListResponse{Reviews: reviews, Total: total, Page: page, Limit: limit}, nil
+}
+
+func (s *reviewService) GetReviewsByStatus(status string, req *model.GetReviewsRequest) (*model.ReviewListResponse, error) {
+	page, limit := normalizeReviewPage(req)
+	reviews, total, err := s.reviewRepo.GetByStatus(status, limit, (page-1)*limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reviews: %w", err)
+	}
+	return &model.ReviewListResponse{Reviews: reviews, Total: total, Page: page, Limit: limit}, nil
+}
+
+func (s *reviewService) UpdateReview(id, userID uuid.UUID, req *model.UpdateReviewRequest) (*model.Review, error) {
+	review, err := s.findReview(id)
+	if err != nil {
+		return nil, err
+	}
+	if review.UserID != userID {
+		return nil, ErrReviewForbidden
+	}
+	if review.Status == model.ReviewStatusHidden || time.Since(review.CreatedAt) > s.editWindow {
+		return nil, ErrReviewEditWindowEnded
+	}
+
+	if req.Rating != nil {
+		review.Rating = *req.Rating
+	}
+	if req.Comment != nil {
+		review.Comment = *req.Comment
+	}
+
+	if err := s.reviewRepo.Update(review); err != nil {
+		return nil, fmt.Errorf("failed to update review: %w", err)
+	}
+	return review, nil
+}
+
+// ReplyToReview lets the reviewed expert answer publicly; replying again
+// replaces the previous answer
+func (s *reviewService) ReplyToReview(id, userID uuid.UUID, req *model.ReplyReviewRequest) (*model.Review, error) {
+	review, err := s.findReview(id)
+	if err != nil {
+		return nil, err
+	}
+
+	expert, err := s.expertRepo.GetByID(review.ExpertID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expert: %w", err)
+	}
+	if expert == nil || expert.UserID != userID {
+		return nil, ErrReviewForbidden
+	}
+
+	now := time.Now()
+	review.ExpertReply = req.Reply
+	review.RepliedAt = &now
+	if err := s.reviewRepo.UpdateReply(review); err != nil {
+		return nil, fmt.Errorf("failed to save reply: %w", err)
+	}
+	return review, nil
+}
+
+func (s *reviewService) ModerateReview(id uuid.UUID, req *model.ModerateReviewRequest) (*model.Review, error) {
+	review, err := s.findReview(id)
+	if err != nil {
+		return nil, err
+	}
+
+	review.Status = req.Status
+	review.ModerationReason = req.Reason
+	if err := s.reviewRepo.UpdateStatus(review); err != nil {
+		return nil, fmt.Errorf("failed to moderate review: %w", err)
+	}
+	return review, nil
+}
+
+func normalizeReviewPage(req *model.GetReviewsRequest) (int, int) {
+	page, limit := req.Page, req.Limit
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > maxReviewPageSize {
+		limit = defaultReviewPageSize
+	}
+	return page, limit
+}
@@ -1,11 +1,13 @@
 package service
 
 import (
+	"expert-service/internal/events"
 	"expert-service/internal/model"
 	"expert-service/internal/repository"
 	"fmt"
 	"time"
 
+	"booking-system/shared/pkg/apperr"
 	"github.com/google/uuid"
 )
 
@@ -23,11 +25,13 @@ type ScheduleService interface {
 
 type scheduleService struct {
 	scheduleRepo repository.ScheduleRepository
+	publisher    events.Publisher
 }
 
-func NewScheduleService(scheduleRepo repository.ScheduleRepository) ScheduleService {
+func NewScheduleService(scheduleRepo repository.ScheduleRepository, publisher events.Publisher) ScheduleService {
 	return &scheduleService{
 		scheduleRepo: scheduleRepo,
+		publisher:    publisher,
 	}
 }
 
@@ -54,6 +58,7 @@ func (s *scheduleService) CreateSchedule(req *model.CreateScheduleRequest) (*mod
 		return nil, fmt.Errorf("failed to create schedule: %w", err)
 	}
 
+	s.publisher.Publish(events.TypeScheduleChanged, schedule.ExpertID)
 	return schedule, nil
 }
 
@@ -67,7 +72,7 @@ func (s *scheduleService) GetScheduleByID(id uuid.UUID) (*model.Schedule, error)
 		return nil, fmt.Errorf("failed to get schedule: %w", err)
 	}
 	if schedule == nil {
-		return nil, fmt.Errorf("schedule not found")
+		return nil, apperr.NotFound("schedule_not_found")
 	}
 	return schedule, nil
 }
@@ -78,9 +83,11 @@ func (s *scheduleService) UpdateSchedule(id uuid.UUID, req *model.UpdateSchedule
 		return fmt.Errorf("failed to get schedule for update: %w", err)
 	}
 	if schedule == nil {
-		return fmt.Errorf("schedule with ID %s not found", id.String())
+		return apperr.NotFound("schedule_not_found")
 	}
 
+	previousExpertID := schedule.ExpertID
+
 	// Apply updates from request
 	if req.ExpertID != nil {
 		schedule.ExpertID = *req.ExpertID
@@ -124,11 +131,31 @@ func (s *scheduleService) UpdateSchedule(id uuid.UUID, req *model.UpdateSchedule
 
 	schedule.UpdatedAt = time.Now()
 
-	return s.scheduleRepo.Update(schedule)
+	if err := s.scheduleRepo.Update(schedule); err != nil {
+		return err
+	}
+
+	s.publisher.Publish(events.TypeScheduleChanged, schedule.ExpertID)
+	if previousExpertID != schedule.ExpertID {
+		s.publisher.Publish(events.TypeScheduleChanged, previousExpertID)
+	}
+	return nil
 }
 
 func (s *scheduleService) DeleteSchedule(id uuid.UUID) error {
-	return s.scheduleRepo.Delete(id)
+	schedule, err := s.scheduleRepo.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("failed to get schedule for deletion: %w", err)
+	}
+
+	if err := s.scheduleRepo.Delete(id); err != nil {
+		return err
+	}
+
+	if schedule != nil {
+		s.publisher.Publish(events.TypeScheduleChanged, schedule.ExpertID)
+	}
+	return nil
 }
 
 // GetSchedules retrieves schedules based on provided filters
@@ -143,14 +170,19 @@ func (s *scheduleService) CancelSchedule(id uuid.UUID) error {
 		return fmt.Errorf("failed to get schedule for cancellation: %w", err)
 	}
 	if schedule == nil {
-		return fmt.Errorf("schedule with ID %s not found", id.String())
+		return apperr.NotFound("schedule_not_found")
 	}
 
 	schedule.Status = "cancelled"
 	schedule.IsActive = false // Deactivate cancelled schedules
 	schedule.UpdatedAt = time.Now()
 
-	return s.scheduleRepo.Update(schedule)
+	if err := s.scheduleRepo.Update(schedule); err != nil {
+		return err
+	}
+
+	s.publisher.Publish(events.TypeScheduleChanged, schedule.ExpertID)
+	return nil
 }
 
 // ConfirmSchedule updates a schedule's status to confirmed
@@ -160,7 +192,7 @@ func (s *scheduleService) ConfirmSchedule(id uuid.UUID) error {
 		return fmt.Errorf("failed to get schedule for confirmation: %w", err)
 	}
 	if schedule == nil {
-		return fmt.Errorf("schedule with ID %s not found", id.String())
+		return apperr.NotFound("schedule_not_found")
 	}
 
 	schedule.Status = "confirmed"
@@ -176,7 +208,7 @@ func (s *scheduleService) CompleteSchedule(id uuid.UUID) error {
 		return fmt.Errorf("failed to get schedule for completion: %w", err)
 	}
 	if schedule == nil {
-		return fmt.Errorf("schedule with ID %s not found", id.String())
+		return apperr.NotFound("schedule_not_found")
 	}
 
 	schedule.Status = "completed"
@@ -0,0 +1,323 @@
+package service
+
+import (
+	"expert-service/internal/model"
+	"expert-service/internal/repository"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"booking-system/shared/pkg/apperr"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultSearchPageSize     = 10
+	maxSearchPageSize         = 100
+	defaultSearchDuration     = 60
+	defaultAvailabilityWindow = 14 * 24 * time.Hour
+
+	// bayesianMinReviews is how many reviews at the global mean every expert
+	// is assumed to have before their own reviews count
+	bayesianMinReviews = 5
+)
+
+// Upper bounds of the price and experience facet buckets; the last bucket is open-ended
+var (
+	priceFacetBounds      = []float64{50, 100, 200, 500}
+	experienceFacetBounds = []int{2, 5, 10}
+	ratingFacetMinimums   = []float64{4, 3, 2, 1}
+)
+
+type ExpertSearchService interface {
+	SearchExperts(req *model.SearchExpertsRequest) (*model.SearchExpertsResponse, error)
+}
+
+type expertSearchService struct {
+	expertRepo repository.ExpertRepository
+	slotSvc    SlotService
+}
+
+func NewExpertSearchService(expertRepo repository.ExpertRepository, slotSvc SlotService) ExpertSearchService {
+	return &expertSearchService{
+		expertRepo: expertRepo,
+		slotSvc:    slotSvc,
+	}
+}
+
+// SearchExperts lọc chuyên gia theo thuộc tính, khoảng thời gian rảnh, rồi
+// xếp hạng và phân trang. Facet được đếm trên toàn bộ kết quả trước khi phân trang.
+func (s *expertSearchService) SearchExperts(req *model.SearchExpertsRequest) (*model.SearchExpertsResponse, error) {
+	if req.MinRate != nil && req.MaxRate != nil && *req.MinRate > *req.MaxRate {
+		return nil, apperr.Validation("invalid_rate_range")
+	}
+	sortBy := req.Sort
+	if sortBy == "" {
+		sortBy = model.SearchSortRelevance
+	}
+
+	results, err := s.expertRepo.Search(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search experts: %w", err)
+	}
+
+	windowRequested := !req.AvailableFrom.IsZero() || !req.AvailableTo.IsZero()
+	from, to, duration, err := availabilityWindow(req)
+	if err != nil {
+		return nil, err
+	}
+	// Filtering by a window and ranking by availability need every match's
+	// next slot; otherwise it is only looked up for the returned page
+	allSlots := windowRequested || sortBy == model.SearchSortAvailability
+	if allSlots {
+		results, err = s.filterByAvailability(results, from, to, duration, windowRequested)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	prior, err := s.expertRepo.GetReviewWeightedRating()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rating prior: %w", err)
+	}
+	for _, result := range results {
+		result.WeightedRating = bayesianRating(result.Rating, result.TotalReviews, prior)
+		result.Relevance = relevance(result, req.Query)
+	}
+
+	sortSearchResults(results, sortBy)
+
+	page, limit := req.Page, req.Limit
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > maxSearchPageSize {
+		limit = defaultSearchPageSize
+	}
+	start := (page - 1) * limit
+	if start > len(results) {
+		start = len(results)
+	}
+	end := start + limit
+	if end > len(results) {
+		end = len(results)
+	}
+
+	if !allSlots {
+		if err := s.setNextAvailableSlots(results[start:end], from, to, duration); err != nil {
+			return nil, err
+		}
+	}
+
+	return &model.SearchExpertsResponse{
+		Experts: results[start:end],
+		Facets:  buildFacets(results),
+		Total:   len(results),
+		Page:    page,
+		Limit:   limit,
+	}, nil
+}
+
+// availabilityWindow returns the window searched for free slots, the
+// requested one or the coming two weeks, and the session length
+func availabilityWindow(req *model.SearchExpertsRequest) (from, to time.Time, duration int, err error) {
+	from, to = req.AvailableFrom, req.AvailableTo
+	if from.IsZero() || from.Before(time.Now()) {
+		from = time.Now()
+	}
+	if to.IsZero() {
+		to = from.Add(defaultAvailabilityWindow)
+	}
+	if !to.After(from) {
+		return from, to, 0, apperr.Validation("availability_window_before_start")
+	}
+	if to.Sub(from) > maxSlotRangeDays*24*time.Hour {
+		return from, to, 0, apperr.Validation("availability_window_too_long", maxSlotRangeDays)
+	}
+	duration = req.Duration
+	if duration == 0 {
+		duration = defaultSearchDuration
+	}
+	return from, to, duration, nil
+}
+
+// filterByAvailability finds each expert's first free slot in the window.
+// When only sorting by availability, experts without a slot are kept and
+// ranked last; when a window was given they are dropped.
+func (s *expertSearchService) filterByAvailability(results []*model.ExpertSearchResult, from, to time.Time, duration int, windowRequested bool) ([]*model.ExpertSearchResult, error) {
+	if err := s.setNextAvailableSlots(results, from, to, duration); err != nil {
+		return nil, err
+	}
+	if !windowRequested {
+		return results, nil
+	}
+	filtered := make([]*model.ExpertSearchResult, 0, len(results))
+	for _, result := range results {
+		if result.NextAvailableSlot != nil {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered, nil
+}
+
+// setNextAvailableSlots looks up the first free slot of all results at once
+func (s *expertSearchService) setNextAvailableSlots(results []*model.ExpertSearchResult, from, to time.Time, duration int) error {
+	expertIDs := make([]uuid.UUID, len(results))
+	for i, result := range results {
+		expertIDs[i] = result.ID
+	}
+	slots, err := s.slotSvc.FindFirstSlots(expertIDs, from, to, duration)
+	if err != nil {
+		return fmt.Errorf("failed to compute availability of experts: %w", err)
+	}
+	for _, result := range results {
+		result.NextAvailableSlot = slots[result.ID]
+	}
+	return nil
+}
+
+// bayesianRating shrinks an expert's average toward the global mean
+// in proportion to how few reviews they have
+func bayesianRating(rating float64, totalReviews int, prior float64) float64 {
+	n := float64(totalReviews)
+	return (bayesianMinReviews*prior + rating*n) / (bayesianMinReviews + n)
+}
+
+// relevance scores how well an expert matches the free-text query; without a
+// query every expert is equally relevant and the weighted rating decides
+func relevance(result *model.ExpertSearchResult, query string) float64 {
+	score := result.WeightedRating / 5
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return score
+	}
+
+	specialization := strings.ToLower(result.Specialization)
+	switch {
+	case specialization == query:
+		score += 3
+	case strings.Contains(specialization, query):
+		score += 2
+	}
+	if strings.Contains(strings.ToLower(result.FullName), query) {
+		score += 1.5
+	}
+	for _, certification := range result.Certifications {
+		if strings.Contains(strings.ToLower(certification), query) {
+			score++
+			break
+		}
+	}
+	return score
+}
+
+func sortSearchResults(results []*model.ExpertSearchResult, sortBy string) {
+	var less func(a, b *model.ExpertSearchResult) bool
+	switch sortBy {
+	case model.SearchSortPriceAsc:
+		less = func(a, b *model.ExpertSearchResult) bool { return a.HourlyRate < b.HourlyRate }
+	case model.SearchSortPriceDesc:
+		less = func(a, b *model.ExpertSearchResult) bool { return a.HourlyRate > b.HourlyRate }
+	case model.SearchSortRating:
+		less = func(a, b *model.ExpertSearchResult) bool { return a.WeightedRating > b.WeightedRating }
+	case model.SearchSortAvailability:
+		less = func(a, b *model.ExpertSearchResult) bool {
+			if a.NextAvailableSlot == nil || b.NextAvailableSlot == nil {
+				return a.NextAvailableSlot != nil && b.NextAvailableSlot == nil
+			}
+			return a.NextAvailableSlot.StartTime.Before(b.NextAvailableSlot.StartTime)
+		}
+	default:
+		less = func(a, b *model.ExpertSearchResult) bool { return a.Relevance > b.Relevance }
+	}
+	// Stable so that ties keep the repository order (newest experts first)
+	sort.SliceStable(results, func(i, j int) bool { return less(results[i], results[j]) })
+}
+
+func buildFacets(results []*model.ExpertSearchResult) model.SearchFacets {
+	specializations := make(map[string]int)
+	certifications := make(map[string]int)
+	meetingTypes := make(map[string]int)
+	prices := make([]int, len(priceFacetBounds)+1)
+	experience := make([]int, len(experienceFacetBounds)+1)
+	ratings := make([]int, len(ratingFacetMinimums))
+
+	for _, result := range results {
+		specializations[result.Specialization]++
+		for _, certification := range result.Certifications {
+			certifications[certification]++
+		}
+		for _, meetingType := range result.MeetingTypes {
+			meetingTypes[meetingType]++
+		}
+
+		bucket := len(priceFacetBounds)
+		for i, bound := range priceFacetBounds {
+			if result.HourlyRate < bound {
+				bucket = i
+				break
+			}
+		}
+		prices[bucket]++
+
+		bucket = len(experienceFacetBounds)
+		for i, bound := range experienceFacetBounds {
+			if result.ExperienceYears <= bound {
+				bucket = i
+				break
+			}
+		}
+		experience[bucket]++
+
+		// Rating ranges are cumulative ("4+" also counts toward "3+")
+		for i, minimum := range ratingFacetMinimums {
+			if result.Rating >= minimum {
+				ratings[i]++
+			}
+		}
+	}
+
+	facets := model.SearchFacets{
+		Specializations: countsToFacets(specializations),
+		Certifications:  countsToFacets(certifications),
+		MeetingTypes:    countsToFacets(meetingTypes),
+	}
+	lower := 0.0
+	for i, count := range prices {
+		label := fmt.Sprintf("%g+", lower)
+		if i < len(priceFacetBounds) {
+			label = fmt.Sprintf("%g-%g", lower, priceFacetBounds[i])
+			lower = priceFacetBounds[i]
+		}
+		facets.PriceRanges = append(facets.PriceRanges, model.FacetCount{Value: label, Count: count})
+	}
+	lowerYears := 0
+	for i, count := range experience {
+		label := fmt.Sprintf("%d+", lowerYears)
+		if i < len(experienceFacetBounds) {
+			label = fmt.Sprintf("%d-%d", lowerYears, experienceFacetBounds[i])
+			lowerYears = experienceFacetBounds[i] + 1
+		}
+		facets.ExperienceRanges = append(facets.ExperienceRanges, model.FacetCount{Value: label, Count: count})
+	}
+	for i, count := range ratings {
+		facets.RatingRanges = append(facets.RatingRanges, model.FacetCount{Value: fmt.Sprintf("%g+", ratingFacetMinimums[i]), Count: count})
+	}
+	return facets
+}
+
+// countsToFacets orders facet values by count, then alphabetically
+func countsToFacets(counts map[string]int) []model.FacetCount {
+	facets := make([]model.FacetCount, 0, len(counts))
+	for value, count := range counts {
+		facets = append(facets, model.FacetCount{Value: value, Count: count})
+	}
+	sort.Slice(facets, func(i, j int) bool {
+		if facets[i].Count != facets[j].Count {
+			return facets[i].Count > facets[j].Count
+		}
+		return facets[i].Value < facets[j].Value
+	})
+	return facets
+}
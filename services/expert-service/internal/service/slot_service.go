@@ -0,0 +1,641 @@
+package service
+
+import (
+	"encoding/json"
+	"expert-service/internal/cache"
+	"expert-service/internal/events"
+	"expert-service/internal/model"
+	"expert-service/internal/repository"
+	"fmt"
+	"sort"
+	"time"
+
+	"booking-system/shared/pkg/apperr"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultSlotGranularity = 30
+	maxSlotRangeDays       = 31
+)
+
+type SlotService interface {
+	GetAvailableSlots(req *model.GetSlotsRequest) ([]model.Slot, error)
+	GetBookingRule(expertID string) (*model.BookingRule, error)
+	UpdateBookingRule(expertID string, req *model.UpdateBookingRuleRequest) (*model.BookingRule, error)
+	CheckBookingWindow(req *model.CheckBookingWindowRequest) (*model.BookingWindowCheck, error)
+	FindFirstSlot(expertID string, from, to time.Time, durationMinutes int) (*model.Slot, error)
+	FindFirstSlots(expertIDs []uuid.UUID, from, to time.Time, durationMinutes int) (map[uuid.UUID]*model.Slot, error)
+}
+
+type slotService struct {
+	expertRepo      repository.ExpertRepository
+	scheduleRepo    repository.ScheduleRepository
+	offTimeRepo     repository.OffTimeRepository
+	bookingRepo     repository.BookingRepository
+	ruleRepo        repository.BookingRuleRepository
+	availabilitySvc ExpertAvailabilityService
+	holidaySvc      HolidayService
+	cache           cache.AvailabilityCache
+	publisher       events.Publisher
+}
+
+func NewSlotService(
+	expertRepo repository.ExpertRepository,
+	scheduleRepo repository.ScheduleRepository,
+	offTimeRepo repository.OffTimeRepository,
+	bookingRepo repository.BookingRepository,
+	ruleRepo repository.BookingRuleRepository,
+	availabilitySvc ExpertAvailabilityService,
+	holidaySvc HolidayService,
+	cache cache.AvailabilityCache,
+	publisher events.Publisher,
+) SlotService {
+	return &slotService{
+		expertRepo:      expertRepo,
+		scheduleRepo:    scheduleRepo,
+		offTimeRepo:     offTimeRepo,
+		bookingRepo:     bookingRepo,
+		ruleRepo:        ruleRepo,
+		availabilitySvc: availabilitySvc,
+		holidaySvc:      holidaySvc,
+		cache:           cache,
+		publisher:       publisher,
+	}
+}
+
+// interval is a half-open [start, end) time range
+type interval struct {
+	start time.Time
+	end   time.Time
+}
+
+func (i interval) overlaps(other interval) bool {
+	return i.start.Before(other.end) && other.start.Before(i.end)
+}
+
+// GetAvailableSlots trả về các thời điểm bắt đầu còn trống của chuyên gia
+// sau khi trừ thời gian nghỉ, lịch đã đặt, buffer và các ràng buộc khác.
+func (s *slotService) GetAvailableSlots(req *model.GetSlotsRequest) ([]model.Slot, error) {
+	expertUUID, err := uuid.Parse(req.ExpertID)
+	if err != nil {
+		return nil, apperr.Validation("invalid_expert_id")
+	}
+	expert, err := s.expertRepo.GetByID(expertUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expert: %w", err)
+	}
+	if expert == nil {
+		return nil, apperr.NotFound("expert_not_found")
+	}
+	if !expert.IsAvailable {
+		return []model.Slot{}, nil
+	}
+	verified, err := s.expertRepo.IsEmailVerified(expertUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check expert email verification: %w", err)
+	}
+	if !verified {
+		return []model.Slot{}, nil
+	}
+	approved, err := s.expertRepo.IsApproved(expertUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check expert approval: %w", err)
+	}
+	if !approved {
+		return []model.Slot{}, nil
+	}
+
+	startDate, err := time.ParseInLocation("2006-01-02", req.StartDate, time.Local)
+	if err != nil {
+		return nil, apperr.Validation("invalid_start_date")
+	}
+	endDate, err := time.ParseInLocation("2006-01-02", req.EndDate, time.Local)
+	if err != nil {
+		return nil, apperr.Validation("invalid_end_date")
+	}
+	if endDate.Before(startDate) {
+		return nil, apperr.Validation("end_date_before_start")
+	}
+	if endDate.Sub(startDate) > maxSlotRangeDays*24*time.Hour {
+		return nil, apperr.Validation("date_range_too_long", maxSlotRangeDays)
+	}
+
+	granularity := req.GranularityMinutes
+	if granularity <= 0 {
+		granularity = defaultSlotGranularity
+	}
+	if granularity < 5 || granularity > 240 {
+		return nil, apperr.Validation("invalid_granularity")
+	}
+
+	rule, err := s.getRule(expertUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := cache.SlotKey(req.ExpertID, req.StartDate, req.EndDate, req.DurationMinutes, granularity)
+	if cached, err := s.cache.GetAvailability(cacheKey); err == nil && cached != nil {
+		var slots []model.Slot
+		if err := json.Unmarshal(cached, &slots); err == nil {
+			return filterByNotice(slots, rule, time.Now()), nil
+		}
+	}
+
+	slots, err := s.computeSlots(expertUUID, startDate, endDate, req.DurationMinutes, granularity, rule)
+	if err != nil {
+		return nil, err
+	}
+
+	data, _ := json.Marshal(slots)
+	if err := s.cache.SetAvailability(cacheKey, data); err != nil {
+		return nil, fmt.Errorf("failed to cache slots: %w", err)
+	}
+	return filterByNotice(slots, rule, time.Now()), nil
+}
+
+// FindFirstSlot returns the earliest bookable slot that fits entirely in
+// [from, to], or nil when there is none
+func (s *slotService) FindFirstSlot(expertID string, from, to time.Time, durationMinutes int) (*model.Slot, error) {
+	slots, err := s.GetAvailableSlots(&model.GetSlotsRequest{
+		ExpertID:        expertID,
+		StartDate:       from.In(time.Local).Format("2006-01-02"),
+		EndDate:         to.In(time.Local).Format("2006-01-02"),
+		DurationMinutes: durationMinutes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, slot := range slots {
+		if !slot.StartTime.Before(from) && !slot.EndTime.After(to) {
+			return &slot, nil
+		}
+	}
+	return nil, nil
+}
+
+// FindFirstSlots is FindFirstSlot for several experts at once, keyed by
+// expert ID. It skips the expert checks of GetAvailableSlots, so it is meant
+// for experts already known to be bookable, such as search results. Cached
+// slots are used where present; for the other experts schedules, off-times,
+// bookings and booking rules are read in one query each.
+func (s *slotService) FindFirstSlots(expertIDs []uuid.UUID, from, to time.Time, durationMinutes int) (map[uuid.UUID]*model.Slot, error) {
+	first := make(map[uuid.UUID]*model.Slot, len(expertIDs))
+	if len(expertIDs) == 0 {
+		return first, nil
+	}
+	startDay, endDay := from.In(time.Local).Format("2006-01-02"), to.In(time.Local).Format("2006-01-02")
+	startDate, _ := time.ParseInLocation("2006-01-02", startDay, time.Local)
+	endDate, _ := time.ParseInLocation("2006-01-02", endDay, time.Local)
+
+	rules, err := s.ruleRepo.GetByExpertIDs(expertIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking rules: %w", err)
+	}
+	for _, expertID := range expertIDs {
+		if rules[expertID] == nil {
+			rules[expertID] = model.DefaultBookingRule(expertID)
+		}
+	}
+
+	slots := make(map[uuid.UUID][]model.Slot, len(expertIDs))
+	var missing []uuid.UUID
+	for _, expertID := range expertIDs {
+		data, err := s.cache.GetAvailability(cache.SlotKey(expertID.String(), startDay, endDay, durationMinutes, defaultSlotGranularity))
+		if err == nil && data != nil {
+			var cached []model.Slot
+			if err := json.Unmarshal(data, &cached); err == nil {
+				slots[expertID] = cached
+				continue
+			}
+		}
+		missing = append(missing, expertID)
+	}
+
+	if len(missing) > 0 {
+		rangeStart, rangeEnd := startDate, endDate.AddDate(0, 0, 1)
+		var padding time.Duration
+		for _, expertID := range missing {
+			if p := bookingPadding(rules[expertID]); p > padding {
+				padding = p
+			}
+		}
+		offTimes, err := s.offTimeRepo.GetOverlappingByExpertIDs(missing, rangeStart, rangeEnd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load off-times: %w", err)
+		}
+		bookings, err := s.bookingRepo.GetActiveByExpertIDsAndRange(missing, rangeStart.Add(-padding), rangeEnd.Add(padding))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load bookings: %w", err)
+		}
+		schedules, err := s.scheduleRepo.GetByExpertIDs(missing)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load schedules: %w", err)
+		}
+		schedulesByExpert := make(map[string][]*model.Schedule)
+		for _, schedule := range schedules {
+			schedulesByExpert[schedule.ExpertID] = append(schedulesByExpert[schedule.ExpertID], schedule)
+		}
+
+		now := time.Now()
+		for _, expertID := range missing {
+			in, err := s.loadSlotInputs(expertID, startDate, endDate, offTimes[expertID], bookings[expertID], schedulesByExpert[expertID.String()])
+			if err != nil {
+				return nil, err
+			}
+			slots[expertID] = buildSlots(in, startDate, endDate, durationMinutes, defaultSlotGranularity, rules[expertID], now)
+			// A failed cache write only costs a computation next time
+			if data, err := json.Marshal(slots[expertID]); err == nil {
+				s.cache.SetAvailability(cache.SlotKey(expertID.String(), startDay, endDay, durationMinutes, defaultSlotGranularity), data)
+			}
+		}
+	}
+
+	now := time.Now()
+	for _, expertID := range expertIDs {
+		for _, slot := range filterByNotice(slots[expertID], rules[expertID], now) {
+			if !slot.StartTime.Before(from) && !slot.EndTime.After(to) {
+				slot := slot
+				first[expertID] = &slot
+				break
+			}
+		}
+	}
+	return first, nil
+}
+
+// slotInputs is what slot computation reads about one expert over a range of days
+type slotInputs struct {
+	// blocked holds the off-times and observed holidays
+	blocked  []interval
+	bookings []*model.BookedInterval
+	// schedules holds the weekly schedule by weekday
+	schedules map[int][]*model.Schedule
+	// availabilities holds the free one-off slots and rule occurrences by date
+	availabilities map[string][]*model.Availability
+}
+
+func (s *slotService) computeSlots(expertID uuid.UUID, startDate, endDate time.Time, durationMinutes, granularity int, rule *model.BookingRule) ([]model.Slot, error) {
+	rangeStart := startDate
+	rangeEnd := endDate.AddDate(0, 0, 1)
+
+	offTimes, err := s.offTimeRepo.GetOverlapping(expertID, rangeStart, rangeEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load off-times: %w", err)
+	}
+	padding := bookingPadding(rule)
+	bookings, err := s.bookingRepo.GetActiveByExpertIDAndRange(expertID, rangeStart.Add(-padding), rangeEnd.Add(padding))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load bookings: %w", err)
+	}
+	schedules, err := s.scheduleRepo.GetByExpertID(expertID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load schedules: %w", err)
+	}
+
+	in, err := s.loadSlotInputs(expertID, startDate, endDate, offTimes, bookings, schedules)
+	if err != nil {
+		return nil, err
+	}
+	return buildSlots(in, startDate, endDate, durationMinutes, granularity, rule, time.Now()), nil
+}
+
+// loadSlotInputs adds the expert's holidays and free availability over
+// [startDate, endDate] to the off-times, bookings and schedules the caller read
+func (s *slotService) loadSlotInputs(expertID uuid.UUID, startDate, endDate time.Time, offTimes []*model.OffTime, bookings []*model.BookedInterval, schedules []*model.Schedule) (*slotInputs, error) {
+	holidays, err := s.holidaySvc.HolidayOffTimes(expertID, startDate, endDate.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	isBooked := false
+	availabilities, err := s.availabilitySvc.GetAvailabilities(expertID.String(), startDate, endDate, &isBooked)
+	if err != nil {
+		return nil, err
+	}
+
+	in := newSlotInputs(schedules, availabilities)
+	in.bookings = bookings
+	for _, offTimes := range [][]*model.OffTime{offTimes, holidays} {
+		for _, offTime := range offTimes {
+			in.blocked = append(in.blocked, interval{offTime.StartDateTime, offTime.EndDateTime})
+		}
+	}
+	return in, nil
+}
+
+func newSlotInputs(schedules []*model.Schedule, availabilities []*model.Availability) *slotInputs {
+	in := &slotInputs{
+		schedules:      make(map[int][]*model.Schedule),
+		availabilities: make(map[string][]*model.Availability),
+	}
+	for _, schedule := range schedules {
+		in.schedules[schedule.DayOfWeek] = append(in.schedules[schedule.DayOfWeek], schedule)
+	}
+	for _, availability := range availabilities {
+		in.availabilities[availability.Date] = append(in.availabilities[availability.Date], availability)
+	}
+	return in
+}
+
+// buildSlots lists the start times on each day of [startDate, endDate] at
+// which a session of durationMinutes fits in the working windows without
+// touching off-times or, with the rule's buffers, bookings. Days past the
+// rule's advance limit from now and days that reached the rule's booking
+// limit are left out.
+func buildSlots(in *slotInputs, startDate, endDate time.Time, durationMinutes, granularity int, rule *model.BookingRule, now time.Time) []model.Slot {
+	bufferBefore := time.Duration(rule.BufferBeforeMinutes) * time.Minute
+	bufferAfter := time.Duration(rule.BufferAfterMinutes) * time.Minute
+	bookingsPerDay := make(map[string]int)
+	for _, booking := range in.bookings {
+		bookingsPerDay[booking.StartTime.In(time.Local).Format("2006-01-02")]++
+	}
+
+	duration := time.Duration(durationMinutes) * time.Minute
+	step := time.Duration(granularity) * time.Minute
+	latest := now.AddDate(0, 0, rule.MaxAdvanceDays)
+
+	slots := []model.Slot{}
+	for day := startDate; !day.After(endDate); day = day.AddDate(0, 0, 1) {
+		if day.After(latest) {
+			break
+		}
+		if rule.MaxBookingsPerDay > 0 && bookingsPerDay[day.Format("2006-01-02")] >= rule.MaxBookingsPerDay {
+			continue
+		}
+
+		for _, window := range in.windows(day) {
+			for start := alignUp(window.start, day, step); !start.Add(duration).After(window.end); start = start.Add(step) {
+				if start.After(latest) {
+					break
+				}
+				candidate := interval{start, start.Add(duration)}
+				if overlapsAny(candidate, in.blocked) {
+					continue
+				}
+				if conflictsWithBookings(candidate, in.bookings, bufferBefore, bufferAfter) {
+					continue
+				}
+				slots = append(slots, model.Slot{StartTime: candidate.start, EndTime: candidate.end})
+			}
+		}
+	}
+	return slots
+}
+
+// windows merges the weekly schedule and free availability of a day
+func (in *slotInputs) windows(day time.Time) []interval {
+	var windows []interval
+	for _, schedule := range in.schedules[int(day.Weekday())] {
+		if window, ok := clockInterval(day, schedule.StartTime, schedule.EndTime); ok {
+			windows = append(windows, window)
+		}
+	}
+	for _, availability := range in.availabilities[day.Format("2006-01-02")] {
+		if window, ok := clockInterval(day, availability.StartTime, availability.EndTime); ok {
+			windows = append(windows, window)
+		}
+	}
+	return mergeIntervals(windows)
+}
+
+// workingWindows merges the weekly schedule and ad-hoc availability of a day
+func (s *slotService) workingWindows(expertID uuid.UUID, day time.Time) ([]interval, error) {
+	schedules, err := s.scheduleRepo.GetByExpertIDAndDay(expertID.String(), int(day.Weekday()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedules: %w", err)
+	}
+	isBooked := false
+	availabilities, err := s.availabilitySvc.GetAvailabilities(expertID.String(), day, day, &isBooked)
+	if err != nil {
+		return nil, err
+	}
+	return newSlotInputs(schedules, availabilities).windows(day), nil
+}
+
+// bookingPadding is how far outside a range bookings can still affect slots
+// in it through the rule's buffers
+func bookingPadding(rule *model.BookingRule) time.Duration {
+	return time.Duration(rule.BufferBeforeMinutes+rule.BufferAfterMinutes) * time.Minute
+}
+
+// CheckBookingWindow kiểm tra chuyên gia có làm việc trong toàn bộ khoảng thời gian
+// yêu cầu không. Lịch đã đặt do booking-service tự kiểm tra nên không xét ở đây.
+func (s *slotService) CheckBookingWindow(req *model.CheckBookingWindowRequest) (*model.BookingWindowCheck, error) {
+	expertUUID, err := uuid.Parse(req.ExpertID)
+	if err != nil {
+		return nil, apperr.Validation("invalid_expert_id")
+	}
+	expert, err := s.expertRepo.GetByID(expertUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expert: %w", err)
+	}
+	if expert == nil {
+		return &model.BookingWindowCheck{Reason: model.WindowReasonExpertNotFound}, nil
+	}
+	if !expert.IsAvailable {
+		return &model.BookingWindowCheck{Reason: model.WindowReasonExpertUnavailable}, nil
+	}
+	verified, err := s.expertRepo.IsEmailVerified(expertUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check expert email verification: %w", err)
+	}
+	if !verified {
+		return &model.BookingWindowCheck{Reason: model.WindowReasonExpertUnverified}, nil
+	}
+	approved, err := s.expertRepo.IsApproved(expertUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check expert approval: %w", err)
+	}
+	if !approved {
+		return &model.BookingWindowCheck{Reason: model.WindowReasonExpertNotApproved}, nil
+	}
+
+	start := req.StartTime.In(time.Local)
+	requested := interval{start, start.Add(time.Duration(req.DurationMinutes) * time.Minute)}
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.Local)
+
+	windows, err := s.workingWindows(expertUUID, day)
+	if err != nil {
+		return nil, err
+	}
+	inside := false
+	for _, window := range windows {
+		if !requested.start.Before(window.start) && !requested.end.After(window.end) {
+			inside = true
+			break
+		}
+	}
+	if !inside {
+		return &model.BookingWindowCheck{Reason: model.WindowReasonOutsideWorkingHours}, nil
+	}
+
+	offTimes, err := s.offTimeRepo.GetOverlapping(expertUUID, requested.start, requested.end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check off-times: %w", err)
+	}
+	// Observed holidays block bookings like off-times
+	holidays, err := s.holidaySvc.HolidayOffTimes(expertUUID, requested.start, requested.end)
+	if err != nil {
+		return nil, err
+	}
+	if len(offTimes) > 0 || len(holidays) > 0 {
+		return &model.BookingWindowCheck{Reason: model.WindowReasonOffTime}, nil
+	}
+
+	return &model.BookingWindowCheck{Available: true}, nil
+}
+
+func (s *slotService) GetBookingRule(expertID string) (*model.BookingRule, error) {
+	expertUUID, err := uuid.Parse(expertID)
+	if err != nil {
+		return nil, apperr.Validation("invalid_expert_id")
+	}
+	return s.getRule(expertUUID)
+}
+
+func (s *slotService) UpdateBookingRule(expertID string, req *model.UpdateBookingRuleRequest) (*model.BookingRule, error) {
+	expertUUID, err := uuid.Parse(expertID)
+	if err != nil {
+		return nil, apperr.Validation("invalid_expert_id")
+	}
+	expert, err := s.expertRepo.GetByID(expertUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expert: %w", err)
+	}
+	if expert == nil {
+		return nil, apperr.NotFound("expert_not_found")
+	}
+
+	rule, err := s.getRule(expertUUID)
+	if err != nil {
+		return nil, err
+	}
+	if req.BufferBeforeMinutes != nil {
+		rule.BufferBeforeMinutes = *req.BufferBeforeMinutes
+	}
+	if req.BufferAfterMinutes != nil {
+		rule.BufferAfterMinutes = *req.BufferAfterMinutes
+	}
+	if req.MinNoticeMinutes != nil {
+		rule.MinNoticeMinutes = *req.MinNoticeMinutes
+	}
+	if req.MaxAdvanceDays != nil {
+		rule.MaxAdvanceDays = *req.MaxAdvanceDays
+	}
+	if req.MaxBookingsPerDay != nil {
+		rule.MaxBookingsPerDay = *req.MaxBookingsPerDay
+	}
+
+	if err := s.ruleRepo.Upsert(rule); err != nil {
+		return nil, fmt.Errorf("failed to save booking rule: %w", err)
+	}
+
+	s.publisher.Publish(events.TypeBookingRuleChanged, expertID)
+	return rule, nil
+}
+
+func (s *slotService) getRule(expertID uuid.UUID) (*model.BookingRule, error) {
+	rule, err := s.ruleRepo.GetByExpertID(expertID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking rule: %w", err)
+	}
+	if rule == nil {
+		rule = model.DefaultBookingRule(expertID)
+	}
+	return rule, nil
+}
+
+// filterByNotice drops slots that start too soon. It runs on every read
+// because cached results age while the minimum notice window moves forward.
+func filterByNotice(slots []model.Slot, rule *model.BookingRule, now time.Time) []model.Slot {
+	earliest := now.Add(time.Duration(rule.MinNoticeMinutes) * time.Minute)
+	filtered := make([]model.Slot, 0, len(slots))
+	for _, slot := range slots {
+		if !slot.StartTime.Before(earliest) {
+			filtered = append(filtered, slot)
+		}
+	}
+	return filtered
+}
+
+// conflictsWithBookings applies the buffers on both sides: the candidate
+// must not touch a booking's buffers and a booking must not touch the
+// candidate's buffers.
+func conflictsWithBookings(candidate interval, bookings []*model.BookedInterval, bufferBefore, bufferAfter time.Duration) bool {
+	padded := interval{candidate.start.Add(-bufferBefore), candidate.end.Add(bufferAfter)}
+	for _, booking := range bookings {
+		raw := interval{booking.StartTime, booking.EndTime}
+		if padded.overlaps(raw) {
+			return true
+		}
+		if candidate.overlaps(interval{raw.start.Add(-bufferBefore), raw.end.Add(bufferAfter)}) {
+			return true
+		}
+	}
+	return false
+}
+
+func overlapsAny(candidate interval, blocked []interval) bool {
+	for _, b := range blocked {
+		if candidate.overlaps(b) {
+			return true
+		}
+	}
+	return false
+}
+
+func mergeIntervals(intervals []interval) []interval {
+	if len(intervals) == 0 {
+		return nil
+	}
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i].start.Before(intervals[j].start) })
+
+	merged := []interval{intervals[0]}
+	for _, current := range intervals[1:] {
+		last := &merged[len(merged)-1]
+		if !current.start.After(last.end) {
+			if current.end.After(last.end) {
+				last.end = current.end
+			}
+			continue
+		}
+		merged = append(merged, current)
+	}
+	return merged
+}
+
+// alignUp rounds t up to the next multiple of step counted from midnight
+func alignUp(t, day time.Time, step time.Duration) time.Time {
+	offset := t.Sub(day)
+	if rem := offset % step; rem != 0 {
+		offset += step - rem
+	}
+	return day.Add(offset)
+}
+
+// clockInterval turns "HH:MM" or "HH:MM:SS" clock strings into an interval on day
+func clockInterval(day time.Time, startClock, endClock string) (interval, bool) {
+	start, err := atClock(day, startClock)
+	if err != nil {
+		return interval{}, false
+	}
+	end, err := atClock(day, endClock)
+	if err != nil || !end.After(start) {
+		return interval{}, false
+	}
+	return interval{start, end}, true
+}
+
+func atClock(day time.Time, clock string) (time.Time, error) {
+	var t time.Time
+	var err error
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		if t, err = time.Parse(layout, clock); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), t.Second(), 0, day.Location()), nil
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"expert-service/internal/model"
+)
+
+func TestBuildSlots(t *testing.T) {
+	at := func(value string) time.Time {
+		parsed, err := time.ParseInLocation("2006-01-02 15:04", value, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+	// 2026-01-05 is a Monday
+	monday := at("2026-01-05 00:00")
+	morning := []*model.Schedule{{DayOfWeek: 1, StartTime: "09:00", EndTime: "11:00"}}
+
+	tests := []struct {
+		name           string
+		schedules      []*model.Schedule
+		availabilities []*model.Availability
+		blocked        []interval
+		bookings       []*model.BookedInterval
+		endDate        time.Time
+		granularity    int
+		rule           model.BookingRule
+		now            string
+		// Start of each expected one-hour slot
+		want []string
+	}{
+		{
+			name:        "weekly schedule",
+			schedules:   morning,
+			granularity: 30,
+			want:        []string{"2026-01-05 09:00", "2026-01-05 09:30", "2026-01-05 10:00"},
+		},
+		{
+			name:        "slots start on the granularity",
+			schedules:   []*model.Schedule{{DayOfWeek: 1, StartTime: "09:10", EndTime: "11:00"}},
+			granularity: 30,
+			want:        []string{"2026-01-05 09:30", "2026-01-05 10:00"},
+		},
+		{
+			name:           "availability extends the schedule",
+			schedules:      []*model.Schedule{{DayOfWeek: 1, StartTime: "09:00", EndTime: "10:00"}},
+			availabilities: []*model.Availability{{Date: "2026-01-05", StartTime: "10:00", EndTime: "11:00"}},
+			granularity:    30,
+			want:           []string{"2026-01-05 09:00", "2026-01-05 09:30", "2026-01-05 10:00"},
+		},
+		{
+			name:           "availability on a day without schedule",
+			availabilities: []*model.Availability{{Date: "2026-01-06", StartTime: "14:00", EndTime: "15:00"}},
+			endDate:        at("2026-01-06 00:00"),
+			granularity:    30,
+			want:           []string{"2026-01-06 14:00"},
+		},
+		{
+			name:        "off-time blocks overlapping slots",
+			schedules:   morning,
+			blocked:     []interval{{at("2026-01-05 09:30"), at("2026-01-05 10:00")}},
+			granularity: 30,
+			want:        []string{"2026-01-05 10:00"},
+		},
+		{
+			name:        "bookings are kept apart by the buffers",
+			schedules:   []*model.Schedule{{DayOfWeek: 1, StartTime: "09:00", EndTime: "12:00"}},
+			bookings:    []*model.BookedInterval{{StartTime: at("2026-01-05 09:00"), EndTime: at("2026-01-05 10:00")}},
+			granularity: 30,
+			rule:        model.BookingRule{BufferAfterMinutes: 30},
+			want:        []string{"2026-01-05 10:30", "2026-01-05 11:00"},
+		},
+		{
+			name:        "day at its booking limit is skipped",
+			schedules:   morning,
+			bookings:    []*model.BookedInterval{{StartTime: at("2026-01-05 15:00"), EndTime: at("2026-01-05 16:00")}},
+			granularity: 30,
+			rule:        model.BookingRule{MaxBookingsPerDay: 1},
+			want:        []string{},
+		},
+		{
+			name: "slots past the advance limit are left out",
+			schedules: []*model.Schedule{
+				{DayOfWeek: 1, StartTime: "09:00", EndTime: "11:00"},
+				{DayOfWeek: 2, StartTime: "09:00", EndTime: "11:00"},
+			},
+			endDate:     at("2026-01-06 00:00"),
+			granularity: 30,
+			rule:        model.BookingRule{MaxAdvanceDays: 1},
+			now:         "2026-01-04 09:30",
+			want:        []string{"2026-01-05 09:00", "2026-01-05 09:30"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := newSlotInputs(tt.schedules, tt.availabilities)
+			in.blocked = tt.blocked
+			in.bookings = tt.bookings
+			endDate := tt.endDate
+			if endDate.IsZero() {
+				endDate = monday
+			}
+			rule := tt.rule
+			if rule.MaxAdvanceDays == 0 {
+				rule.MaxAdvanceDays = 30
+			}
+			now := "2026-01-01 00:00"
+			if tt.now != "" {
+				now = tt.now
+			}
+
+			slots := buildSlots(in, monday, endDate, 60, tt.granularity, &rule, at(now))
+			got := []string{}
+			for _, slot := range slots {
+				got = append(got, slot.StartTime.Format("2006-01-02 15:04"))
+				if slot.EndTime.Sub(slot.StartTime) != time.Hour {
+					t.Errorf("slot %s lasts %s, want 1h", got[len(got)-1], slot.EndTime.Sub(slot.StartTime))
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildSlots() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+-- Per-expert constraints used by the bookable slot computation
+CREATE TABLE IF NOT EXISTS expert_booking_rules (
+    expert_id UUID PRIMARY KEY REFERENCES experts(id) ON DELETE CASCADE,
+    buffer_before_minutes INTEGER NOT NULL DEFAULT 0 CHECK (buffer_before_minutes >= 0),
+    buffer_after_minutes INTEGER NOT NULL DEFAULT 0 CHECK (buffer_after_minutes >= 0),
+    min_notice_minutes INTEGER NOT NULL DEFAULT 60 CHECK (min_notice_minutes >= 0),
+    max_advance_days INTEGER NOT NULL DEFAULT 90 CHECK (max_advance_days > 0),
+    max_bookings_per_day INTEGER NOT NULL DEFAULT 0 CHECK (max_bookings_per_day >= 0), -- 0 = unlimited
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_expert_booking_rules_updated_at
+    BEFORE UPDATE ON expert_booking_rules
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,19 @@
+-- Consultation types offered by an expert, each with a fixed duration and price
+CREATE TABLE IF NOT EXISTS expert_services (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    expert_id UUID NOT NULL REFERENCES experts(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    duration_minutes INTEGER NOT NULL CHECK (duration_minutes BETWEEN 15 AND 480),
+    price DECIMAL(10,2) NOT NULL DEFAULT 0 CHECK (price >= 0),
+    meeting_type VARCHAR(20) NOT NULL DEFAULT 'online' CHECK (meeting_type IN ('online', 'offline')),
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_expert_services_expert_id ON expert_services(expert_id) WHERE is_active = true;
+
+CREATE TRIGGER update_expert_services_updated_at
+    BEFORE UPDATE ON expert_services
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,11 @@
+-- Expert replies and admin moderation for reviews. Hidden reviews are
+-- excluded from experts.rating and experts.total_reviews.
+ALTER TABLE reviews
+    ADD COLUMN IF NOT EXISTS expert_reply TEXT,
+    ADD COLUMN IF NOT EXISTS replied_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'visible'
+        CHECK (status IN ('visible', 'hidden', 'flagged')),
+    ADD COLUMN IF NOT EXISTS moderation_reason TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_reviews_expert_id_created_at ON reviews(expert_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_reviews_status ON reviews(status) WHERE status <> 'visible';
@@ -0,0 +1,19 @@
+-- Ad-hoc availability slots of an expert. Redis only caches them per day.
+CREATE TABLE IF NOT EXISTS expert_availabilities (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    expert_id UUID NOT NULL REFERENCES experts(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    is_booked BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_time > start_time),
+    UNIQUE (expert_id, date, start_time)
+);
+
+CREATE INDEX idx_expert_availabilities_expert_date ON expert_availabilities(expert_id, date);
+
+CREATE TRIGGER update_expert_availabilities_updated_at
+    BEFORE UPDATE ON expert_availabilities
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,31 @@
+-- Recurring availability stored as a rule and expanded on read, instead of
+-- one row per occurrence. Editing "from a date forward" closes the current
+-- rule the day before and starts a new one, so past occurrences are kept.
+CREATE TABLE IF NOT EXISTS expert_availability_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    expert_id UUID NOT NULL REFERENCES experts(id) ON DELETE CASCADE,
+    days_of_week INTEGER[] NOT NULL, -- 0=Sunday, 6=Saturday
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    effective_from DATE NOT NULL,
+    effective_to DATE, -- NULL = open-ended
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_time > start_time),
+    CHECK (effective_to IS NULL OR effective_to >= effective_from)
+);
+
+CREATE INDEX idx_expert_availability_rules_expert_id ON expert_availability_rules(expert_id);
+
+CREATE TRIGGER update_expert_availability_rules_updated_at
+    BEFORE UPDATE ON expert_availability_rules
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Dates on which a rule does not apply
+CREATE TABLE IF NOT EXISTS expert_availability_rule_exceptions (
+    rule_id UUID NOT NULL REFERENCES expert_availability_rules(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (rule_id, date)
+);
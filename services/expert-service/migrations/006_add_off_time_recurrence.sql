@@ -0,0 +1,22 @@
+-- Recurrence rules for off-times. A recurring off-time keeps its first
+-- occurrence in start_datetime/end_datetime; later occurrences are expanded
+-- on read. Rows marked is_recurring before this migration have no frequency
+-- and are treated as weekly on the weekday of their first occurrence.
+ALTER TABLE expert_off_times
+    ADD COLUMN IF NOT EXISTS recurrence_frequency VARCHAR(10)
+        CHECK (recurrence_frequency IN ('daily', 'weekly', 'monthly')),
+    ADD COLUMN IF NOT EXISTS recurrence_interval INTEGER NOT NULL DEFAULT 1
+        CHECK (recurrence_interval > 0),
+    ADD COLUMN IF NOT EXISTS recurrence_days_of_week INTEGER[], -- weekly only, 0=Sunday, 6=Saturday
+    ADD COLUMN IF NOT EXISTS recurrence_until DATE; -- NULL = open-ended
+
+CREATE INDEX IF NOT EXISTS idx_expert_off_times_expert_id_start ON expert_off_times(expert_id, start_datetime);
+
+-- Dates on which a recurring off-time does not occur
+CREATE TABLE IF NOT EXISTS expert_off_time_exceptions (
+    off_time_id UUID NOT NULL REFERENCES expert_off_times(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (off_time_id, date)
+);
@@ -0,0 +1,42 @@
+-- Public holiday calendars. Bundled calendars (internal/holidays/data) are
+-- seeded on startup when missing; admins can edit them afterwards, and a
+-- seeded calendar is never overwritten by the bundled file again.
+CREATE TABLE IF NOT EXISTS holiday_calendars (
+    code VARCHAR(20) PRIMARY KEY, -- e.g. 'VN'
+    name VARCHAR(100) NOT NULL,
+    country_code VARCHAR(2) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_holiday_calendars_updated_at
+    BEFORE UPDATE ON holiday_calendars
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE IF NOT EXISTS holidays (
+    calendar_code VARCHAR(20) NOT NULL REFERENCES holiday_calendars(code) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    PRIMARY KEY (calendar_code, date)
+);
+
+-- Calendars an expert observes. Experts without a row observe the default
+-- calendar (DEFAULT_HOLIDAY_CALENDAR); an empty array opts out of all of them.
+CREATE TABLE IF NOT EXISTS expert_holiday_calendars (
+    expert_id UUID PRIMARY KEY REFERENCES experts(id) ON DELETE CASCADE,
+    calendar_codes TEXT[] NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_expert_holiday_calendars_updated_at
+    BEFORE UPDATE ON expert_holiday_calendars
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Holidays on which an expert works anyway
+CREATE TABLE IF NOT EXISTS expert_holiday_overrides (
+    expert_id UUID NOT NULL REFERENCES experts(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    note TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (expert_id, date)
+);
@@ -0,0 +1,42 @@
+-- Expert onboarding. A user applies, attaches documents and submits them;
+-- an admin reviews the application and approving it creates the expert
+-- profile and grants the user the expert role. Only experts whose account
+-- holds the expert role are listed in search and can be booked.
+
+CREATE TABLE IF NOT EXISTS expert_applications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(30) NOT NULL DEFAULT 'applied'
+        CHECK (status IN ('applied', 'documents_submitted', 'under_review', 'approved', 'rejected')),
+    specialization VARCHAR(255) NOT NULL,
+    experience_years INTEGER NOT NULL DEFAULT 0,
+    hourly_rate DECIMAL(10,2) NOT NULL,
+    certifications TEXT[] NOT NULL DEFAULT '{}',
+    bio TEXT,
+    reviewer_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    review_notes TEXT,
+    rejection_reason TEXT,
+    expert_id UUID REFERENCES experts(id) ON DELETE SET NULL,
+    submitted_at TIMESTAMP,
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_expert_applications_status ON expert_applications(status, submitted_at);
+
+CREATE TRIGGER update_expert_applications_updated_at
+    BEFORE UPDATE ON expert_applications
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Proof of qualifications attached to an application, e.g. a scanned
+-- licence or diploma
+CREATE TABLE IF NOT EXISTS expert_application_documents (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    application_id UUID NOT NULL REFERENCES expert_applications(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    url TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_expert_application_documents_application ON expert_application_documents(application_id);
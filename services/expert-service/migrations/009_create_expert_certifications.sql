@@ -0,0 +1,52 @@
+-- Structured certifications. Experts describe each certification and upload
+-- proof; an admin verifies it. Only verified, unexpired certifications are
+-- public: experts.certifications now holds just their names, kept in sync by
+-- the service, so search and profiles keep reading that column.
+CREATE TABLE IF NOT EXISTS expert_certifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    expert_id UUID NOT NULL REFERENCES experts(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    issuer VARCHAR(255) NOT NULL DEFAULT '',
+    credential_number VARCHAR(100),
+    issued_on DATE,
+    expires_on DATE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'verified', 'rejected')),
+    verified_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    verified_at TIMESTAMP,
+    rejection_reason TEXT,
+    -- Set when the expert was warned about the upcoming expiry
+    expiry_warned_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (expires_on IS NULL OR issued_on IS NULL OR expires_on >= issued_on)
+);
+
+CREATE INDEX IF NOT EXISTS idx_expert_certifications_expert ON expert_certifications(expert_id);
+CREATE INDEX IF NOT EXISTS idx_expert_certifications_status ON expert_certifications(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_expert_certifications_expiry ON expert_certifications(expires_on) WHERE status = 'verified';
+
+CREATE TRIGGER update_expert_certifications_updated_at
+    BEFORE UPDATE ON expert_certifications
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Proof files; the content lives in the file store under storage_key
+CREATE TABLE IF NOT EXISTS expert_certification_files (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    certification_id UUID NOT NULL REFERENCES expert_certifications(id) ON DELETE CASCADE,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    storage_key TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_expert_certification_files_certification ON expert_certification_files(certification_id);
+
+-- Existing certification names were already shown publicly, so they are
+-- carried over as verified
+INSERT INTO expert_certifications (expert_id, name, status, verified_at)
+SELECT e.id, c.name, 'verified', CURRENT_TIMESTAMP
+FROM experts e, unnest(e.certifications) AS c(name)
+WHERE c.name <> ''
+  AND NOT EXISTS (SELECT 1 FROM expert_certifications ec WHERE ec.expert_id = e.id);
@@ -0,0 +1,10 @@
+-- Booked occurrences of recurring availability. Occurrences are expanded on
+-- read and identified by their rule and date, so only the booked ones are
+-- stored. Splitting a rule moves the bookings from the split date on to the
+-- new rule, like its exceptions.
+CREATE TABLE IF NOT EXISTS expert_availability_rule_bookings (
+    rule_id UUID NOT NULL REFERENCES expert_availability_rules(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (rule_id, date)
+);
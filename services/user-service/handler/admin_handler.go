@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"net/http"
+	"services/user-service/model"
+	"services/user-service/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AdminListUsers searches users by email, name or phone ("q") and filters by
+// role, status and requested_role
+func AdminListUsers(adminService service.AdminService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+		if err != nil || page < 1 {
+			page = 1
+		}
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+		if err != nil || limit < 1 || limit > 100 {
+			limit = 20
+		}
+		filter := model.UserFilter{
+			Query:         c.Query("q"),
+			Role:          model.UserRole(c.Query("role")),
+			Status:        model.UserStatus(c.Query("status")),
+			RequestedRole: model.UserRole(c.Query("requested_role")),
+			Page:          page,
+			Limit:         limit,
+		}
+		users, total, err := adminService.ListUsers(filter)
+		if err != nil {
+			respondFailed(c, err, "list_users_failed")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"users": users,
+			"pagination": gin.H{
+				"page":        page,
+				"limit":       limit,
+				"total":       total,
+				"total_pages": (total + int64(limit) - 1) / int64(limit),
+			},
+		})
+	}
+}
+
+func AdminGetUser(adminService service.AdminService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid_user_id")
+			return
+		}
+		user, err := adminService.GetUser(userID)
+		if err != nil {
+			respondFailed(c, err, "get_user_failed")
+			return
+		}
+		c.JSON(http.StatusOK, user)
+	}
+}
+
+func AdminChangeRole(adminService service.AdminService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		adminID, userID, ok := adminAndTarget(c)
+		if !ok {
+			return
+		}
+		var req model.ChangeRoleRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondInvalid(c, err)
+			return
+		}
+		user, err := adminService.ChangeRole(adminID, userID, model.UserRole(req.Role), requestInfo(c))
+		if err != nil {
+			respondFailed(c, err, "change_role_failed")
+			return
+		}
+		c.JSON(http.StatusOK, user)
+	}
+}
+
+func AdminSuspendUser(adminService service.AdminService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		adminID, userID, ok := adminAndTarget(c)
+		if !ok {
+			return
+		}
+		var req model.SuspendUserRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondInvalid(c, err)
+			return
+		}
+		user, err := adminService.Suspend(adminID, userID, req.Reason, requestInfo(c))
+		if err != nil {
+			respondFailed(c, err, "suspend_user_failed")
+			return
+		}
+		c.JSON(http.StatusOK, user)
+	}
+}
+
+func AdminReactivateUser(adminService service.AdminService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid_user_id")
+			return
+		}
+		user, err := adminService.Reactivate(userID, requestInfo(c))
+		if err != nil {
+			respondFailed(c, err, "reactivate_user_failed")
+			return
+		}
+		c.JSON(http.StatusOK, user)
+	}
+}
+
+// AdminForcePasswordReset signs the user out and emails them a reset link;
+// they cannot log in until they use it
+func AdminForcePasswordReset(passwordService service.PasswordService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid_user_id")
+			return
+		}
+		if err := passwordService.ForcePasswordReset(userID, requestInfo(c)); err != nil {
+			respondFailed(c, err, "force_password_reset_failed")
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{"message": message(c, "password_reset_forced")})
+	}
+}
+
+// adminAndTarget parses the calling admin and the user in the path
+func adminAndTarget(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	adminID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "invalid_user_id")
+		return uuid.Nil, uuid.Nil, false
+	}
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "invalid_user_id")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return adminID, userID, true
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"services/user-service/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// UploadAvatar replaces the caller's profile picture with the JPEG or PNG in
+// the multipart field "file"
+func UploadAvatar(avatarService service.AvatarService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.GetString("userID"))
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid_user_id")
+			return
+		}
+		// Leave room for the multipart framing around the file
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, service.MaxAvatarSize+1<<20)
+		header, err := c.FormFile("file")
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				respondErr(c, service.ErrImageTooLarge)
+				return
+			}
+			respondError(c, http.StatusBadRequest, "file_required")
+			return
+		}
+		// The content is checked as well; this rejects obvious mistakes early
+		switch header.Header.Get("Content-Type") {
+		case "", "image/jpeg", "image/png":
+		default:
+			respondErr(c, service.ErrUnsupportedImage)
+			return
+		}
+		if header.Size > service.MaxAvatarSize {
+			respondErr(c, service.ErrImageTooLarge)
+			return
+		}
+		src, err := header.Open()
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "read_file_failed")
+			return
+		}
+		defer src.Close()
+		data, err := io.ReadAll(src)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "read_file_failed")
+			return
+		}
+
+		res, err := avatarService.Upload(userID, data)
+		if err != nil {
+			respondFailed(c, err, "save_avatar_failed")
+			return
+		}
+		c.JSON(http.StatusOK, res)
+	}
+}
+
+func DeleteAvatar(avatarService service.AvatarService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.GetString("userID"))
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid_user_id")
+			return
+		}
+		user, err := avatarService.Remove(userID)
+		if err != nil {
+			respondFailed(c, err, "remove_avatar_failed")
+			return
+		}
+		c.JSON(http.StatusOK, user)
+	}
+}
+
+// GetAvatar serves a stored picture. A URL always shows the same picture,
+// so it may be cached indefinitely.
+func GetAvatar(avatarService service.AvatarService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.Param("user_id"))
+		if err != nil {
+			respondErr(c, service.ErrAvatarNotFound)
+			return
+		}
+		avatarID, err := uuid.Parse(c.Param("avatar_id"))
+		if err != nil {
+			respondErr(c, service.ErrAvatarNotFound)
+			return
+		}
+		content, err := avatarService.Open(userID, avatarID, c.Param("file"))
+		if err != nil {
+			respondFailed(c, err, "read_avatar_failed")
+			return
+		}
+		defer content.Close()
+
+		c.DataFromReader(http.StatusOK, -1, "image/jpeg", content, map[string]string{
+			"Cache-Control":          "public, max-age=31536000, immutable",
+			"X-Content-Type-Options": "nosniff",
+		})
+	}
+}
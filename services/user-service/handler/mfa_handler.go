@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"net/http"
+	"services/user-service/model"
+	"services/user-service/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// LoginMFA completes a login with an authenticator or recovery code
+func LoginMFA(mfaService service.MFAService, tokenService service.TokenService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req model.LoginMFARequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondInvalid(c, err)
+			return
+		}
+		if req.Code == "" && req.RecoveryCode == "" {
+			respondError(c, http.StatusBadRequest, "mfa_code_required")
+			return
+		}
+		user, err := mfaService.VerifyLogin(req.MFAToken, req.Code, req.RecoveryCode, requestInfo(c))
+		if err != nil {
+			respondFailed(c, err, "login_failed")
+			return
+		}
+		respondWithTokens(c, tokenService, user, nil)
+	}
+}
+
+// LoginMFAEnroll starts enrolment for a user whose role requires MFA but who
+// has no factor yet, using the MFA token from login
+func LoginMFAEnroll(mfaService service.MFAService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req model.MFATokenRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondInvalid(c, err)
+			return
+		}
+		user, err := mfaService.EnrollmentUser(req.MFAToken)
+		if err != nil {
+			respondFailed(c, err, "start_mfa_enrollment_failed")
+			return
+		}
+		enrollment, err := mfaService.Enroll(user.ID)
+		if err != nil {
+			respondFailed(c, err, "start_mfa_enrollment_failed")
+			return
+		}
+		c.JSON(http.StatusOK, enrollment)
+	}
+}
+
+// LoginMFAConfirm finishes enrolment started from login and logs the user in
+func LoginMFAConfirm(mfaService service.MFAService, tokenService service.TokenService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req model.ConfirmMFARequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondInvalid(c, err)
+			return
+		}
+		if req.MFAToken == "" {
+			respondError(c, http.StatusBadRequest, "mfa_token_required")
+			return
+		}
+		user, err := mfaService.EnrollmentUser(req.MFAToken)
+		if err != nil {
+			respondFailed(c, err, "enable_mfa_failed")
+			return
+		}
+		codes, err := mfaService.ConfirmEnrollment(user.ID, req.Code, requestInfo(c))
+		if err != nil {
+			respondFailed(c, err, "enable_mfa_failed")
+			return
+		}
+		respondWithTokens(c, tokenService, user, gin.H{"recovery_codes": codes})
+	}
+}
+
+func GetMFAStatus(mfaService service.MFAService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.GetString("userID"))
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid_user_id")
+			return
+		}
+		status, err := mfaService.Status(userID)
+		if err != nil {
+			respondFailed(c, err, "get_mfa_status_failed")
+			return
+		}
+		c.JSON(http.StatusOK, status)
+	}
+}
+
+// EnrollMFA returns a new secret and its otpauth URI for the authenticator app
+func EnrollMFA(mfaService service.MFAService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.GetString("userID"))
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid_user_id")
+			return
+		}
+		enrollment, err := mfaService.Enroll(userID)
+		if err != nil {
+			respondFailed(c, err, "start_mfa_enrollment_failed")
+			return
+		}
+		c.JSON(http.StatusOK, enrollment)
+	}
+}
+
+// ConfirmMFA enables the factor with a first code and returns the recovery
+// codes
+func ConfirmMFA(mfaService service.MFAService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.GetString("userID"))
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid_user_id")
+			return
+		}
+		var req model.ConfirmMFARequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondInvalid(c, err)
+			return
+		}
+		codes, err := mfaService.ConfirmEnrollment(userID, req.Code, requestInfo(c))
+		if err != nil {
+			respondFailed(c, err, "enable_mfa_failed")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": message(c, "mfa_enabled"), "recovery_codes": codes})
+	}
+}
+
+func DisableMFA(mfaService service.MFAService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.GetString("userID"))
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid_user_id")
+			return
+		}
+		var req model.DisableMFARequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondInvalid(c, err)
+			return
+		}
+		if req.Code == "" && req.RecoveryCode == "" {
+			respondError(c, http.StatusBadRequest, "mfa_code_required")
+			return
+		}
+		if err := mfaService.Disable(userID, req.Password, req.Code, req.RecoveryCode, requestInfo(c)); err != nil {
+			respondFailed(c, err, "disable_mfa_failed")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": message(c, "mfa_disabled")})
+	}
+}
+
+func RegenerateRecoveryCodes(mfaService service.MFAService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.GetString("userID"))
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid_user_id")
+			return
+		}
+		var req model.RegenerateRecoveryCodesRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondInvalid(c, err)
+			return
+		}
+		codes, err := mfaService.RegenerateRecoveryCodes(userID, req.Code, requestInfo(c))
+		if err != nil {
+			respondFailed(c, err, "regenerate_recovery_codes_failed")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+	}
+}
+
+// AdminResetMFA removes a user's factor, e.g. when they lost their device
+// and recovery codes
+func AdminResetMFA(mfaService service.MFAService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid_user_id")
+			return
+		}
+		if err := mfaService.Reset(userID, requestInfo(c)); err != nil {
+			respondFailed(c, err, "reset_mfa_failed")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": message(c, "mfa_reset")})
+	}
+}
+
+func ListMFAPolicies(mfaService service.MFAService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policies, err := mfaService.ListPolicies()
+		if err != nil {
+			respondFailed(c, err, "list_mfa_policies_failed")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"policies": policies})
+	}
+}
+
+// UpdateMFAPolicy makes MFA mandatory or optional for a role
+func UpdateMFAPolicy(mfaService service.MFAService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		adminID, err := uuid.Parse(c.GetString("userID"))
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid_user_id")
+			return
+		}
+		var req model.UpdateMFAPolicyRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondInvalid(c, err)
+			return
+		}
+		role := model.UserRole(c.Param("role"))
+		if err := mfaService.SetPolicy(adminID, role, *req.Required, requestInfo(c)); err != nil {
+			respondFailed(c, err, "update_mfa_policy_failed")
+			return
+		}
+		c.JSON(http.StatusOK, model.MFARolePolicy{Role: role, Required: *req.Required})
+	}
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+	"services/user-service/model"
+	"services/user-service/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetNotificationSettings returns the caller's notification settings,
+// including the preference for every event and channel
+func GetNotificationSettings(notificationService service.NotificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.GetString("userID"))
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid_user_id")
+			return
+		}
+		settings, err := notificationService.GetSettings(userID)
+		if err != nil {
+			respondFailed(c, err, "get_notification_settings_failed")
+			return
+		}
+		c.JSON(http.StatusOK, settings)
+	}
+}
+
+// UpdateNotificationSettings replaces the caller's reminder offsets, quiet
+// hours, time zone, digest mode and Telegram chat
+func UpdateNotificationSettings(notificationService service.NotificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.GetString("userID"))
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid_user_id")
+			return
+		}
+		var req model.UpdateNotificationSettingsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondInvalid(c, err)
+			return
+		}
+		settings, err := notificationService.UpdateSettings(userID, req)
+		if err != nil {
+			respondFailed(c, err, "update_notification_settings_failed")
+			return
+		}
+		c.JSON(http.StatusOK, settings)
+	}
+}
+
+// UpdateNotificationPreferences turns events on or off per channel
+func UpdateNotificationPreferences(notificationService service.NotificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.GetString("userID"))
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid_user_id")
+			return
+		}
+		var req model.UpdateNotificationPreferencesRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondInvalid(c, err)
+			return
+		}
+		settings, err := notificationService.UpdatePreferences(userID, req)
+		if err != nil {
+			respondFailed(c, err, "update_notification_settings_failed")
+			return
+		}
+		c.JSON(http.StatusOK, settings)
+	}
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"services/user-service/model"
+	"services/user-service/service"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ExportData downloads everything the services store about the caller as a
+// ZIP archive of JSON files
+func ExportData(privacyService service.PrivacyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.GetString("userID"))
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid_user_id")
+			return
+		}
+		sendExport(c, privacyService, userID)
+	}
+}
+
+func AdminExportData(privacyService service.PrivacyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid_user_id")
+			return
+		}
+		sendExport(c, privacyService, userID)
+	}
+}
+
+func sendExport(c *gin.Context, privacyService service.PrivacyService, userID uuid.UUID) {
+	archive, err := privacyService.Export(userID, c.GetHeader("Authorization"), requestInfo(c))
+	if err != nil {
+		respondFailed(c, err, "export_data_failed")
+		return
+	}
+	name := fmt.Sprintf("user-data-%s-%s.zip", userID, time.Now().UTC().Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	c.Data(http.StatusOK, "application/zip", archive)
+}
+
+// DeleteProfile erases the caller's account after they confirm their
+// password. Their bookings stay with the experts, without their personal data.
+func DeleteProfile(privacyService service.PrivacyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.GetString("userID"))
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid_user_id")
+			return
+		}
+		var req model.EraseAccountRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondInvalid(c, err)
+			return
+		}
+		if err := privacyService.EraseOwn(userID, req.Password, c.GetHeader("Authorization"), requestInfo(c)); err != nil {
+			respondFailed(c, err, "delete_account_failed")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": message(c, "account_deleted")})
+	}
+}
+
+func AdminEraseUser(privacyService service.PrivacyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		adminID, userID, ok := adminAndTarget(c)
+		if !ok {
+			return
+		}
+		if adminID == userID {
+			respondErr(c, service.ErrCannotModifySelf)
+			return
+		}
+		if err := privacyService.Erase(userID, c.GetHeader("Authorization"), requestInfo(c)); err != nil {
+			respondFailed(c, err, "erase_user_failed")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": message(c, "user_erased")})
+	}
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"services/user-service/utils"
+
+	"booking-system/shared/pkg/apperr"
+	"booking-system/shared/pkg/i18n"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(apperr.JSONFieldName)
+	}
+}
+
+// language is the language responses are written in: the caller's saved
+// preference when they are logged in, otherwise Accept-Language
+func language(c *gin.Context) i18n.Lang {
+	return i18n.Resolve(c.GetString("userLang"), c.GetHeader("Accept-Language"))
+}
+
+// message returns the catalog message code in the caller's language
+func message(c *gin.Context, code string, args ...interface{}) string {
+	return i18n.Message(language(c), code, args...)
+}
+
+// respondError answers with the message code as a problem of the kind that
+// goes with status
+func respondError(c *gin.Context, status int, code string, args ...interface{}) {
+	respondErr(c, apperr.New(apperr.KindFor(status), code, args...))
+}
+
+// respondErr answers with the problem err describes. Errors that are not
+// domain errors are logged and answered with a generic message.
+func respondErr(c *gin.Context, err error) {
+	var domain *apperr.Error
+	if !errors.As(err, &domain) {
+		log.Printf("%s %s failed: %v", c.Request.Method, c.FullPath(), err)
+	}
+	problem := apperr.NewProblem(language(c), err)
+	problem.Instance = c.Request.URL.Path
+	apperr.Write(c.Writer, problem)
+	c.Abort()
+}
+
+// respondFailed answers with the problem err describes when it is a domain
+// error. Other errors are logged and answered with fallback, the code of a
+// message saying what failed.
+func respondFailed(c *gin.Context, err error, fallback string) {
+	var domain *apperr.Error
+	switch {
+	case errors.As(err, &domain):
+		respondErr(c, err)
+	// Repositories report missing users as gorm.ErrRecordNotFound
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		respondErr(c, apperr.NotFound("user_not_found"))
+	default:
+		log.Printf("%s %s failed: %v", c.Request.Method, c.FullPath(), err)
+		respondErr(c, apperr.New(apperr.KindInternal, fallback))
+	}
+}
+
+// respondInvalid answers for a request body or query that failed binding
+// or validation, listing the rejected fields
+func respondInvalid(c *gin.Context, err error) {
+	respondErr(c, utils.ValidationError(err))
+}
@@ -1,29 +1,88 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"os"
 	"services/user-service/middleware"
 	"services/user-service/model"
 	"services/user-service/service"
 	"services/user-service/utils"
+	"strconv"
+
+	"booking-system/shared/pkg/apperr"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
-func RegisterRoutes(r *gin.Engine, userService service.UserService, jwtService service.JWTService) {
+func RegisterRoutes(r *gin.Engine, userService service.UserService, jwtService service.JWTService, tokenService service.TokenService, verificationService service.VerificationService, passwordService service.PasswordService, mfaService service.MFAService, loginGuard service.LoginGuard, adminService service.AdminService, privacyService service.PrivacyService, avatarService service.AvatarService, notificationService service.NotificationService) {
+	auth := middleware.AuthMiddleware(jwtService, tokenService)
+	r.GET("/.well-known/jwks.json", JWKS(jwtService))
 	userGroup := r.Group("/user")
 	{
 		userGroup.POST("/register", Register(userService))
-		userGroup.POST("/login", Login(userService, jwtService))
-		userGroup.POST("/refresh", RefreshToken(jwtService))
-		userGroup.GET("/profile", middleware.AuthMiddleware(jwtService), GetProfile(userService))
-		userGroup.PUT("/profile", middleware.AuthMiddleware(jwtService), UpdateProfile(userService))
-		userGroup.DELETE("/profile", middleware.AuthMiddleware(jwtService), DeleteProfile(userService))
-		userGroup.GET("/bookings", middleware.AuthMiddleware(jwtService), GetBookingHistory())
+		userGroup.GET("/verify-email", VerifyEmail(verificationService))
+		userGroup.POST("/verify-email", VerifyEmail(verificationService))
+		userGroup.POST("/verify-email/resend", ResendVerification(verificationService))
+		userGroup.POST("/login", Login(userService, tokenService, mfaService))
+		userGroup.POST("/login/mfa", LoginMFA(mfaService, tokenService))
+		userGroup.POST("/login/mfa/enroll", LoginMFAEnroll(mfaService))
+		userGroup.POST("/login/mfa/enroll/confirm", LoginMFAConfirm(mfaService, tokenService))
+		userGroup.GET("/unlock-account", UnlockAccount(loginGuard))
+		userGroup.POST("/unlock-account", UnlockAccount(loginGuard))
+		userGroup.POST("/refresh", RefreshToken(tokenService))
+		userGroup.POST("/logout", auth, Logout(tokenService))
+		userGroup.POST("/logout-all", auth, LogoutAll(tokenService))
+		userGroup.GET("/sessions", auth, ListSessions(tokenService))
+		userGroup.DELETE("/sessions/:id", auth, RevokeSession(tokenService))
+		userGroup.POST("/forgot-password", ForgotPassword(passwordService))
+		userGroup.POST("/reset-password", ResetPassword(passwordService))
+		userGroup.POST("/change-password", auth, ChangePassword(passwordService, userService, tokenService))
+		userGroup.GET("/profile", auth, GetProfile(userService))
+		userGroup.PUT("/profile", auth, UpdateProfile(userService))
+		userGroup.DELETE("/profile", auth, DeleteProfile(privacyService))
+		userGroup.GET("/data-export", auth, ExportData(privacyService))
+		userGroup.POST("/profile/avatar", auth, UploadAvatar(avatarService))
+		userGroup.DELETE("/profile/avatar", auth, DeleteAvatar(avatarService))
+		userGroup.GET("/avatars/:user_id/:avatar_id/:file", GetAvatar(avatarService))
+		userGroup.GET("/bookings", auth, GetBookingHistory())
+		userGroup.GET("/notification-settings", auth, GetNotificationSettings(notificationService))
+		userGroup.PUT("/notification-settings", auth, UpdateNotificationSettings(notificationService))
+		userGroup.PUT("/notification-settings/preferences", auth, UpdateNotificationPreferences(notificationService))
+		userGroup.GET("/mfa", auth, GetMFAStatus(mfaService))
+		userGroup.POST("/mfa/enroll", auth, EnrollMFA(mfaService))
+		userGroup.POST("/mfa/enroll/confirm", auth, ConfirmMFA(mfaService))
+		userGroup.POST("/mfa/disable", auth, DisableMFA(mfaService))
+		userGroup.POST("/mfa/recovery-codes", auth, RegenerateRecoveryCodes(mfaService))
+
+		admin := userGroup.Group("/admin", auth, middleware.RequireRole(string(model.RoleAdmin)))
+		admin.GET("/users", AdminListUsers(adminService))
+		admin.GET("/users/:id", AdminGetUser(adminService))
+		admin.PUT("/users/:id/role", AdminChangeRole(adminService))
+		admin.POST("/users/:id/suspend", AdminSuspendUser(adminService))
+		admin.POST("/users/:id/reactivate", AdminReactivateUser(adminService))
+		admin.POST("/users/:id/force-password-reset", AdminForcePasswordReset(passwordService))
+		admin.GET("/users/:id/sessions", AdminListSessions(tokenService))
+		admin.DELETE("/users/:id/sessions", AdminRevokeAllSessions(tokenService))
+		admin.DELETE("/users/:id/sessions/:session_id", AdminRevokeSession(tokenService))
+		admin.DELETE("/users/:id/mfa", AdminResetMFA(mfaService))
+		admin.POST("/users/:id/unlock", AdminUnlockAccount(loginGuard))
+		admin.GET("/users/:id/data-export", AdminExportData(privacyService))
+		admin.POST("/users/:id/erase", AdminEraseUser(privacyService))
+		admin.GET("/mfa-policies", ListMFAPolicies(mfaService))
+		admin.PUT("/mfa-policies/:role", UpdateMFAPolicy(mfaService))
+	}
+}
+
+// JWKS publishes the public keys other services verify access tokens with
+func JWKS(jwtService service.JWTService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, jwtService.JWKS())
 	}
 }
 
@@ -31,89 +90,400 @@ func Register(userService service.UserService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req model.RegisterRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": utils.ParseValidationError(err)})
+			respondInvalid(c, err)
 			return
 		}
 		if err := utils.ValidateRegisterInput(req); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			respondInvalid(c, err)
 			return
 		}
 		user, err := userService.Register(req)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			respondFailed(c, err, "register_failed")
 			return
 		}
 		c.JSON(http.StatusCreated, user)
 	}
 }
 
-func Login(userService service.UserService, jwtService service.JWTService) gin.HandlerFunc {
+// VerifyEmail accepts the token from the emailed link, either as the "token"
+// query parameter (the link itself) or in a JSON body
+func VerifyEmail(verificationService service.VerificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.Query("token")
+		if token == "" {
+			var req model.VerifyEmailRequest
+			if err := c.ShouldBindJSON(&req); err != nil {
+				respondInvalid(c, err)
+				return
+			}
+			token = req.Token
+		}
+		user, err := verificationService.VerifyEmail(token)
+		if err != nil {
+			respondFailed(c, err, "verify_email_failed")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": message(c, "email_verified"), "user": user})
+	}
+}
+
+func ResendVerification(verificationService service.VerificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req model.ResendVerificationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondInvalid(c, err)
+			return
+		}
+		if err := verificationService.ResendVerification(req.Email); err != nil {
+			if errors.Is(err, service.ErrVerificationThrottled) {
+				c.Header("Retry-After", strconv.Itoa(int(verificationService.ResendCooldown().Seconds())))
+				respondErr(c, err)
+				return
+			}
+			respondFailed(c, err, "send_verification_failed")
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{"message": message(c, "verification_email_sent")})
+	}
+}
+
+// Login answers with tokens, or with an MFA challenge when the user has to
+// complete a second step first
+func Login(userService service.UserService, tokenService service.TokenService, mfaService service.MFAService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req model.LoginRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": utils.ParseValidationError(err)})
+			respondInvalid(c, err)
+			return
+		}
+		user, err := userService.Login(req, requestInfo(c))
+		if err != nil {
+			if respondLoginBlocked(c, err) {
+				return
+			}
+			respondFailed(c, err, "login_failed")
 			return
 		}
-		user, err := userService.Login(req)
+		challenge, err := mfaService.Challenge(user)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			respondFailed(c, err, "login_failed")
+			return
+		}
+		if challenge != nil {
+			c.JSON(http.StatusOK, challenge)
+			return
+		}
+		respondWithTokens(c, tokenService, user, nil)
+	}
+}
+
+// respondLoginBlocked answers for a throttled login or a locked account and
+// reports whether err was one
+func respondLoginBlocked(c *gin.Context, err error) bool {
+	var blocked *service.LoginBlockedError
+	if !errors.As(err, &blocked) {
+		return false
+	}
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(blocked.RetryAfter.Seconds()))))
+	respondErr(c, err)
+	return true
+}
+
+// UnlockAccount accepts the token from the emailed unlock link, either as
+// the "token" query parameter or in a JSON body
+func UnlockAccount(loginGuard service.LoginGuard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.Query("token")
+		if token == "" {
+			var req model.UnlockAccountRequest
+			if err := c.ShouldBindJSON(&req); err != nil {
+				respondInvalid(c, err)
+				return
+			}
+			token = req.Token
+		}
+		if err := loginGuard.Unlock(token, requestInfo(c)); err != nil {
+			respondFailed(c, err, "unlock_account_failed")
 			return
 		}
-		token, refreshToken, err := jwtService.GenerateTokens(user)
+		c.JSON(http.StatusOK, gin.H{"message": message(c, "account_unlocked_log_in")})
+	}
+}
+
+func AdminUnlockAccount(loginGuard service.LoginGuard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.Param("id"))
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, http.StatusBadRequest, "invalid_user_id")
+			return
+		}
+		if err := loginGuard.AdminUnlock(userID, requestInfo(c)); err != nil {
+			respondFailed(c, err, "unlock_account_failed")
 			return
 		}
-		c.JSON(http.StatusOK, gin.H{"access_token": token, "refresh_token": refreshToken, "user": user})
+		c.JSON(http.StatusOK, gin.H{"message": message(c, "account_unlocked")})
 	}
 }
 
-func RefreshToken(jwtService service.JWTService) gin.HandlerFunc {
+// respondWithTokens starts a session for user and answers with its tokens,
+// adding extra to the response
+func respondWithTokens(c *gin.Context, tokenService service.TokenService, user *model.User, extra gin.H) {
+	tokens, err := tokenService.IssueTokens(user, requestInfo(c))
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+	response := gin.H{
+		"access_token":  tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+		"user":          user,
+	}
+	for key, value := range extra {
+		response[key] = value
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// RefreshToken rotates the refresh token: the one presented stops working
+// and a new pair is returned
+func RefreshToken(tokenService service.TokenService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		refreshToken := c.GetHeader("X-Refresh-Token")
 		if refreshToken == "" {
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Refresh token is required"})
+			respondError(c, http.StatusBadRequest, "refresh_token_required")
+			return
+		}
+
+		tokens, err := tokenService.Refresh(refreshToken, requestInfo(c))
+		if err != nil {
+			respondFailed(c, err, "refresh_failed")
+			return
+		}
+
+		c.JSON(http.StatusOK, tokens)
+	}
+}
+
+// Logout revokes the access token used for the request and, when the
+// X-Refresh-Token header is sent, the session it belongs to
+func Logout(tokenService service.TokenService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.GetString("userID"))
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid_user_id")
+			return
+		}
+		if err := tokenService.Logout(userID, currentClaims(c), c.GetHeader("X-Refresh-Token")); err != nil {
+			respondFailed(c, err, "logout_failed")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": message(c, "logged_out")})
+	}
+}
+
+func LogoutAll(tokenService service.TokenService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.GetString("userID"))
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid_user_id")
+			return
+		}
+		if err := tokenService.LogoutAll(userID, currentClaims(c)); err != nil {
+			respondFailed(c, err, "logout_failed")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": message(c, "logged_out_everywhere")})
+	}
+}
+
+// ListSessions lists the devices the caller is logged in on
+func ListSessions(tokenService service.TokenService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.GetString("userID"))
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid_user_id")
+			return
+		}
+		currentSessionID := ""
+		if claims := currentClaims(c); claims != nil {
+			currentSessionID = claims.SessionID
+		}
+		sessions, err := tokenService.ListSessions(userID, currentSessionID)
+		if err != nil {
+			respondFailed(c, err, "list_login_sessions_failed")
 			return
 		}
+		c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+	}
+}
 
-		claims, err := jwtService.ValidateToken(refreshToken)
+// RevokeSession logs the caller out on one of their devices
+func RevokeSession(tokenService service.TokenService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.GetString("userID"))
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+			respondError(c, http.StatusBadRequest, "invalid_user_id")
 			return
 		}
+		sessionID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid_login_session_id")
+			return
+		}
+		revokeSession(c, tokenService, userID, sessionID)
+	}
+}
 
-		userID, err := uuid.Parse(claims.Subject)
+// AdminListSessions lets an admin see where a user is logged in
+func AdminListSessions(tokenService service.TokenService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.Param("id"))
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID in token"})
+			respondError(c, http.StatusBadRequest, "invalid_user_id")
+			return
+		}
+		sessions, err := tokenService.ListSessions(userID, "")
+		if err != nil {
+			respondFailed(c, err, "list_login_sessions_failed")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+	}
+}
+
+func AdminRevokeSession(tokenService service.TokenService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid_user_id")
+			return
+		}
+		sessionID, err := uuid.Parse(c.Param("session_id"))
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid_login_session_id")
+			return
+		}
+		revokeSession(c, tokenService, userID, sessionID)
+	}
+}
+
+// AdminRevokeAllSessions forces a user to log in again on every device
+func AdminRevokeAllSessions(tokenService service.TokenService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid_user_id")
+			return
+		}
+		if err := tokenService.RevokeAll(userID); err != nil {
+			respondFailed(c, err, "revoke_login_sessions_failed")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": message(c, "login_sessions_revoked")})
+	}
+}
+
+func revokeSession(c *gin.Context, tokenService service.TokenService, userID, sessionID uuid.UUID) {
+	if err := tokenService.RevokeSession(userID, sessionID); err != nil {
+		respondFailed(c, err, "revoke_login_session_failed")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": message(c, "login_session_revoked")})
+}
+
+func currentClaims(c *gin.Context) *service.Claims {
+	claims, _ := c.Get("claims")
+	current, _ := claims.(*service.Claims)
+	return current
+}
+
+// ForgotPassword always answers the same way, whether or not the email is
+// registered
+func ForgotPassword(passwordService service.PasswordService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req model.ForgotPasswordRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondInvalid(c, err)
 			return
 		}
+		if err := passwordService.ForgotPassword(req.Email, requestInfo(c)); err != nil {
+			respondFailed(c, err, "send_password_reset_failed")
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{"message": message(c, "password_reset_email_sent")})
+	}
+}
 
-		dummyUser := &model.User{ID: userID}
+func ResetPassword(passwordService service.PasswordService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req model.ResetPasswordRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondInvalid(c, err)
+			return
+		}
+		if err := passwordService.ResetPassword(req.Token, req.NewPassword, requestInfo(c)); err != nil {
+			respondFailed(c, err, "reset_password_failed")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": message(c, "password_reset")})
+	}
+}
 
-		newAccessToken, newRefreshToken, err := jwtService.GenerateTokens(dummyUser)
+// ChangePassword revokes the caller's refresh tokens along with all others,
+// so it returns a fresh pair for the current session
+func ChangePassword(passwordService service.PasswordService, userService service.UserService, tokenService service.TokenService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.GetString("userID"))
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate new tokens"})
+			respondError(c, http.StatusBadRequest, "invalid_user_id")
+			return
+		}
+		var req model.ChangePasswordRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondInvalid(c, err)
+			return
+		}
+		if err := passwordService.ChangePassword(userID, req.CurrentPassword, req.NewPassword, requestInfo(c)); err != nil {
+			respondFailed(c, err, "change_password_failed")
 			return
 		}
 
+		user, err := userService.GetProfile(userID)
+		if err != nil {
+			c.JSON(http.StatusOK, gin.H{"message": message(c, "password_changed_log_in_again")})
+			return
+		}
+		tokens, err := tokenService.IssueTokens(user, requestInfo(c))
+		if err != nil {
+			c.JSON(http.StatusOK, gin.H{"message": message(c, "password_changed_log_in_again")})
+			return
+		}
 		c.JSON(http.StatusOK, gin.H{
-			"access_token":  newAccessToken,
-			"refresh_token": newRefreshToken,
+			"message":       message(c, "password_changed"),
+			"access_token":  tokens.AccessToken,
+			"refresh_token": tokens.RefreshToken,
+			"expires_in":    tokens.ExpiresIn,
 		})
 	}
 }
 
+func requestInfo(c *gin.Context) model.RequestInfo {
+	return model.RequestInfo{IPAddress: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+}
+
 func GetProfile(userService service.UserService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userIDStr := c.GetString("userID")
 		userID, err := uuid.Parse(userIDStr)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			respondError(c, http.StatusBadRequest, "invalid_user_id")
 			return
 		}
 		user, err := userService.GetProfile(userID)
 		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			respondFailed(c, err, "get_user_failed")
 			return
 		}
 		c.JSON(http.StatusOK, user)
@@ -125,43 +495,27 @@ func UpdateProfile(userService service.UserService) gin.HandlerFunc {
 		userIDStr := c.GetString("userID")
 		userID, err := uuid.Parse(userIDStr)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			respondError(c, http.StatusBadRequest, "invalid_user_id")
 			return
 		}
 		var req model.UpdateProfileRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": utils.ParseValidationError(err)})
+			respondInvalid(c, err)
 			return
 		}
 		if err := utils.ValidateUpdateProfileInput(req); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			respondInvalid(c, err)
 			return
 		}
 		user, err := userService.UpdateProfile(userID, req)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			respondFailed(c, err, "update_profile_failed")
 			return
 		}
 		c.JSON(http.StatusOK, user)
 	}
 }
 
-func DeleteProfile(userService service.UserService) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		userIDStr := c.GetString("userID")
-		userID, err := uuid.Parse(userIDStr)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
-			return
-		}
-		if err := userService.DeleteProfile(userID); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
-	}
-}
-
 func GetBookingHistory() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userID := c.GetString("userID")
@@ -180,21 +534,22 @@ func GetBookingHistory() gin.HandlerFunc {
 		// Gửi request sang booking-service
 		req, err := http.NewRequest("GET", url, nil)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal error"})
+			respondErr(c, err)
 			return
 		}
-		// Truyền JWT từ header gốc
+		// Truyền JWT và ngôn ngữ từ header gốc
 		req.Header.Set("Authorization", c.GetHeader("Authorization"))
+		req.Header.Set("Accept-Language", c.GetHeader("Accept-Language"))
 
 		client := &http.Client{}
 		resp, err := client.Do(req)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Cannot connect to booking service"})
+			respondErr(c, apperr.Unavailable("booking_service_unavailable").Wrap(err))
 			return
 		}
 		defer resp.Body.Close()
 
 		body, _ := ioutil.ReadAll(resp.Body)
-		c.Data(resp.StatusCode, "application/json", body)
+		c.Data(resp.StatusCode, resp.Header.Get("Content-Type"), body)
 	}
 }
@@ -0,0 +1,217 @@
+// Package imaging turns uploaded photos into square JPEG thumbnails.
+// Re-encoding drops every metadata block of the upload (EXIF, GPS, ICC,
+// comments); the EXIF orientation is applied first so photos taken on
+// phones are not shown sideways.
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("image must be a JPEG or PNG")
+	ErrTooManyPixels     = errors.New("image dimensions are too large")
+)
+
+const jpegQuality = 85
+
+// Decode sniffs the content type of data, which must be JPEG or PNG, and
+// decodes it. Images with more than maxPixels pixels are rejected before
+// decoding. It returns the image and its EXIF orientation (1 if none).
+func Decode(data []byte, maxPixels int) (image.Image, int, error) {
+	var decodeConfig func([]byte) (image.Config, error)
+	var decode func([]byte) (image.Image, error)
+	isJPEG := false
+	switch http.DetectContentType(data) {
+	case "image/jpeg":
+		isJPEG = true
+		decodeConfig = func(b []byte) (image.Config, error) { return jpeg.DecodeConfig(bytes.NewReader(b)) }
+		decode = func(b []byte) (image.Image, error) { return jpeg.Decode(bytes.NewReader(b)) }
+	case "image/png":
+		decodeConfig = func(b []byte) (image.Config, error) { return png.DecodeConfig(bytes.NewReader(b)) }
+		decode = func(b []byte) (image.Image, error) { return png.Decode(bytes.NewReader(b)) }
+	default:
+		return nil, 0, ErrUnsupportedFormat
+	}
+
+	config, err := decodeConfig(data)
+	if err != nil {
+		return nil, 0, ErrUnsupportedFormat
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxPixels {
+		return nil, 0, ErrTooManyPixels
+	}
+	img, err := decode(data)
+	if err != nil {
+		return nil, 0, ErrUnsupportedFormat
+	}
+
+	orientation := 1
+	if isJPEG {
+		orientation = jpegOrientation(data)
+	}
+	return img, orientation, nil
+}
+
+// Thumbnail crops the centre square of src and scales it to size×size,
+// averaging the source pixels that fall in each target pixel. The EXIF
+// orientation is applied to the result; rotating the centre square is the
+// same as taking the centre square of the rotated photo.
+func Thumbnail(src image.Image, orientation, size int) *image.RGBA {
+	b := src.Bounds()
+	side := b.Dx()
+	if b.Dy() < side {
+		side = b.Dy()
+	}
+	x0 := b.Min.X + (b.Dx()-side)/2
+	y0 := b.Min.Y + (b.Dy()-side)/2
+	scale := float64(side) / float64(size)
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		sy0, sy1 := span(y, scale, side)
+		for x := 0; x < size; x++ {
+			sx0, sx1 := span(x, scale, side)
+			var r, g, bl, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					cr, cg, cb, ca := src.At(x0+sx, y0+sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			dst.SetRGBA64(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(bl / n), A: uint16(a / n)})
+		}
+	}
+	return orient(dst, orientation)
+}
+
+// span returns the source pixels [from, to) covered by target pixel i
+func span(i int, scale float64, limit int) (int, int) {
+	from := int(float64(i) * scale)
+	to := int(float64(i+1)*scale + 0.999)
+	if to > limit {
+		to = limit
+	}
+	if to <= from {
+		to = from + 1
+	}
+	return from, to
+}
+
+// orient applies an EXIF orientation (1-8) to a square image
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+	n := src.Bounds().Dx()
+	dst := image.NewRGBA(src.Bounds())
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			sx, sy := x, y
+			switch orientation {
+			case 2: // mirrored
+				sx = n - 1 - x
+			case 3: // rotated 180°
+				sx, sy = n-1-x, n-1-y
+			case 4: // mirrored vertically
+				sy = n - 1 - y
+			case 5: // transposed
+				sx, sy = y, x
+			case 6: // rotated 90° clockwise
+				sx, sy = y, n-1-x
+			case 7: // transversed
+				sx, sy = n-1-y, n-1-x
+			case 8: // rotated 90° counter-clockwise
+				sx, sy = n-1-y, x
+			}
+			dst.SetRGBA(x, y, src.RGBAAt(sx, sy))
+		}
+	}
+	return dst
+}
+
+// EncodeJPEG flattens transparency onto white and encodes img as a JPEG
+// without any metadata
+func EncodeJPEG(img image.Image) ([]byte, error) {
+	flat := image.NewRGBA(img.Bounds())
+	draw.Draw(flat, flat.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), img, img.Bounds().Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, flat, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// jpegOrientation reads the orientation tag from the EXIF block of a JPEG,
+// returning 1 when there is none or it cannot be read
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		// Start of scan: no more metadata segments
+		if marker == 0xDA || marker == 0xD9 {
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return 1
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && len(segment) > 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return exifOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for e := 0; e < entries; e++ {
+		offset := ifd + 2 + e*12
+		if offset+12 > len(tiff) {
+			return 1
+		}
+		// Tag 0x0112 is the orientation, a SHORT stored in the value field
+		if order.Uint16(tiff[offset:]) == 0x0112 {
+			value := int(order.Uint16(tiff[offset+8:]))
+			if value >= 1 && value <= 8 {
+				return value
+			}
+			return 1
+		}
+	}
+	return 1
+}
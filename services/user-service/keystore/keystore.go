@@ -0,0 +1,269 @@
+package keystore
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"booking-system/shared/pkg/jwks"
+)
+
+const createdHeader = "Created"
+
+// SigningKey is a private key used to sign access tokens
+type SigningKey struct {
+	ID        string
+	Algorithm string
+	Signer    crypto.Signer
+	CreatedAt time.Time
+}
+
+type Config struct {
+	// Dir holds one PEM file per key. It must not be readable by other
+	// services; replicas of user-service share it.
+	Dir string
+	// Algorithm for new keys: RS256 or EdDSA
+	Algorithm string
+	// RotationPeriod is how long a key signs tokens before a new one
+	// replaces it
+	RotationPeriod time.Duration
+	// PublishAhead is how long a new key is published before it signs
+	// tokens, so verifiers have it cached by the time they see it
+	PublishAhead time.Duration
+	// Grace keeps a replaced key published until the tokens it signed have
+	// expired
+	Grace time.Duration
+}
+
+// KeyStore keeps the signing keys on disk, rotates them on schedule and
+// exposes their public halves as a JWKS
+type KeyStore struct {
+	config Config
+
+	mu   sync.RWMutex
+	keys []*SigningKey // oldest first
+}
+
+// New loads the keys in config.Dir and creates the first one if needed
+func New(config Config) (*KeyStore, error) {
+	if config.Algorithm != jwks.AlgRS256 && config.Algorithm != jwks.AlgEdDSA {
+		return nil, fmt.Errorf("unsupported signing algorithm %q", config.Algorithm)
+	}
+	if err := os.MkdirAll(config.Dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create key directory: %w", err)
+	}
+	s := &KeyStore{config: config}
+	if err := s.Rotate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Current returns the key new tokens are signed with: the newest key that is
+// past its publish-ahead period. The very first key is used right away.
+func (s *KeyStore) Current() *SigningKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	now := time.Now()
+	for i := len(s.keys) - 1; i > 0; i-- {
+		if !now.Before(s.activatesAt(s.keys[i])) {
+			return s.keys[i]
+		}
+	}
+	return s.keys[0]
+}
+
+// PublicKey returns the public key with the given ID, if it is still published
+func (s *KeyStore) PublicKey(kid string) (crypto.PublicKey, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, key := range s.keys {
+		if key.ID == kid {
+			return key.Signer.Public(), true
+		}
+	}
+	return nil, false
+}
+
+// JWKS returns the public keys that may still verify unexpired tokens
+func (s *KeyStore) JWKS() jwks.Set {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	set := jwks.Set{Keys: make([]jwks.Key, 0, len(s.keys))}
+	for i := len(s.keys) - 1; i >= 0; i-- {
+		jwk, err := jwks.NewKey(s.keys[i].ID, s.keys[i].Signer.Public())
+		if err != nil {
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+// Rotate reloads the key directory, so keys created by other replicas are
+// picked up, creates a new key when the current one is due and deletes keys
+// whose grace period is over
+func (s *KeyStore) Rotate() error {
+	keys, err := s.load()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if len(keys) == 0 || now.Sub(keys[len(keys)-1].CreatedAt) >= s.config.RotationPeriod {
+		key, err := s.create(now)
+		if err != nil {
+			return err
+		}
+		log.Printf("created signing key %s (%s)", key.ID, key.Algorithm)
+		keys = append(keys, key)
+	}
+
+	// A key is retired when the next one starts signing
+	live := keys[:0]
+	for i, key := range keys {
+		if i < len(keys)-1 && now.Sub(s.activatesAt(keys[i+1])) > s.config.Grace {
+			if err := os.Remove(s.path(key.ID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+				log.Printf("failed to delete signing key %s: %v", key.ID, err)
+			}
+			continue
+		}
+		live = append(live, key)
+	}
+
+	s.mu.Lock()
+	s.keys = live
+	s.mu.Unlock()
+	return nil
+}
+
+// Run calls Rotate every interval until ctx is done
+func (s *KeyStore) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Rotate(); err != nil {
+				log.Printf("signing key rotation failed: %v", err)
+			}
+		}
+	}
+}
+
+func (s *KeyStore) load() ([]*SigningKey, error) {
+	files, err := filepath.Glob(filepath.Join(s.config.Dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	var keys []*SigningKey
+	for _, file := range files {
+		key, err := readKey(file)
+		if err != nil {
+			log.Printf("skipping signing key %s: %v", file, err)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.Before(keys[j].CreatedAt) })
+	return keys, nil
+}
+
+func (s *KeyStore) create(now time.Time) (*SigningKey, error) {
+	var signer crypto.Signer
+	var err error
+	if s.config.Algorithm == jwks.AlgEdDSA {
+		_, signer, err = ed25519.GenerateKey(rand.Reader)
+	} else {
+		signer, err = rsa.GenerateKey(rand.Reader, 2048)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+	key := &SigningKey{
+		ID:        now.UTC().Format("20060102T150405") + "-" + hex.EncodeToString(suffix),
+		Algorithm: s.config.Algorithm,
+		Signer:    signer,
+		CreatedAt: now,
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(signer)
+	if err != nil {
+		return nil, err
+	}
+	block := &pem.Block{
+		Type:    "PRIVATE KEY",
+		Headers: map[string]string{createdHeader: now.UTC().Format(time.RFC3339)},
+		Bytes:   der,
+	}
+	// Write then rename so other replicas never read a partial file
+	tmp := s.path(key.ID) + ".tmp"
+	if err := os.WriteFile(tmp, pem.EncodeToMemory(block), 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write signing key: %w", err)
+	}
+	if err := os.Rename(tmp, s.path(key.ID)); err != nil {
+		return nil, fmt.Errorf("failed to write signing key: %w", err)
+	}
+	return key, nil
+}
+
+func (s *KeyStore) activatesAt(key *SigningKey) time.Time {
+	return key.CreatedAt.Add(s.config.PublishAhead)
+}
+
+func (s *KeyStore) path(kid string) string {
+	return filepath.Join(s.config.Dir, kid+".pem")
+}
+
+func readKey(file string) (*SigningKey, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, errors.New("not a PEM private key")
+	}
+	createdAt, err := time.Parse(time.RFC3339, block.Headers[createdHeader])
+	if err != nil {
+		return nil, fmt.Errorf("missing %s header", createdHeader)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &SigningKey{
+		ID:        strings.TrimSuffix(filepath.Base(file), ".pem"),
+		CreatedAt: createdAt,
+	}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Algorithm, key.Signer = jwks.AlgRS256, k
+	case ed25519.PrivateKey:
+		key.Algorithm, key.Signer = jwks.AlgEdDSA, k
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+	return key, nil
+}
@@ -1,17 +1,38 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"services/user-service/handler"
+	"services/user-service/keystore"
+	"services/user-service/model"
 	"services/user-service/repository"
 	"services/user-service/service"
+	"strconv"
+	"strings"
+	"time"
+
+	"booking-system/shared/pkg/jwks"
+	"booking-system/shared/pkg/mailer"
+	"booking-system/shared/pkg/notify"
+	"booking-system/shared/pkg/storage"
 
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+const (
+	keyRotationCheckInterval  = time.Minute
+	avatarGCInterval          = time.Hour
+	notificationFlushInterval = time.Minute
+	// minJWTSecretLength is the shortest JWT_SECRET accepted, the key size of
+	// HS256
+	minJWTSecretLength = 32
+)
+
 func main() {
 	dsn := os.Getenv("USER_SERVICE_DSN")
 	if dsn == "" {
@@ -24,15 +45,105 @@ func main() {
 	// Remove auto-migrate since we're using our own schema
 	// db.AutoMigrate(&model.User{})
 
+	// Redis holds the access token denylist shared with the api-gateway
+	redisOpt, err := redis.ParseURL(getEnv("REDIS_URL", "redis://localhost:6379/0"))
+	if err != nil {
+		log.Fatalf("invalid REDIS_URL: %v", err)
+	}
+	redisClient := redis.NewClient(redisOpt)
+	if err := redisClient.Ping(context.Background()).Err(); err != nil {
+		log.Fatalf("failed to connect redis: %v", err)
+	}
+	defer redisClient.Close()
+
 	repo := repository.NewUserRepository(db)
-	userService := service.NewUserService(repo)
+	// JWT_SECRET now only signs email verification and unlock links and MFA
+	// tokens and encrypts TOTP secrets; it never leaves user-service
 	jwtSecret := os.Getenv("JWT_SECRET")
+	if len(jwtSecret) < minJWTSecretLength {
+		log.Fatalf("JWT_SECRET must be set to at least %d bytes", minJWTSecretLength)
+	}
+	mail := mailer.FromEnv()
+	verificationService := service.NewVerificationService(repo, mail, jwtSecret,
+		time.Duration(getEnvAsInt("EMAIL_VERIFICATION_TTL_HOURS", 24))*time.Hour,
+		time.Duration(getEnvAsInt("EMAIL_VERIFICATION_RESEND_COOLDOWN_SECONDS", 60))*time.Second,
+		getEnv("EMAIL_VERIFICATION_URL", "http://localhost:8080/user/verify-email"))
+	auditRepo := repository.NewAuditRepository(db)
+	loginGuard := service.NewLoginGuard(repo, repository.NewLoginAttempts(redisClient), auditRepo, mail, jwtSecret, service.LoginGuardConfig{
+		Window:           time.Duration(getEnvAsInt("LOGIN_FAILURE_WINDOW_MINUTES", 15)) * time.Minute,
+		FreeAttempts:     getEnvAsInt("LOGIN_FREE_ATTEMPTS", 3),
+		IPFreeAttempts:   getEnvAsInt("LOGIN_IP_FREE_ATTEMPTS", 20),
+		BaseDelay:        time.Second,
+		MaxDelay:         5 * time.Minute,
+		LockoutThreshold: getEnvAsInt("LOGIN_LOCKOUT_THRESHOLD", 10),
+		LockoutDuration:  time.Duration(getEnvAsInt("LOGIN_LOCKOUT_MINUTES", 30)) * time.Minute,
+		UnlockURL:        getEnv("ACCOUNT_UNLOCK_URL", "http://localhost:8080/user/unlock-account"),
+	})
+	userService := service.NewUserService(repo, verificationService, loginGuard)
 	jwtExpiry := 3600
 	refreshExpiry := 604800
-	jwtService := service.NewJWTService(jwtSecret, jwtExpiry, refreshExpiry)
+
+	// Access tokens are signed with a private key only user-service can read;
+	// a new key replaces it every JWT_KEY_ROTATION_DAYS
+	keys, err := keystore.New(keystore.Config{
+		Dir:            getEnv("JWT_KEY_DIR", "./keys"),
+		Algorithm:      getEnv("JWT_SIGNING_ALG", jwks.AlgRS256),
+		RotationPeriod: time.Duration(getEnvAsInt("JWT_KEY_ROTATION_DAYS", 30)) * 24 * time.Hour,
+		PublishAhead:   time.Hour,
+		Grace:          time.Duration(jwtExpiry)*time.Second + keyRotationCheckInterval,
+	})
+	if err != nil {
+		log.Fatalf("failed to load signing keys: %v", err)
+	}
+	go keys.Run(context.Background(), keyRotationCheckInterval)
+	jwtService := service.NewJWTService(keys, getEnv("JWT_ISSUER", "user-service"), jwtExpiry)
+	denylist := repository.NewTokenDenylist(redisClient)
+	tokenService := service.NewTokenService(jwtService, repo, repository.NewRefreshTokenRepository(db),
+		repository.NewSessionRepository(db), denylist, time.Duration(refreshExpiry)*time.Second)
+	passwordService := service.NewPasswordService(repo, repository.NewPasswordResetRepository(db), auditRepo, tokenService, mail,
+		time.Duration(getEnvAsInt("PASSWORD_RESET_TTL_MINUTES", 60))*time.Minute,
+		getEnv("PASSWORD_RESET_URL", "http://localhost:8080/user/reset-password"))
+
+	mfaService, err := service.NewMFAService(repo, repository.NewMFARepository(db), auditRepo, denylist, loginGuard, jwtSecret,
+		getEnv("MFA_ISSUER", "Booking System"), time.Duration(getEnvAsInt("MFA_CHALLENGE_TTL_SECONDS", 300))*time.Second)
+	if err != nil {
+		log.Fatalf("failed to set up MFA: %v", err)
+	}
+
+	// Profile pictures live in AVATAR_STORAGE_DIR and are served through the
+	// gateway under AVATAR_BASE_URL
+	avatarService := service.NewAvatarService(repo, repository.NewAvatarRepository(db),
+		storage.NewLocalFileStore(getEnv("AVATAR_STORAGE_DIR", "./uploads")),
+		getEnv("AVATAR_BASE_URL", "http://localhost:8081/avatars"),
+		time.Duration(getEnvAsInt("AVATAR_GC_GRACE_HOURS", 24))*time.Hour)
+	go collectAvatarGarbage(context.Background(), avatarService, avatarGCInterval)
+
+	// Notifications waiting for quiet hours to end or for a digest are kept
+	// in the outbox, which this service flushes for all services
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatalf("failed to get database handle: %v", err)
+	}
+	notificationService := service.NewNotificationService(repo, notify.NewStore(sqlDB),
+		notify.NewNotifier(sqlDB, notify.SendersFromEnv(mail)))
+	go flushNotifications(context.Background(), notificationService, notificationFlushInterval)
+
+	if email := os.Getenv("BOOTSTRAP_ADMIN_EMAIL"); email != "" {
+		bootstrapAdmin(repo, email)
+	}
 
 	r := gin.Default()
-	handler.RegisterRoutes(r, userService, jwtService)
+	// Login throttling and the IPs recorded for sessions and audit use the
+	// client IP, so X-Forwarded-For is only honoured from the gateway. Without
+	// TRUSTED_PROXIES the service is reached directly and the header is ignored.
+	if err := r.SetTrustedProxies(splitList(os.Getenv("TRUSTED_PROXIES"))); err != nil {
+		log.Fatalf("invalid TRUSTED_PROXIES: %v", err)
+	}
+	handler.RegisterRoutes(r, userService, jwtService, tokenService, verificationService, passwordService, mfaService, loginGuard,
+		service.NewAdminService(repo, auditRepo, tokenService),
+		service.NewPrivacyService(repo, repository.NewPrivacyRepository(db), auditRepo, tokenService,
+			getEnv("BOOKING_SERVICE_URL", "http://localhost:8082"), getEnv("EXPERT_SERVICE_URL", "http://localhost:8083")),
+		avatarService, notificationService)
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -40,3 +151,86 @@ func main() {
 	}
 	r.Run(":" + port)
 }
+
+// collectAvatarGarbage deletes replaced profile pictures every interval
+func collectAvatarGarbage(ctx context.Context, avatarService service.AvatarService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := avatarService.CollectGarbage(); err != nil {
+			log.Printf("profile picture garbage collection failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// flushNotifications sends the queued notifications that are due every
+// interval
+func flushNotifications(ctx context.Context, notificationService service.NotificationService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := notificationService.Flush(); err != nil {
+			log.Printf("notification flush failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// bootstrapAdmin promotes the registered user with email to admin while no
+// admin exists, since admins can no longer register themselves
+func bootstrapAdmin(repo repository.UserRepository, email string) {
+	admins, err := repo.CountByRole(model.RoleAdmin)
+	if err != nil {
+		log.Printf("failed to count admins: %v", err)
+		return
+	}
+	if admins > 0 {
+		return
+	}
+	user, err := repo.FindByEmail(email)
+	if err != nil {
+		log.Printf("bootstrap admin %s is not registered yet: %v", email, err)
+		return
+	}
+	if err := repo.UpdateRole(user.ID, model.RoleAdmin); err != nil {
+		log.Printf("failed to promote %s to admin: %v", email, err)
+		return
+	}
+	log.Printf("promoted %s to admin", email)
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// splitList parses a comma separated list, returning nil when it is empty
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getEnvAsInt returns defaultValue when the variable is unset or not a positive integer
+func getEnvAsInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
@@ -5,30 +5,61 @@ import (
 	"services/user-service/service"
 	"strings"
 
+	"booking-system/shared/pkg/apperr"
+	"booking-system/shared/pkg/i18n"
+
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware(jwtService service.JWTService) gin.HandlerFunc {
+func AuthMiddleware(jwtService service.JWTService, tokenService service.TokenService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+			abort(c, http.StatusUnauthorized, "authorization_required")
 			return
 		}
 
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
+			abort(c, http.StatusUnauthorized, "invalid_authorization_header")
 			return
 		}
 
 		claims, err := jwtService.ValidateToken(parts[1])
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+		if err != nil || tokenService.IsAccessTokenRevoked(claims.ID) {
+			abort(c, http.StatusUnauthorized, "invalid_token")
 			return
 		}
 
 		c.Set("userID", claims.Subject)
+		c.Set("userRole", claims.Role)
+		c.Set("userLang", claims.Language)
+		c.Set("claims", claims)
 		c.Next()
 	}
 }
+
+// RequireRole rejects callers whose role is not one of roles. It must run
+// after AuthMiddleware.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("userRole")
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+		abort(c, http.StatusForbidden, "access_denied")
+	}
+}
+
+// abort answers with a problem for the message code in the caller's
+// language; until the token is verified only Accept-Language is known
+func abort(c *gin.Context, status int, code string) {
+	lang := i18n.Resolve(c.GetString("userLang"), c.GetHeader("Accept-Language"))
+	problem := apperr.NewProblem(lang, apperr.New(apperr.KindFor(status), code))
+	problem.Instance = c.Request.URL.Path
+	apperr.Write(c.Writer, problem)
+	c.Abort()
+}
@@ -0,0 +1,10 @@
+-- Email verification. Accounts created before verification existed never got
+-- a link, so they are treated as verified rather than locked out of booking
+-- (new accounts always have email_verification_sent_at set).
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS email_verification_sent_at TIMESTAMP;
+
+UPDATE users
+SET email_verified = true, email_verified_at = created_at
+WHERE email_verified IS DISTINCT FROM true AND email_verification_sent_at IS NULL;
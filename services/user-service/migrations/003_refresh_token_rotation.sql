@@ -0,0 +1,20 @@
+-- Persistent refresh tokens. Every refresh issues a new token in the same
+-- family and revokes the old one; presenting a revoked token again revokes
+-- the whole family. Each row also remembers the access token issued with it,
+-- so logout can put that token on the gateway's denylist.
+ALTER TABLE refresh_tokens
+    ADD COLUMN IF NOT EXISTS family_id UUID,
+    ADD COLUMN IF NOT EXISTS replaced_by UUID REFERENCES refresh_tokens(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS access_token_id VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS access_expires_at TIMESTAMP;
+
+UPDATE refresh_tokens SET family_id = id WHERE family_id IS NULL;
+ALTER TABLE refresh_tokens ALTER COLUMN family_id SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refresh_tokens_token_hash ON refresh_tokens(token_hash);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family ON refresh_tokens(family_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user ON refresh_tokens(user_id);
+
+-- Revocation now goes through refresh_tokens
+ALTER TABLE users DROP COLUMN IF EXISTS token_version;
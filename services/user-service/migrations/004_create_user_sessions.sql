@@ -0,0 +1,28 @@
+-- A session is one login on one device: the family of refresh tokens rotated
+-- from it. refresh_tokens.family_id is the session ID.
+CREATE TABLE IF NOT EXISTS user_sessions (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_agent TEXT,
+    ip_address VARCHAR(45),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_sessions_user ON user_sessions(user_id, last_used_at DESC);
+
+-- Families created before sessions existed become sessions without device data
+INSERT INTO user_sessions (id, user_id, created_at, last_used_at, expires_at, revoked_at)
+SELECT family_id, user_id, MIN(created_at), MAX(created_at), MAX(expires_at),
+       CASE WHEN bool_and(is_revoked) THEN COALESCE(MAX(revoked_at), CURRENT_TIMESTAMP) END
+FROM refresh_tokens
+WHERE user_id IS NOT NULL
+GROUP BY family_id, user_id
+ON CONFLICT (id) DO NOTHING;
+
+DELETE FROM refresh_tokens WHERE family_id NOT IN (SELECT id FROM user_sessions);
+
+ALTER TABLE refresh_tokens
+    ADD CONSTRAINT fk_refresh_tokens_session FOREIGN KEY (family_id) REFERENCES user_sessions(id) ON DELETE CASCADE;
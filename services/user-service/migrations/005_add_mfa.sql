@@ -0,0 +1,35 @@
+-- TOTP second factor. The secret is encrypted by user-service; the factor is
+-- active once enabled_at is set. last_used_step stops a code from being used
+-- twice.
+CREATE TABLE IF NOT EXISTS user_mfa (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret TEXT NOT NULL,
+    enabled_at TIMESTAMP,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Single-use codes for when the authenticator is lost, stored hashed
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_mfa_recovery_codes_user ON mfa_recovery_codes(user_id);
+
+-- Roles whose members must log in with a second factor
+CREATE TABLE IF NOT EXISTS mfa_role_policies (
+    role VARCHAR(20) PRIMARY KEY CHECK (role IN ('user', 'expert', 'admin')),
+    required BOOLEAN NOT NULL DEFAULT false,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO mfa_role_policies (role, required) VALUES
+    ('user', false),
+    ('expert', false),
+    ('admin', false)
+ON CONFLICT (role) DO NOTHING;
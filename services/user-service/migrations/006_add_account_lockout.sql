@@ -0,0 +1,4 @@
+-- Set when too many failed logins lock the account. Failed attempts are
+-- counted in Redis; only the lock itself is kept here.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP;
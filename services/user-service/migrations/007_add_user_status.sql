@@ -0,0 +1,12 @@
+-- Account status managed by admins. Suspended users cannot log in or
+-- refresh tokens. Self-registered experts start as users with a pending
+-- requested_role until an admin grants it.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'suspended')),
+    ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS suspension_reason TEXT,
+    ADD COLUMN IF NOT EXISTS requested_role VARCHAR(20) CHECK (requested_role IN ('expert')),
+    ADD COLUMN IF NOT EXISTS password_reset_required BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS idx_users_status ON users(status);
+CREATE INDEX IF NOT EXISTS idx_users_requested_role ON users(requested_role) WHERE requested_role IS NOT NULL;
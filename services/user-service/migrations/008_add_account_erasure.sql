@@ -0,0 +1,6 @@
+-- Erased accounts keep their row so bookings, reviews and status history
+-- that experts rely on stay intact; the personal data on it is replaced.
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_status_check;
+ALTER TABLE users
+    ADD CONSTRAINT users_status_check CHECK (status IN ('active', 'suspended', 'erased')),
+    ADD COLUMN IF NOT EXISTS erased_at TIMESTAMP;
@@ -0,0 +1,12 @@
+-- Uploaded profile pictures. users.image points at the current one; a new
+-- upload or removal marks the previous one replaced, and its files are
+-- deleted once the grace period for cached pages has passed.
+CREATE TABLE IF NOT EXISTS user_avatars (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    replaced_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_avatars_current ON user_avatars(user_id) WHERE replaced_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_user_avatars_replaced ON user_avatars(replaced_at) WHERE replaced_at IS NOT NULL;
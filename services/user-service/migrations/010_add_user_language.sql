@@ -0,0 +1,3 @@
+-- Language of API messages chosen by the user; empty means none, in which
+-- case the request's Accept-Language is used
+ALTER TABLE users ADD COLUMN IF NOT EXISTS language VARCHAR(5) NOT NULL DEFAULT '';
@@ -0,0 +1,59 @@
+-- Notification settings: several reminder offsets, quiet hours and a digest
+-- mode, all in the user's time zone. Times of day are stored as 'HH:MM'.
+ALTER TABLE notification_settings ADD COLUMN IF NOT EXISTS reminder_offsets INTEGER[] NOT NULL DEFAULT '{60}';
+ALTER TABLE notification_settings ADD COLUMN IF NOT EXISTS quiet_hours_start VARCHAR(5);
+ALTER TABLE notification_settings ADD COLUMN IF NOT EXISTS quiet_hours_end VARCHAR(5);
+ALTER TABLE notification_settings ADD COLUMN IF NOT EXISTS time_zone VARCHAR(64) NOT NULL DEFAULT 'Asia/Ho_Chi_Minh';
+ALTER TABLE notification_settings ADD COLUMN IF NOT EXISTS digest VARCHAR(10) NOT NULL DEFAULT 'off'
+    CHECK (digest IN ('off', 'daily', 'weekly'));
+ALTER TABLE notification_settings ADD COLUMN IF NOT EXISTS digest_time VARCHAR(5) NOT NULL DEFAULT '08:00';
+ALTER TABLE notification_settings ADD COLUMN IF NOT EXISTS telegram_chat_id VARCHAR(64) NOT NULL DEFAULT '';
+
+-- Whether to send each event over each channel. Missing rows mean the
+-- default: in-app and email on, Telegram and SMS off.
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event VARCHAR(50) NOT NULL,
+    channel VARCHAR(20) NOT NULL CHECK (channel IN ('email', 'telegram', 'in_app', 'sms')),
+    enabled BOOLEAN NOT NULL,
+    PRIMARY KEY (user_id, event, channel)
+);
+
+-- Carry over the old single reminder and email switch
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'notification_settings' AND column_name = 'reminder_minutes') THEN
+        UPDATE notification_settings SET reminder_offsets = ARRAY[reminder_minutes]
+        WHERE reminder_minutes IS NOT NULL AND reminder_minutes > 0;
+
+        INSERT INTO notification_preferences (user_id, event, channel, enabled)
+        SELECT s.user_id, e.event, 'email', false
+        FROM notification_settings s
+        CROSS JOIN (VALUES ('booking_created'), ('booking_confirmed'), ('booking_rejected'),
+                           ('booking_cancelled'), ('booking_reminder'), ('certification_expiring')) AS e(event)
+        WHERE s.email_enabled = false
+        ON CONFLICT DO NOTHING;
+
+        ALTER TABLE notification_settings DROP COLUMN reminder_minutes;
+        ALTER TABLE notification_settings DROP COLUMN email_enabled;
+    END IF;
+END $$;
+
+-- Notifications waiting for the end of the user's quiet hours, their next
+-- digest or another attempt after the channel failed. Title and body are
+-- already in the user's language.
+CREATE TABLE IF NOT EXISTS notification_outbox (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event VARCHAR(50) NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    digest BOOLEAN NOT NULL DEFAULT false,
+    send_after TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_outbox_send_after ON notification_outbox(send_after);
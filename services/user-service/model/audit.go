@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit actions recorded for security-relevant account changes
+const (
+	AuditPasswordResetRequested = "password_reset_requested"
+	AuditPasswordReset          = "password_reset"
+	AuditPasswordChanged        = "password_changed"
+	AuditMFAEnabled             = "mfa_enabled"
+	AuditMFADisabled            = "mfa_disabled"
+	AuditMFARecoveryCodeUsed    = "mfa_recovery_code_used"
+	AuditMFARecoveryCodesReset  = "mfa_recovery_codes_regenerated"
+	AuditMFAResetByAdmin        = "mfa_reset_by_admin"
+	AuditMFAPolicyChanged       = "mfa_policy_changed"
+	AuditAccountLocked          = "account_locked"
+	AuditAccountUnlocked        = "account_unlocked"
+	AuditAccountUnlockedByAdmin = "account_unlocked_by_admin"
+	AuditRoleChanged            = "role_changed"
+	AuditAccountSuspended       = "account_suspended"
+	AuditAccountReactivated     = "account_reactivated"
+	AuditPasswordResetForced    = "password_reset_forced"
+	AuditDataExported           = "data_exported"
+	AuditAccountErased          = "account_erased"
+)
+
+type AuditEntry struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID    *uuid.UUID `json:"user_id" gorm:"type:uuid"`
+	Action    string     `json:"action" gorm:"not null"`
+	IPAddress string     `json:"ip_address" gorm:"column:ip_address"`
+	UserAgent string     `json:"user_agent" gorm:"column:user_agent"`
+	CreatedAt time.Time  `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+func (AuditEntry) TableName() string {
+	return "user_audit_log"
+}
+
+// RequestInfo identifies where a request came from, for the audit log
+type RequestInfo struct {
+	IPAddress string
+	UserAgent string
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Avatar is one uploaded profile picture, stored in several sizes
+type Avatar struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primary_key"`
+	UserID     uuid.UUID  `json:"user_id" gorm:"type:uuid;not null"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+	ReplacedAt *time.Time `json:"replaced_at,omitempty" gorm:"column:replaced_at"`
+}
+
+func (Avatar) TableName() string {
+	return "user_avatars"
+}
+
+// AvatarResponse is the updated profile with the URL of every size of the
+// new picture, keyed by width in pixels
+type AvatarResponse struct {
+	User   *User             `json:"user"`
+	Images map[string]string `json:"images"`
+}
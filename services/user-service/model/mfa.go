@@ -0,0 +1,96 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MFAFactor is a user's TOTP authenticator. It is pending until the first
+// code is confirmed.
+type MFAFactor struct {
+	UserID       uuid.UUID  `gorm:"type:uuid;primary_key"`
+	Secret       string     `gorm:"not null"` // encrypted
+	EnabledAt    *time.Time `gorm:"column:enabled_at"`
+	LastUsedStep int64      `gorm:"column:last_used_step"`
+	CreatedAt    time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt    time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+func (MFAFactor) TableName() string {
+	return "user_mfa"
+}
+
+type RecoveryCode struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null"`
+	CodeHash  string     `gorm:"column:code_hash;not null"`
+	UsedAt    *time.Time `gorm:"column:used_at"`
+	CreatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+func (RecoveryCode) TableName() string {
+	return "mfa_recovery_codes"
+}
+
+// MFARolePolicy says whether members of a role must use a second factor
+type MFARolePolicy struct {
+	Role      UserRole  `json:"role" gorm:"primary_key"`
+	Required  bool      `json:"required"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (MFARolePolicy) TableName() string {
+	return "mfa_role_policies"
+}
+
+// MFAChallenge is returned by login instead of tokens when a second factor
+// is needed. With EnrollmentRequired the user must first set one up.
+type MFAChallenge struct {
+	MFARequired        bool   `json:"mfa_required"`
+	EnrollmentRequired bool   `json:"mfa_enrollment_required"`
+	MFAToken           string `json:"mfa_token"`
+	ExpiresIn          int    `json:"expires_in"`
+}
+
+type MFAEnrollment struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+type MFAStatus struct {
+	Enabled                bool `json:"enabled"`
+	Required               bool `json:"required"`
+	RecoveryCodesRemaining int  `json:"recovery_codes_remaining"`
+}
+
+// LoginMFARequest completes a login with either an authenticator code or a
+// recovery code
+type LoginMFARequest struct {
+	MFAToken     string `json:"mfa_token" binding:"required"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+type MFATokenRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+}
+
+type ConfirmMFARequest struct {
+	MFAToken string `json:"mfa_token"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type DisableMFARequest struct {
+	Password     string `json:"password" binding:"required"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+type RegenerateRecoveryCodesRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type UpdateMFAPolicyRequest struct {
+	Required *bool `json:"required" binding:"required"`
+}
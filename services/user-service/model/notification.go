@@ -0,0 +1,28 @@
+package model
+
+import "booking-system/shared/pkg/notify"
+
+// UpdateNotificationSettingsRequest replaces the caller's notification
+// settings other than the per-event preferences. Times of day are "HH:MM" in
+// TimeZone; a null QuietHours turns quiet hours off.
+type UpdateNotificationSettingsRequest struct {
+	ReminderOffsets []int              `json:"reminder_offsets" binding:"max=5,dive,min=1"`
+	QuietHours      *notify.QuietHours `json:"quiet_hours"`
+	TimeZone        string             `json:"time_zone" binding:"required"`
+	Digest          string             `json:"digest" binding:"required,oneof=off daily weekly"`
+	DigestTime      string             `json:"digest_time" binding:"required"`
+	TelegramChatID  string             `json:"telegram_chat_id" binding:"max=64"`
+}
+
+// NotificationPreferenceChange turns one event on or off for one channel
+type NotificationPreferenceChange struct {
+	Event   string `json:"event" binding:"required"`
+	Channel string `json:"channel" binding:"required,oneof=email telegram in_app sms"`
+	Enabled *bool  `json:"enabled" binding:"required"`
+}
+
+// UpdateNotificationPreferencesRequest changes the listed preferences and
+// keeps the others
+type UpdateNotificationPreferencesRequest struct {
+	Preferences []NotificationPreferenceChange `json:"preferences" binding:"required,min=1,dive"`
+}
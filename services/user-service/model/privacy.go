@@ -0,0 +1,75 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Notification is a message sent to a user, read for the data export
+type Notification struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid"`
+	BookingID *uuid.UUID `json:"booking_id,omitempty" gorm:"type:uuid"`
+	Title     string     `json:"title"`
+	Message   string     `json:"message"`
+	Type      string     `json:"type"`
+	IsRead    bool       `json:"is_read"`
+	SentAt    *time.Time `json:"sent_at,omitempty"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// NotificationSettings are the user's notification settings, read for the
+// data export
+type NotificationSettings struct {
+	ID              uuid.UUID     `json:"id" gorm:"type:uuid;primary_key"`
+	UserID          uuid.UUID     `json:"user_id" gorm:"type:uuid"`
+	ReminderOffsets pq.Int64Array `json:"reminder_offsets" gorm:"type:integer[]"`
+	QuietHoursStart *string       `json:"quiet_hours_start,omitempty"`
+	QuietHoursEnd   *string       `json:"quiet_hours_end,omitempty"`
+	TimeZone        string        `json:"time_zone"`
+	Digest          string        `json:"digest"`
+	DigestTime      string        `json:"digest_time"`
+	TelegramChatID  string        `json:"telegram_chat_id"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+}
+
+// NotificationPreference tells whether the user wants an event sent over a
+// channel, read for the data export
+type NotificationPreference struct {
+	UserID  uuid.UUID `json:"user_id" gorm:"type:uuid"`
+	Event   string    `json:"event"`
+	Channel string    `json:"channel"`
+	Enabled bool      `json:"enabled"`
+}
+
+// AccountData is what user-service itself stores about a user
+type AccountData struct {
+	Profile              *User                 `json:"profile"`
+	Sessions             []Session             `json:"sessions"`
+	AuditLog             []AuditEntry          `json:"audit_log"`
+	Notifications        []Notification        `json:"notifications"`
+	NotificationSettings *NotificationSettings `json:"notification_settings"`
+	// NotificationPreferences are the events and channels the user changed
+	// from the defaults
+	NotificationPreferences []NotificationPreference `json:"notification_preferences"`
+}
+
+// DataExport is everything stored about a user across the services. The
+// parts owned by other services are kept as those services returned them.
+type DataExport struct {
+	UserID      uuid.UUID       `json:"user_id"`
+	GeneratedAt time.Time       `json:"generated_at"`
+	Account     *AccountData    `json:"account"`
+	Bookings    json.RawMessage `json:"bookings"`
+	Expert      json.RawMessage `json:"expert"`
+}
+
+type EraseAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
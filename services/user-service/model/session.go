@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is one login on one device. Its ID is the family ID of the refresh
+// tokens rotated from that login.
+type Session struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primary_key"`
+	UserID     uuid.UUID  `json:"user_id" gorm:"type:uuid;not null"`
+	UserAgent  string     `json:"user_agent" gorm:"column:user_agent"`
+	IPAddress  string     `json:"ip_address" gorm:"column:ip_address"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+	LastUsedAt time.Time  `json:"last_used_at" gorm:"column:last_used_at"`
+	ExpiresAt  time.Time  `json:"expires_at" gorm:"not null"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" gorm:"column:revoked_at"`
+	// Current marks the session the request was made from
+	Current bool `json:"current" gorm:"-"`
+}
+
+func (Session) TableName() string {
+	return "user_sessions"
+}
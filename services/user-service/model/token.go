@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken is one issued refresh token; only its hash is stored. Tokens
+// rotated from the same login share a FamilyID.
+type RefreshToken struct {
+	ID              uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID          uuid.UUID  `gorm:"type:uuid;not null"`
+	FamilyID        uuid.UUID  `gorm:"type:uuid;not null"`
+	TokenHash       string     `gorm:"column:token_hash;not null"`
+	ExpiresAt       time.Time  `gorm:"not null"`
+	IsRevoked       bool       `gorm:"column:is_revoked;default:false"`
+	RevokedAt       *time.Time `gorm:"column:revoked_at"`
+	ReplacedBy      *uuid.UUID `gorm:"type:uuid;column:replaced_by"`
+	AccessTokenID   string     `gorm:"column:access_token_id"`
+	AccessExpiresAt time.Time  `gorm:"column:access_expires_at"`
+	CreatedAt       time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// LiveAccessToken is an access token that has not expired yet
+type LiveAccessToken struct {
+	ID        string
+	ExpiresAt time.Time
+}
+
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
@@ -14,19 +14,39 @@ const (
 	RoleUser   UserRole = "user"
 )
 
+type UserStatus string
+
+const (
+	StatusActive    UserStatus = "active"
+	StatusSuspended UserStatus = "suspended"
+	// Erased accounts had their personal data removed on request; the row
+	// stays for the bookings and reviews that refer to it
+	StatusErased UserStatus = "erased"
+)
+
 type User struct {
-	ID            uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	Email         string    `json:"email" gorm:"unique;not null"`
-	PasswordHash  string    `json:"-" gorm:"column:password_hash;not null"`
-	FullName      string    `json:"fullname" gorm:"column:fullname;not null"`
-	Phone         string    `json:"phone"`
-	Image         string    `json:"image"`
-	Gender        string    `json:"gender"`
-	Description   string    `json:"description"`
-	Role          UserRole  `json:"role" gorm:"default:'user'"`
-	EmailVerified bool      `json:"email_verified" gorm:"default:false"`
-	CreatedAt     time.Time `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
-	UpdatedAt     time.Time `json:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
+	ID                    uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Email                 string     `json:"email" gorm:"unique;not null"`
+	PasswordHash          string     `json:"-" gorm:"column:password_hash;not null"`
+	FullName              string     `json:"fullname" gorm:"column:fullname;not null"`
+	Phone                 string     `json:"phone"`
+	Image                 string     `json:"image"`
+	Gender                string     `json:"gender"`
+	Description           string     `json:"description"`
+	Language              string     `json:"language,omitempty"`
+	Role                  UserRole   `json:"role" gorm:"default:'user'"`
+	EmailVerified         bool       `json:"email_verified" gorm:"default:false"`
+	EmailVerifiedAt       *time.Time `json:"email_verified_at,omitempty" gorm:"column:email_verified_at"`
+	VerificationSentAt    *time.Time `json:"-" gorm:"column:email_verification_sent_at"`
+	LockedUntil           *time.Time `json:"locked_until,omitempty" gorm:"column:locked_until"`
+	Status                UserStatus `json:"status" gorm:"default:'active'"`
+	SuspendedAt           *time.Time `json:"suspended_at,omitempty" gorm:"column:suspended_at"`
+	SuspensionReason      string     `json:"suspension_reason,omitempty" gorm:"column:suspension_reason"`
+	RequestedRole         *UserRole  `json:"requested_role,omitempty" gorm:"column:requested_role"`
+	PasswordResetRequired bool       `json:"password_reset_required" gorm:"column:password_reset_required"`
+	ErasedAt              *time.Time `json:"erased_at,omitempty" gorm:"column:erased_at"`
+	CreatedAt             time.Time  `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt             time.Time  `json:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
 }
 
 type RegisterRequest struct {
@@ -35,7 +55,8 @@ type RegisterRequest struct {
 	FullName string `json:"fullname" binding:"required"`
 	Phone    string `json:"phone"`
 	Gender   string `json:"gender"`
-	Role     string `json:"role" binding:"required,oneof=admin expert user"`
+	// Admins are only created by other admins; experts need approval
+	Role string `json:"role" binding:"omitempty,oneof=expert user"`
 }
 
 type LoginRequest struct {
@@ -43,10 +64,43 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+// UpdateProfileRequest has no image; pictures are uploaded, see AvatarService.
+// Language is the language of API messages, "vi" or "en".
 type UpdateProfileRequest struct {
 	FullName    string `json:"fullname"`
 	Phone       string `json:"phone"`
-	Image       string `json:"image"`
 	Gender      string `json:"gender"`
 	Description string `json:"description"`
+	Language    string `json:"language" binding:"omitempty,oneof=vi en"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type UnlockAccountRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// UserFilter selects users for the admin list. Query matches email, name
+// and phone.
+type UserFilter struct {
+	Query         string
+	Role          UserRole
+	Status        UserStatus
+	RequestedRole UserRole
+	Page          int
+	Limit         int
+}
+
+type ChangeRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=admin expert user"`
+}
+
+type SuspendUserRequest struct {
+	Reason string `json:"reason" binding:"required"`
 }
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"services/user-service/model"
+
+	"gorm.io/gorm"
+)
+
+type AuditRepository interface {
+	Record(entry *model.AuditEntry) error
+}
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{db}
+}
+
+func (r *auditRepository) Record(entry *model.AuditEntry) error {
+	return r.db.Create(entry).Error
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"services/user-service/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AvatarRepository interface {
+	Replace(avatar *model.Avatar, imageURL string) error
+	Remove(userID uuid.UUID, now time.Time) error
+	FindReplacedBefore(before time.Time, limit int) ([]model.Avatar, error)
+	Delete(id uuid.UUID) error
+}
+
+type avatarRepository struct {
+	db *gorm.DB
+}
+
+func NewAvatarRepository(db *gorm.DB) AvatarRepository {
+	return &avatarRepository{db}
+}
+
+// Replace makes avatar the user's current picture and points the profile at
+// it; the previous picture is left for garbage collection
+func (r *avatarRepository) Replace(avatar *model.Avatar, imageURL string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := markReplaced(tx, avatar.UserID, avatar.CreatedAt); err != nil {
+			return err
+		}
+		if err := tx.Create(avatar).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.User{}).Where("id = ?", avatar.UserID).
+			Update("image", imageURL).Error
+	})
+}
+
+// Remove clears the user's picture
+func (r *avatarRepository) Remove(userID uuid.UUID, now time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := markReplaced(tx, userID, now); err != nil {
+			return err
+		}
+		return tx.Model(&model.User{}).Where("id = ?", userID).
+			Update("image", nil).Error
+	})
+}
+
+func (r *avatarRepository) FindReplacedBefore(before time.Time, limit int) ([]model.Avatar, error) {
+	var avatars []model.Avatar
+	err := r.db.Where("replaced_at IS NOT NULL AND replaced_at < ?", before).
+		Order("replaced_at").
+		Limit(limit).
+		Find(&avatars).Error
+	return avatars, err
+}
+
+func (r *avatarRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&model.Avatar{}, "id = ?", id).Error
+}
+
+// markReplaced retires the user's current picture, if any
+func markReplaced(tx *gorm.DB, userID uuid.UUID, at time.Time) error {
+	return tx.Model(&model.Avatar{}).
+		Where("user_id = ? AND replaced_at IS NULL", userID).
+		Update("replaced_at", at).Error
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	loginFailuresPrefix = "auth:login:failures:"
+	loginDelayPrefix    = "auth:login:delay:"
+)
+
+// LoginAttempts counts failed logins per key (an account or an IP) in Redis,
+// so all replicas of user-service share them
+type LoginAttempts interface {
+	RecordFailure(key string, window time.Duration) (int, error)
+	SetDelay(key string, delay time.Duration) error
+	Delay(key string) (time.Duration, error)
+	Reset(key string) error
+}
+
+type redisLoginAttempts struct {
+	client *redis.Client
+}
+
+func NewLoginAttempts(client *redis.Client) LoginAttempts {
+	return &redisLoginAttempts{client}
+}
+
+// RecordFailure returns the number of failures within window, which starts
+// at the first failure
+func (a *redisLoginAttempts) RecordFailure(key string, window time.Duration) (int, error) {
+	ctx := context.Background()
+	var count *redis.IntCmd
+	_, err := a.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		count = pipe.Incr(ctx, loginFailuresPrefix+key)
+		pipe.ExpireNX(ctx, loginFailuresPrefix+key, window)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(count.Val()), nil
+}
+
+func (a *redisLoginAttempts) SetDelay(key string, delay time.Duration) error {
+	return a.client.Set(context.Background(), loginDelayPrefix+key, 1, delay).Err()
+}
+
+// Delay returns how long logins for key must still wait, zero when they can
+// go ahead
+func (a *redisLoginAttempts) Delay(key string) (time.Duration, error) {
+	ttl, err := a.client.PTTL(context.Background(), loginDelayPrefix+key).Result()
+	if err != nil || ttl < 0 {
+		return 0, err
+	}
+	return ttl, nil
+}
+
+func (a *redisLoginAttempts) Reset(key string) error {
+	return a.client.Del(context.Background(), loginFailuresPrefix+key, loginDelayPrefix+key).Err()
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"services/user-service/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MFARepository interface {
+	FindFactor(userID uuid.UUID) (*model.MFAFactor, error)
+	SavePendingFactor(userID uuid.UUID, secret string, now time.Time) error
+	EnableFactor(userID uuid.UUID, step int64, codeHashes []string, now time.Time) (bool, error)
+	ClaimStep(userID uuid.UUID, step int64) (bool, error)
+	DeleteFactor(userID uuid.UUID) error
+	ReplaceRecoveryCodes(userID uuid.UUID, codeHashes []string) error
+	ConsumeRecoveryCode(userID uuid.UUID, codeHash string, now time.Time) (bool, error)
+	CountRecoveryCodes(userID uuid.UUID) (int, error)
+	ListPolicies() ([]model.MFARolePolicy, error)
+	IsRequired(role model.UserRole) (bool, error)
+	SetPolicy(role model.UserRole, required bool, now time.Time) error
+}
+
+type mfaRepository struct {
+	db *gorm.DB
+}
+
+func NewMFARepository(db *gorm.DB) MFARepository {
+	return &mfaRepository{db}
+}
+
+func (r *mfaRepository) FindFactor(userID uuid.UUID) (*model.MFAFactor, error) {
+	var factor model.MFAFactor
+	if err := r.db.Where("user_id = ?", userID).First(&factor).Error; err != nil {
+		return nil, err
+	}
+	return &factor, nil
+}
+
+// SavePendingFactor stores a new secret awaiting confirmation, replacing an
+// earlier unconfirmed one. An enabled factor is left untouched.
+func (r *mfaRepository) SavePendingFactor(userID uuid.UUID, secret string, now time.Time) error {
+	factor := model.MFAFactor{UserID: userID, Secret: secret, CreatedAt: now, UpdatedAt: now}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "last_used_step", "created_at", "updated_at"}),
+		Where:     clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "user_mfa.enabled_at IS NULL"}}},
+	}).Create(&factor).Error
+}
+
+// EnableFactor activates a pending factor together with its first recovery
+// codes. It returns false when there was no pending factor to enable.
+func (r *mfaRepository) EnableFactor(userID uuid.UUID, step int64, codeHashes []string, now time.Time) (bool, error) {
+	enabled := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.MFAFactor{}).
+			Where("user_id = ? AND enabled_at IS NULL", userID).
+			Updates(map[string]interface{}{"enabled_at": now, "last_used_step": step, "updated_at": now})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		enabled = true
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+	return enabled, err
+}
+
+// ClaimStep records the time step of an accepted code. The check and the
+// update are one statement, so a code works only once even when submitted
+// concurrently.
+func (r *mfaRepository) ClaimStep(userID uuid.UUID, step int64) (bool, error) {
+	res := r.db.Model(&model.MFAFactor{}).
+		Where("user_id = ? AND enabled_at IS NOT NULL AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	return res.RowsAffected == 1, res.Error
+}
+
+// DeleteFactor removes the factor and its recovery codes
+func (r *mfaRepository) DeleteFactor(userID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&model.MFAFactor{}).Error
+	})
+}
+
+func (r *mfaRepository) ReplaceRecoveryCodes(userID uuid.UUID, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID uuid.UUID, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	codes := make([]model.RecoveryCode, len(codeHashes))
+	for i, hash := range codeHashes {
+		codes[i] = model.RecoveryCode{ID: uuid.New(), UserID: userID, CodeHash: hash}
+	}
+	return tx.Create(&codes).Error
+}
+
+// ConsumeRecoveryCode marks an unused code as used in a single statement
+func (r *mfaRepository) ConsumeRecoveryCode(userID uuid.UUID, codeHash string, now time.Time) (bool, error) {
+	res := r.db.Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", now)
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *mfaRepository) CountRecoveryCodes(userID uuid.UUID) (int, error) {
+	var count int64
+	err := r.db.Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return int(count), err
+}
+
+func (r *mfaRepository) ListPolicies() ([]model.MFARolePolicy, error) {
+	var policies []model.MFARolePolicy
+	err := r.db.Order("role").Find(&policies).Error
+	return policies, err
+}
+
+func (r *mfaRepository) IsRequired(role model.UserRole) (bool, error) {
+	var policies []model.MFARolePolicy
+	if err := r.db.Where("role = ?", role).Limit(1).Find(&policies).Error; err != nil {
+		return false, err
+	}
+	return len(policies) == 1 && policies[0].Required, nil
+}
+
+func (r *mfaRepository) SetPolicy(role model.UserRole, required bool, now time.Time) error {
+	policy := model.MFARolePolicy{Role: role, Required: required, UpdatedAt: now}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "role"}},
+		DoUpdates: clause.AssignmentColumns([]string{"required", "updated_at"}),
+	}).Create(&policy).Error
+}
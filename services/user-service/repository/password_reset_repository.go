@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"services/user-service/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PasswordResetRepository interface {
+	Create(token *model.PasswordResetToken) error
+	Consume(tokenHash string, now time.Time) (uuid.UUID, error)
+	InvalidateForUser(userID uuid.UUID, now time.Time) error
+}
+
+type passwordResetRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetRepository(db *gorm.DB) PasswordResetRepository {
+	return &passwordResetRepository{db}
+}
+
+func (r *passwordResetRepository) Create(token *model.PasswordResetToken) error {
+	return r.db.Create(token).Error
+}
+
+// Consume marks an unused, unexpired token as used and returns its user.
+// The check and the update are one statement, so a token works only once.
+// gorm.ErrRecordNotFound is returned when no such token exists.
+func (r *passwordResetRepository) Consume(tokenHash string, now time.Time) (uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	err := r.db.Raw(`
+        UPDATE password_reset_tokens SET used_at = ?
+        WHERE token_hash = ? AND used_at IS NULL AND expires_at > ?
+        RETURNING user_id`, now, tokenHash, now).Scan(&userIDs).Error
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if len(userIDs) == 0 {
+		return uuid.Nil, gorm.ErrRecordNotFound
+	}
+	return userIDs[0], nil
+}
+
+// InvalidateForUser marks every outstanding token of the user as used
+func (r *passwordResetRepository) InvalidateForUser(userID uuid.UUID, now time.Time) error {
+	return r.db.Model(&model.PasswordResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", now).Error
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"services/user-service/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PrivacyRepository reads everything user-service stores about a user for
+// their data export, and erases it
+type PrivacyRepository interface {
+	GetAccountData(userID uuid.UUID) (*model.AccountData, error)
+	Erase(userID uuid.UUID, at time.Time) error
+}
+
+type privacyRepository struct {
+	db *gorm.DB
+}
+
+func NewPrivacyRepository(db *gorm.DB) PrivacyRepository {
+	return &privacyRepository{db}
+}
+
+func (r *privacyRepository) GetAccountData(userID uuid.UUID) (*model.AccountData, error) {
+	data := &model.AccountData{
+		Sessions:      []model.Session{},
+		AuditLog:      []model.AuditEntry{},
+		Notifications: []model.Notification{},
+	}
+	data.NotificationPreferences = []model.NotificationPreference{}
+	var user model.User
+	if err := r.db.Where("id = ?", userID).First(&user).Error; err != nil {
+		return nil, err
+	}
+	data.Profile = &user
+
+	if err := r.db.Where("user_id = ?", userID).Order("created_at").Find(&data.Sessions).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Where("user_id = ?", userID).Order("created_at").Find(&data.AuditLog).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Where("user_id = ?", userID).Order("created_at").Find(&data.Notifications).Error; err != nil {
+		return nil, err
+	}
+	var settings model.NotificationSettings
+	err := r.db.Where("user_id = ?", userID).First(&settings).Error
+	switch {
+	case err == nil:
+		data.NotificationSettings = &settings
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+	if err := r.db.Where("user_id = ?", userID).Order("event, channel").Find(&data.NotificationPreferences).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Erase replaces the personal data on the user row and deletes the data that
+// only matters to the user: logins, MFA, reset links and notifications. The
+// row stays, marked erased, so the bookings and reviews pointing at it keep
+// working. The audit log keeps the actions but forgets where they came from.
+func (r *privacyRepository) Erase(userID uuid.UUID, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.User{}).Where("id = ?", userID).
+			Updates(map[string]interface{}{
+				"email":                      fmt.Sprintf("deleted-%s@deleted.invalid", userID),
+				"password_hash":              "",
+				"fullname":                   "Deleted user",
+				"phone":                      nil,
+				"image":                      nil,
+				"gender":                     nil,
+				"description":                nil,
+				"email_verified":             false,
+				"email_verified_at":          nil,
+				"email_verification_sent_at": nil,
+				"locked_until":               nil,
+				"suspended_at":               nil,
+				"suspension_reason":          nil,
+				"requested_role":             nil,
+				"password_reset_required":    false,
+				"status":                     model.StatusErased,
+				"erased_at":                  at,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		// The picture files go with the next garbage collection
+		if err := markReplaced(tx, userID, at); err != nil {
+			return err
+		}
+		for _, table := range []string{"refresh_tokens", "user_sessions", "user_mfa", "mfa_recovery_codes",
+			"password_reset_tokens", "notifications", "notification_settings",
+			"notification_preferences", "notification_outbox"} {
+			if err := tx.Exec("DELETE FROM "+table+" WHERE user_id = ?", userID).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&model.AuditEntry{}).Where("user_id = ?", userID).
+			Updates(map[string]interface{}{"ip_address": nil, "user_agent": nil}).Error
+	})
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"errors"
+	"services/user-service/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var errAlreadyRevoked = errors.New("refresh token already revoked")
+
+type RefreshTokenRepository interface {
+	Create(token *model.RefreshToken) error
+	FindByHash(tokenHash string) (*model.RefreshToken, error)
+	Rotate(oldID uuid.UUID, next *model.RefreshToken, now time.Time) (bool, error)
+	RevokeFamily(familyID uuid.UUID, now time.Time) error
+	RevokeAllForUser(userID uuid.UUID, now time.Time) error
+	LiveAccessTokensByFamily(familyID uuid.UUID, now time.Time) ([]model.LiveAccessToken, error)
+	LiveAccessTokensByUser(userID uuid.UUID, now time.Time) ([]model.LiveAccessToken, error)
+}
+
+type refreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{db}
+}
+
+func (r *refreshTokenRepository) Create(token *model.RefreshToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *refreshTokenRepository) FindByHash(tokenHash string) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Rotate revokes the old token and stores its replacement in one
+// transaction. It reports false, storing nothing, when the old token was
+// already revoked, so two concurrent refreshes with the same token cannot
+// both succeed.
+func (r *refreshTokenRepository) Rotate(oldID uuid.UUID, next *model.RefreshToken, now time.Time) (bool, error) {
+	rotated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+		res := tx.Model(&model.RefreshToken{}).
+			Where("id = ? AND is_revoked = false", oldID).
+			Updates(map[string]interface{}{"is_revoked": true, "revoked_at": now, "replaced_by": next.ID})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			// Roll back the replacement
+			return errAlreadyRevoked
+		}
+		rotated = true
+		return nil
+	})
+	if err == errAlreadyRevoked {
+		return false, nil
+	}
+	return rotated, err
+}
+
+func (r *refreshTokenRepository) RevokeFamily(familyID uuid.UUID, now time.Time) error {
+	return r.db.Model(&model.RefreshToken{}).
+		Where("family_id = ? AND is_revoked = false", familyID).
+		Updates(map[string]interface{}{"is_revoked": true, "revoked_at": now}).Error
+}
+
+func (r *refreshTokenRepository) RevokeAllForUser(userID uuid.UUID, now time.Time) error {
+	return r.db.Model(&model.RefreshToken{}).
+		Where("user_id = ? AND is_revoked = false", userID).
+		Updates(map[string]interface{}{"is_revoked": true, "revoked_at": now}).Error
+}
+
+// LiveAccessTokensByFamily returns the access tokens issued alongside the
+// family's refresh tokens that have not expired yet, rotated ones included
+func (r *refreshTokenRepository) LiveAccessTokensByFamily(familyID uuid.UUID, now time.Time) ([]model.LiveAccessToken, error) {
+	return r.liveAccessTokens("family_id = ?", familyID, now)
+}
+
+func (r *refreshTokenRepository) LiveAccessTokensByUser(userID uuid.UUID, now time.Time) ([]model.LiveAccessToken, error) {
+	return r.liveAccessTokens("user_id = ?", userID, now)
+}
+
+func (r *refreshTokenRepository) liveAccessTokens(condition string, value interface{}, now time.Time) ([]model.LiveAccessToken, error) {
+	var tokens []model.LiveAccessToken
+	err := r.db.Model(&model.RefreshToken{}).
+		Select("access_token_id AS id, access_expires_at AS expires_at").
+		Where(condition, value).
+		Where("access_token_id IS NOT NULL AND access_expires_at > ?", now).
+		Scan(&tokens).Error
+	return tokens, err
+}
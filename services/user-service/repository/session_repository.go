@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"services/user-service/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SessionRepository interface {
+	Create(session *model.Session) error
+	FindByID(id uuid.UUID) (*model.Session, error)
+	ListActiveByUser(userID uuid.UUID, now time.Time) ([]model.Session, error)
+	Touch(id uuid.UUID, ipAddress string, now, expiresAt time.Time) error
+	Revoke(id uuid.UUID, now time.Time) error
+	RevokeAllForUser(userID uuid.UUID, now time.Time) error
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db}
+}
+
+func (r *sessionRepository) Create(session *model.Session) error {
+	return r.db.Create(session).Error
+}
+
+func (r *sessionRepository) FindByID(id uuid.UUID) (*model.Session, error) {
+	var session model.Session
+	if err := r.db.Where("id = ?", id).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListActiveByUser returns the sessions that are neither revoked nor
+// expired, most recently used first
+func (r *sessionRepository) ListActiveByUser(userID uuid.UUID, now time.Time) ([]model.Session, error) {
+	var sessions []model.Session
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_used_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// Touch records a refresh: when and from where the session was last used
+// and its new expiry
+func (r *sessionRepository) Touch(id uuid.UUID, ipAddress string, now, expiresAt time.Time) error {
+	return r.db.Model(&model.Session{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"ip_address": ipAddress, "last_used_at": now, "expires_at": expiresAt}).Error
+}
+
+func (r *sessionRepository) Revoke(id uuid.UUID, now time.Time) error {
+	return r.db.Model(&model.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", now).Error
+}
+
+func (r *sessionRepository) RevokeAllForUser(userID uuid.UUID, now time.Time) error {
+	return r.db.Model(&model.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", now).Error
+}
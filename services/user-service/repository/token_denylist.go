@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// accessTokenDenylistPrefix must match the key the api-gateway checks
+const accessTokenDenylistPrefix = "auth:denylist:"
+
+// TokenDenylist holds the IDs of revoked access tokens until they expire
+type TokenDenylist interface {
+	Add(tokenID string, ttl time.Duration) error
+	Contains(tokenID string) (bool, error)
+}
+
+type redisTokenDenylist struct {
+	client *redis.Client
+}
+
+func NewTokenDenylist(client *redis.Client) TokenDenylist {
+	return &redisTokenDenylist{client}
+}
+
+func (d *redisTokenDenylist) Add(tokenID string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	return d.client.Set(context.Background(), accessTokenDenylistPrefix+tokenID, 1, ttl).Err()
+}
+
+func (d *redisTokenDenylist) Contains(tokenID string) (bool, error) {
+	n, err := d.client.Exists(context.Background(), accessTokenDenylistPrefix+tokenID).Result()
+	return n > 0, err
+}
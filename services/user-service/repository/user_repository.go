@@ -2,6 +2,7 @@ package repository
 
 import (
 	"services/user-service/model"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -12,7 +13,17 @@ type UserRepository interface {
 	FindByEmail(email string) (*model.User, error)
 	FindByID(id uuid.UUID) (*model.User, error)
 	Update(user *model.User) error
-	Delete(id uuid.UUID) error
+	ClaimVerificationSend(id uuid.UUID, now time.Time, cooldown time.Duration) (bool, error)
+	MarkEmailVerified(id uuid.UUID, at time.Time) error
+	UpdatePassword(id uuid.UUID, passwordHash string) error
+	Lock(id uuid.UUID, until, now time.Time) (bool, error)
+	Unlock(id uuid.UUID) error
+	Search(filter model.UserFilter) ([]model.User, int64, error)
+	CountByRole(role model.UserRole) (int64, error)
+	UpdateRole(id uuid.UUID, role model.UserRole) error
+	Suspend(id uuid.UUID, reason string, at time.Time) error
+	Reactivate(id uuid.UUID) error
+	RequirePasswordReset(id uuid.UUID) error
 }
 
 type userRepository struct {
@@ -43,10 +54,104 @@ func (r *userRepository) FindByID(id uuid.UUID) (*model.User, error) {
 	return &user, nil
 }
 
+// Update saves the profile fields only, so it cannot undo a concurrent
+// change of status, role, lock or picture
 func (r *userRepository) Update(user *model.User) error {
-	return r.db.Save(user).Error
+	return r.db.Model(user).
+		Select("fullname", "phone", "gender", "description", "language", "updated_at").
+		Updates(user).Error
 }
 
-func (r *userRepository) Delete(id uuid.UUID) error {
-	return r.db.Delete(&model.User{}, "id = ?", id).Error
+// ClaimVerificationSend records that a verification email is being sent,
+// unless one was sent less than cooldown ago. The check and the update are a
+// single statement, so concurrent resends cannot both pass.
+func (r *userRepository) ClaimVerificationSend(id uuid.UUID, now time.Time, cooldown time.Duration) (bool, error) {
+	res := r.db.Model(&model.User{}).
+		Where("id = ? AND (email_verification_sent_at IS NULL OR email_verification_sent_at <= ?)", id, now.Add(-cooldown)).
+		Update("email_verification_sent_at", now)
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *userRepository) MarkEmailVerified(id uuid.UUID, at time.Time) error {
+	return r.db.Model(&model.User{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"email_verified": true, "email_verified_at": at}).Error
+}
+
+// UpdatePassword also clears a reset forced by an admin
+func (r *userRepository) UpdatePassword(id uuid.UUID, passwordHash string) error {
+	return r.db.Model(&model.User{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"password_hash": passwordHash, "password_reset_required": false}).Error
+}
+
+// Lock locks the account until the given time, unless it is already locked.
+// It returns false when it was.
+func (r *userRepository) Lock(id uuid.UUID, until, now time.Time) (bool, error) {
+	res := r.db.Model(&model.User{}).
+		Where("id = ? AND (locked_until IS NULL OR locked_until <= ?)", id, now).
+		Update("locked_until", until)
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *userRepository) Unlock(id uuid.UUID) error {
+	return r.db.Model(&model.User{}).Where("id = ?", id).
+		Update("locked_until", nil).Error
+}
+
+func (r *userRepository) Search(filter model.UserFilter) ([]model.User, int64, error) {
+	query := r.db.Model(&model.User{})
+	if filter.Query != "" {
+		pattern := "%" + filter.Query + "%"
+		query = query.Where("email ILIKE ? OR fullname ILIKE ? OR phone ILIKE ?", pattern, pattern, pattern)
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.RequestedRole != "" {
+		query = query.Where("requested_role = ?", filter.RequestedRole)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var users []model.User
+	err := query.Order("created_at DESC").
+		Offset((filter.Page - 1) * filter.Limit).
+		Limit(filter.Limit).
+		Find(&users).Error
+	return users, total, err
+}
+
+func (r *userRepository) CountByRole(role model.UserRole) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.User{}).Where("role = ?", role).Count(&count).Error
+	return count, err
+}
+
+// UpdateRole sets the role and drops a pending request for it
+func (r *userRepository) UpdateRole(id uuid.UUID, role model.UserRole) error {
+	return r.db.Model(&model.User{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"role":           role,
+			"requested_role": gorm.Expr("CASE WHEN requested_role = ? THEN NULL ELSE requested_role END", role),
+		}).Error
+}
+
+func (r *userRepository) Suspend(id uuid.UUID, reason string, at time.Time) error {
+	return r.db.Model(&model.User{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"status": model.StatusSuspended, "suspended_at": at, "suspension_reason": reason}).Error
+}
+
+func (r *userRepository) Reactivate(id uuid.UUID) error {
+	return r.db.Model(&model.User{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"status": model.StatusActive, "suspended_at": nil, "suspension_reason": nil}).Error
+}
+
+// RequirePasswordReset blocks logins until the password is reset by email
+func (r *userRepository) RequirePasswordReset(id uuid.UUID) error {
+	return r.db.Model(&model.User{}).Where("id = ?", id).
+		Update("password_reset_required", true).Error
 }
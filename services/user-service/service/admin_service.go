@@ -0,0 +1,106 @@
+package service
+
+import (
+	"services/user-service/model"
+	"services/user-service/repository"
+	"time"
+
+	"booking-system/shared/pkg/apperr"
+
+	"github.com/google/uuid"
+)
+
+var ErrCannotModifySelf = apperr.Forbidden("cannot_modify_self")
+
+// AdminService is the user management available to admins. Role and status
+// changes sign the user out everywhere, so they take effect immediately.
+type AdminService interface {
+	ListUsers(filter model.UserFilter) ([]model.User, int64, error)
+	GetUser(userID uuid.UUID) (*model.User, error)
+	ChangeRole(adminID, userID uuid.UUID, role model.UserRole, info model.RequestInfo) (*model.User, error)
+	Suspend(adminID, userID uuid.UUID, reason string, info model.RequestInfo) (*model.User, error)
+	Reactivate(userID uuid.UUID, info model.RequestInfo) (*model.User, error)
+}
+
+type adminService struct {
+	repo      repository.UserRepository
+	auditRepo repository.AuditRepository
+	tokens    TokenService
+}
+
+func NewAdminService(repo repository.UserRepository, auditRepo repository.AuditRepository, tokens TokenService) AdminService {
+	return &adminService{repo, auditRepo, tokens}
+}
+
+func (s *adminService) ListUsers(filter model.UserFilter) ([]model.User, int64, error) {
+	return s.repo.Search(filter)
+}
+
+func (s *adminService) GetUser(userID uuid.UUID) (*model.User, error) {
+	return s.repo.FindByID(userID)
+}
+
+// ChangeRole also approves a role requested at registration
+func (s *adminService) ChangeRole(adminID, userID uuid.UUID, role model.UserRole, info model.RequestInfo) (*model.User, error) {
+	if adminID == userID {
+		return nil, ErrCannotModifySelf
+	}
+	switch role {
+	case model.RoleAdmin, model.RoleExpert, model.RoleUser:
+	default:
+		return nil, ErrUnknownRole
+	}
+	if err := s.checkNotErased(userID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateRole(userID, role); err != nil {
+		return nil, err
+	}
+	// Access tokens carry the role
+	if err := s.tokens.RevokeAll(userID); err != nil {
+		return nil, err
+	}
+	recordAudit(s.auditRepo, userID, model.AuditRoleChanged, info)
+	return s.repo.FindByID(userID)
+}
+
+func (s *adminService) Suspend(adminID, userID uuid.UUID, reason string, info model.RequestInfo) (*model.User, error) {
+	if adminID == userID {
+		return nil, ErrCannotModifySelf
+	}
+	if err := s.checkNotErased(userID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Suspend(userID, reason, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.tokens.RevokeAll(userID); err != nil {
+		return nil, err
+	}
+	recordAudit(s.auditRepo, userID, model.AuditAccountSuspended, info)
+	return s.repo.FindByID(userID)
+}
+
+func (s *adminService) Reactivate(userID uuid.UUID, info model.RequestInfo) (*model.User, error) {
+	if err := s.checkNotErased(userID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Reactivate(userID); err != nil {
+		return nil, err
+	}
+	recordAudit(s.auditRepo, userID, model.AuditAccountReactivated, info)
+	return s.repo.FindByID(userID)
+}
+
+// checkNotErased fails for unknown users and for erased accounts, which can
+// no longer be changed
+func (s *adminService) checkNotErased(userID uuid.UUID) error {
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.Status == model.StatusErased {
+		return ErrAccountErased
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"services/user-service/imaging"
+	"services/user-service/model"
+	"services/user-service/repository"
+	"strconv"
+	"strings"
+	"time"
+
+	"booking-system/shared/pkg/apperr"
+	"booking-system/shared/pkg/storage"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxAvatarSize is the largest picture a user may upload
+	MaxAvatarSize = 5 << 20
+	// maxAvatarPixels guards against small files that decode to huge images
+	maxAvatarPixels = 40_000_000
+	// profileAvatarSize is the size users.image points at
+	profileAvatarSize = 256
+	avatarGCBatchSize = 100
+)
+
+// AvatarSizes are the square sizes, in pixels, every picture is stored in,
+// largest first
+var AvatarSizes = []int{512, 256, 64}
+
+var (
+	ErrUnsupportedImage = apperr.UnsupportedMedia("unsupported_image")
+	ErrImageTooLarge    = apperr.TooLarge("image_too_large")
+	ErrAvatarNotFound   = apperr.NotFound("avatar_not_found")
+)
+
+// AvatarService stores profile pictures. Uploads are re-encoded as square
+// JPEGs in every AvatarSizes size, which strips their metadata. Each upload
+// gets new URLs, so they can be cached forever; replaced pictures are
+// deleted after a grace period by CollectGarbage.
+type AvatarService interface {
+	Upload(userID uuid.UUID, data []byte) (*model.AvatarResponse, error)
+	Remove(userID uuid.UUID) (*model.User, error)
+	Open(userID, avatarID uuid.UUID, file string) (io.ReadCloser, error)
+	CollectGarbage() error
+}
+
+type avatarService struct {
+	repo       repository.UserRepository
+	avatarRepo repository.AvatarRepository
+	store      storage.FileStore
+	baseURL    string
+	grace      time.Duration
+}
+
+// NewAvatarService serves pictures under baseURL, e.g.
+// http://localhost:8081/avatars, and keeps replaced ones for grace
+func NewAvatarService(repo repository.UserRepository, avatarRepo repository.AvatarRepository, store storage.FileStore, baseURL string, grace time.Duration) AvatarService {
+	return &avatarService{
+		repo:       repo,
+		avatarRepo: avatarRepo,
+		store:      store,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		grace:      grace,
+	}
+}
+
+func (s *avatarService) Upload(userID uuid.UUID, data []byte) (*model.AvatarResponse, error) {
+	if len(data) > MaxAvatarSize {
+		return nil, ErrImageTooLarge
+	}
+	img, orientation, err := imaging.Decode(data, maxAvatarPixels)
+	if errors.Is(err, imaging.ErrTooManyPixels) {
+		return nil, ErrImageTooLarge
+	}
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+
+	avatar := &model.Avatar{ID: uuid.New(), UserID: userID, CreatedAt: time.Now()}
+	images := make(map[string]string, len(AvatarSizes))
+	// Smaller sizes are scaled from the largest rather than the upload
+	var source image.Image = img
+	for _, size := range AvatarSizes {
+		thumbnail := imaging.Thumbnail(source, orientation, size)
+		source, orientation = thumbnail, 1
+		encoded, err := imaging.EncodeJPEG(thumbnail)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.store.Save(avatarKey(userID, avatar.ID, size), encoded); err != nil {
+			s.deleteFiles(*avatar)
+			return nil, err
+		}
+		images[strconv.Itoa(size)] = s.url(userID, avatar.ID, size)
+	}
+
+	if err := s.avatarRepo.Replace(avatar, s.url(userID, avatar.ID, profileAvatarSize)); err != nil {
+		s.deleteFiles(*avatar)
+		return nil, err
+	}
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	user.PasswordHash = ""
+	return &model.AvatarResponse{User: user, Images: images}, nil
+}
+
+func (s *avatarService) Remove(userID uuid.UUID) (*model.User, error) {
+	if err := s.avatarRepo.Remove(userID, time.Now()); err != nil {
+		return nil, err
+	}
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	user.PasswordHash = ""
+	return user, nil
+}
+
+// Open returns one stored size of a picture; file is "<size>.jpg"
+func (s *avatarService) Open(userID, avatarID uuid.UUID, file string) (io.ReadCloser, error) {
+	size, err := strconv.Atoi(strings.TrimSuffix(file, ".jpg"))
+	if err != nil || !strings.HasSuffix(file, ".jpg") || !isAvatarSize(size) {
+		return nil, ErrAvatarNotFound
+	}
+	content, err := s.store.Open(avatarKey(userID, avatarID, size))
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, ErrAvatarNotFound
+	}
+	return content, err
+}
+
+// CollectGarbage deletes the files of pictures replaced more than the grace
+// period ago, then their records
+func (s *avatarService) CollectGarbage() error {
+	before := time.Now().Add(-s.grace)
+	deleted := 0
+	for {
+		avatars, err := s.avatarRepo.FindReplacedBefore(before, avatarGCBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, avatar := range avatars {
+			if err := s.deleteFiles(avatar); err != nil {
+				return err
+			}
+			if err := s.avatarRepo.Delete(avatar.ID); err != nil {
+				return err
+			}
+			deleted++
+		}
+		if len(avatars) < avatarGCBatchSize {
+			if deleted > 0 {
+				log.Printf("deleted %d replaced profile pictures", deleted)
+			}
+			return nil
+		}
+	}
+}
+
+func (s *avatarService) deleteFiles(avatar model.Avatar) error {
+	for _, size := range AvatarSizes {
+		if err := s.store.Delete(avatarKey(avatar.UserID, avatar.ID, size)); err != nil {
+			log.Printf("failed to delete profile picture %s: %v", avatarKey(avatar.UserID, avatar.ID, size), err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *avatarService) url(userID, avatarID uuid.UUID, size int) string {
+	return fmt.Sprintf("%s/%s/%s/%d.jpg", s.baseURL, userID, avatarID, size)
+}
+
+func avatarKey(userID, avatarID uuid.UUID, size int) string {
+	return fmt.Sprintf("avatars/%s/%s/%d.jpg", userID, avatarID, size)
+}
+
+func isAvatarSize(size int) bool {
+	for _, s := range AvatarSizes {
+		if s == size {
+			return true
+		}
+	}
+	return false
+}
@@ -1,58 +1,98 @@
 package service
 
 import (
+	"errors"
+	"services/user-service/keystore"
 	"services/user-service/model"
 	"time"
 
+	"booking-system/shared/pkg/jwks"
+
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+// Claims carries the user's role next to the registered claims so other
+// services can authorize without calling back into user-service. The ID
+// (jti) lets a single access token be put on the denylist, the session ID
+// (sid) ties it to the login it was issued for. The language (lang) is the
+// user's saved preference for API messages, if any.
+type Claims struct {
+	Role      string `json:"role,omitempty"`
+	SessionID string `json:"sid,omitempty"`
+	Language  string `json:"lang,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// AccessToken is a signed access token with the values logout needs later
+type AccessToken struct {
+	Token     string
+	ID        string
+	ExpiresAt time.Time
+}
+
 type JWTService interface {
-	GenerateTokens(user *model.User) (string, string, error)
-	ValidateToken(token string) (*jwt.RegisteredClaims, error)
+	GenerateAccessToken(user *model.User, sessionID uuid.UUID) (*AccessToken, error)
+	ValidateToken(token string) (*Claims, error)
+	JWKS() jwks.Set
 }
 
+// jwtService signs access tokens with the key store's current key. Other
+// services verify them with the public keys from JWKS.
 type jwtService struct {
-	secret             string
-	expiry             int
-	refreshTokenExpiry int
+	keys   *keystore.KeyStore
+	issuer string
+	expiry int
 }
 
-func NewJWTService(secret string, expiry int, refreshTokenExpiry int) JWTService {
-	return &jwtService{secret, expiry, refreshTokenExpiry}
+func NewJWTService(keys *keystore.KeyStore, issuer string, expiry int) JWTService {
+	return &jwtService{keys, issuer, expiry}
 }
 
-func (j *jwtService) GenerateTokens(user *model.User) (string, string, error) {
-	claims := jwt.RegisteredClaims{
-		Subject:   user.ID.String(),
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(j.expiry) * time.Second)),
+func (j *jwtService) GenerateAccessToken(user *model.User, sessionID uuid.UUID) (*AccessToken, error) {
+	now := time.Now()
+	id := uuid.New().String()
+	expiresAt := now.Add(time.Duration(j.expiry) * time.Second)
+	claims := Claims{
+		Role:      string(user.Role),
+		SessionID: sessionID.String(),
+		Language:  user.Language,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id,
+			Issuer:    j.issuer,
+			Subject:   user.ID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
 	}
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := accessToken.SignedString([]byte(j.secret))
+	key := j.keys.Current()
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.ID
+	signed, err := token.SignedString(key.Signer)
 	if err != nil {
-		return "", "", err
-	}
-	refreshClaims := jwt.RegisteredClaims{
-		Subject:   user.ID.String(),
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(j.refreshTokenExpiry) * time.Second)),
-	}
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-	refreshTokenString, err := refreshToken.SignedString([]byte(j.secret))
-	if err != nil {
-		return "", "", err
+		return nil, err
 	}
-	return tokenString, refreshTokenString, nil
+	return &AccessToken{Token: signed, ID: id, ExpiresAt: expiresAt}, nil
 }
 
-func (j *jwtService) ValidateToken(token string) (*jwt.RegisteredClaims, error) {
-	parsed, err := jwt.ParseWithClaims(token, &jwt.RegisteredClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(j.secret), nil
-	})
+func (j *jwtService) ValidateToken(token string) (*Claims, error) {
+	parsed, err := jwt.ParseWithClaims(token, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := j.keys.PublicKey(kid)
+		if !ok {
+			return nil, jwks.ErrUnknownKey
+		}
+		return key, nil
+	}, jwt.WithValidMethods([]string{jwks.AlgRS256, jwks.AlgEdDSA}), jwt.WithIssuer(j.issuer))
 	if err != nil {
 		return nil, err
 	}
-	if claims, ok := parsed.Claims.(*jwt.RegisteredClaims); ok && parsed.Valid {
+	if claims, ok := parsed.Claims.(*Claims); ok && parsed.Valid {
 		return claims, nil
 	}
-	return nil, err
+	return nil, errors.New("invalid token claims")
+}
+
+func (j *jwtService) JWKS() jwks.Set {
+	return j.keys.JWKS()
 }
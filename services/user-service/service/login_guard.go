@@ -0,0 +1,262 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"services/user-service/model"
+	"services/user-service/repository"
+	"strings"
+	"time"
+
+	"booking-system/shared/pkg/apperr"
+	"booking-system/shared/pkg/mailer"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidCredentials = apperr.Unauthorized("invalid_credentials")
+	ErrLoginThrottled     = apperr.RateLimited("login_throttled")
+	ErrAccountLocked      = apperr.Locked("account_locked")
+	ErrInvalidUnlockToken = apperr.Validation("invalid_unlock_token")
+)
+
+const accountUnlockPurpose = "account_unlock"
+
+// LoginBlockedError is returned while logins are delayed (ErrLoginThrottled)
+// or the account is locked (ErrAccountLocked)
+type LoginBlockedError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *LoginBlockedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *LoginBlockedError) Unwrap() error {
+	return e.Err
+}
+
+type LoginGuardConfig struct {
+	// Window is how long a failed login counts
+	Window time.Duration
+	// FreeAttempts failures per account are allowed before each further one
+	// makes the next login wait, starting at BaseDelay and doubling up to
+	// MaxDelay
+	FreeAttempts   int
+	IPFreeAttempts int
+	BaseDelay      time.Duration
+	MaxDelay       time.Duration
+	// LockoutThreshold failures lock the account for LockoutDuration
+	LockoutThreshold int
+	LockoutDuration  time.Duration
+	// UnlockURL is the page the emailed unlock link points to
+	UnlockURL string
+}
+
+// LoginGuard protects password and MFA checks against guessing: failures
+// per account and per IP slow down further logins, and an account that
+// keeps failing is locked until it is unlocked by email or expires.
+type LoginGuard interface {
+	Check(email, ip string) error
+	CheckAccount(user *model.User) error
+	RecordFailure(user *model.User, email string, info model.RequestInfo)
+	RecordSuccess(email string)
+	Unlock(token string, info model.RequestInfo) error
+	AdminUnlock(userID uuid.UUID, info model.RequestInfo) error
+}
+
+// unlockClaims binds the token to the lock it was sent for, so an old link
+// cannot lift a later lock
+type unlockClaims struct {
+	Purpose     string `json:"purpose"`
+	LockedUntil int64  `json:"locked_until"`
+	jwt.RegisteredClaims
+}
+
+type loginGuard struct {
+	repo      repository.UserRepository
+	attempts  repository.LoginAttempts
+	auditRepo repository.AuditRepository
+	mailer    mailer.Mailer
+	key       []byte
+	config    LoginGuardConfig
+}
+
+func NewLoginGuard(repo repository.UserRepository, attempts repository.LoginAttempts, auditRepo repository.AuditRepository, mailer mailer.Mailer, secret string, config LoginGuardConfig) LoginGuard {
+	return &loginGuard{
+		repo:      repo,
+		attempts:  attempts,
+		auditRepo: auditRepo,
+		mailer:    mailer,
+		key:       deriveKey(secret, accountUnlockPurpose),
+		config:    config,
+	}
+}
+
+// Check rejects a login while the account or the IP has to wait. Redis
+// being unreachable does not block logins.
+func (g *loginGuard) Check(email, ip string) error {
+	for _, key := range []string{accountKey(email), ipKey(ip)} {
+		delay, err := g.attempts.Delay(key)
+		if err != nil {
+			log.Printf("failed to check login delay for %s: %v", key, err)
+			continue
+		}
+		if delay > 0 {
+			return &LoginBlockedError{Err: ErrLoginThrottled, RetryAfter: delay}
+		}
+	}
+	return nil
+}
+
+func (g *loginGuard) CheckAccount(user *model.User) error {
+	if user.LockedUntil != nil && user.LockedUntil.After(time.Now()) {
+		return &LoginBlockedError{Err: ErrAccountLocked, RetryAfter: time.Until(*user.LockedUntil)}
+	}
+	return nil
+}
+
+// RecordFailure counts a failed password or MFA code. user is nil when the
+// email is unknown, which is counted the same way.
+func (g *loginGuard) RecordFailure(user *model.User, email string, info model.RequestInfo) {
+	failures := g.recordFailure(accountKey(email), g.config.FreeAttempts)
+	g.recordFailure(ipKey(info.IPAddress), g.config.IPFreeAttempts)
+	if user != nil && failures >= g.config.LockoutThreshold {
+		g.lock(user, info)
+	}
+}
+
+func (g *loginGuard) RecordSuccess(email string) {
+	if err := g.attempts.Reset(accountKey(email)); err != nil {
+		log.Printf("failed to reset login failures of %s: %v", email, err)
+	}
+}
+
+// Unlock lifts the lock an emailed link was sent for. Using a link after
+// the lock has expired succeeds.
+func (g *loginGuard) Unlock(token string, info model.RequestInfo) error {
+	claims := &unlockClaims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		return g.key, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil || !parsed.Valid || claims.Purpose != accountUnlockPurpose {
+		return ErrInvalidUnlockToken
+	}
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return ErrInvalidUnlockToken
+	}
+	user, err := g.repo.FindByID(userID)
+	if err != nil {
+		return ErrInvalidUnlockToken
+	}
+	if user.LockedUntil == nil {
+		return nil
+	}
+	if user.LockedUntil.Unix() != claims.LockedUntil {
+		return ErrInvalidUnlockToken
+	}
+	return g.unlock(user, model.AuditAccountUnlocked, info)
+}
+
+func (g *loginGuard) AdminUnlock(userID uuid.UUID, info model.RequestInfo) error {
+	user, err := g.repo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	return g.unlock(user, model.AuditAccountUnlockedByAdmin, info)
+}
+
+func (g *loginGuard) unlock(user *model.User, action string, info model.RequestInfo) error {
+	if err := g.repo.Unlock(user.ID); err != nil {
+		return err
+	}
+	g.RecordSuccess(user.Email)
+	recordAudit(g.auditRepo, user.ID, action, info)
+	return nil
+}
+
+// recordFailure counts a failure for key and, past free attempts, makes the
+// next login for key wait. It returns the number of failures in the window.
+func (g *loginGuard) recordFailure(key string, free int) int {
+	failures, err := g.attempts.RecordFailure(key, g.config.Window)
+	if err != nil {
+		log.Printf("failed to record login failure for %s: %v", key, err)
+		return 0
+	}
+	if failures > free {
+		if err := g.attempts.SetDelay(key, g.delay(failures-free)); err != nil {
+			log.Printf("failed to set login delay for %s: %v", key, err)
+		}
+	}
+	return failures
+}
+
+// delay doubles with every failure past the free attempts
+func (g *loginGuard) delay(excess int) time.Duration {
+	delay := g.config.BaseDelay
+	for i := 1; i < excess && delay < g.config.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > g.config.MaxDelay {
+		delay = g.config.MaxDelay
+	}
+	return delay
+}
+
+// lock locks the account, emails an unlock link and starts counting afresh
+// for when the lock ends. Failures are only logged: the login is rejected
+// either way.
+func (g *loginGuard) lock(user *model.User, info model.RequestInfo) {
+	now := time.Now()
+	// Second precision, so the value in the unlock link matches the column
+	until := now.Add(g.config.LockoutDuration).Truncate(time.Second)
+	locked, err := g.repo.Lock(user.ID, until, now)
+	if err != nil {
+		log.Printf("failed to lock account %s: %v", user.ID, err)
+		return
+	}
+	if !locked {
+		return
+	}
+	g.RecordSuccess(user.Email)
+	recordAudit(g.auditRepo, user.ID, model.AuditAccountLocked, info)
+	if err := g.sendUnlockLink(user, until); err != nil {
+		log.Printf("failed to send unlock email to %s: %v", user.Email, err)
+	}
+}
+
+func (g *loginGuard) sendUnlockLink(user *model.User, until time.Time) error {
+	claims := unlockClaims{
+		Purpose:     accountUnlockPurpose,
+		LockedUntil: until.Unix(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user.ID.String(),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(until),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(g.key)
+	if err != nil {
+		return err
+	}
+
+	link := g.config.UnlockURL + "?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf("Hi %s,\n\nYour account was locked for %d minutes after too many failed login attempts. If this was you, open the link below to unlock it now.\n\n%s\n\nIf it was not you, someone may be trying to guess your password; consider changing it once you are logged in.",
+		user.FullName, int(g.config.LockoutDuration.Minutes()), link)
+	return g.mailer.Send(user.Email, "Your account has been locked", body)
+}
+
+// accountKey counts by email, so unknown addresses are throttled like
+// existing ones
+func accountKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
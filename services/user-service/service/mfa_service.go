@@ -0,0 +1,505 @@
+package service
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"services/user-service/model"
+	"services/user-service/repository"
+	"services/user-service/totp"
+	"services/user-service/utils"
+	"strings"
+	"time"
+
+	"booking-system/shared/pkg/apperr"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidMFAToken    = apperr.Unauthorized("invalid_mfa_token")
+	ErrInvalidMFACode     = apperr.Unauthorized("invalid_mfa_code")
+	ErrMFAAlreadyEnabled  = apperr.InvalidTransition("mfa_already_enabled")
+	ErrMFANotEnabled      = apperr.InvalidTransition("mfa_not_enabled")
+	ErrMFANotPending      = apperr.InvalidTransition("mfa_not_pending")
+	ErrMFARequiredForRole = apperr.Forbidden("mfa_required_for_role")
+	ErrUnknownRole        = apperr.Validation("unknown_role")
+)
+
+const (
+	mfaLoginPurpose      = "mfa_login"
+	mfaEnrollmentPurpose = "mfa_enrollment"
+	mfaSecretPurpose     = "mfa_secret"
+
+	recoveryCodeCount = 10
+)
+
+// MFAService handles TOTP second factors. Login becomes two steps for users
+// with a factor, or whose role requires one: the password yields a
+// short-lived MFA token, which is exchanged for tokens together with a code.
+type MFAService interface {
+	Challenge(user *model.User) (*model.MFAChallenge, error)
+	VerifyLogin(mfaToken, code, recoveryCode string, info model.RequestInfo) (*model.User, error)
+	EnrollmentUser(mfaToken string) (*model.User, error)
+	Enroll(userID uuid.UUID) (*model.MFAEnrollment, error)
+	ConfirmEnrollment(userID uuid.UUID, code string, info model.RequestInfo) ([]string, error)
+	Disable(userID uuid.UUID, password, code, recoveryCode string, info model.RequestInfo) error
+	RegenerateRecoveryCodes(userID uuid.UUID, code string, info model.RequestInfo) ([]string, error)
+	Status(userID uuid.UUID) (*model.MFAStatus, error)
+	Reset(userID uuid.UUID, info model.RequestInfo) error
+	ListPolicies() ([]model.MFARolePolicy, error)
+	SetPolicy(adminID uuid.UUID, role model.UserRole, required bool, info model.RequestInfo) error
+}
+
+// mfaClaims is the payload of an MFA token. It only proves the password was
+// right and can never be used as an access token.
+type mfaClaims struct {
+	Purpose string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+type mfaService struct {
+	repo         repository.UserRepository
+	mfaRepo      repository.MFARepository
+	auditRepo    repository.AuditRepository
+	denylist     repository.TokenDenylist
+	guard        LoginGuard
+	tokenKey     []byte
+	secretCipher cipher.AEAD
+	issuer       string
+	challengeTTL time.Duration
+}
+
+// NewMFAService derives the MFA token signing key and the key that encrypts
+// TOTP secrets at rest from secret
+func NewMFAService(repo repository.UserRepository, mfaRepo repository.MFARepository, auditRepo repository.AuditRepository, denylist repository.TokenDenylist, guard LoginGuard, secret, issuer string, challengeTTL time.Duration) (MFAService, error) {
+	block, err := aes.NewCipher(deriveKey(secret, mfaSecretPurpose))
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &mfaService{
+		repo:         repo,
+		mfaRepo:      mfaRepo,
+		auditRepo:    auditRepo,
+		denylist:     denylist,
+		guard:        guard,
+		tokenKey:     deriveKey(secret, mfaLoginPurpose),
+		secretCipher: aead,
+		issuer:       issuer,
+		challengeTTL: challengeTTL,
+	}, nil
+}
+
+// Challenge returns nil when the user can be given tokens right away, which
+// completes the login
+func (s *mfaService) Challenge(user *model.User) (*model.MFAChallenge, error) {
+	factor, err := s.enabledFactor(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	purpose := mfaLoginPurpose
+	if factor == nil {
+		required, err := s.mfaRepo.IsRequired(user.Role)
+		if err != nil {
+			return nil, err
+		}
+		if !required {
+			s.guard.RecordSuccess(user.Email)
+			return nil, nil
+		}
+		purpose = mfaEnrollmentPurpose
+	}
+
+	now := time.Now()
+	claims := mfaClaims{
+		Purpose: purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Subject:   user.ID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.challengeTTL)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.tokenKey)
+	if err != nil {
+		return nil, err
+	}
+	return &model.MFAChallenge{
+		MFARequired:        true,
+		EnrollmentRequired: purpose == mfaEnrollmentPurpose,
+		MFAToken:           token,
+		ExpiresIn:          int(s.challengeTTL.Seconds()),
+	}, nil
+}
+
+// VerifyLogin completes the second login step. The MFA token works once.
+// Wrong codes count as failed logins.
+func (s *mfaService) VerifyLogin(mfaToken, code, recoveryCode string, info model.RequestInfo) (*model.User, error) {
+	claims, user, err := s.parseToken(mfaToken, mfaLoginPurpose)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.guard.Check(user.Email, info.IPAddress); err != nil {
+		return nil, err
+	}
+	if err := s.guard.CheckAccount(user); err != nil {
+		return nil, err
+	}
+	factor, err := s.enabledFactor(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if factor == nil {
+		return nil, ErrInvalidMFAToken
+	}
+	// Codes are single-use on their own, so an unreachable Redis only
+	// lets the token itself be reused
+	if denied, err := s.denylist.Contains(claims.ID); err == nil && denied {
+		return nil, ErrInvalidMFAToken
+	}
+	if err := s.checkSecondFactor(factor, code, recoveryCode, info); err != nil {
+		if errors.Is(err, ErrInvalidMFACode) {
+			s.guard.RecordFailure(user, user.Email, info)
+		}
+		return nil, err
+	}
+	s.guard.RecordSuccess(user.Email)
+	if err := s.denylist.Add(claims.ID, time.Until(claims.ExpiresAt.Time)); err != nil {
+		log.Printf("failed to consume MFA token %s: %v", claims.ID, err)
+	}
+	user.PasswordHash = ""
+	return user, nil
+}
+
+// EnrollmentUser returns the user an enrolment MFA token was issued to, for
+// users who must set up a factor before they can log in
+func (s *mfaService) EnrollmentUser(mfaToken string) (*model.User, error) {
+	_, user, err := s.parseToken(mfaToken, mfaEnrollmentPurpose)
+	if err != nil {
+		return nil, err
+	}
+	user.PasswordHash = ""
+	return user, nil
+}
+
+// Enroll creates a new secret. It only takes effect once a code generated
+// from it is confirmed; enrolling again before that replaces it.
+func (s *mfaService) Enroll(userID uuid.UUID) (*model.MFAEnrollment, error) {
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	factor, err := s.enabledFactor(userID)
+	if err != nil {
+		return nil, err
+	}
+	if factor != nil {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := s.encrypt(secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mfaRepo.SavePendingFactor(userID, encrypted, time.Now()); err != nil {
+		return nil, err
+	}
+	return &model.MFAEnrollment{Secret: secret, OTPAuthURI: totp.URI(s.issuer, user.Email, secret)}, nil
+}
+
+// ConfirmEnrollment enables the pending factor and returns the recovery
+// codes. They are only ever shown here and on regeneration.
+func (s *mfaService) ConfirmEnrollment(userID uuid.UUID, code string, info model.RequestInfo) ([]string, error) {
+	factor, err := s.mfaRepo.FindFactor(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMFANotPending
+		}
+		return nil, err
+	}
+	if factor.EnabledAt != nil {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	secret, err := s.decrypt(factor.Secret)
+	if err != nil {
+		return nil, err
+	}
+	step, ok := totp.Validate(secret, code, time.Now(), 0)
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	enabled, err := s.mfaRepo.EnableFactor(userID, step, hashes, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	recordAudit(s.auditRepo, userID, model.AuditMFAEnabled, info)
+	return codes, nil
+}
+
+// Disable removes the factor after checking the password and a second
+// factor. Users whose role requires MFA cannot turn it off.
+func (s *mfaService) Disable(userID uuid.UUID, password, code, recoveryCode string, info model.RequestInfo) error {
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if !utils.CheckPasswordHash(password, user.PasswordHash) {
+		return ErrIncorrectPassword
+	}
+	factor, err := s.enabledFactor(userID)
+	if err != nil {
+		return err
+	}
+	if factor == nil {
+		return ErrMFANotEnabled
+	}
+	required, err := s.mfaRepo.IsRequired(user.Role)
+	if err != nil {
+		return err
+	}
+	if required {
+		return ErrMFARequiredForRole
+	}
+	if err := s.checkSecondFactor(factor, code, recoveryCode, info); err != nil {
+		return err
+	}
+	if err := s.mfaRepo.DeleteFactor(userID); err != nil {
+		return err
+	}
+	recordAudit(s.auditRepo, userID, model.AuditMFADisabled, info)
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes, used or not
+func (s *mfaService) RegenerateRecoveryCodes(userID uuid.UUID, code string, info model.RequestInfo) ([]string, error) {
+	factor, err := s.enabledFactor(userID)
+	if err != nil {
+		return nil, err
+	}
+	if factor == nil {
+		return nil, ErrMFANotEnabled
+	}
+	if err := s.checkSecondFactor(factor, code, "", info); err != nil {
+		return nil, err
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mfaRepo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	recordAudit(s.auditRepo, userID, model.AuditMFARecoveryCodesReset, info)
+	return codes, nil
+}
+
+func (s *mfaService) Status(userID uuid.UUID) (*model.MFAStatus, error) {
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	factor, err := s.enabledFactor(userID)
+	if err != nil {
+		return nil, err
+	}
+	required, err := s.mfaRepo.IsRequired(user.Role)
+	if err != nil {
+		return nil, err
+	}
+	status := &model.MFAStatus{Enabled: factor != nil, Required: required}
+	if factor != nil {
+		if status.RecoveryCodesRemaining, err = s.mfaRepo.CountRecoveryCodes(userID); err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// Reset removes a user's factor, e.g. after they lost both the
+// authenticator and the recovery codes. If their role requires MFA they are
+// asked to enrol again at the next login.
+func (s *mfaService) Reset(userID uuid.UUID, info model.RequestInfo) error {
+	if _, err := s.repo.FindByID(userID); err != nil {
+		return err
+	}
+	if err := s.mfaRepo.DeleteFactor(userID); err != nil {
+		return err
+	}
+	recordAudit(s.auditRepo, userID, model.AuditMFAResetByAdmin, info)
+	return nil
+}
+
+func (s *mfaService) ListPolicies() ([]model.MFARolePolicy, error) {
+	return s.mfaRepo.ListPolicies()
+}
+
+// SetPolicy applies from the next login: members of the role without a
+// factor then have to enrol before they get tokens
+func (s *mfaService) SetPolicy(adminID uuid.UUID, role model.UserRole, required bool, info model.RequestInfo) error {
+	switch role {
+	case model.RoleAdmin, model.RoleExpert, model.RoleUser:
+	default:
+		return ErrUnknownRole
+	}
+	if err := s.mfaRepo.SetPolicy(role, required, time.Now()); err != nil {
+		return err
+	}
+	recordAudit(s.auditRepo, adminID, model.AuditMFAPolicyChanged, info)
+	return nil
+}
+
+// enabledFactor returns nil when the user has no active factor
+func (s *mfaService) enabledFactor(userID uuid.UUID) (*model.MFAFactor, error) {
+	factor, err := s.mfaRepo.FindFactor(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if factor.EnabledAt == nil {
+		return nil, nil
+	}
+	return factor, nil
+}
+
+// checkSecondFactor accepts either a current authenticator code or an
+// unused recovery code. Both work only once.
+func (s *mfaService) checkSecondFactor(factor *model.MFAFactor, code, recoveryCode string, info model.RequestInfo) error {
+	if recoveryCode != "" {
+		used, err := s.mfaRepo.ConsumeRecoveryCode(factor.UserID, hashToken(normalizeRecoveryCode(recoveryCode)), time.Now())
+		if err != nil {
+			return err
+		}
+		if !used {
+			return ErrInvalidMFACode
+		}
+		recordAudit(s.auditRepo, factor.UserID, model.AuditMFARecoveryCodeUsed, info)
+		return nil
+	}
+
+	secret, err := s.decrypt(factor.Secret)
+	if err != nil {
+		return err
+	}
+	step, ok := totp.Validate(secret, code, time.Now(), factor.LastUsedStep)
+	if !ok {
+		return ErrInvalidMFACode
+	}
+	claimed, err := s.mfaRepo.ClaimStep(factor.UserID, step)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return ErrInvalidMFACode
+	}
+	return nil
+}
+
+// parseToken returns the claims of a valid MFA token with the given purpose
+// and the user it was issued to
+func (s *mfaService) parseToken(token, purpose string) (*mfaClaims, *model.User, error) {
+	claims := &mfaClaims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		return s.tokenKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil || !parsed.Valid || claims.Purpose != purpose {
+		return nil, nil, ErrInvalidMFAToken
+	}
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, nil, ErrInvalidMFAToken
+	}
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrInvalidMFAToken
+		}
+		return nil, nil, err
+	}
+	// The account may have been suspended or forced to reset its password
+	// since the token was issued after the password step
+	if err := checkLoginAllowed(user); err != nil {
+		return nil, nil, err
+	}
+	return claims, user, nil
+}
+
+func (s *mfaService) encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, s.secretCipher.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := s.secretCipher.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (s *mfaService) decrypt(ciphertext string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	size := s.secretCipher.NonceSize()
+	if len(sealed) < size {
+		return "", errors.New("malformed MFA secret")
+	}
+	plaintext, err := s.secretCipher.Open(nil, sealed[:size], sealed[size:], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt MFA secret: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// newRecoveryCodes returns codes formatted for display and their hashes
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	for i := range codes {
+		b := make([]byte, 8)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(encoding.EncodeToString(b))[:12]
+		codes[i] = raw[:4] + "-" + raw[4:8] + "-" + raw[8:]
+		hashes[i] = hashToken(raw)
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode accepts codes typed with or without dashes, spaces
+// and in any case
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+func deriveKey(secret, purpose string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
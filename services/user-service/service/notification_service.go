@@ -0,0 +1,99 @@
+package service
+
+import (
+	"services/user-service/model"
+	"services/user-service/repository"
+
+	"booking-system/shared/pkg/apperr"
+	"booking-system/shared/pkg/notify"
+
+	"github.com/google/uuid"
+)
+
+var ErrPhoneRequiredForSMS = apperr.Validation("phone_required_for_sms")
+
+// NotificationService manages how users are notified: which events reach
+// them over which channels, reminder offsets, quiet hours and digests.
+// Booking and expert services send through the same settings.
+type NotificationService interface {
+	GetSettings(userID uuid.UUID) (*notify.Settings, error)
+	UpdateSettings(userID uuid.UUID, req model.UpdateNotificationSettingsRequest) (*notify.Settings, error)
+	UpdatePreferences(userID uuid.UUID, req model.UpdateNotificationPreferencesRequest) (*notify.Settings, error)
+	// Flush sends the queued notifications that are due
+	Flush() error
+}
+
+type notificationService struct {
+	repo     repository.UserRepository
+	store    *notify.Store
+	notifier *notify.Notifier
+}
+
+func NewNotificationService(repo repository.UserRepository, store *notify.Store, notifier *notify.Notifier) NotificationService {
+	return &notificationService{repo: repo, store: store, notifier: notifier}
+}
+
+func (s *notificationService) GetSettings(userID uuid.UUID) (*notify.Settings, error) {
+	if _, err := s.repo.FindByID(userID); err != nil {
+		return nil, err
+	}
+	return s.store.Load(userID.String())
+}
+
+func (s *notificationService) UpdateSettings(userID uuid.UUID, req model.UpdateNotificationSettingsRequest) (*notify.Settings, error) {
+	settings, err := s.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	settings.ReminderOffsets = req.ReminderOffsets
+	if settings.ReminderOffsets == nil {
+		settings.ReminderOffsets = []int{}
+	}
+	settings.QuietHours = req.QuietHours
+	settings.TimeZone = req.TimeZone
+	settings.Digest = notify.Digest(req.Digest)
+	settings.DigestTime = req.DigestTime
+	settings.TelegramChatID = req.TelegramChatID
+	return settings, s.save(userID, settings)
+}
+
+func (s *notificationService) UpdatePreferences(userID uuid.UUID, req model.UpdateNotificationPreferencesRequest) (*notify.Settings, error) {
+	settings, err := s.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, pref := range req.Preferences {
+		event := notify.Event(pref.Event)
+		if settings.Preferences[event] == nil {
+			settings.Preferences[event] = map[notify.Channel]bool{}
+		}
+		settings.Preferences[event][notify.Channel(pref.Channel)] = *pref.Enabled
+	}
+	return settings, s.save(userID, settings)
+}
+
+// save validates settings and stores them. Text messages need a phone number
+// on the profile.
+func (s *notificationService) save(userID uuid.UUID, settings *notify.Settings) error {
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+	for _, event := range notify.Events {
+		if !settings.Enabled(event, notify.ChannelSMS) {
+			continue
+		}
+		user, err := s.repo.FindByID(userID)
+		if err != nil {
+			return err
+		}
+		if user.Phone == "" {
+			return ErrPhoneRequiredForSMS
+		}
+		break
+	}
+	return s.store.Save(userID.String(), settings)
+}
+
+func (s *notificationService) Flush() error {
+	return s.notifier.Flush()
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"services/user-service/model"
+	"services/user-service/repository"
+	"services/user-service/utils"
+	"time"
+
+	"booking-system/shared/pkg/apperr"
+	"booking-system/shared/pkg/mailer"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidResetToken = apperr.Validation("invalid_reset_token")
+	ErrIncorrectPassword = apperr.Unauthorized("incorrect_password")
+	ErrPasswordUnchanged = apperr.Validation("password_unchanged")
+)
+
+type PasswordService interface {
+	ForgotPassword(email string, info model.RequestInfo) error
+	ResetPassword(token, newPassword string, info model.RequestInfo) error
+	ChangePassword(userID uuid.UUID, currentPassword, newPassword string, info model.RequestInfo) error
+	ForcePasswordReset(userID uuid.UUID, info model.RequestInfo) error
+}
+
+type passwordService struct {
+	repo      repository.UserRepository
+	resetRepo repository.PasswordResetRepository
+	auditRepo repository.AuditRepository
+	tokens    TokenService
+	mailer    mailer.Mailer
+	ttl       time.Duration
+	resetURL  string
+}
+
+func NewPasswordService(repo repository.UserRepository, resetRepo repository.PasswordResetRepository, auditRepo repository.AuditRepository, tokens TokenService, mailer mailer.Mailer, ttl time.Duration, resetURL string) PasswordService {
+	return &passwordService{
+		repo:      repo,
+		resetRepo: resetRepo,
+		auditRepo: auditRepo,
+		tokens:    tokens,
+		mailer:    mailer,
+		ttl:       ttl,
+		resetURL:  resetURL,
+	}
+}
+
+// ForgotPassword emails a reset link. Unknown addresses are silently ignored
+// so the endpoint does not reveal which emails exist. A new link replaces any
+// earlier one.
+func (s *passwordService) ForgotPassword(email string, info model.RequestInfo) error {
+	user, err := s.repo.FindByEmail(email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	link, err := s.newResetLink(user.ID)
+	if err != nil {
+		return err
+	}
+	s.audit(user.ID, model.AuditPasswordResetRequested, info)
+
+	body := fmt.Sprintf("Hi %s,\n\nWe received a request to reset your password. Open the link below to choose a new one. It expires in %d minutes and can be used once.\n\n%s\n\nIf you did not ask for this, you can ignore this email; your password stays the same.",
+		user.FullName, int(s.ttl.Minutes()), link)
+	return s.mailer.Send(user.Email, "Reset your password", body)
+}
+
+// ForcePasswordReset is used by admins, e.g. when an account may be
+// compromised: the user is signed out everywhere and cannot log in until
+// they set a new password through the emailed link
+func (s *passwordService) ForcePasswordReset(userID uuid.UUID, info model.RequestInfo) error {
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.Status == model.StatusErased {
+		return ErrAccountErased
+	}
+	if err := s.repo.RequirePasswordReset(userID); err != nil {
+		return err
+	}
+	if err := s.tokens.RevokeAll(userID); err != nil {
+		return err
+	}
+	link, err := s.newResetLink(userID)
+	if err != nil {
+		return err
+	}
+	s.audit(userID, model.AuditPasswordResetForced, info)
+
+	body := fmt.Sprintf("Hi %s,\n\nAn administrator has asked you to choose a new password. You have been signed out and can log in again once you have set it using the link below. It expires in %d minutes and can be used once.\n\n%s\n\nIf the link has expired, use \"Forgot password\" to get a new one.",
+		user.FullName, int(s.ttl.Minutes()), link)
+	return s.mailer.Send(user.Email, "Please reset your password", body)
+}
+
+// newResetLink replaces any outstanding reset token of the user with a new
+// one and returns the link for it
+func (s *passwordService) newResetLink(userID uuid.UUID) (string, error) {
+	token, err := newOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	if err := s.resetRepo.InvalidateForUser(userID, now); err != nil {
+		return "", err
+	}
+	if err := s.resetRepo.Create(&model.PasswordResetToken{
+		UserID:    userID,
+		TokenHash: hashToken(token),
+		ExpiresAt: now.Add(s.ttl),
+	}); err != nil {
+		return "", err
+	}
+	return s.resetURL + "?token=" + url.QueryEscape(token), nil
+}
+
+// ResetPassword sets a new password using a token from ForgotPassword and
+// signs the user out everywhere
+func (s *passwordService) ResetPassword(token, newPassword string, info model.RequestInfo) error {
+	userID, err := s.resetRepo.Consume(hashToken(token), time.Now())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+	if err := s.setPassword(userID, newPassword); err != nil {
+		return err
+	}
+	s.audit(userID, model.AuditPasswordReset, info)
+	return nil
+}
+
+// ChangePassword replaces the password of a signed-in user. Existing refresh
+// tokens are revoked, so other sessions have to log in again.
+func (s *passwordService) ChangePassword(userID uuid.UUID, currentPassword, newPassword string, info model.RequestInfo) error {
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if !utils.CheckPasswordHash(currentPassword, user.PasswordHash) {
+		return ErrIncorrectPassword
+	}
+	if currentPassword == newPassword {
+		return ErrPasswordUnchanged
+	}
+	if err := s.setPassword(userID, newPassword); err != nil {
+		return err
+	}
+	// A pending reset link must not undo the change
+	if err := s.resetRepo.InvalidateForUser(userID, time.Now()); err != nil {
+		log.Printf("failed to invalidate password reset tokens of %s: %v", userID, err)
+	}
+	s.audit(userID, model.AuditPasswordChanged, info)
+	return nil
+}
+
+// setPassword stores the new password and signs the user out everywhere
+func (s *passwordService) setPassword(userID uuid.UUID, password string) error {
+	hashed, err := utils.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.UpdatePassword(userID, hashed); err != nil {
+		return err
+	}
+	return s.tokens.RevokeAll(userID)
+}
+
+func (s *passwordService) audit(userID uuid.UUID, action string, info model.RequestInfo) {
+	recordAudit(s.auditRepo, userID, action, info)
+}
+
+// recordAudit records an entry; the change itself has already happened, so
+// a failure is only logged
+func recordAudit(auditRepo repository.AuditRepository, userID uuid.UUID, action string, info model.RequestInfo) {
+	entry := &model.AuditEntry{
+		UserID:    &userID,
+		Action:    action,
+		IPAddress: info.IPAddress,
+		UserAgent: info.UserAgent,
+	}
+	if err := auditRepo.Record(entry); err != nil {
+		log.Printf("failed to record %s audit entry for %s: %v", action, userID, err)
+	}
+}
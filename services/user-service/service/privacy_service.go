@@ -0,0 +1,210 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"services/user-service/model"
+	"services/user-service/repository"
+	"services/user-service/utils"
+	"time"
+
+	"booking-system/shared/pkg/apperr"
+	"booking-system/shared/pkg/i18n"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAccountErased     = apperr.Gone("account_erased")
+	ErrErasureNotAllowed = apperr.Conflict("account_erasure_not_allowed")
+	// ErrErasureBlocked carries the reason another service refused to erase
+	// its part of the account, e.g. upcoming bookings
+	ErrErasureBlocked     = apperr.Conflict("account_erasure_blocked")
+	ErrServiceUnavailable = apperr.Unavailable("data_service_unavailable")
+)
+
+// PrivacyService gathers a user's data from every service for export, and
+// erases it. Other services are called with the caller's access token, so
+// they apply their own access rules.
+type PrivacyService interface {
+	Export(userID uuid.UUID, authorization string, info model.RequestInfo) ([]byte, error)
+	EraseOwn(userID uuid.UUID, password, authorization string, info model.RequestInfo) error
+	Erase(userID uuid.UUID, authorization string, info model.RequestInfo) error
+}
+
+type privacyService struct {
+	repo        repository.UserRepository
+	privacyRepo repository.PrivacyRepository
+	auditRepo   repository.AuditRepository
+	tokens      TokenService
+	bookingURL  string
+	expertURL   string
+	client      *http.Client
+}
+
+func NewPrivacyService(repo repository.UserRepository, privacyRepo repository.PrivacyRepository, auditRepo repository.AuditRepository, tokens TokenService, bookingURL, expertURL string) PrivacyService {
+	return &privacyService{
+		repo:        repo,
+		privacyRepo: privacyRepo,
+		auditRepo:   auditRepo,
+		tokens:      tokens,
+		bookingURL:  bookingURL,
+		expertURL:   expertURL,
+		client:      &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Export returns a ZIP archive with one JSON file per kind of data
+func (s *privacyService) Export(userID uuid.UUID, authorization string, info model.RequestInfo) ([]byte, error) {
+	account, err := s.privacyRepo.GetAccountData(userID)
+	if err != nil {
+		return nil, err
+	}
+	if account.Profile.Status == model.StatusErased {
+		return nil, ErrAccountErased
+	}
+	account.Profile.PasswordHash = ""
+
+	export := &model.DataExport{UserID: userID, GeneratedAt: time.Now().UTC(), Account: account}
+	// booking-service wraps its payload in {"data": ...}
+	var bookings struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := s.call(http.MethodGet, fmt.Sprintf("%s/ExportUserData?user_id=%s", s.bookingURL, userID), authorization, &bookings); err != nil {
+		return nil, err
+	}
+	export.Bookings = bookings.Data
+	if err := s.call(http.MethodGet, fmt.Sprintf("%s/api/users/%s/data-export", s.expertURL, userID), authorization, &export.Expert); err != nil {
+		return nil, err
+	}
+
+	archive, err := writeArchive(export)
+	if err != nil {
+		return nil, err
+	}
+	recordAudit(s.auditRepo, userID, model.AuditDataExported, info)
+	return archive, nil
+}
+
+// EraseOwn erases the caller's account once they confirm their password
+func (s *privacyService) EraseOwn(userID uuid.UUID, password, authorization string, info model.RequestInfo) error {
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if !utils.CheckPasswordHash(password, user.PasswordHash) {
+		return ErrIncorrectPassword
+	}
+	return s.Erase(userID, authorization, info)
+}
+
+// Erase anonymises the user everywhere. The other services go first, since
+// booking-service refuses while the user has upcoming bookings; the account
+// itself is erased last so a failure leaves it usable to retry.
+func (s *privacyService) Erase(userID uuid.UUID, authorization string, info model.RequestInfo) error {
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.Status == model.StatusErased {
+		return ErrAccountErased
+	}
+	if user.Role != model.RoleUser {
+		return ErrErasureNotAllowed
+	}
+
+	if err := s.call(http.MethodPost, fmt.Sprintf("%s/AnonymizeUserData?user_id=%s", s.bookingURL, userID), authorization, nil); err != nil {
+		return err
+	}
+	if err := s.call(http.MethodPost, fmt.Sprintf("%s/api/users/%s/erase", s.expertURL, userID), authorization, nil); err != nil {
+		return err
+	}
+
+	if err := s.tokens.RevokeAll(userID); err != nil {
+		return err
+	}
+	if err := s.privacyRepo.Erase(userID, time.Now()); err != nil {
+		return err
+	}
+	recordAudit(s.auditRepo, userID, model.AuditAccountErased, info)
+	return nil
+}
+
+// call sends a request to another service and decodes its JSON response
+// into out, if given. A 409 from the service becomes ErrErasureBlocked,
+// explained by the service's message code.
+func (s *privacyService) call(method, url, authorization string, out interface{}) error {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", authorization)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		log.Printf("privacy request %s %s failed: %v", method, url, err)
+		return ErrServiceUnavailable
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ErrServiceUnavailable
+	}
+	if resp.StatusCode == http.StatusConflict {
+		var reason apperr.Problem
+		json.Unmarshal(body, &reason)
+		if reason.Code == "" {
+			return ErrErasureBlocked.Wrap(errors.New(reason.Detail))
+		}
+		return ErrErasureBlocked.Wrap(i18n.NewError(reason.Code))
+	}
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("privacy request %s %s returned %d: %s", method, url, resp.StatusCode, body)
+		return ErrServiceUnavailable
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(body, out)
+}
+
+func writeArchive(export *model.DataExport) ([]byte, error) {
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"export.json", map[string]interface{}{"user_id": export.UserID, "generated_at": export.GeneratedAt}},
+		{"profile.json", export.Account.Profile},
+		{"sessions.json", export.Account.Sessions},
+		{"audit_log.json", export.Account.AuditLog},
+		{"notifications.json", export.Account.Notifications},
+		{"notification_settings.json", export.Account.NotificationSettings},
+		{"notification_preferences.json", export.Account.NotificationPreferences},
+		{"bookings.json", export.Bookings},
+		{"expert.json", export.Expert},
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, file := range files {
+		w, err := archive.CreateHeader(&zip.FileHeader{Name: file.name, Method: zip.Deflate, Modified: export.GeneratedAt})
+		if err != nil {
+			return nil, err
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
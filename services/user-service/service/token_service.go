@@ -0,0 +1,316 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"services/user-service/model"
+	"services/user-service/repository"
+	"time"
+
+	"booking-system/shared/pkg/apperr"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidRefreshToken = apperr.Unauthorized("invalid_refresh_token")
+	ErrRefreshTokenReused  = apperr.Unauthorized("refresh_token_reused")
+	ErrSessionNotFound     = apperr.NotFound("login_session_not_found")
+)
+
+// TokenService issues access/refresh token pairs and revokes them. Refresh
+// tokens are opaque, stored hashed and rotated on every use. Every login
+// starts a session; the refresh tokens rotated from it share its ID as family.
+type TokenService interface {
+	IssueTokens(user *model.User, info model.RequestInfo) (*model.TokenPair, error)
+	Refresh(refreshToken string, info model.RequestInfo) (*model.TokenPair, error)
+	Logout(userID uuid.UUID, accessClaims *Claims, refreshToken string) error
+	LogoutAll(userID uuid.UUID, accessClaims *Claims) error
+	RevokeAll(userID uuid.UUID) error
+	ListSessions(userID uuid.UUID, currentSessionID string) ([]model.Session, error)
+	RevokeSession(userID, sessionID uuid.UUID) error
+	IsAccessTokenRevoked(tokenID string) bool
+}
+
+type tokenService struct {
+	jwtService    JWTService
+	userRepo      repository.UserRepository
+	refreshRepo   repository.RefreshTokenRepository
+	sessionRepo   repository.SessionRepository
+	denylist      repository.TokenDenylist
+	refreshExpiry time.Duration
+}
+
+func NewTokenService(jwtService JWTService, userRepo repository.UserRepository, refreshRepo repository.RefreshTokenRepository, sessionRepo repository.SessionRepository, denylist repository.TokenDenylist, refreshExpiry time.Duration) TokenService {
+	return &tokenService{
+		jwtService:    jwtService,
+		userRepo:      userRepo,
+		refreshRepo:   refreshRepo,
+		sessionRepo:   sessionRepo,
+		denylist:      denylist,
+		refreshExpiry: refreshExpiry,
+	}
+}
+
+// IssueTokens starts a new session on the device described by info, e.g. on
+// login
+func (s *tokenService) IssueTokens(user *model.User, info model.RequestInfo) (*model.TokenPair, error) {
+	pair, record, err := s.newPair(user, uuid.New())
+	if err != nil {
+		return nil, err
+	}
+	session := &model.Session{
+		ID:         record.FamilyID,
+		UserID:     user.ID,
+		UserAgent:  info.UserAgent,
+		IPAddress:  info.IPAddress,
+		LastUsedAt: time.Now(),
+		ExpiresAt:  record.ExpiresAt,
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, err
+	}
+	if err := s.refreshRepo.Create(record); err != nil {
+		if revokeErr := s.sessionRepo.Revoke(session.ID, time.Now()); revokeErr != nil {
+			log.Printf("failed to revoke session %s without tokens: %v", session.ID, revokeErr)
+		}
+		return nil, err
+	}
+	return pair, nil
+}
+
+// Refresh exchanges a refresh token for a new pair in the same family.
+// Presenting a token that was already rotated or revoked means it leaked,
+// so the whole family is revoked.
+func (s *tokenService) Refresh(refreshToken string, info model.RequestInfo) (*model.TokenPair, error) {
+	current, err := s.refreshRepo.FindByHash(hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	// The role or status may have changed since the family was started
+	user, err := s.userRepo.FindByID(current.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+	if user.Status == model.StatusSuspended {
+		return nil, ErrAccountSuspended
+	}
+	if current.IsRevoked {
+		s.revokeFamily(current.FamilyID)
+		return nil, ErrRefreshTokenReused
+	}
+	if !current.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	pair, next, err := s.newPair(user, current.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+	rotated, err := s.refreshRepo.Rotate(current.ID, next, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		// Lost a race with another refresh using the same token
+		s.revokeFamily(current.FamilyID)
+		return nil, ErrRefreshTokenReused
+	}
+	if err := s.sessionRepo.Touch(current.FamilyID, info.IPAddress, time.Now(), next.ExpiresAt); err != nil {
+		log.Printf("failed to record use of session %s: %v", current.FamilyID, err)
+	}
+	return pair, nil
+}
+
+// Logout ends the current session: the presented access token and the
+// session it was issued for. Access tokens issued before sessions carried
+// their ID only identify the session through the refresh token, when given.
+func (s *tokenService) Logout(userID uuid.UUID, accessClaims *Claims, refreshToken string) error {
+	sessionID := uuid.Nil
+	if accessClaims != nil {
+		if id, err := uuid.Parse(accessClaims.SessionID); err == nil {
+			sessionID = id
+		}
+	}
+	if sessionID == uuid.Nil && refreshToken != "" {
+		token, err := s.refreshRepo.FindByHash(hashToken(refreshToken))
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err == nil && token.UserID == userID {
+			sessionID = token.FamilyID
+		}
+	}
+	if sessionID != uuid.Nil {
+		if err := s.revokeSession(sessionID); err != nil {
+			return err
+		}
+	}
+	s.denyClaims(accessClaims)
+	return nil
+}
+
+// LogoutAll ends every session of the user on every device
+func (s *tokenService) LogoutAll(userID uuid.UUID, accessClaims *Claims) error {
+	if err := s.RevokeAll(userID); err != nil {
+		return err
+	}
+	s.denyClaims(accessClaims)
+	return nil
+}
+
+// RevokeAll revokes every refresh token of the user along with the access
+// tokens issued with them, e.g. after a password change
+func (s *tokenService) RevokeAll(userID uuid.UUID) error {
+	now := time.Now()
+	if err := s.sessionRepo.RevokeAllForUser(userID, now); err != nil {
+		return err
+	}
+	if err := s.refreshRepo.RevokeAllForUser(userID, now); err != nil {
+		return err
+	}
+	live, err := s.refreshRepo.LiveAccessTokensByUser(userID, now)
+	if err != nil {
+		return err
+	}
+	s.denyAccessTokens(live)
+	return nil
+}
+
+// ListSessions returns the user's active sessions, marking the one with
+// currentSessionID as current
+func (s *tokenService) ListSessions(userID uuid.UUID, currentSessionID string) ([]model.Session, error) {
+	sessions, err := s.sessionRepo.ListActiveByUser(userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID.String() == currentSessionID
+	}
+	return sessions, nil
+}
+
+// RevokeSession logs one device out: its refresh tokens stop working and its
+// live access tokens are denied. Revoking an already revoked session of the
+// user succeeds.
+func (s *tokenService) RevokeSession(userID, sessionID uuid.UUID) error {
+	session, err := s.sessionRepo.FindByID(sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	if session.UserID != userID {
+		return ErrSessionNotFound
+	}
+	return s.revokeSession(sessionID)
+}
+
+// IsAccessTokenRevoked fails open when Redis cannot be reached: access
+// tokens are short-lived and the refresh tokens stay revoked in Postgres
+func (s *tokenService) IsAccessTokenRevoked(tokenID string) bool {
+	if tokenID == "" {
+		return false
+	}
+	denied, err := s.denylist.Contains(tokenID)
+	if err != nil {
+		log.Printf("failed to check access token denylist: %v", err)
+		return false
+	}
+	return denied
+}
+
+func (s *tokenService) newPair(user *model.User, familyID uuid.UUID) (*model.TokenPair, *model.RefreshToken, error) {
+	access, err := s.jwtService.GenerateAccessToken(user, familyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	refresh, err := newOpaqueToken()
+	if err != nil {
+		return nil, nil, err
+	}
+	record := &model.RefreshToken{
+		ID:              uuid.New(),
+		UserID:          user.ID,
+		FamilyID:        familyID,
+		TokenHash:       hashToken(refresh),
+		ExpiresAt:       time.Now().Add(s.refreshExpiry),
+		AccessTokenID:   access.ID,
+		AccessExpiresAt: access.ExpiresAt,
+	}
+	pair := &model.TokenPair{
+		AccessToken:  access.Token,
+		RefreshToken: refresh,
+		ExpiresIn:    int(time.Until(access.ExpiresAt).Seconds()),
+	}
+	return pair, record, nil
+}
+
+// revokeSession revokes the session with its refresh token family and denies
+// the access tokens issued with them
+func (s *tokenService) revokeSession(sessionID uuid.UUID) error {
+	now := time.Now()
+	if err := s.sessionRepo.Revoke(sessionID, now); err != nil {
+		return err
+	}
+	if err := s.refreshRepo.RevokeFamily(sessionID, now); err != nil {
+		return err
+	}
+	live, err := s.refreshRepo.LiveAccessTokensByFamily(sessionID, now)
+	if err != nil {
+		return err
+	}
+	s.denyAccessTokens(live)
+	return nil
+}
+
+// revokeFamily is used on reuse detection, where the caller already gets an
+// error, so failures are only logged
+func (s *tokenService) revokeFamily(familyID uuid.UUID) {
+	if err := s.revokeSession(familyID); err != nil {
+		log.Printf("failed to revoke session %s: %v", familyID, err)
+	}
+}
+
+func (s *tokenService) denyAccessTokens(tokens []model.LiveAccessToken) {
+	for _, token := range tokens {
+		if err := s.denylist.Add(token.ID, time.Until(token.ExpiresAt)); err != nil {
+			log.Printf("failed to deny access token %s: %v", token.ID, err)
+		}
+	}
+}
+
+func (s *tokenService) denyClaims(claims *Claims) {
+	if claims == nil || claims.ID == "" || claims.ExpiresAt == nil {
+		return
+	}
+	s.denyAccessTokens([]model.LiveAccessToken{{ID: claims.ID, ExpiresAt: claims.ExpiresAt.Time}})
+}
+
+func newOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken is how refresh and password reset tokens are stored
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"errors"
+	"services/user-service/model"
+	"services/user-service/repository"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// In-memory fakes of the repositories the token service uses. The embedded
+// interfaces are nil, so calls to methods the tests do not expect panic.
+
+type fakeJWTService struct{ JWTService }
+
+func (fakeJWTService) GenerateAccessToken(user *model.User, sessionID uuid.UUID) (*AccessToken, error) {
+	id := uuid.New().String()
+	return &AccessToken{Token: id, ID: id, ExpiresAt: time.Now().Add(15 * time.Minute)}, nil
+}
+
+type fakeUserRepository struct {
+	repository.UserRepository
+	users map[uuid.UUID]*model.User
+}
+
+func (r *fakeUserRepository) FindByID(id uuid.UUID) (*model.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return user, nil
+}
+
+type fakeRefreshTokenRepository struct {
+	tokens map[uuid.UUID]*model.RefreshToken
+	// rotateLoses makes Rotate behave as if another refresh got there first
+	rotateLoses bool
+}
+
+func (r *fakeRefreshTokenRepository) Create(token *model.RefreshToken) error {
+	stored := *token
+	r.tokens[token.ID] = &stored
+	return nil
+}
+
+func (r *fakeRefreshTokenRepository) FindByHash(tokenHash string) (*model.RefreshToken, error) {
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			found := *token
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeRefreshTokenRepository) Rotate(oldID uuid.UUID, next *model.RefreshToken, now time.Time) (bool, error) {
+	old := r.tokens[oldID]
+	if r.rotateLoses || old == nil || old.IsRevoked {
+		return false, nil
+	}
+	old.IsRevoked = true
+	old.RevokedAt = &now
+	old.ReplacedBy = &next.ID
+	return true, r.Create(next)
+}
+
+func (r *fakeRefreshTokenRepository) RevokeFamily(familyID uuid.UUID, now time.Time) error {
+	for _, token := range r.tokens {
+		if token.FamilyID == familyID && !token.IsRevoked {
+			token.IsRevoked = true
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *fakeRefreshTokenRepository) RevokeAllForUser(userID uuid.UUID, now time.Time) error {
+	for _, token := range r.tokens {
+		if token.UserID == userID && !token.IsRevoked {
+			token.IsRevoked = true
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *fakeRefreshTokenRepository) LiveAccessTokensByFamily(familyID uuid.UUID, now time.Time) ([]model.LiveAccessToken, error) {
+	var live []model.LiveAccessToken
+	for _, token := range r.tokens {
+		if token.FamilyID == familyID && token.AccessExpiresAt.After(now) {
+			live = append(live, model.LiveAccessToken{ID: token.AccessTokenID, ExpiresAt: token.AccessExpiresAt})
+		}
+	}
+	return live, nil
+}
+
+func (r *fakeRefreshTokenRepository) LiveAccessTokensByUser(userID uuid.UUID, now time.Time) ([]model.LiveAccessToken, error) {
+	var live []model.LiveAccessToken
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.AccessExpiresAt.After(now) {
+			live = append(live, model.LiveAccessToken{ID: token.AccessTokenID, ExpiresAt: token.AccessExpiresAt})
+		}
+	}
+	return live, nil
+}
+
+type fakeSessionRepository struct {
+	repository.SessionRepository
+	sessions map[uuid.UUID]*model.Session
+}
+
+func (r *fakeSessionRepository) Create(session *model.Session) error {
+	stored := *session
+	r.sessions[session.ID] = &stored
+	return nil
+}
+
+func (r *fakeSessionRepository) Touch(id uuid.UUID, ipAddress string, now, expiresAt time.Time) error {
+	if session, ok := r.sessions[id]; ok {
+		session.IPAddress = ipAddress
+		session.LastUsedAt = now
+		session.ExpiresAt = expiresAt
+	}
+	return nil
+}
+
+func (r *fakeSessionRepository) Revoke(id uuid.UUID, now time.Time) error {
+	if session, ok := r.sessions[id]; ok && session.RevokedAt == nil {
+		session.RevokedAt = &now
+	}
+	return nil
+}
+
+type fakeTokenDenylist map[string]bool
+
+func (d fakeTokenDenylist) Add(tokenID string, ttl time.Duration) error {
+	d[tokenID] = true
+	return nil
+}
+
+func (d fakeTokenDenylist) Contains(tokenID string) (bool, error) {
+	return d[tokenID], nil
+}
+
+func TestTokenServiceRefresh(t *testing.T) {
+	tests := []struct {
+		name string
+		// present logs in and returns the refresh token to present
+		present func(t *testing.T, s TokenService, refreshRepo *fakeRefreshTokenRepository, user *model.User) string
+		wantErr error
+		// wantFamilyRevoked is whether the session and every token issued in
+		// it must be revoked afterwards
+		wantFamilyRevoked bool
+	}{
+		{
+			name: "current token is rotated",
+			present: func(t *testing.T, s TokenService, _ *fakeRefreshTokenRepository, user *model.User) string {
+				return issue(t, s, user).RefreshToken
+			},
+		},
+		{
+			name: "rotated token is rotated again",
+			present: func(t *testing.T, s TokenService, _ *fakeRefreshTokenRepository, user *model.User) string {
+				return refresh(t, s, issue(t, s, user).RefreshToken).RefreshToken
+			},
+		},
+		{
+			name: "reused token revokes the family",
+			present: func(t *testing.T, s TokenService, _ *fakeRefreshTokenRepository, user *model.User) string {
+				first := issue(t, s, user).RefreshToken
+				refresh(t, s, first)
+				return first
+			},
+			wantErr:           ErrRefreshTokenReused,
+			wantFamilyRevoked: true,
+		},
+		{
+			name: "lost rotation race revokes the family",
+			present: func(t *testing.T, s TokenService, refreshRepo *fakeRefreshTokenRepository, user *model.User) string {
+				token := issue(t, s, user).RefreshToken
+				refreshRepo.rotateLoses = true
+				return token
+			},
+			wantErr:           ErrRefreshTokenReused,
+			wantFamilyRevoked: true,
+		},
+		{
+			name: "unknown token",
+			present: func(t *testing.T, s TokenService, _ *fakeRefreshTokenRepository, user *model.User) string {
+				issue(t, s, user)
+				return "unknown"
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "expired token",
+			present: func(t *testing.T, s TokenService, refreshRepo *fakeRefreshTokenRepository, user *model.User) string {
+				token := issue(t, s, user).RefreshToken
+				for _, stored := range refreshRepo.tokens {
+					stored.ExpiresAt = time.Now().Add(-time.Minute)
+				}
+				return token
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "suspended user",
+			present: func(t *testing.T, s TokenService, _ *fakeRefreshTokenRepository, user *model.User) string {
+				token := issue(t, s, user).RefreshToken
+				user.Status = model.StatusSuspended
+				return token
+			},
+			wantErr: ErrAccountSuspended,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &model.User{ID: uuid.New(), Status: model.StatusActive}
+			refreshRepo := &fakeRefreshTokenRepository{tokens: make(map[uuid.UUID]*model.RefreshToken)}
+			sessionRepo := &fakeSessionRepository{sessions: make(map[uuid.UUID]*model.Session)}
+			denylist := fakeTokenDenylist{}
+			s := NewTokenService(fakeJWTService{},
+				&fakeUserRepository{users: map[uuid.UUID]*model.User{user.ID: user}},
+				refreshRepo, sessionRepo, denylist, time.Hour)
+
+			presented := tt.present(t, s, refreshRepo, user)
+			pair, err := s.Refresh(presented, model.RequestInfo{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Refresh() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err == nil {
+				// The new token is live and the presented one is spent
+				refresh(t, s, pair.RefreshToken)
+				if _, err := s.Refresh(presented, model.RequestInfo{}); !errors.Is(err, ErrRefreshTokenReused) {
+					t.Errorf("reusing the presented token: error = %v, want %v", err, ErrRefreshTokenReused)
+				}
+				return
+			}
+			for _, session := range sessionRepo.sessions {
+				if revoked := session.RevokedAt != nil; revoked != tt.wantFamilyRevoked {
+					t.Errorf("session revoked = %v, want %v", revoked, tt.wantFamilyRevoked)
+				}
+			}
+			for _, token := range refreshRepo.tokens {
+				if tt.wantFamilyRevoked && !token.IsRevoked {
+					t.Errorf("refresh token %s not revoked", token.ID)
+				}
+				if denied := denylist[token.AccessTokenID]; denied != tt.wantFamilyRevoked {
+					t.Errorf("access token %s denied = %v, want %v", token.AccessTokenID, denied, tt.wantFamilyRevoked)
+				}
+			}
+		})
+	}
+}
+
+func issue(t *testing.T, s TokenService, user *model.User) *model.TokenPair {
+	t.Helper()
+	pair, err := s.IssueTokens(user, model.RequestInfo{})
+	if err != nil {
+		t.Fatalf("IssueTokens() error = %v", err)
+	}
+	return pair
+}
+
+func refresh(t *testing.T, s TokenService, refreshToken string) *model.TokenPair {
+	t.Helper()
+	pair, err := s.Refresh(refreshToken, model.RequestInfo{})
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	return pair
+}
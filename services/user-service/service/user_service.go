@@ -1,31 +1,43 @@
 package service
 
 import (
-	"errors"
+	"log"
 	"services/user-service/model"
 	"services/user-service/repository"
 	"services/user-service/utils"
 
+	"booking-system/shared/pkg/apperr"
+
 	"github.com/google/uuid"
 )
 
+var (
+	ErrAccountSuspended      = apperr.Forbidden("account_suspended")
+	ErrEmailTaken            = apperr.Conflict("email_taken")
+	ErrPasswordResetRequired = apperr.Forbidden("password_reset_required")
+)
+
 type UserService interface {
 	Register(req model.RegisterRequest) (*model.User, error)
-	Login(req model.LoginRequest) (*model.User, error)
+	Login(req model.LoginRequest, info model.RequestInfo) (*model.User, error)
 	GetProfile(userID uuid.UUID) (*model.User, error)
 	UpdateProfile(userID uuid.UUID, req model.UpdateProfileRequest) (*model.User, error)
-	DeleteProfile(userID uuid.UUID) error
 }
 
 type userService struct {
-	repo repository.UserRepository
+	repo         repository.UserRepository
+	verification VerificationService
+	guard        LoginGuard
 }
 
-func NewUserService(repo repository.UserRepository) UserService {
-	return &userService{repo}
+func NewUserService(repo repository.UserRepository, verification VerificationService, guard LoginGuard) UserService {
+	return &userService{repo, verification, guard}
 }
 
 func (s *userService) Register(req model.RegisterRequest) (*model.User, error) {
+	if _, err := s.repo.FindByEmail(req.Email); err == nil {
+		return nil, ErrEmailTaken
+	}
 	hashed, err := utils.HashPassword(req.Password)
 	if err != nil {
 		return nil, err
@@ -37,27 +49,65 @@ func (s *userService) Register(req model.RegisterRequest) (*model.User, error) {
 		FullName:     req.FullName,
 		Phone:        req.Phone,
 		Gender:       req.Gender,
-		Role:         model.UserRole(req.Role),
+		Role:         model.RoleUser,
+		Status:       model.StatusActive,
+	}
+	// Experts act as users until an admin approves the role
+	if model.UserRole(req.Role) == model.RoleExpert {
+		requested := model.RoleExpert
+		user.RequestedRole = &requested
 	}
 	if err := s.repo.Create(user); err != nil {
 		return nil, err
 	}
+	// The account exists either way; the user can ask for a new link
+	if err := s.verification.SendVerification(user); err != nil {
+		log.Printf("failed to send verification email to %s: %v", user.Email, err)
+	}
 	user.PasswordHash = ""
 	return user, nil
 }
 
-func (s *userService) Login(req model.LoginRequest) (*model.User, error) {
+// Login checks the password. Repeated failures delay further attempts and
+// eventually lock the account, see LoginGuard.
+func (s *userService) Login(req model.LoginRequest, info model.RequestInfo) (*model.User, error) {
+	if err := s.guard.Check(req.Email, info.IPAddress); err != nil {
+		return nil, err
+	}
 	user, err := s.repo.FindByEmail(req.Email)
 	if err != nil {
-		return nil, errors.New("invalid email or password")
+		s.guard.RecordFailure(nil, req.Email, info)
+		return nil, ErrInvalidCredentials
+	}
+	if err := s.guard.CheckAccount(user); err != nil {
+		return nil, err
 	}
 	if !utils.CheckPasswordHash(req.Password, user.PasswordHash) {
-		return nil, errors.New("invalid email or password")
+		s.guard.RecordFailure(user, req.Email, info)
+		return nil, ErrInvalidCredentials
 	}
+	// Only reported once the password is known to be right
+	if err := checkLoginAllowed(user); err != nil {
+		return nil, err
+	}
+	// Failures are forgotten once the login completes, which may still
+	// take an MFA code; see MFAService.Challenge
 	user.PasswordHash = ""
 	return user, nil
 }
 
+// checkLoginAllowed rejects accounts an admin suspended or forced to reset
+// their password, at every login step
+func checkLoginAllowed(user *model.User) error {
+	if user.Status == model.StatusSuspended {
+		return ErrAccountSuspended
+	}
+	if user.PasswordResetRequired {
+		return ErrPasswordResetRequired
+	}
+	return nil
+}
+
 func (s *userService) GetProfile(userID uuid.UUID) (*model.User, error) {
 	user, err := s.repo.FindByID(userID)
 	if err != nil {
@@ -78,22 +128,19 @@ func (s *userService) UpdateProfile(userID uuid.UUID, req model.UpdateProfileReq
 	if req.Phone != "" {
 		user.Phone = req.Phone
 	}
-	if req.Image != "" {
-		user.Image = req.Image
-	}
 	if req.Gender != "" {
 		user.Gender = req.Gender
 	}
 	if req.Description != "" {
 		user.Description = req.Description
 	}
+	// Access tokens carry the language from the next refresh on
+	if req.Language != "" {
+		user.Language = req.Language
+	}
 	if err := s.repo.Update(user); err != nil {
 		return nil, err
 	}
 	user.PasswordHash = ""
 	return user, nil
 }
-
-func (s *userService) DeleteProfile(userID uuid.UUID) error {
-	return s.repo.Delete(userID)
-}
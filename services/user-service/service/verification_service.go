@@ -0,0 +1,142 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"net/url"
+	"services/user-service/model"
+	"services/user-service/repository"
+	"time"
+
+	"booking-system/shared/pkg/apperr"
+	"booking-system/shared/pkg/mailer"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidVerificationToken = apperr.Validation("invalid_verification_token")
+	ErrVerificationThrottled    = apperr.RateLimited("verification_throttled")
+)
+
+const emailVerificationPurpose = "email_verification"
+
+type VerificationService interface {
+	SendVerification(user *model.User) error
+	VerifyEmail(token string) (*model.User, error)
+	ResendVerification(email string) error
+	ResendCooldown() time.Duration
+}
+
+// verificationClaims binds the token to the address it was sent to, so a
+// link stops working once the user changes their email
+type verificationClaims struct {
+	Email   string `json:"email"`
+	Purpose string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+type verificationService struct {
+	repo      repository.UserRepository
+	mailer    mailer.Mailer
+	key       []byte
+	ttl       time.Duration
+	cooldown  time.Duration
+	verifyURL string
+}
+
+// NewVerificationService signs verification links with a key derived from
+// secret, so they can never be used as access tokens
+func NewVerificationService(repo repository.UserRepository, mailer mailer.Mailer, secret string, ttl, cooldown time.Duration, verifyURL string) VerificationService {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(emailVerificationPurpose))
+	return &verificationService{
+		repo:      repo,
+		mailer:    mailer,
+		key:       mac.Sum(nil),
+		ttl:       ttl,
+		cooldown:  cooldown,
+		verifyURL: verifyURL,
+	}
+}
+
+// SendVerification emails a verification link, at most once per cooldown
+func (s *verificationService) SendVerification(user *model.User) error {
+	if user.EmailVerified {
+		return nil
+	}
+	now := time.Now()
+	claimed, err := s.repo.ClaimVerificationSend(user.ID, now, s.cooldown)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return ErrVerificationThrottled
+	}
+
+	claims := verificationClaims{
+		Email:   user.Email,
+		Purpose: emailVerificationPurpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user.ID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.ttl)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.key)
+	if err != nil {
+		return err
+	}
+
+	link := s.verifyURL + "?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf("Hi %s,\n\nPlease confirm your email address by opening the link below. It expires in %d hours.\n\n%s\n\nIf you did not create an account, you can ignore this email.",
+		user.FullName, int(s.ttl.Hours()), link)
+	return s.mailer.Send(user.Email, "Confirm your email address", body)
+}
+
+// VerifyEmail marks the address in a valid token as verified. Verifying an
+// already verified address succeeds again.
+func (s *verificationService) VerifyEmail(token string) (*model.User, error) {
+	claims := &verificationClaims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		return s.key, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || !parsed.Valid || claims.Purpose != emailVerificationPurpose {
+		return nil, ErrInvalidVerificationToken
+	}
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	user, err := s.repo.FindByID(userID)
+	if err != nil || user.Email != claims.Email {
+		return nil, ErrInvalidVerificationToken
+	}
+	if !user.EmailVerified {
+		now := time.Now()
+		if err := s.repo.MarkEmailVerified(user.ID, now); err != nil {
+			return nil, err
+		}
+		user.EmailVerified = true
+		user.EmailVerifiedAt = &now
+	}
+	user.PasswordHash = ""
+	return user, nil
+}
+
+// ResendVerification sends a new link. Unknown and already verified addresses
+// are silently ignored so the endpoint does not reveal which emails exist.
+func (s *verificationService) ResendVerification(email string) error {
+	user, err := s.repo.FindByEmail(email)
+	if err != nil || user.EmailVerified {
+		return nil
+	}
+	return s.SendVerification(user)
+}
+
+func (s *verificationService) ResendCooldown() time.Duration {
+	return s.cooldown
+}
@@ -0,0 +1,65 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA1 key from the RFC 6238 test vectors, base32 encoded
+var rfcSecret = encoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestValidate(t *testing.T) {
+	// RFC 6238 test vectors, truncated to six digits
+	const (
+		codeAt59         = "287082" // step 1
+		codeAt1111111109 = "081804" // step 37037036
+	)
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		at       int64
+		after    int64
+		wantStep int64
+		wantOK   bool
+	}{
+		{name: "current step", secret: rfcSecret, code: codeAt59, at: 59, wantStep: 1, wantOK: true},
+		{name: "other test vector", secret: rfcSecret, code: codeAt1111111109, at: 1111111109, wantStep: 37037036, wantOK: true},
+		{name: "lowercase secret", secret: strings.ToLower(rfcSecret), code: codeAt59, at: 59, wantStep: 1, wantOK: true},
+		{name: "previous step within skew", secret: rfcSecret, code: codeAt59, at: 89, wantStep: 1, wantOK: true},
+		{name: "next step within skew", secret: rfcSecret, code: codeAt59, at: 0, wantStep: 1, wantOK: true},
+		{name: "outside skew", secret: rfcSecret, code: codeAt59, at: 120},
+		{name: "replayed step", secret: rfcSecret, code: codeAt59, at: 59, after: 1},
+		{name: "replayed after a later step", secret: rfcSecret, code: codeAt59, at: 89, after: 2},
+		{name: "earlier step used", secret: rfcSecret, code: codeAt1111111109, at: 1111111109, after: 37037035, wantStep: 37037036, wantOK: true},
+		{name: "wrong code", secret: rfcSecret, code: "000000", at: 59},
+		{name: "wrong length", secret: rfcSecret, code: "94287082", at: 59},
+		{name: "invalid secret", secret: "not base32!", code: codeAt59, at: 59},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Validate(tt.secret, tt.code, time.Unix(tt.at, 0), tt.after)
+			if step != tt.wantStep || ok != tt.wantOK {
+				t.Errorf("Validate() = (%d, %v), want (%d, %v)", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestValidateRejectsReplay(t *testing.T) {
+	now := time.Unix(59, 0)
+	step, ok := Validate(rfcSecret, "287082", now, 0)
+	if !ok {
+		t.Fatal("first use rejected")
+	}
+	// The step recorded after the first use rejects the same code, even a
+	// step later while it is still within the skew
+	for _, at := range []time.Time{now, now.Add(Period)} {
+		if _, ok := Validate(rfcSecret, "287082", at, step); ok {
+			t.Errorf("code replayed at %s accepted", at.UTC().Format(time.TimeOnly))
+		}
+	}
+}
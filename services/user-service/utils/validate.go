@@ -1,10 +1,20 @@
 package utils
 
 import (
+	"errors"
+
+	"booking-system/shared/pkg/apperr"
+
 	"github.com/go-playground/validator/v10"
 )
 
-var validate = validator.New()
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(apperr.JSONFieldName)
+	return v
+}
 
 func ValidateRegisterInput(req interface{}) error {
 	if err := validate.Struct(req); err != nil {
@@ -20,9 +30,17 @@ func ValidateUpdateProfileInput(req interface{}) error {
 	return nil
 }
 
-func ParseValidationError(err error) string {
-	if errs, ok := err.(validator.ValidationErrors); ok {
-		return errs.Error()
+// ValidationError turns a binding or validation error into a validation
+// error listing the rejected fields. Bodies that could not be parsed at all
+// have no fields; the parser's message explains them.
+func ValidationError(err error) *apperr.Error {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return apperr.Validation("invalid_request", err.Error())
+	}
+	fields := make([]apperr.FieldError, 0, len(errs))
+	for _, e := range errs {
+		fields = append(fields, apperr.FieldError{Field: e.Field(), Rule: e.Tag(), Param: e.Param()})
 	}
-	return err.Error()
+	return apperr.Validation("invalid_fields").WithFields(fields...)
 }
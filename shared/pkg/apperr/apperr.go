@@ -0,0 +1,187 @@
+// Package apperr defines the domain errors shared by the services. Every
+// error has a kind, which decides its HTTP status code, and a catalog
+// message; see Problem for how they are written to clients.
+package apperr
+
+import (
+	"errors"
+	"net/http"
+
+	"booking-system/shared/pkg/i18n"
+)
+
+// Kind tells clients what went wrong, and so what they can do about it
+type Kind string
+
+const (
+	KindValidation        Kind = "validation"
+	KindUnauthorized      Kind = "unauthorized"
+	KindForbidden         Kind = "forbidden"
+	KindNotFound          Kind = "not_found"
+	KindConflict          Kind = "conflict"
+	KindInvalidTransition Kind = "invalid_transition"
+	KindGone              Kind = "gone"
+	KindTooLarge          Kind = "too_large"
+	KindUnsupportedMedia  Kind = "unsupported_media"
+	KindLocked            Kind = "locked"
+	KindRateLimited       Kind = "rate_limited"
+	KindUnavailable       Kind = "unavailable"
+	KindInternal          Kind = "internal"
+)
+
+var statuses = map[Kind]int{
+	KindValidation:        http.StatusBadRequest,
+	KindUnauthorized:      http.StatusUnauthorized,
+	KindForbidden:         http.StatusForbidden,
+	KindNotFound:          http.StatusNotFound,
+	KindConflict:          http.StatusConflict,
+	KindInvalidTransition: http.StatusConflict,
+	KindGone:              http.StatusGone,
+	KindTooLarge:          http.StatusRequestEntityTooLarge,
+	KindUnsupportedMedia:  http.StatusUnsupportedMediaType,
+	KindLocked:            http.StatusLocked,
+	KindRateLimited:       http.StatusTooManyRequests,
+	KindUnavailable:       http.StatusServiceUnavailable,
+	KindInternal:          http.StatusInternalServerError,
+}
+
+// Status returns the HTTP status code of errors of kind k
+func (k Kind) Status() int {
+	if status, ok := statuses[k]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// KindFor returns the kind of errors answered with status. Conflicts are
+// KindConflict; invalid transitions are only known to the services.
+func KindFor(status int) Kind {
+	if status == http.StatusConflict {
+		return KindConflict
+	}
+	for kind, s := range statuses {
+		if s == status {
+			return kind
+		}
+	}
+	if status >= 400 && status < 500 {
+		return KindValidation
+	}
+	return KindInternal
+}
+
+// FieldError explains why one field of a request was rejected. Rule is the
+// validation rule it broke, e.g. "required" or "max", and Param the rule's
+// argument, e.g. the maximum.
+type FieldError struct {
+	Field string
+	Rule  string
+	Param string
+}
+
+// Error is a domain error. Errors with the same message code match in
+// errors.Is, so package-level errors can serve as sentinels.
+type Error struct {
+	Kind Kind
+	// Fields lists the rejected fields of a validation error
+	Fields []FieldError
+	msg    *i18n.Error
+}
+
+// New returns an error of the given kind with the message code and its
+// arguments
+func New(kind Kind, code string, args ...interface{}) *Error {
+	return &Error{Kind: kind, msg: i18n.NewError(code, args...)}
+}
+
+func Validation(code string, args ...interface{}) *Error {
+	return New(KindValidation, code, args...)
+}
+
+func Unauthorized(code string, args ...interface{}) *Error {
+	return New(KindUnauthorized, code, args...)
+}
+
+func Forbidden(code string, args ...interface{}) *Error {
+	return New(KindForbidden, code, args...)
+}
+
+func NotFound(code string, args ...interface{}) *Error {
+	return New(KindNotFound, code, args...)
+}
+
+func Conflict(code string, args ...interface{}) *Error {
+	return New(KindConflict, code, args...)
+}
+
+// InvalidTransition is returned when a resource cannot move to the
+// requested state, or an action is not allowed in its current state
+func InvalidTransition(code string, args ...interface{}) *Error {
+	return New(KindInvalidTransition, code, args...)
+}
+
+func Gone(code string, args ...interface{}) *Error {
+	return New(KindGone, code, args...)
+}
+
+func TooLarge(code string, args ...interface{}) *Error {
+	return New(KindTooLarge, code, args...)
+}
+
+func UnsupportedMedia(code string, args ...interface{}) *Error {
+	return New(KindUnsupportedMedia, code, args...)
+}
+
+func Locked(code string, args ...interface{}) *Error {
+	return New(KindLocked, code, args...)
+}
+
+func RateLimited(code string, args ...interface{}) *Error {
+	return New(KindRateLimited, code, args...)
+}
+
+// Unavailable is returned when a service the request depends on cannot be
+// reached
+func Unavailable(code string, args ...interface{}) *Error {
+	return New(KindUnavailable, code, args...)
+}
+
+// Code returns the message code of e
+func (e *Error) Code() string {
+	return e.msg.Code
+}
+
+// Wrap returns a copy of e explained by cause; it still matches e in
+// errors.Is
+func (e *Error) Wrap(cause error) *Error {
+	return &Error{Kind: e.Kind, Fields: e.Fields, msg: e.msg.Wrap(cause)}
+}
+
+// WithFields returns a copy of e listing the rejected fields
+func (e *Error) WithFields(fields ...FieldError) *Error {
+	return &Error{Kind: e.Kind, Fields: fields, msg: e.msg}
+}
+
+func (e *Error) Error() string {
+	return e.msg.Error()
+}
+
+// Unwrap returns the catalog message, so i18n.Localize finds it
+func (e *Error) Unwrap() error {
+	return e.msg
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.msg.Code == e.msg.Code
+}
+
+// KindOf returns the kind of err, KindInternal for errors that are not an
+// *Error
+func KindOf(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return KindInternal
+}
@@ -0,0 +1,104 @@
+package apperr
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"booking-system/shared/pkg/i18n"
+)
+
+// ContentType is the media type of problem bodies (RFC 7807)
+const ContentType = "application/problem+json"
+
+const typePrefix = "urn:booking-system:problem:"
+
+// Problem is the body of every error response. Type identifies the kind of
+// error and Code the message; both are stable, while Title and Detail are
+// written in the caller's language.
+type Problem struct {
+	Type     string         `json:"type"`
+	Title    string         `json:"title"`
+	Status   int            `json:"status"`
+	Detail   string         `json:"detail"`
+	Instance string         `json:"instance,omitempty"`
+	Code     string         `json:"code"`
+	Errors   []FieldProblem `json:"errors,omitempty"`
+}
+
+// FieldProblem is a rejected field of a validation problem
+type FieldProblem struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// NewProblem describes err in lang. Errors that are not an *Error are
+// internal errors: their text is not shown unless they wrap a catalog
+// message, and the caller should log them.
+func NewProblem(lang i18n.Lang, err error) *Problem {
+	kind := KindInternal
+	var fields []FieldError
+	var e *Error
+	if errors.As(err, &e) {
+		kind, fields = e.Kind, e.Fields
+	}
+	code, detail := i18n.Localize(lang, err)
+	p := &Problem{
+		Type:   typePrefix + string(kind),
+		Title:  i18n.Message(lang, "problem_"+string(kind)),
+		Status: kind.Status(),
+		Detail: detail,
+		Code:   code,
+	}
+	for _, f := range fields {
+		p.Errors = append(p.Errors, FieldProblem{Field: f.Field, Rule: f.Rule, Message: fieldMessage(lang, f)})
+	}
+	return p
+}
+
+// fieldMessage explains a broken validation rule. Rules with an argument
+// have it in their message, e.g. "Must be at most %s".
+func fieldMessage(lang i18n.Lang, f FieldError) string {
+	code := "field_" + f.Rule
+	if i18n.Message(lang, code) == code {
+		return i18n.Message(lang, "field_invalid")
+	}
+	if f.Param == "" {
+		return i18n.Message(lang, code)
+	}
+	return i18n.Message(lang, code, f.Param)
+}
+
+// Write sends p as the response
+func Write(w http.ResponseWriter, p *Problem) {
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// Respond answers a plain net/http request with the problem err describes,
+// in the language of its Accept-Language header
+func Respond(w http.ResponseWriter, r *http.Request, err error) {
+	p := NewProblem(i18n.Resolve("", r.Header.Get("Accept-Language")), err)
+	p.Instance = r.URL.Path
+	Write(w, p)
+}
+
+// JSONFieldName names struct fields by their json (or form) tag in
+// validation errors, so clients see the names they sent. Register it with
+// the validator's RegisterTagNameFunc.
+func JSONFieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
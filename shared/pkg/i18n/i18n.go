@@ -0,0 +1,167 @@
+// Package i18n holds the catalogs of API messages shared by the services.
+// Every message has a stable code that clients can match on; the text is
+// chosen from the user's saved language or the request's Accept-Language.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+type Lang string
+
+const (
+	Vietnamese Lang = "vi"
+	English    Lang = "en"
+)
+
+// Default is used when neither the user nor the request asks for a
+// supported language. It is also the language of Error.Error, which ends up
+// in logs.
+const Default = English
+
+// InternalError is the code of the message shown for errors that carry no
+// code of their own, whose text is not meant for clients
+const InternalError = "internal_error"
+
+//go:embed locales/*.json
+var locales embed.FS
+
+var catalogs = map[Lang]map[string]string{}
+
+func init() {
+	for _, lang := range []Lang{Vietnamese, English} {
+		data, err := locales.ReadFile(path.Join("locales", string(lang)+".json"))
+		if err != nil {
+			panic(fmt.Sprintf("i18n: missing catalog for %q: %v", lang, err))
+		}
+		messages := map[string]string{}
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalog for %q: %v", lang, err))
+		}
+		catalogs[lang] = messages
+	}
+}
+
+// Supported reports whether there is a catalog for lang
+func Supported(lang string) bool {
+	_, ok := catalogs[Lang(lang)]
+	return ok
+}
+
+// Message formats the message with the given code in lang, falling back to
+// Default when lang has no translation and to the code itself when no
+// catalog has it. Args are applied with fmt verbs; catalogs may use indexed
+// verbs such as %[2]s when the word order differs.
+func Message(lang Lang, code string, args ...interface{}) string {
+	format, ok := catalogs[lang][code]
+	if !ok {
+		if format, ok = catalogs[Default][code]; !ok {
+			return code
+		}
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Resolve picks the language of a response. A saved preference wins, since
+// the user chose it; otherwise the best supported language of the
+// Accept-Language header is used.
+func Resolve(saved, acceptLanguage string) Lang {
+	if Supported(saved) {
+		return Lang(saved)
+	}
+	if lang, ok := parseAcceptLanguage(acceptLanguage); ok {
+		return lang
+	}
+	return Default
+}
+
+// parseAcceptLanguage returns the supported language with the highest
+// weight in an Accept-Language header such as "vi-VN,vi;q=0.9,en;q=0.8".
+// Region subtags are ignored; languages with q=0 are refused.
+func parseAcceptLanguage(header string) (Lang, bool) {
+	var best Lang
+	bestQ := 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if q > bestQ && Supported(primary) {
+			best, bestQ = Lang(primary), q
+		}
+	}
+	return best, bestQ > 0
+}
+
+// Error is an error whose text comes from the catalogs, so handlers can
+// show it in the caller's language
+type Error struct {
+	Code string
+	Args []interface{}
+	// Cause explains the error, e.g. which rule a request broke. Clients
+	// only see it when it is an *Error too; logs always do.
+	Cause error
+}
+
+// NewError returns an error with the message code and its arguments
+func NewError(code string, args ...interface{}) *Error {
+	return &Error{Code: code, Args: args}
+}
+
+// Wrap returns a copy of e explained by cause; it still matches e in
+// errors.Is
+func (e *Error) Wrap(cause error) *Error {
+	return &Error{Code: e.Code, Args: e.Args, Cause: cause}
+}
+
+func (e *Error) Error() string {
+	if e.Cause != nil {
+		return Message(Default, e.Code, e.Args...) + ": " + e.Cause.Error()
+	}
+	return Message(Default, e.Code, e.Args...)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// Is matches errors with the same code, whatever their arguments, so
+// errors.Is works against a sentinel created with NewError
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Localize returns the code and text of err in lang. Errors that do not
+// wrap an *Error get the InternalError message instead of their own text.
+func Localize(lang Lang, err error) (string, string) {
+	var coded *Error
+	if !errors.As(err, &coded) {
+		return InternalError, Message(lang, InternalError)
+	}
+	return coded.Code, coded.text(lang)
+}
+
+func (e *Error) text(lang Lang) string {
+	text := Message(lang, e.Code, e.Args...)
+	var cause *Error
+	if errors.As(e.Cause, &cause) {
+		text += ": " + cause.text(lang)
+	}
+	return text
+}
@@ -0,0 +1,204 @@
+package jwks
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrUnknownKey   = errors.New("token signed with an unknown key")
+	ErrMissingKeyID = errors.New("token has no key ID")
+)
+
+// Claims are the claims user-service puts in access tokens. Language is the
+// user's saved language for API messages, empty when they have none.
+type Claims struct {
+	Role     string `json:"role,omitempty"`
+	Language string `json:"lang,omitempty"`
+	jwt.RegisteredClaims
+}
+
+type VerifierConfig struct {
+	// URL of user-service's /.well-known/jwks.json
+	URL string
+	// Issuer, when set, must match the token's iss claim
+	Issuer string
+	// CacheTTL is how long fetched keys are used before refetching
+	CacheTTL time.Duration
+	// MinRefreshInterval limits refetches triggered by unknown key IDs
+	MinRefreshInterval time.Duration
+	Timeout            time.Duration
+}
+
+// Verifier checks access tokens against the public keys published by
+// user-service. Keys are cached; a token with an unknown key ID triggers a
+// refetch so rotated keys are picked up without waiting for the TTL. When the
+// JWKS endpoint is down, the last fetched keys keep being used.
+type Verifier struct {
+	config VerifierConfig
+	client *http.Client
+
+	mu          sync.RWMutex
+	keys        map[string]crypto.PublicKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+	refreshMu   sync.Mutex
+}
+
+func NewVerifier(config VerifierConfig) *Verifier {
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = 10 * time.Minute
+	}
+	if config.MinRefreshInterval <= 0 {
+		config.MinRefreshInterval = 10 * time.Second
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 3 * time.Second
+	}
+	return &Verifier{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+		keys:   map[string]crypto.PublicKey{},
+	}
+}
+
+// Verify parses and validates an access token
+func (v *Verifier) Verify(tokenString string) (*Claims, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{AlgRS256, AlgEdDSA}),
+		jwt.WithExpirationRequired(),
+	}
+	if v.config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(v.config.Issuer))
+	}
+
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, v.keyFunc, options...)
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
+func (v *Verifier) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, ErrMissingKeyID
+	}
+	key, err := v.key(kid)
+	if err != nil {
+		return nil, err
+	}
+	// The key type must fit the algorithm in the header
+	if keyAlg(key) != token.Method.Alg() {
+		return nil, fmt.Errorf("key %s cannot verify %s", kid, token.Method.Alg())
+	}
+	return key, nil
+}
+
+func (v *Verifier) key(kid string) (crypto.PublicKey, error) {
+	v.mu.RLock()
+	key, ok := v.keys[kid]
+	fresh := time.Since(v.fetchedAt) < v.config.CacheTTL
+	v.mu.RUnlock()
+	if ok && fresh {
+		return key, nil
+	}
+
+	v.refresh(!ok)
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if key, ok := v.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, ErrUnknownKey
+}
+
+// refresh refetches the key set. Refetches for unknown key IDs are limited
+// to one per MinRefreshInterval so forged key IDs cannot flood user-service.
+func (v *Verifier) refresh(unknownKey bool) {
+	v.refreshMu.Lock()
+	defer v.refreshMu.Unlock()
+
+	v.mu.RLock()
+	stale := time.Since(v.fetchedAt) >= v.config.CacheTTL
+	throttled := time.Since(v.attemptedAt) < v.config.MinRefreshInterval
+	v.mu.RUnlock()
+	if throttled || (!stale && !unknownKey) {
+		return
+	}
+
+	keys, err := v.fetch()
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.attemptedAt = time.Now()
+	if err != nil {
+		log.Printf("failed to fetch JWKS from %s: %v", v.config.URL, err)
+		return
+	}
+	v.keys = keys
+	v.fetchedAt = time.Now()
+}
+
+func (v *Verifier) fetch() (map[string]crypto.PublicKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), v.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.config.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var set Set
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("invalid JWKS: %w", err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.PublicKey()
+		if err != nil {
+			log.Printf("skipping JWK %s: %v", jwk.Kid, err)
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	return keys, nil
+}
+
+func keyAlg(key crypto.PublicKey) string {
+	switch key.(type) {
+	case *rsa.PublicKey:
+		return AlgRS256
+	case ed25519.PublicKey:
+		return AlgEdDSA
+	default:
+		return ""
+	}
+}
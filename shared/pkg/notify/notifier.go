@@ -0,0 +1,193 @@
+package notify
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"booking-system/shared/pkg/i18n"
+)
+
+const (
+	// retryDelay is how long a notification that failed to send waits before
+	// the next attempt
+	retryDelay = 5 * time.Minute
+	// flushBatch is how many queued notifications one Flush sends at most
+	flushBatch = 500
+	// timeLayout is how times in notifications are written
+	timeLayout = "2006-01-02 15:04 MST"
+)
+
+// Notifier sends notifications according to each user's settings
+type Notifier struct {
+	store   *Store
+	senders map[Channel]Sender
+}
+
+// NewNotifier sends through senders, keyed by channel; in-app notifications
+// are stored in db
+func NewNotifier(db *sql.DB, senders map[Channel]Sender) *Notifier {
+	return &Notifier{store: NewStore(db), senders: senders}
+}
+
+// Settings returns the user's notification settings
+func (n *Notifier) Settings(userID string) (*Settings, error) {
+	return n.store.Load(userID)
+}
+
+// Notify tells the user about msg on every channel they enabled for its
+// event. In-app notifications are delivered at once; the others wait for
+// the end of the user's quiet hours or for their digest. Failing channels
+// are retried later, so only storage errors are returned.
+func (n *Notifier) Notify(userID string, msg Message) error {
+	settings, err := n.store.Load(userID)
+	if err != nil {
+		return err
+	}
+	to, err := n.store.recipient(userID, settings)
+	if err != nil || to == nil {
+		return err
+	}
+	lang := i18n.Resolve(to.Language, "")
+	title := i18n.Message(lang, msg.Title)
+	body := i18n.Message(lang, msg.Body, localArgs(msg.Args, settings.Location())...)
+	now := time.Now()
+
+	var errs []error
+	for _, channel := range Channels {
+		if !settings.Enabled(msg.Event, channel) {
+			continue
+		}
+		if channel == ChannelInApp {
+			errs = append(errs, n.store.insertInApp(userID, msg, title, body, now))
+			continue
+		}
+		p := pending{UserID: userID, Event: msg.Event, Channel: channel, Title: title, Body: body}
+		if !msg.Expires.IsZero() {
+			p.Expires = sql.NullTime{Time: msg.Expires.UTC(), Valid: true}
+		}
+		sendAt := now
+		if settings.Digest != DigestOff && msg.digestible() {
+			sendAt, p.Digest = settings.NextDigest(now), true
+		}
+		sendAt = settings.QuietUntil(sendAt)
+		if p.Expires.Valid && sendAt.After(p.Expires.Time) {
+			continue
+		}
+		if sendAt.After(now) {
+			errs = append(errs, n.store.enqueue(p, sendAt))
+			continue
+		}
+		errs = append(errs, n.send(to, p, now))
+	}
+	return errors.Join(errs...)
+}
+
+// localArgs shows times in the user's time zone
+func localArgs(args []interface{}, loc *time.Location) []interface{} {
+	local := make([]interface{}, len(args))
+	for i, arg := range args {
+		if t, ok := arg.(time.Time); ok {
+			arg = t.In(loc).Format(timeLayout)
+		}
+		local[i] = arg
+	}
+	return local
+}
+
+// send delivers p now, queueing it for another attempt when the channel
+// fails
+func (n *Notifier) send(to *Recipient, p pending, now time.Time) error {
+	sender, ok := n.senders[p.Channel]
+	if !ok {
+		return nil
+	}
+	err := sender.Send(to, p.Title, p.Body)
+	switch {
+	case err == nil, errors.Is(err, errNoAddress):
+		return nil
+	case p.Expires.Valid && now.Add(retryDelay).After(p.Expires.Time):
+		log.Printf("Failed to send %s notification to user %s: %v", p.Channel, to.UserID, err)
+		return nil
+	default:
+		log.Printf("Failed to send %s notification to user %s, retrying: %v", p.Channel, to.UserID, err)
+		return n.store.enqueue(p, now.Add(retryDelay))
+	}
+}
+
+// Flush sends the queued notifications that are due, bundling digest
+// notifications into one message per user and channel. Run it every minute
+// or so.
+func (n *Notifier) Flush() error {
+	now := time.Now()
+	due, err := n.store.claimDue(now, flushBatch)
+	if err != nil {
+		return err
+	}
+
+	type digestKey struct {
+		userID  string
+		channel Channel
+	}
+	digests := map[digestKey][]pending{}
+	recipients := map[string]*Recipient{}
+	recipient := func(userID string) (*Recipient, error) {
+		if to, ok := recipients[userID]; ok {
+			return to, nil
+		}
+		settings, err := n.store.Load(userID)
+		if err != nil {
+			return nil, err
+		}
+		to, err := n.store.recipient(userID, settings)
+		if err != nil {
+			return nil, err
+		}
+		recipients[userID] = to
+		return to, nil
+	}
+
+	var errs []error
+	for _, p := range due {
+		if p.Digest {
+			key := digestKey{p.UserID, p.Channel}
+			digests[key] = append(digests[key], p)
+			continue
+		}
+		to, err := recipient(p.UserID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if to != nil {
+			errs = append(errs, n.send(to, p, now))
+		}
+	}
+
+	for key, items := range digests {
+		to, err := recipient(key.userID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if to == nil {
+			continue
+		}
+		lang := i18n.Resolve(to.Language, "")
+		lines := make([]string, len(items))
+		for i, p := range items {
+			lines[i] = fmt.Sprintf("• %s: %s", p.Title, p.Body)
+		}
+		digest := pending{
+			UserID:  key.userID,
+			Channel: key.channel,
+			Title:   i18n.Message(lang, "notification_digest_title", len(items)),
+			Body:    strings.Join(lines, "\n"),
+		}
+		errs = append(errs, n.send(to, digest, now))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,261 @@
+// Package notify sends notifications about bookings and accounts the way
+// each user asked for: per event and channel, outside their quiet hours and,
+// in digest mode, bundled into one message per day or week. Security emails
+// such as verification and password reset links bypass it and are always
+// sent.
+package notify
+
+import (
+	"slices"
+	"time"
+	_ "time/tzdata" // users pick any IANA time zone
+
+	"booking-system/shared/pkg/apperr"
+)
+
+// Event is what a notification is about
+type Event string
+
+const (
+	EventBookingCreated        Event = "booking_created"
+	EventBookingConfirmed      Event = "booking_confirmed"
+	EventBookingRejected       Event = "booking_rejected"
+	EventBookingCancelled      Event = "booking_cancelled"
+	EventBookingReminder       Event = "booking_reminder"
+	EventCertificationExpiring Event = "certification_expiring"
+)
+
+// Events lists every event users can set preferences for
+var Events = []Event{
+	EventBookingCreated,
+	EventBookingConfirmed,
+	EventBookingRejected,
+	EventBookingCancelled,
+	EventBookingReminder,
+	EventCertificationExpiring,
+}
+
+// Channel is how a notification reaches the user
+type Channel string
+
+const (
+	ChannelEmail    Channel = "email"
+	ChannelTelegram Channel = "telegram"
+	ChannelInApp    Channel = "in_app"
+	ChannelSMS      Channel = "sms"
+)
+
+// Channels lists every channel, in the order they are tried
+var Channels = []Channel{ChannelInApp, ChannelEmail, ChannelTelegram, ChannelSMS}
+
+// Digest is how often bundled notifications are sent
+type Digest string
+
+const (
+	DigestOff    Digest = "off"
+	DigestDaily  Digest = "daily"
+	DigestWeekly Digest = "weekly"
+)
+
+const (
+	// DefaultTimeZone is used until the user picks one
+	DefaultTimeZone = "Asia/Ho_Chi_Minh"
+	// DefaultReminderOffset is how long before a booking users are reminded
+	// unless they chose otherwise, in minutes
+	DefaultReminderOffset = 60
+	// DefaultDigestTime is when digests are sent in the user's time zone
+	DefaultDigestTime = "08:00"
+	// MaxReminderOffsets is how many reminders a user may ask for per booking
+	MaxReminderOffsets = 5
+	// MaxReminderOffset is the earliest reminder, a week ahead, in minutes
+	MaxReminderOffset = 7 * 24 * 60
+)
+
+// clockLayout is the format of quiet hours and the digest time
+const clockLayout = "15:04"
+
+// QuietHours is a daily period, in the user's time zone, during which only
+// in-app notifications are delivered; others wait until it ends. Start after
+// End spans midnight, e.g. 22:00 to 07:00.
+type QuietHours struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// Settings are a user's notification preferences
+type Settings struct {
+	// ReminderOffsets are how long before a booking to send reminders, in
+	// minutes
+	ReminderOffsets []int       `json:"reminder_offsets"`
+	QuietHours      *QuietHours `json:"quiet_hours"`
+	TimeZone        string      `json:"time_zone"`
+	Digest          Digest      `json:"digest"`
+	DigestTime      string      `json:"digest_time"`
+	TelegramChatID  string      `json:"telegram_chat_id"`
+	// Preferences tells for every event and channel whether to send
+	Preferences map[Event]map[Channel]bool `json:"preferences"`
+}
+
+// DefaultSettings are the settings of users who changed nothing: in-app and
+// email notifications for every event, one reminder an hour ahead
+func DefaultSettings() *Settings {
+	s := &Settings{
+		ReminderOffsets: []int{DefaultReminderOffset},
+		TimeZone:        DefaultTimeZone,
+		Digest:          DigestOff,
+		DigestTime:      DefaultDigestTime,
+		Preferences:     map[Event]map[Channel]bool{},
+	}
+	for _, event := range Events {
+		s.Preferences[event] = map[Channel]bool{}
+		for _, channel := range Channels {
+			s.Preferences[event][channel] = defaultEnabled(channel)
+		}
+	}
+	return s
+}
+
+// defaultEnabled tells whether channel is on for events the user has not
+// set a preference for. Telegram and SMS need the user to sign up first.
+func defaultEnabled(channel Channel) bool {
+	return channel == ChannelInApp || channel == ChannelEmail
+}
+
+// Enabled tells whether the user wants to hear about event on channel
+func (s *Settings) Enabled(event Event, channel Channel) bool {
+	if enabled, ok := s.Preferences[event][channel]; ok {
+		return enabled
+	}
+	return defaultEnabled(channel)
+}
+
+// Location returns the user's time zone
+func (s *Settings) Location() *time.Location {
+	if loc, err := time.LoadLocation(s.TimeZone); err == nil {
+		return loc
+	}
+	loc, _ := time.LoadLocation(DefaultTimeZone)
+	return loc
+}
+
+// QuietUntil returns when the quiet hours around t end, or t itself when t is
+// outside them
+func (s *Settings) QuietUntil(t time.Time) time.Time {
+	if s.QuietHours == nil {
+		return t
+	}
+	start, errStart := time.Parse(clockLayout, s.QuietHours.Start)
+	end, errEnd := time.Parse(clockLayout, s.QuietHours.End)
+	if errStart != nil || errEnd != nil || start.Equal(end) {
+		return t
+	}
+	local := t.In(s.Location())
+	at := func(day time.Time, clock time.Time) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, day.Location())
+	}
+	startToday, endToday := at(local, start), at(local, end)
+	if start.Before(end) {
+		if !local.Before(startToday) && local.Before(endToday) {
+			return endToday
+		}
+		return t
+	}
+	// The quiet hours span midnight
+	switch {
+	case local.Before(endToday):
+		return endToday
+	case !local.Before(startToday):
+		return at(local.AddDate(0, 0, 1), end)
+	default:
+		return t
+	}
+}
+
+// NextDigest returns when the digest covering t is sent: the next digest
+// time, on Mondays for weekly digests
+func (s *Settings) NextDigest(t time.Time) time.Time {
+	clock, err := time.Parse(clockLayout, s.DigestTime)
+	if err != nil {
+		clock, _ = time.Parse(clockLayout, DefaultDigestTime)
+	}
+	local := t.In(s.Location())
+	next := time.Date(local.Year(), local.Month(), local.Day(), clock.Hour(), clock.Minute(), 0, 0, local.Location())
+	if !next.After(local) {
+		next = next.AddDate(0, 0, 1)
+	}
+	if s.Digest == DigestWeekly {
+		for next.Weekday() != time.Monday {
+			next = next.AddDate(0, 0, 1)
+		}
+	}
+	return next
+}
+
+// Validate checks settings a user submitted, returning a validation error
+// for the first problem found
+func (s *Settings) Validate() error {
+	if len(s.ReminderOffsets) > MaxReminderOffsets {
+		return apperr.Validation("too_many_reminder_offsets", MaxReminderOffsets)
+	}
+	for i, offset := range s.ReminderOffsets {
+		if offset <= 0 || offset > MaxReminderOffset {
+			return apperr.Validation("invalid_reminder_offset", MaxReminderOffset)
+		}
+		if slices.Contains(s.ReminderOffsets[:i], offset) {
+			return apperr.Validation("duplicate_reminder_offset", offset)
+		}
+	}
+	if _, err := time.LoadLocation(s.TimeZone); err != nil || s.TimeZone == "" {
+		return apperr.Validation("invalid_time_zone")
+	}
+	switch s.Digest {
+	case DigestOff, DigestDaily, DigestWeekly:
+	default:
+		return apperr.Validation("invalid_digest_mode")
+	}
+	if _, err := time.Parse(clockLayout, s.DigestTime); err != nil {
+		return apperr.Validation("invalid_digest_time")
+	}
+	if s.QuietHours != nil {
+		_, errStart := time.Parse(clockLayout, s.QuietHours.Start)
+		_, errEnd := time.Parse(clockLayout, s.QuietHours.End)
+		if errStart != nil || errEnd != nil || s.QuietHours.Start == s.QuietHours.End {
+			return apperr.Validation("invalid_quiet_hours")
+		}
+	}
+	for event, channels := range s.Preferences {
+		if !slices.Contains(Events, event) {
+			return apperr.Validation("unknown_notification_event", string(event))
+		}
+		for channel, enabled := range channels {
+			if !slices.Contains(Channels, channel) {
+				return apperr.Validation("unknown_notification_channel", string(channel))
+			}
+			if enabled && channel == ChannelTelegram && s.TelegramChatID == "" {
+				return apperr.Validation("telegram_chat_id_required")
+			}
+		}
+	}
+	return nil
+}
+
+// Message is a notification to send. Title and Body are catalog message
+// codes, written in each recipient's language; Args fill in the body, with
+// times shown in the recipient's time zone.
+type Message struct {
+	Event     Event
+	Title     string
+	Body      string
+	Args      []interface{}
+	BookingID string
+	// Expires drops the notification when it could only be delivered after
+	// this time, e.g. a reminder for a booking that has started. Zero means
+	// it never expires.
+	Expires time.Time
+}
+
+// digestible tells whether msg may wait for the user's digest; reminders
+// are only useful on time
+func (m Message) digestible() bool {
+	return m.Expires.IsZero()
+}
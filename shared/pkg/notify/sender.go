@@ -0,0 +1,133 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"booking-system/shared/pkg/mailer"
+)
+
+// Sender delivers a notification over one channel
+type Sender interface {
+	Send(to *Recipient, title, body string) error
+}
+
+// errNoAddress is returned when the recipient cannot be reached on a channel,
+// e.g. SMS to a user without a phone number; the notification is dropped
+var errNoAddress = errors.New("recipient has no address on this channel")
+
+type emailSender struct {
+	mailer mailer.Mailer
+}
+
+// NewEmailSender sends notifications as emails
+func NewEmailSender(m mailer.Mailer) Sender {
+	return &emailSender{mailer: m}
+}
+
+func (s *emailSender) Send(to *Recipient, title, body string) error {
+	if to.Email == "" {
+		return errNoAddress
+	}
+	return s.mailer.Send(to.Email, title, body)
+}
+
+type telegramSender struct {
+	url    string
+	client *http.Client
+}
+
+// NewTelegramSender sends notifications through a Telegram bot to the chat
+// the user linked
+func NewTelegramSender(token string) Sender {
+	return &telegramSender{
+		url:    "https://api.telegram.org/bot" + token + "/sendMessage",
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *telegramSender) Send(to *Recipient, title, body string) error {
+	if to.TelegramChatID == "" {
+		return errNoAddress
+	}
+	return postJSON(s.client, s.url, map[string]string{
+		"chat_id": to.TelegramChatID,
+		"text":    title + "\n\n" + body,
+	})
+}
+
+type smsSender struct {
+	url    string
+	client *http.Client
+}
+
+// NewSMSSender sends notifications through an SMS gateway that accepts
+// {"to": ..., "text": ...} as JSON
+func NewSMSSender(url string) Sender {
+	return &smsSender{url: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (s *smsSender) Send(to *Recipient, title, body string) error {
+	if to.Phone == "" {
+		return errNoAddress
+	}
+	return postJSON(s.client, s.url, map[string]string{
+		"to":   to.Phone,
+		"text": title + ": " + body,
+	})
+}
+
+func postJSON(client *http.Client, url string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("gateway answered %s", resp.Status)
+	}
+	return nil
+}
+
+type logSender struct {
+	channel Channel
+}
+
+// NewLogSender writes notifications to the log instead of sending them, for
+// local development
+func NewLogSender(channel Channel) Sender {
+	return &logSender{channel: channel}
+}
+
+func (s *logSender) Send(to *Recipient, title, body string) error {
+	log.Printf("%s notification to user %s: %s\n%s", s.channel, to.UserID, title, body)
+	return nil
+}
+
+// SendersFromEnv builds the senders of every channel but in-app: email
+// through m, Telegram when TELEGRAM_BOT_TOKEN is set and SMS when
+// SMS_GATEWAY_URL is set. Unconfigured channels are logged.
+func SendersFromEnv(m mailer.Mailer) map[Channel]Sender {
+	senders := map[Channel]Sender{
+		ChannelEmail:    NewEmailSender(m),
+		ChannelTelegram: NewLogSender(ChannelTelegram),
+		ChannelSMS:      NewLogSender(ChannelSMS),
+	}
+	if token := os.Getenv("TELEGRAM_BOT_TOKEN"); token != "" {
+		senders[ChannelTelegram] = NewTelegramSender(token)
+	}
+	if url := os.Getenv("SMS_GATEWAY_URL"); url != "" {
+		senders[ChannelSMS] = NewSMSSender(url)
+	}
+	return senders
+}
@@ -0,0 +1,209 @@
+package notify
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Store reads and writes notification settings and pending notifications.
+// The tables are created by user-service's migrations.
+type Store struct {
+	db *sql.DB
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Load returns the user's settings, the defaults for anything they have not
+// changed
+func (s *Store) Load(userID string) (*Settings, error) {
+	settings := DefaultSettings()
+	var offsets pq.Int64Array
+	var quietStart, quietEnd sql.NullString
+	err := s.db.QueryRow(`
+		SELECT reminder_offsets, quiet_hours_start, quiet_hours_end, time_zone, digest, digest_time, telegram_chat_id
+		FROM notification_settings WHERE user_id = $1`, userID).
+		Scan(&offsets, &quietStart, &quietEnd, &settings.TimeZone, &settings.Digest, &settings.DigestTime, &settings.TelegramChatID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return settings, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to load notification settings: %w", err)
+	}
+	settings.ReminderOffsets = make([]int, len(offsets))
+	for i, offset := range offsets {
+		settings.ReminderOffsets[i] = int(offset)
+	}
+	if quietStart.Valid && quietEnd.Valid {
+		settings.QuietHours = &QuietHours{Start: quietStart.String, End: quietEnd.String}
+	}
+
+	rows, err := s.db.Query(`SELECT event, channel, enabled FROM notification_preferences WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load notification preferences: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var event Event
+		var channel Channel
+		var enabled bool
+		if err := rows.Scan(&event, &channel, &enabled); err != nil {
+			return nil, fmt.Errorf("failed to load notification preferences: %w", err)
+		}
+		// Preferences for events that were since removed are ignored
+		if channels, ok := settings.Preferences[event]; ok {
+			channels[channel] = enabled
+		}
+	}
+	return settings, rows.Err()
+}
+
+// Save stores the user's settings, replacing the previous ones
+func (s *Store) Save(userID string, settings *Settings) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	offsets := make(pq.Int64Array, len(settings.ReminderOffsets))
+	for i, offset := range settings.ReminderOffsets {
+		offsets[i] = int64(offset)
+	}
+	var quietStart, quietEnd sql.NullString
+	if settings.QuietHours != nil {
+		quietStart = sql.NullString{String: settings.QuietHours.Start, Valid: true}
+		quietEnd = sql.NullString{String: settings.QuietHours.End, Valid: true}
+	}
+	_, err = tx.Exec(`
+		INSERT INTO notification_settings (user_id, reminder_offsets, quiet_hours_start, quiet_hours_end, time_zone, digest, digest_time, telegram_chat_id, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (user_id) DO UPDATE SET
+			reminder_offsets = EXCLUDED.reminder_offsets,
+			quiet_hours_start = EXCLUDED.quiet_hours_start,
+			quiet_hours_end = EXCLUDED.quiet_hours_end,
+			time_zone = EXCLUDED.time_zone,
+			digest = EXCLUDED.digest,
+			digest_time = EXCLUDED.digest_time,
+			telegram_chat_id = EXCLUDED.telegram_chat_id,
+			updated_at = EXCLUDED.updated_at`,
+		userID, offsets, quietStart, quietEnd, settings.TimeZone, settings.Digest, settings.DigestTime, settings.TelegramChatID, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to save notification settings: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM notification_preferences WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+	for event, channels := range settings.Preferences {
+		for channel, enabled := range channels {
+			if _, err := tx.Exec(`INSERT INTO notification_preferences (user_id, event, channel, enabled) VALUES ($1, $2, $3, $4)`,
+				userID, event, channel, enabled); err != nil {
+				return fmt.Errorf("failed to save notification preferences: %w", err)
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// Recipient is where a user's notifications go
+type Recipient struct {
+	UserID         string
+	Email          string
+	Phone          string
+	FullName       string
+	Language       string
+	TelegramChatID string
+}
+
+// recipient returns the user's contact details, or nil when the user no
+// longer exists or has been erased
+func (s *Store) recipient(userID string, settings *Settings) (*Recipient, error) {
+	r := &Recipient{UserID: userID, TelegramChatID: settings.TelegramChatID}
+	err := s.db.QueryRow(`
+		SELECT email, COALESCE(phone, ''), fullname, language
+		FROM users WHERE id = $1 AND status <> 'erased'`, userID).
+		Scan(&r.Email, &r.Phone, &r.FullName, &r.Language)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recipient: %w", err)
+	}
+	return r, nil
+}
+
+// insertInApp adds a notification to the user's in-app inbox
+func (s *Store) insertInApp(userID string, msg Message, title, body string, now time.Time) error {
+	var bookingID sql.NullString
+	if msg.BookingID != "" {
+		bookingID = sql.NullString{String: msg.BookingID, Valid: true}
+	}
+	var expires sql.NullTime
+	if !msg.Expires.IsZero() {
+		expires = sql.NullTime{Time: msg.Expires.UTC(), Valid: true}
+	}
+	_, err := s.db.Exec(`
+		INSERT INTO notifications (user_id, booking_id, title, message, type, sent_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		userID, bookingID, title, body, msg.Event, now.UTC(), expires)
+	if err != nil {
+		return fmt.Errorf("failed to add in-app notification: %w", err)
+	}
+	return nil
+}
+
+// pending is a notification waiting in the outbox for the end of the
+// user's quiet hours or their next digest
+type pending struct {
+	UserID  string
+	Event   Event
+	Channel Channel
+	Title   string
+	Body    string
+	Digest  bool
+	Expires sql.NullTime
+}
+
+func (s *Store) enqueue(p pending, sendAfter time.Time) error {
+	_, err := s.db.Exec(`
+		INSERT INTO notification_outbox (user_id, event, channel, title, body, digest, send_after, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		p.UserID, p.Event, p.Channel, p.Title, p.Body, p.Digest, sendAfter.UTC(), p.Expires)
+	if err != nil {
+		return fmt.Errorf("failed to queue notification: %w", err)
+	}
+	return nil
+}
+
+// claimDue removes up to limit notifications that are due from the outbox
+// and returns those that have not expired. Concurrent callers claim
+// different notifications.
+func (s *Store) claimDue(now time.Time, limit int) ([]pending, error) {
+	rows, err := s.db.Query(`
+		DELETE FROM notification_outbox WHERE id IN (
+			SELECT id FROM notification_outbox WHERE send_after <= $1
+			ORDER BY created_at LIMIT $2 FOR UPDATE SKIP LOCKED)
+		RETURNING user_id, event, channel, title, body, digest, expires_at`, now.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim queued notifications: %w", err)
+	}
+	defer rows.Close()
+	var due []pending
+	for rows.Next() {
+		var p pending
+		if err := rows.Scan(&p.UserID, &p.Event, &p.Channel, &p.Title, &p.Body, &p.Digest, &p.Expires); err != nil {
+			return nil, fmt.Errorf("failed to claim queued notifications: %w", err)
+		}
+		if p.Expires.Valid && p.Expires.Time.Before(now) {
+			continue
+		}
+		due = append(due, p)
+	}
+	return due, rows.Err()
+}
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var ErrNotFound = errors.New("file not found")
+
+// FileStore keeps uploaded files under opaque keys such as
+// "certifications/<id>/<file id>.pdf"
+type FileStore interface {
+	Save(key string, data []byte) error
+	Open(key string) (io.ReadCloser, error)
+	Delete(key string) error
+}
+
+type localFileStore struct {
+	dir string
+}
+
+// NewLocalFileStore stores files on the local disk below dir
+func NewLocalFileStore(dir string) FileStore {
+	return &localFileStore{dir: dir}
+}
+
+func (s *localFileStore) Save(key string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o640)
+}
+
+func (s *localFileStore) Open(key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+// Delete removes a file; deleting a missing file is not an error
+func (s *localFileStore) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path maps a key to a file below dir, rejecting keys that would escape it
+func (s *localFileStore) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if clean == "/" || strings.Contains(key, "..") {
+		return "", errors.New("invalid storage key: " + key)
+	}
+	return filepath.Join(s.dir, clean), nil
+}